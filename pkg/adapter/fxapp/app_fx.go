@@ -46,6 +46,8 @@ func CreateFxApp(
 		fxlog.FxLogger,
 		fx.ErrorHook(NewFxErrorHandler(app.logger)),
 		AppModule,
+		// After AppModule, so that phased shutdown runs before the invokes' OnStop hooks
+		ShutdownModule,
 	)
 
 	return fxApp
@@ -0,0 +1,31 @@
+package contracts
+
+import (
+	"context"
+	"time"
+)
+
+// ShutdownPhase orders the shutdown of application components: every hook of a phase
+// has stopped before the hooks of the next (higher) phase start stopping
+type ShutdownPhase int
+
+const (
+	// ShutdownPhaseIngress stops accepting traffic: HTTP and gRPC servers
+	ShutdownPhaseIngress ShutdownPhase = 100
+	// ShutdownPhaseWorkers drains background work: queue consumers, schedulers, batch writers
+	ShutdownPhaseWorkers ShutdownPhase = 200
+	// ShutdownPhaseResources closes shared resources: databases, caches, tracer providers
+	ShutdownPhaseResources ShutdownPhase = 300
+)
+
+// ShutdownHook stops a component during a shutdown phase
+type ShutdownHook struct {
+	// Name identifies the component in logs and errors
+	Name string
+	// Phase is the phase the hook runs in
+	Phase ShutdownPhase
+	// Timeout bounds the hook; the default timeout is used when 0
+	Timeout time.Duration
+	// OnStop stops the component, returning once it is stopped or ctx is done
+	OnStop func(ctx context.Context) error
+}
@@ -0,0 +1,140 @@
+package fxapp
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/fxapp/contracts"
+	"github.com/phatnt199/go-infra/pkg/logger"
+
+	"go.uber.org/fx"
+)
+
+// DefaultShutdownHookTimeout bounds shutdown hooks that don't set a Timeout
+const DefaultShutdownHookTimeout = 10 * time.Second
+
+// shutdownGroup is the fx value group collecting shutdown hooks
+const shutdownGroup = `group:"shutdown_hooks"`
+
+// ShutdownModule stops the hooks provided with AsShutdownHook phase by phase when the
+// application stops. It is included by the applications built with CreateFxApp, where
+// its stop hook runs before the OnStop hooks registered by the application's invokes.
+var ShutdownModule = fx.Module(
+	"shutdownfx",
+	fx.Invoke(registerShutdownHooks),
+)
+
+// AsShutdownHook annotates a constructor returning a contracts.ShutdownHook to add it to
+// the hooks stopped by ShutdownModule.
+//
+// Example:
+//
+//	builder.Provide(fxapp.AsShutdownHook(func(server contracts.HttpServer) fxcontracts.ShutdownHook {
+//		return fxcontracts.ShutdownHook{
+//			Name:    "http",
+//			Phase:   fxcontracts.ShutdownPhaseIngress,
+//			Timeout: 15 * time.Second,
+//			OnStop:  server.GracefulShutdown,
+//		}
+//	}))
+func AsShutdownHook(constructor interface{}) interface{} {
+	return fx.Annotate(
+		constructor,
+		fx.ResultTags(shutdownGroup),
+	)
+}
+
+type shutdownParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Logger    logger.Logger
+	Hooks     []contracts.ShutdownHook `group:"shutdown_hooks"`
+}
+
+// registerShutdownHooks appends the fx stop hook running the shutdown phases
+func registerShutdownHooks(params shutdownParams) {
+	if len(params.Hooks) == 0 {
+		return
+	}
+
+	params.Lifecycle.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return StopInPhases(ctx, params.Logger, params.Hooks...)
+		},
+	})
+}
+
+// StopInPhases runs hooks phase by phase, in ascending phase order. The hooks of a phase
+// run concurrently, each bounded by its Timeout, and the next phase starts once they all
+// returned, so a phase lasts at most as long as its longest timeout. Failing hooks
+// don't stop the shutdown: every failure is returned, joined with errors.Join and
+// prefixed with the hook name.
+func StopInPhases(ctx context.Context, log logger.Logger, hooks ...contracts.ShutdownHook) error {
+	sorted := append([]contracts.ShutdownHook(nil), hooks...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Phase < sorted[j].Phase })
+
+	var errs []error
+	for start := 0; start < len(sorted); {
+		end := start
+		for end < len(sorted) && sorted[end].Phase == sorted[start].Phase {
+			end++
+		}
+
+		phase := sorted[start].Phase
+		log.InfoFields("stopping shutdown phase", logger.Int("phase", int(phase)), logger.Int("hooks", end-start))
+		errs = append(errs, stopPhase(ctx, log, sorted[start:end])...)
+		start = end
+	}
+
+	return stdErrors.Join(errs...)
+}
+
+// stopPhase runs hooks concurrently and returns their errors
+func stopPhase(ctx context.Context, log logger.Logger, hooks []contracts.ShutdownHook) []error {
+	errs := make([]error, len(hooks))
+
+	var wg sync.WaitGroup
+	for i, hook := range hooks {
+		if hook.OnStop == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, hook contracts.ShutdownHook) {
+			defer wg.Done()
+
+			timeout := hook.Timeout
+			if timeout <= 0 {
+				timeout = DefaultShutdownHookTimeout
+			}
+			hookCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			if err := hook.OnStop(hookCtx); err != nil {
+				log.ErrorFields("shutdown hook failed",
+					logger.String("hook", hook.Name),
+					logger.Int("phase", int(hook.Phase)),
+					logger.Err(err))
+				errs[i] = fmt.Errorf("%s: %w", hook.Name, err)
+				return
+			}
+			log.InfoFields("shutdown hook stopped",
+				logger.String("hook", hook.Name),
+				logger.Duration("duration", time.Since(start)))
+		}(i, hook)
+	}
+	wg.Wait()
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	return failed
+}
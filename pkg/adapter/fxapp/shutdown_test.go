@@ -0,0 +1,126 @@
+package fxapp
+
+import (
+	"context"
+	stdErrors "errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/fxapp/contracts"
+	"github.com/phatnt199/go-infra/pkg/logger"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+// stopRecorder records the order components are stopped in
+type stopRecorder struct {
+	mu      sync.Mutex
+	stopped []string
+}
+
+func (r *stopRecorder) hook(name string, phase contracts.ShutdownPhase) contracts.ShutdownHook {
+	return contracts.ShutdownHook{
+		Name:  name,
+		Phase: phase,
+		OnStop: func(ctx context.Context) error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.stopped = append(r.stopped, name)
+			return nil
+		},
+	}
+}
+
+func (r *stopRecorder) order() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.stopped...)
+}
+
+func Test_Shutdown_Module_Stops_Phases_In_Order(t *testing.T) {
+	recorder := &stopRecorder{}
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(fx.Annotate(empty.EmptyLogger, fx.As(new(logger.Logger)))),
+		// Provided in reverse order of the expected shutdown
+		fx.Provide(
+			AsShutdownHook(func() contracts.ShutdownHook {
+				return recorder.hook("database", contracts.ShutdownPhaseResources)
+			}),
+			AsShutdownHook(func() contracts.ShutdownHook {
+				return recorder.hook("queue-consumer", contracts.ShutdownPhaseWorkers)
+			}),
+			AsShutdownHook(func() contracts.ShutdownHook {
+				return recorder.hook("http", contracts.ShutdownPhaseIngress)
+			}),
+		),
+		ShutdownModule,
+	)
+	require.NoError(t, app.Err())
+
+	ctx := context.Background()
+	require.NoError(t, app.Start(ctx))
+	assert.Empty(t, recorder.order())
+	require.NoError(t, app.Stop(ctx))
+
+	assert.Equal(t, []string{"http", "queue-consumer", "database"}, recorder.order())
+}
+
+func Test_Stop_In_Phases_Waits_For_Each_Phase(t *testing.T) {
+	recorder := &stopRecorder{}
+	slow := recorder.hook("slow-http", contracts.ShutdownPhaseIngress)
+	slowStop := slow.OnStop
+	slow.OnStop = func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return slowStop(ctx)
+	}
+
+	err := StopInPhases(context.Background(), empty.EmptyLogger,
+		recorder.hook("database", contracts.ShutdownPhaseResources),
+		slow,
+		recorder.hook("grpc", contracts.ShutdownPhaseIngress),
+	)
+	require.NoError(t, err)
+
+	// Both ingress hooks stop, the fast one first, before the database is closed
+	assert.Equal(t, []string{"grpc", "slow-http", "database"}, recorder.order())
+}
+
+func Test_Stop_In_Phases_Applies_Hook_Timeouts_And_Continues_On_Errors(t *testing.T) {
+	recorder := &stopRecorder{}
+	stuck := contracts.ShutdownHook{
+		Name:    "stuck-consumer",
+		Phase:   contracts.ShutdownPhaseWorkers,
+		Timeout: 20 * time.Millisecond,
+		OnStop: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	failing := contracts.ShutdownHook{
+		Name:   "scheduler",
+		Phase:  contracts.ShutdownPhaseWorkers,
+		OnStop: func(ctx context.Context) error { return stdErrors.New("jobs still running") },
+	}
+
+	start := time.Now()
+	err := StopInPhases(context.Background(), empty.EmptyLogger,
+		stuck,
+		failing,
+		recorder.hook("database", contracts.ShutdownPhaseResources),
+	)
+	assert.Less(t, time.Since(start), time.Second)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "stuck-consumer: context deadline exceeded")
+	assert.Contains(t, err.Error(), "scheduler: jobs still running")
+
+	// The resources are still released
+	assert.Equal(t, []string{"database"}, recorder.order())
+}
@@ -0,0 +1,69 @@
+package customgrpc
+
+import (
+	"context"
+	"net"
+
+	appConfig "github.com/phatnt199/go-infra/pkg/application/config"
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/logger"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/fx"
+)
+
+var (
+	// Module provides a gRPC *Server built from the loaded application configuration,
+	// listening when the application starts and gracefully stopped when it stops.
+	// Register services on Server.GRPCServer from fx.Invoke functions.
+	Module = fx.Module(
+		"grpcfx",
+		grpcProviders,
+		grpcInvokes,
+	)
+
+	grpcProviders = fx.Options(fx.Provide(ProvideServer))
+
+	grpcInvokes = fx.Options(fx.Invoke(registerHooks))
+)
+
+// ProvideServer creates a gRPC server from config.Get().Server.GRPC, reporting recovered
+// panics to the logger
+func ProvideServer(log logger.Logger, meter metric.Meter) (*Server, error) {
+	cfg := appConfig.Get()
+	if cfg == nil {
+		return nil, errors.Internal("application configuration is not loaded")
+	}
+
+	return NewServer(&cfg.Server.GRPC, log, meter, WithPanicReporter(errors.NewLoggerPanicReporter(log)))
+}
+
+// registerHooks starts serving on start and gracefully stops the server on stop. The
+// port is bound before start returns, so a port already in use fails the startup.
+func registerHooks(lc fx.Lifecycle, server *Server, log logger.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", server.Address())
+			if err != nil {
+				return err
+			}
+
+			go func() {
+				if err := server.Serve(ln); err != nil {
+					log.Fatalf("(GrpcServer.Serve) error in running server: {%v}", err)
+				}
+			}()
+			log.Infof("gRPC server is listening on {%s}", server.Address())
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if err := server.GracefulShutdown(ctx); err != nil {
+				log.Errorf("error shutting down gRPC server: %v", err)
+			} else {
+				log.Info("gRPC server shutdown gracefully")
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,155 @@
+package customgrpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/logger"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RPCDurationMetricName is the histogram of gRPC call latencies, in seconds
+const RPCDurationMetricName = "rpc.server.duration"
+
+// redactedMetadata are the metadata keys never written to access logs
+var redactedMetadata = []string{"authorization", "cookie", "x-api-key"}
+
+// RecoveryUnaryInterceptor converts panics into CodeInternal errors, handed to reporter when
+// it is not nil, and converts the errors returned by handlers into gRPC statuses with
+// errors.ToGRPCStatus. Errors that already are gRPC statuses are returned unchanged.
+func RecoveryUnaryInterceptor(reporter errors.PanicReporter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = recovered(ctx, reporter, info.FullMethod, rec)
+			}
+		}()
+
+		resp, err = handler(ctx, req)
+		return resp, statusError(err)
+	}
+}
+
+// RecoveryStreamInterceptor is the stream counterpart of RecoveryUnaryInterceptor
+func RecoveryStreamInterceptor(reporter errors.PanicReporter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = recovered(ss.Context(), reporter, info.FullMethod, rec)
+			}
+		}()
+
+		return statusError(handler(srv, ss))
+	}
+}
+
+// recovered reports the panic rec and returns its gRPC status error
+func recovered(ctx context.Context, reporter errors.PanicReporter, method string, rec interface{}) error {
+	appErr := errors.FromPanic(rec).WithContext("method", method)
+	if reporter != nil {
+		go func() {
+			defer func() { _ = recover() }()
+			reporter.Report(context.WithoutCancel(ctx), appErr)
+		}()
+	}
+	return statusError(appErr)
+}
+
+// statusError converts err to a gRPC status error with errors.ToGRPCStatus
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return errors.ToGRPCStatus(err).Err()
+}
+
+// LoggingUnaryInterceptor writes an access log line for every call with
+// logger.GrpcMiddlewareAccessLogger. Credentials are redacted from the metadata.
+func LoggingUnaryInterceptor(log logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		log.GrpcMiddlewareAccessLogger(info.FullMethod, time.Since(start), loggedMetadata(ctx), err)
+		return resp, err
+	}
+}
+
+// LoggingStreamInterceptor is the stream counterpart of LoggingUnaryInterceptor
+func LoggingStreamInterceptor(log logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		log.GrpcMiddlewareAccessLogger(info.FullMethod, time.Since(start), loggedMetadata(ss.Context()), err)
+		return err
+	}
+}
+
+// loggedMetadata returns the incoming metadata of ctx without credentials
+func loggedMetadata(ctx context.Context) map[string][]string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	for _, key := range redactedMetadata {
+		if _, ok := md[key]; ok {
+			md[key] = []string{"[REDACTED]"}
+		}
+	}
+	return md
+}
+
+// RPCMetrics records the duration of gRPC calls as an OpenTelemetry histogram, labelled
+// with the service, the method and the status code.
+type RPCMetrics struct {
+	duration metric.Float64Histogram
+}
+
+// NewRPCMetrics registers the RPCDurationMetricName histogram on meter
+func NewRPCMetrics(meter metric.Meter) (*RPCMetrics, error) {
+	duration, err := meter.Float64Histogram(
+		RPCDurationMetricName,
+		metric.WithDescription("Duration of gRPC calls"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RPCMetrics{duration: duration}, nil
+}
+
+// UnaryInterceptor returns the interceptor recording unary call durations
+func (m *RPCMetrics) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.record(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamInterceptor returns the interceptor recording stream durations
+func (m *RPCMetrics) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.record(ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
+
+func (m *RPCMetrics) record(ctx context.Context, fullMethod string, start time.Time, err error) {
+	// Full methods are "/package.Service/Method"
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	m.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", method),
+		attribute.String("rpc.grpc.status_code", status.Code(statusError(err)).String()),
+	))
+}
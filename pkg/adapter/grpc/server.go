@@ -0,0 +1,162 @@
+// Package customgrpc provides a gRPC server configured from config.GRPCConfig, with
+// recovery, logging and metrics interceptors mirroring the Fiber HTTP middlewares,
+// and an fx module starting and gracefully stopping it with the application.
+package customgrpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	appConfig "github.com/phatnt199/go-infra/pkg/application/config"
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/logger"
+
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Server is a gRPC server. Register services on GRPCServer before calling Run.
+type Server struct {
+	server *grpc.Server
+	cfg    appConfig.GRPCConfig
+	log    logger.Logger
+}
+
+// ServerOption configures NewServer
+type ServerOption func(*serverConfig)
+
+type serverConfig struct {
+	reporter    errors.PanicReporter
+	grpcOptions []grpc.ServerOption
+	unary       []grpc.UnaryServerInterceptor
+	stream      []grpc.StreamServerInterceptor
+}
+
+// WithPanicReporter hands the errors built from recovered panics to reporter, e.g.
+// errors.NewLoggerPanicReporter(log), in addition to failing the call
+func WithPanicReporter(reporter errors.PanicReporter) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.reporter = reporter
+	}
+}
+
+// WithUnaryInterceptors appends interceptors running after the built-in ones, e.g.
+// authentication
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.unary = append(cfg.unary, interceptors...)
+	}
+}
+
+// WithStreamInterceptors appends stream interceptors running after the built-in ones
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.stream = append(cfg.stream, interceptors...)
+	}
+}
+
+// WithGRPCOptions passes options to grpc.NewServer, e.g. credentials or message size
+// limits
+func WithGRPCOptions(opts ...grpc.ServerOption) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.grpcOptions = append(cfg.grpcOptions, opts...)
+	}
+}
+
+// NewServer creates a gRPC server using the keepalive and connection age settings of
+// cfg. Every call goes through the metrics, logging and recovery interceptors, in that
+// order, and returned errors are converted to gRPC statuses from their AppError code.
+// Metrics are only recorded when meter is not nil.
+//
+// Example:
+//
+//	server, err := customgrpc.NewServer(&cfg.GRPC, log, meter)
+//	pb.RegisterUserServiceServer(server.GRPCServer(), userService)
+//	go server.Run()
+func NewServer(cfg *appConfig.GRPCConfig, log logger.Logger, meter metric.Meter, opts ...ServerOption) (*Server, error) {
+	config := &serverConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	if meter != nil {
+		metrics, err := NewRPCMetrics(meter)
+		if err != nil {
+			return nil, err
+		}
+		unary = append(unary, metrics.UnaryInterceptor())
+		stream = append(stream, metrics.StreamInterceptor())
+	}
+	unary = append(unary, LoggingUnaryInterceptor(log), RecoveryUnaryInterceptor(config.reporter))
+	stream = append(stream, LoggingStreamInterceptor(log), RecoveryStreamInterceptor(config.reporter))
+
+	serverOpts := []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     cfg.MaxConnectionIdle,
+			MaxConnectionAge:      cfg.MaxConnectionAge,
+			MaxConnectionAgeGrace: cfg.MaxConnectionAgeGrace,
+			Time:                  cfg.KeepAliveTime,
+			Timeout:               cfg.KeepAliveTimeout,
+		}),
+		grpc.ChainUnaryInterceptor(append(unary, config.unary...)...),
+		grpc.ChainStreamInterceptor(append(stream, config.stream...)...),
+	}
+
+	return &Server{
+		server: grpc.NewServer(append(serverOpts, config.grpcOptions...)...),
+		cfg:    *cfg,
+		log:    log,
+	}, nil
+}
+
+// GRPCServer returns the underlying server to register services on
+func (s *Server) GRPCServer() *grpc.Server {
+	return s.server
+}
+
+// Address returns the host:port the server listens on
+func (s *Server) Address() string {
+	return net.JoinHostPort(s.cfg.Host, fmt.Sprint(s.cfg.Port))
+}
+
+// Run listens on Address and serves until the server is stopped. It returns nil once
+// GracefulShutdown was called.
+func (s *Server) Run() error {
+	ln, err := net.Listen("tcp", s.Address())
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve serves on ln until the server is stopped, e.g. on a bufconn listener in tests
+func (s *Server) Serve(ln net.Listener) error {
+	if err := s.server.Serve(ln); err != nil && err != grpc.ErrServerStopped {
+		return err
+	}
+	return nil
+}
+
+// GracefulShutdown stops accepting connections and waits, until ctx is done, for the
+// calls in flight to complete. Calls still running then are cancelled.
+func (s *Server) GracefulShutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.log.Warn("shutdown deadline reached with gRPC calls in flight")
+		s.server.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
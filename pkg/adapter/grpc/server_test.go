@@ -0,0 +1,231 @@
+package customgrpc
+
+import (
+	"context"
+	stdErrors "errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	appConfig "github.com/phatnt199/go-infra/pkg/application/config"
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/logger"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// testHandler is the implementation of the single method of testServiceDesc
+type testHandler func(ctx context.Context) error
+
+var testServiceDesc = grpc.ServiceDesc{
+	ServiceName: "test.TestService",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Call",
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			in := new(emptypb.Empty)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return &emptypb.Empty{}, srv.(testHandler)(ctx)
+			}
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.TestService/Call"}, handler)
+		},
+	}},
+}
+
+// accessLogger records the access log calls
+type accessLogger struct {
+	logger.Logger
+	mu       sync.Mutex
+	methods  []string
+	metadata []map[string][]string
+}
+
+func (l *accessLogger) GrpcMiddlewareAccessLogger(method string, _ time.Duration, md map[string][]string, _ error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.methods = append(l.methods, method)
+	l.metadata = append(l.metadata, md)
+}
+
+// startTestServer serves handler over an in-memory listener and returns a connected client
+func startTestServer(t *testing.T, handler testHandler, log logger.Logger, opts ...ServerOption) (*Server, *grpc.ClientConn) {
+	t.Helper()
+	server, err := NewServer(&appConfig.GRPCConfig{}, log, nil, opts...)
+	require.NoError(t, err)
+	server.GRPCServer().RegisterService(&testServiceDesc, handler)
+
+	ln := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(ln) }()
+	t.Cleanup(server.GRPCServer().Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return server, conn
+}
+
+func call(ctx context.Context, conn *grpc.ClientConn) error {
+	return conn.Invoke(ctx, "/test.TestService/Call", &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+func Test_Server_Converts_AppErrors_To_Status(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected codes.Code
+	}{
+		{errors.NotFound("user"), codes.NotFound},
+		{errors.Unauthorized("missing token"), codes.Unauthenticated},
+		{errors.Validation("invalid email"), codes.InvalidArgument},
+		{errors.Conflict("duplicate"), codes.Aborted},
+		{status.Error(codes.Aborted, "aborted"), codes.Aborted},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{stdErrors.New("boom"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		_, conn := startTestServer(t, func(ctx context.Context) error { return tt.err }, empty.EmptyLogger)
+
+		err := call(context.Background(), conn)
+		assert.Equal(t, tt.expected, status.Code(err), tt.err.Error())
+	}
+}
+
+func Test_Server_Recovers_Panics(t *testing.T) {
+	reported := make(chan *errors.AppError, 1)
+	reporter := errors.PanicReporterFunc(func(_ context.Context, err *errors.AppError) { reported <- err })
+
+	_, conn := startTestServer(t, func(ctx context.Context) error { panic("boom") }, empty.EmptyLogger,
+		WithPanicReporter(reporter))
+
+	err := call(context.Background(), conn)
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	select {
+	case appErr := <-reported:
+		assert.Equal(t, errors.CodeInternal, appErr.Code)
+		assert.Equal(t, "/test.TestService/Call", appErr.Context["method"])
+	case <-time.After(time.Second):
+		t.Fatal("panic was not reported")
+	}
+
+	// The server keeps serving
+	_, conn = startTestServer(t, func(ctx context.Context) error { return nil }, empty.EmptyLogger)
+	assert.NoError(t, call(context.Background(), conn))
+}
+
+func Test_Server_Logs_Calls_Without_Credentials(t *testing.T) {
+	log := &accessLogger{Logger: empty.EmptyLogger}
+	_, conn := startTestServer(t, func(ctx context.Context) error { return nil }, log)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret", "x-tenant", "acme")
+	require.NoError(t, call(ctx, conn))
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	require.Equal(t, []string{"/test.TestService/Call"}, log.methods)
+	assert.Equal(t, []string{"[REDACTED]"}, log.metadata[0]["authorization"])
+	assert.Equal(t, []string{"acme"}, log.metadata[0]["x-tenant"])
+}
+
+func Test_Server_Records_Call_Durations(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	server, err := NewServer(&appConfig.GRPCConfig{}, empty.EmptyLogger, meter)
+	require.NoError(t, err)
+	server.GRPCServer().RegisterService(&testServiceDesc, testHandler(func(ctx context.Context) error {
+		return errors.NotFound("user")
+	}))
+	ln := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(ln) }()
+	defer server.GRPCServer().Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Error(t, call(context.Background(), conn))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	metric := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, RPCDurationMetricName, metric.Name)
+
+	points := metric.Data.(metricdata.Histogram[float64]).DataPoints
+	require.Len(t, points, 1)
+	for key, expected := range map[string]string{
+		"rpc.service":          "test.TestService",
+		"rpc.method":           "Call",
+		"rpc.grpc.status_code": codes.NotFound.String(),
+	} {
+		value, ok := points[0].Attributes.Value(attribute.Key(key))
+		require.True(t, ok, key)
+		assert.Equal(t, expected, value.AsString(), key)
+	}
+}
+
+func Test_Graceful_Shutdown_Waits_For_Calls_In_Flight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server, conn := startTestServer(t, func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}, empty.EmptyLogger)
+
+	result := make(chan error, 1)
+	go func() { result <- call(context.Background(), conn) }()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- server.GracefulShutdown(context.Background()) }()
+
+	select {
+	case <-shutdown:
+		t.Fatal("shutdown returned with a call in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-result)
+	assert.NoError(t, <-shutdown)
+}
+
+func Test_Graceful_Shutdown_Stops_At_Deadline(t *testing.T) {
+	started := make(chan struct{})
+	server, conn := startTestServer(t, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, empty.EmptyLogger)
+
+	go func() { _ = call(context.Background(), conn) }()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, server.GracefulShutdown(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,70 @@
+// Package contextkey provides typed accessors for values stored on a
+// contracts.Context, so handlers and middleware never share raw string keys.
+package contextkey
+
+import (
+	"fmt"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+	"github.com/phatnt199/go-infra/pkg/correlation"
+	"github.com/phatnt199/go-infra/pkg/crypto"
+)
+
+var (
+	// Claims holds the authenticated JWT claims, set by the JWT middleware
+	Claims = New[*crypto.Claims]("claims")
+
+	// RequestID holds the correlation ID of the current request
+	RequestID = New[string](correlation.FieldName)
+)
+
+// Key is a typed handle for a value stored on a contracts.Context.
+type Key[T any] struct {
+	name string
+}
+
+// New returns a key that stores values of type T under name.
+//
+// Example:
+//
+//	var tenantKey = contextkey.New[string]("tenant")
+//
+//	tenantKey.Set(c, "acme")
+//	tenant, ok := tenantKey.Get(c)
+func New[T any](name string) Key[T] {
+	return Key[T]{name: name}
+}
+
+// Name returns the underlying storage key.
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// Get returns the value stored under the key. It reports false when nothing
+// has been set or when the stored value is not a T.
+func (k Key[T]) Get(c contracts.Context) (T, bool) {
+	v, ok := c.Get(k.name).(T)
+	return v, ok
+}
+
+// MustGet returns the value stored under the key. It panics when nothing has
+// been set or when the stored value is not a T, e.g. in a handler mounted
+// without the middleware setting the key.
+func (k Key[T]) MustGet(c contracts.Context) T {
+	v, ok := k.Get(c)
+	if !ok {
+		panic(fmt.Sprintf("contextkey: %q is not set", k.name))
+	}
+	return v
+}
+
+// GetOrZero returns the value stored under the key, or the zero value of T.
+func (k Key[T]) GetOrZero(c contracts.Context) T {
+	v, _ := k.Get(c)
+	return v
+}
+
+// Set stores v under the key.
+func (k Key[T]) Set(c contracts.Context, v T) {
+	c.Set(k.name, v)
+}
@@ -0,0 +1,72 @@
+package contextkey
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+	"github.com/phatnt199/go-infra/pkg/crypto"
+)
+
+type mapContext struct {
+	contracts.Context
+	values map[string]interface{}
+}
+
+func newMapContext() *mapContext {
+	return &mapContext{values: map[string]interface{}{}}
+}
+
+func (m *mapContext) Get(key string) interface{} {
+	return m.values[key]
+}
+
+func (m *mapContext) Set(key string, val interface{}) {
+	m.values[key] = val
+}
+
+func Test_Get_Before_Set_Returns_False(t *testing.T) {
+	c := newMapContext()
+	key := New[int]("count")
+
+	v, ok := key.Get(c)
+	assert.False(t, ok)
+	assert.Zero(t, v)
+	assert.Zero(t, key.GetOrZero(c))
+	assert.PanicsWithValue(t, `contextkey: "count" is not set`, func() { key.MustGet(c) })
+}
+
+func Test_Set_Then_Get(t *testing.T) {
+	c := newMapContext()
+	key := New[int]("count")
+
+	key.Set(c, 42)
+
+	v, ok := key.Get(c)
+	assert.True(t, ok)
+	assert.Equal(t, 42, v)
+}
+
+func Test_Get_With_Type_Mismatch_Returns_False(t *testing.T) {
+	c := newMapContext()
+	New[string]("count").Set(c, "forty-two")
+
+	v, ok := New[int]("count").Get(c)
+	assert.False(t, ok)
+	assert.Zero(t, v)
+}
+
+func Test_Predefined_Keys(t *testing.T) {
+	c := newMapContext()
+	claims := &crypto.Claims{}
+
+	Claims.Set(c, claims)
+	RequestID.Set(c, "req-1")
+
+	got, ok := Claims.Get(c)
+	assert.True(t, ok)
+	assert.Same(t, claims, got)
+	assert.Equal(t, "req-1", RequestID.MustGet(c))
+	assert.Equal(t, "req-1", c.values["request_id"])
+}
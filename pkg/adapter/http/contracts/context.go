@@ -2,6 +2,7 @@
 package contracts
 
 import (
+	"context"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -41,16 +42,31 @@ type Context interface {
 	// Set saves data in the context
 	Set(key string, val interface{})
 
+	// SetHeader sets a response header
+	SetHeader(name, value string)
+
 	// Bind binds the request body into provided type
 	// Supports JSON, XML, form data based on Content-Type
 	Bind(i interface{}) error
 
+	// BindQuery binds the query parameters into provided struct using `query:"..."`
+	// tags, then validates it
+	BindQuery(i interface{}) error
+
 	// Validate validates provided struct
 	Validate(i interface{}) error
 
+	// Accepts returns the best match of the given offers for the Accept header,
+	// or an empty string if none is acceptable
+	Accepts(offers ...string) string
+
 	// JSON sends a JSON response with status code
 	JSON(code int, i interface{}) error
 
+	// Paginated sends page as the standard list envelope, with its items under "data"
+	// and its PageMeta under "meta"
+	Paginated(code int, page Pageable) error
+
 	// JSONBlob sends a JSON blob response with status code
 	JSONBlob(code int, b []byte) error
 
@@ -69,6 +85,15 @@ type Context interface {
 	// Stream sends a streaming response with status code and content type
 	Stream(code int, contentType string, r io.Reader) error
 
+	// SSE streams events as Server-Sent Events, flushing each one and sending
+	// heartbeat comments while idle, until ctx is done, events is closed or the
+	// client disconnects
+	SSE(ctx context.Context, events <-chan SSEEvent) error
+
+	// SSEFrom is SSE for the events of produce, which runs with a context canceled
+	// when the stream ends, so that it stops when the client disconnects
+	SSEFrom(ctx context.Context, produce SSEProducer) error
+
 	// NoContent sends a response with no body and status code
 	NoContent(code int) error
 
@@ -16,6 +16,9 @@ type HttpServer interface {
 	// GracefulShutdown gracefully shuts down the server
 	GracefulShutdown(ctx context.Context) error
 
+	// InFlight returns the number of requests currently being served
+	InFlight() int
+
 	// ApplyVersioningFromHeader enables API versioning from headers
 	ApplyVersioningFromHeader()
 
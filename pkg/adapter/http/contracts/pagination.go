@@ -0,0 +1,62 @@
+package contracts
+
+// PageMeta describes the page of a paginated response
+type PageMeta struct {
+	Page       int   `json:"page"`
+	Size       int   `json:"size"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"totalPages"`
+	HasNext    bool  `json:"hasNext"`
+	HasPrev    bool  `json:"hasPrev"`
+}
+
+// NewPageMeta computes the page count and navigation flags of page (1-based) for
+// total items split in pages of size
+func NewPageMeta(page, size int, total int64) PageMeta {
+	totalPages := 0
+	if size > 0 {
+		totalPages = int((total + int64(size) - 1) / int64(size))
+	}
+
+	return PageMeta{
+		Page:       page,
+		Size:       size,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+}
+
+// Paginated is the standard envelope of list responses:
+//
+//	{"data": [...], "meta": {"page": 2, "size": 20, "total": 45, "totalPages": 3, "hasNext": true, "hasPrev": true}}
+type Paginated[T any] struct {
+	Data []T      `json:"data"`
+	Meta PageMeta `json:"meta"`
+}
+
+// NewPaginated wraps the items of one page. A nil items slice is rendered as [].
+func NewPaginated[T any](items []T, page, size int, total int64) Paginated[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Paginated[T]{Data: items, Meta: NewPageMeta(page, size, total)}
+}
+
+// PageMeta returns p.Meta
+func (p Paginated[T]) PageMeta() PageMeta {
+	return p.Meta
+}
+
+// PageData returns p.Data
+func (p Paginated[T]) PageData() interface{} {
+	return p.Data
+}
+
+// Pageable is a page of results rendered by Context.Paginated, implemented by
+// Paginated and utils.ListResult
+type Pageable interface {
+	PageMeta() PageMeta
+	PageData() interface{}
+}
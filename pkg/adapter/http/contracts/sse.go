@@ -0,0 +1,43 @@
+package contracts
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// SSEProducer sends the events of a Server-Sent Events stream until ctx is done, which
+// happens when the client disconnects. It must select on ctx.Done() around its sends
+// and must not close events; the stream closes it when the producer returns.
+type SSEProducer func(ctx context.Context, events chan<- SSEEvent)
+
+// SSEEvent is a Server-Sent Event. Event and ID are optional; multi-line Data is sent
+// as one "data:" line per line.
+type SSEEvent struct {
+	Event string
+	Data  string
+	ID    string
+}
+
+// WriteTo writes the event's text/event-stream frame to w
+func (e SSEEvent) WriteTo(w io.Writer) (int64, error) {
+	var frame strings.Builder
+	if e.ID != "" {
+		frame.WriteString("id: " + singleLine(e.ID) + "\n")
+	}
+	if e.Event != "" {
+		frame.WriteString("event: " + singleLine(e.Event) + "\n")
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(e.Data, "\r\n", "\n"), "\n") {
+		frame.WriteString("data: " + line + "\n")
+	}
+	frame.WriteString("\n")
+
+	n, err := io.WriteString(w, frame.String())
+	return int64(n), err
+}
+
+// singleLine drops line breaks, which would end an id or event field early
+func singleLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
@@ -0,0 +1,29 @@
+package contracts
+
+// WebSocket message types, as defined by RFC 6455
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+)
+
+// WSConn is a framework-agnostic WebSocket connection
+type WSConn interface {
+	// ReadMessage blocks until the next message arrives and returns its type
+	// (TextMessage or BinaryMessage) and payload
+	ReadMessage() (messageType int, data []byte, err error)
+
+	// WriteMessage sends a message of the given type
+	WriteMessage(messageType int, data []byte) error
+
+	// Close closes the connection
+	Close() error
+
+	// Param returns the path parameter of the upgrade request by name
+	Param(name string) string
+
+	// QueryParam returns the query parameter of the upgrade request by name
+	QueryParam(name string) string
+}
+
+// WSHandler serves a WebSocket connection. The connection is closed when it returns.
+type WSHandler func(conn WSConn) error
@@ -2,19 +2,21 @@ package customfiber
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
 	"github.com/phatnt199/go-infra/pkg/adapter/http/fiber_adapter/config"
 	"github.com/phatnt199/go-infra/pkg/adapter/http/fiber_adapter/handlers"
 	"github.com/phatnt199/go-infra/pkg/adapter/http/fiber_adapter/middlewares/log"
 	"github.com/phatnt199/go-infra/pkg/application/constants"
+	"github.com/phatnt199/go-infra/pkg/json"
 	"github.com/phatnt199/go-infra/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
-	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"go.opentelemetry.io/otel/metric"
 )
 
@@ -24,6 +26,8 @@ type fiberHttpServer struct {
 	log          logger.Logger
 	meter        metric.Meter
 	routeBuilder contracts.RouteBuilder
+	inFlight     *InFlightCounter
+	requests     *RequestMetrics
 }
 
 // Compile-time assertion that fiberHttpServer implements contracts.HttpServer
@@ -38,32 +42,86 @@ func NewFiberHttpServer(
 		AppName:      cfg.Name,
 		ReadTimeout:  constants.ReadTimeout,
 		WriteTimeout: constants.WriteTimeout,
-		BodyLimit:    2 * 1024 * 1024, // 2MB
+		BodyLimit:    cfg.GetBodyLimit(),
+		JSONDecoder:  json.UnmarshalUseNumber,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			// Use custom error handler
 			return handlers.ProblemDetailErrorHandlerFunc(err, c, logger)
 		},
 	})
 
+	inFlight, err := NewInFlightCounter(meter)
+	if err != nil {
+		logger.WarnMsg("failed to register in-flight requests gauge", err)
+	}
+
+	var requests *RequestMetrics
+	if meter != nil {
+		if requests, err = NewRequestMetrics(meter, 0); err != nil {
+			logger.WarnMsg("failed to register request duration histogram", err)
+		}
+	}
+
 	return &fiberHttpServer{
 		app:          app,
 		config:       cfg,
 		log:          logger,
 		meter:        meter,
 		routeBuilder: NewFiberRouteBuilder(app),
+		inFlight:     inFlight,
+		requests:     requests,
 	}
 }
 
+// RunHttpServer starts listening on the configured port. When TLS is enabled (in the
+// Fiber options or the application config) it serves HTTPS, with mutual TLS if a client
+// CA file is set; the certificate files are validated first and reloaded on change.
 func (s *fiberHttpServer) RunHttpServer(configFunc ...func(instance interface{})) error {
 	if len(configFunc) > 0 && configFunc[0] != nil {
 		configFunc[0](s.app)
 	}
 
-	return s.app.Listen(s.config.Port)
+	tlsOpts := s.tlsOptions()
+	if !tlsOpts.Enabled {
+		return s.app.Listen(s.config.Port)
+	}
+
+	tlsConfig, err := NewTLSConfig(tlsOpts)
+	if err != nil {
+		return err
+	}
+
+	ln, err := tls.Listen(s.app.Config().Network, s.config.Port, tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	return s.app.Listener(ln)
 }
 
+// GracefulShutdown stops accepting connections and waits, until ctx is done, for the
+// requests in flight to complete.
 func (s *fiberHttpServer) GracefulShutdown(ctx context.Context) error {
-	return s.app.ShutdownWithContext(ctx)
+	if n := s.InFlight(); n > 0 {
+		s.log.InfoFields("waiting for in-flight requests to drain", logger.Int("in_flight", n))
+	}
+
+	if err := s.app.ShutdownWithContext(ctx); err != nil {
+		return err
+	}
+
+	if err := s.inFlight.Wait(ctx); err != nil {
+		s.log.WarnFields("shutdown deadline reached with requests in flight",
+			logger.Int("in_flight", s.InFlight()))
+		return err
+	}
+
+	return nil
+}
+
+// InFlight returns the number of requests currently being served
+func (s *fiberHttpServer) InFlight() int {
+	return s.inFlight.Count()
 }
 
 func (s *fiberHttpServer) ApplyVersioningFromHeader() {
@@ -126,8 +184,16 @@ func (s *fiberHttpServer) SetupDefaultMiddlewares() {
 			strings.Contains(path, "favicon.ico")
 	}
 
-	// Request ID middleware
-	s.app.Use(requestid.New())
+	// In-flight requests counter (first, so it covers the whole chain)
+	s.app.Use(s.inFlight.Middleware())
+
+	// Request duration histogram, labelled by route pattern
+	if s.requests != nil {
+		s.app.Use(s.requests.Middleware())
+	}
+
+	// Correlation ID middleware (must run before the logger)
+	s.app.Use(CorrelationMiddleware())
 
 	// Logger middleware
 	s.app.Use(log.FiberLogger(s.log, log.WithSkipper(skipper)))
@@ -138,6 +204,14 @@ func (s *fiberHttpServer) SetupDefaultMiddlewares() {
 		Next:  skipper,
 	}))
 
+	// Request timeout middleware
+	s.app.Use(TimeoutMiddleware(s.requestTimeout()))
+
+	// Security headers middleware (production only, HSTS is gated on TLS)
+	if !s.config.IsDevelopment() {
+		s.app.Use(SecureHeaders(SecureHeaderOptions{TLSEnabled: s.tlsOptions().Enabled}))
+	}
+
 	// TODO: Add more middlewares as needed:
 	// - OpenTelemetry tracing
 	// - OpenTelemetry metrics
@@ -145,6 +219,15 @@ func (s *fiberHttpServer) SetupDefaultMiddlewares() {
 	// - Problem detail middleware
 }
 
+// requestTimeout returns the per-request timeout configured on the server options.
+// A zero value lets TimeoutMiddleware fall back to config.AppConfig.Timeout.
+func (s *fiberHttpServer) requestTimeout() time.Duration {
+	if s.config.Timeout > 0 {
+		return time.Duration(s.config.Timeout) * time.Second
+	}
+	return 0
+}
+
 // Helper to create skipper from URL patterns
 func createSkipper(patterns ...string) func(*fiber.Ctx) bool {
 	return func(c *fiber.Ctx) bool {
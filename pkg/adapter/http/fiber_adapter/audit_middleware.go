@@ -0,0 +1,156 @@
+package customfiber
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/correlation"
+	"github.com/phatnt199/go-infra/pkg/crypto"
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/logger"
+	defaultLogger "github.com/phatnt199/go-infra/pkg/logger/default_logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditEntry records who did what, when, and with which result
+type AuditEntry struct {
+	UserID    string    `json:"user_id"`
+	Action    string    `json:"action"`   // HTTP method
+	Resource  string    `json:"resource"` // request path
+	Status    int       `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"request_id"`
+}
+
+// AuditSink receives audit entries. Implementations must be safe for concurrent use;
+// a DB-backed sink can persist entries through a repository.
+type AuditSink interface {
+	Record(ctx context.Context, entry AuditEntry)
+}
+
+// AuditSinkFunc adapts a function to an AuditSink
+type AuditSinkFunc func(ctx context.Context, entry AuditEntry)
+
+// Record calls f(ctx, entry)
+func (f AuditSinkFunc) Record(ctx context.Context, entry AuditEntry) {
+	f(ctx, entry)
+}
+
+// NewLoggerAuditSink returns a sink writing each entry as an info log line
+func NewLoggerAuditSink(log logger.Logger) AuditSink {
+	return AuditSinkFunc(func(_ context.Context, entry AuditEntry) {
+		log.InfoFields("audit",
+			logger.String("user_id", entry.UserID),
+			logger.String("action", entry.Action),
+			logger.String("resource", entry.Resource),
+			logger.Int("status", entry.Status),
+			logger.Time("timestamp", entry.Timestamp),
+			logger.String(correlation.FieldName, entry.RequestID),
+		)
+	})
+}
+
+type auditConfig struct {
+	methods map[string]bool
+	userID  func(c *fiber.Ctx) string
+}
+
+// AuditOption configures AuditMiddleware
+type AuditOption func(*auditConfig)
+
+// WithAuditMethods sets the HTTP methods to audit, replacing the default of every
+// method except GET, HEAD and OPTIONS
+func WithAuditMethods(methods ...string) AuditOption {
+	return func(cfg *auditConfig) {
+		cfg.methods = make(map[string]bool, len(methods))
+		for _, method := range methods {
+			cfg.methods[method] = true
+		}
+	}
+}
+
+// WithAuditUserID sets how the acting user is resolved, replacing the default of
+// the UserID of the claims stored by crypto.ContextWithClaims
+func WithAuditUserID(fn func(c *fiber.Ctx) string) AuditOption {
+	return func(cfg *auditConfig) {
+		cfg.userID = fn
+	}
+}
+
+// AuditMiddleware returns a Fiber middleware emitting an AuditEntry to sink for every
+// mutating request once it has been handled. Register it after authentication so the
+// user is known. A nil sink logs entries with the default logger.
+//
+// Example:
+//
+//	app.Use(authMiddleware, customfiber.AuditMiddleware(customfiber.NewLoggerAuditSink(log)))
+func AuditMiddleware(sink AuditSink, opts ...AuditOption) fiber.Handler {
+	cfg := &auditConfig{userID: claimsUserID}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if sink == nil {
+		sink = NewLoggerAuditSink(defaultLogger.GetLogger())
+	}
+
+	return func(c *fiber.Ctx) error {
+		if !cfg.audited(c.Method()) {
+			return c.Next()
+		}
+
+		start := time.Now().UTC()
+		err := c.Next()
+
+		// Fiber strings point into buffers reused after the request, so copy them
+		sink.Record(c.UserContext(), AuditEntry{
+			UserID:    strings.Clone(cfg.userID(c)),
+			Action:    strings.Clone(c.Method()),
+			Resource:  strings.Clone(c.Path()),
+			Status:    responseStatus(c, err),
+			Timestamp: start,
+			RequestID: correlation.FromContext(c.UserContext()),
+		})
+
+		return err
+	}
+}
+
+// audited reports whether requests with method are audited
+func (cfg *auditConfig) audited(method string) bool {
+	if cfg.methods != nil {
+		return cfg.methods[method]
+	}
+	return method != fiber.MethodGet && method != fiber.MethodHead && method != fiber.MethodOptions
+}
+
+// claimsUserID returns the user ID of the authenticated claims, if any
+func claimsUserID(c *fiber.Ctx) string {
+	if claims, ok := crypto.ClaimsFromContext(c.UserContext()); ok {
+		return claims.UserID
+	}
+	return ""
+}
+
+// responseStatus returns the status the response will have once err, which the error
+// handler renders after the middleware chain returns, is handled
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+
+	if appErr, ok := errors.As(err); ok {
+		return appErr.GetHTTPStatus()
+	}
+
+	var fiberErr *fiber.Error
+	if stdErrors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+
+	return http.StatusInternalServerError
+}
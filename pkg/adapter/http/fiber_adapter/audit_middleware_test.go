@@ -0,0 +1,92 @@
+package customfiber
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/correlation"
+	"github.com/phatnt199/go-infra/pkg/crypto"
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAuditSink struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+func (s *recordingAuditSink) Record(_ context.Context, entry AuditEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+}
+
+func newAuditTestApp(sink AuditSink, opts ...AuditOption) *fiber.App {
+	app := newTestApp()
+	app.Use(CorrelationMiddleware())
+	// stands in for an authentication middleware
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(crypto.ContextWithClaims(c.UserContext(), &crypto.Claims{UserID: "user-1"}))
+		return c.Next()
+	})
+	app.Use(AuditMiddleware(sink, opts...))
+	app.Post("/orders", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusCreated) })
+	app.Get("/orders", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+	app.Delete("/orders/:id", func(c *fiber.Ctx) error { return errors.NotFound("Order") })
+	return app
+}
+
+func Test_Audit_Middleware_Emits_Entry_For_Post(t *testing.T) {
+	sink := &recordingAuditSink{}
+	app := newAuditTestApp(sink)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set(correlation.HeaderName, "req-42")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	_, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders", nil))
+	require.NoError(t, err)
+
+	require.Len(t, sink.entries, 1)
+	entry := sink.entries[0]
+	assert.Equal(t, "user-1", entry.UserID)
+	assert.Equal(t, http.MethodPost, entry.Action)
+	assert.Equal(t, "/orders", entry.Resource)
+	assert.Equal(t, http.StatusCreated, entry.Status)
+	assert.Equal(t, "req-42", entry.RequestID)
+	assert.False(t, entry.Timestamp.IsZero())
+}
+
+func Test_Audit_Middleware_Records_Error_Status(t *testing.T) {
+	sink := &recordingAuditSink{}
+	app := newAuditTestApp(sink)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/orders/7", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	require.Len(t, sink.entries, 1)
+	assert.Equal(t, http.StatusNotFound, sink.entries[0].Status)
+	assert.Equal(t, "/orders/7", sink.entries[0].Resource)
+}
+
+func Test_Audit_Middleware_Configurable_Methods(t *testing.T) {
+	sink := &recordingAuditSink{}
+	app := newAuditTestApp(sink, WithAuditMethods(http.MethodGet))
+
+	_, err := app.Test(httptest.NewRequest(http.MethodGet, "/orders", nil))
+	require.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest(http.MethodPost, "/orders", nil))
+	require.NoError(t, err)
+
+	require.Len(t, sink.entries, 1)
+	assert.Equal(t, http.MethodGet, sink.entries[0].Action)
+}
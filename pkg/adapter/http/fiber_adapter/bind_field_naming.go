@@ -0,0 +1,43 @@
+package customfiber
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// FieldNaming selects how Bind matches the keys of JSON bodies to struct fields
+type FieldNaming int
+
+const (
+	// FieldNamingStrict matches keys to the json tags, or to the field names when tags
+	// are missing, as the JSON codec does (names are compared ignoring case only). It
+	// is the default.
+	FieldNamingStrict FieldNaming = iota
+	// FieldNamingLenient also matches keys written in another style, so "firstName",
+	// "first_name" and "FirstName" all bind to `json:"first_name"` (see
+	// utils.MatchJSONKeys)
+	FieldNamingLenient
+)
+
+// fieldNamingLocal is the c.Locals key holding the FieldNaming of the request
+const fieldNamingLocal = "customfiber.field_naming"
+
+// BindFieldNaming sets how Bind matches JSON keys to struct fields for the routes it
+// is registered on, e.g. lenient for an API used by clients sending camelCase while the
+// request structs are tagged in snake_case. Registering FieldNamingStrict on a route
+// restores the default under a lenient group.
+//
+// Example:
+//
+//	api := app.Group("/api", customfiber.BindFieldNaming(customfiber.FieldNamingLenient))
+func BindFieldNaming(naming FieldNaming) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(fieldNamingLocal, naming)
+		return c.Next()
+	}
+}
+
+// fieldNaming returns the FieldNaming set by BindFieldNaming for the request
+func fieldNaming(c *fiber.Ctx) FieldNaming {
+	naming, _ := c.Locals(fieldNamingLocal).(FieldNaming)
+	return naming
+}
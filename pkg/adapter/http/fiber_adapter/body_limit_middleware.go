@@ -0,0 +1,65 @@
+package customfiber
+
+import (
+	"net/http"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit returns a Fiber middleware rejecting requests whose body is larger than
+// limit bytes with a `CodeBadRequest` AppError and status 413. Apply it to groups or
+// routes that need a smaller limit than the server-wide FiberHttpOptions.BodyLimit,
+// which caps every request (fasthttp reads the body before any middleware runs), so a
+// route accepting large uploads requires raising the server limit and guarding the
+// remaining routes with BodyLimit.
+//
+// Example:
+//
+//	// bodyLimit: 52428800 (50MB) in the server options
+//	api := app.Group("/api", customfiber.BodyLimit(2*1024*1024))
+//	app.Post("/uploads", customfiber.BodyLimit(50*1024*1024), uploadHandler)
+func BodyLimit(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := checkBodyLimit(c, limit); err != nil {
+			return err
+		}
+		return c.Next()
+	}
+}
+
+// WithBodyLimit is BodyLimit for the framework-agnostic route builder.
+//
+// Example:
+//
+//	routes.POST("/uploads", uploadHandler, customfiber.WithBodyLimit(50*1024*1024))
+func WithBodyLimit(limit int) contracts.MiddlewareFunc {
+	return func(next contracts.HandlerFunc) contracts.HandlerFunc {
+		return func(ctx contracts.Context) error {
+			if adapter, ok := ctx.(*fiberContextAdapter); ok {
+				if err := checkBodyLimit(adapter.ctx, limit); err != nil {
+					return err
+				}
+			} else if req := ctx.Request(); req.ContentLength > int64(limit) {
+				return bodyTooLargeError(limit)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// checkBodyLimit fails when the declared or actual request body exceeds limit bytes
+func checkBodyLimit(c *fiber.Ctx, limit int) error {
+	if c.Request().Header.ContentLength() > limit || len(c.Request().Body()) > limit {
+		return bodyTooLargeError(limit)
+	}
+	return nil
+}
+
+func bodyTooLargeError(limit int) *errors.AppError {
+	return errors.BadRequest("request body too large").
+		WithHTTPStatus(http.StatusRequestEntityTooLarge).
+		WithContext("limit_bytes", limit)
+}
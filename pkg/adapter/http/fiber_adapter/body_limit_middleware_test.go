@@ -0,0 +1,48 @@
+package customfiber
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postBody(t *testing.T, app *fiber.App, path string, size int) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(make([]byte, size)))
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+func Test_Body_Limit_Per_Route(t *testing.T) {
+	app := newTestApp()
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(http.StatusNoContent) }
+
+	api := app.Group("/api", BodyLimit(64))
+	api.Post("/items", ok)
+	app.Post("/uploads", BodyLimit(1024), ok)
+
+	assert.Equal(t, http.StatusNoContent, postBody(t, app, "/api/items", 64))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, postBody(t, app, "/api/items", 65))
+
+	assert.Equal(t, http.StatusNoContent, postBody(t, app, "/uploads", 512))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, postBody(t, app, "/uploads", 2048))
+}
+
+func Test_WithBodyLimit_On_Route_Builder(t *testing.T) {
+	app := newTestApp()
+	routes := NewFiberRouteBuilder(app)
+	routes.POST("/small", func(ctx contracts.Context) error {
+		return ctx.NoContent(http.StatusNoContent)
+	}, WithBodyLimit(16))
+
+	assert.Equal(t, http.StatusNoContent, postBody(t, app, "/small", 16))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, postBody(t, app, "/small", 17))
+}
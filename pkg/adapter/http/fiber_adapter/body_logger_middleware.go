@@ -0,0 +1,164 @@
+package customfiber
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/phatnt199/go-infra/pkg/json"
+	"github.com/phatnt199/go-infra/pkg/logger"
+	defaultLogger "github.com/phatnt199/go-infra/pkg/logger/default_logger"
+	"github.com/phatnt199/go-infra/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultRedactFields are the body fields BodyLogger redacts unless configured otherwise
+var DefaultRedactFields = []string{
+	"password", "password_confirmation", "current_password", "new_password",
+	"token", "access_token", "refresh_token", "id_token",
+	"secret", "client_secret", "api_key", "authorization",
+}
+
+// BodyLoggerConfig configures BodyLogger
+type BodyLoggerConfig struct {
+	// Logger receives the entries, defaulting to the default logger
+	Logger logger.Logger
+
+	// Enabled logs bodies at info level even when Logger is not at debug level. By
+	// default bodies are only logged, at debug level, when Logger is.
+	Enabled bool
+
+	// RedactFields are the JSON and form field names, matched case-insensitively at
+	// any depth, whose values are masked. Defaults to DefaultRedactFields.
+	RedactFields []string
+
+	// MaxBodySize truncates each logged body to this many bytes, defaulting to 4KB
+	MaxBodySize int
+}
+
+// BodyLogger returns a Fiber middleware logging request and response bodies for
+// debugging. Values of sensitive JSON and form fields are masked with
+// utils.MaskString; other non-text bodies are logged as their size only. Responses
+// rendered by the error handler are logged without a body, as they are written after
+// the middleware chain returns.
+//
+// Example:
+//
+//	app.Use(customfiber.BodyLogger(customfiber.BodyLoggerConfig{
+//	    RedactFields: append(customfiber.DefaultRedactFields, "card_number"),
+//	}))
+func BodyLogger(cfg BodyLoggerConfig) fiber.Handler {
+	if cfg.Logger == nil {
+		cfg.Logger = defaultLogger.GetLogger()
+	}
+	if cfg.RedactFields == nil {
+		cfg.RedactFields = DefaultRedactFields
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = 4 << 10
+	}
+
+	redact := make(map[string]struct{}, len(cfg.RedactFields))
+	for _, field := range cfg.RedactFields {
+		redact[strings.ToLower(field)] = struct{}{}
+	}
+
+	return func(c *fiber.Ctx) error {
+		debug := logger.DebugEnabled(cfg.Logger)
+		if !debug && !cfg.Enabled {
+			return c.Next()
+		}
+
+		requestBody := formatBody(c.Get(fiber.HeaderContentType), c.Body(), redact, cfg.MaxBodySize)
+
+		err := c.Next()
+
+		fields := []logger.Field{
+			logger.String("method", c.Method()),
+			logger.String("path", c.Path()),
+			logger.Int("status", responseStatus(c, err)),
+			logger.String("request_body", requestBody),
+			logger.String("response_body", formatBody(
+				string(c.Response().Header.ContentType()), c.Response().Body(), redact, cfg.MaxBodySize,
+			)),
+		}
+		if err != nil {
+			fields = append(fields, logger.Err(err))
+		}
+
+		if debug {
+			cfg.Logger.DebugFields("http body", fields...)
+		} else {
+			cfg.Logger.InfoFields("http body", fields...)
+		}
+
+		return err
+	}
+}
+
+// formatBody renders body for the log, redacted and truncated
+func formatBody(contentType string, body []byte, redact map[string]struct{}, maxSize int) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+
+	var formatted string
+	switch {
+	case mediaType == fiber.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json"):
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			return fmt.Sprintf("[invalid JSON, %d bytes]", len(body))
+		}
+		redacted, _ := json.Marshal(redactValue(value, redact))
+		formatted = string(redacted)
+	case mediaType == fiber.MIMEApplicationForm:
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return fmt.Sprintf("[invalid form, %d bytes]", len(body))
+		}
+		for key, vals := range values {
+			if _, ok := redact[strings.ToLower(key)]; ok {
+				for i := range vals {
+					vals[i] = maskValue(vals[i])
+				}
+			}
+		}
+		formatted = values.Encode()
+	case strings.HasPrefix(mediaType, "text/"):
+		// Raw user input: drop terminal escapes and control characters to prevent log injection
+		formatted = utils.SanitizeString(utils.StripANSI(string(body)))
+	default:
+		return fmt.Sprintf("[%s body, %d bytes]", utils.DefaultIfZero(mediaType, "unknown"), len(body))
+	}
+
+	if len(formatted) > maxSize {
+		return formatted[:maxSize] + "...(truncated)"
+	}
+	return formatted
+}
+
+// redactValue masks the values of redacted keys in decoded JSON
+func redactValue(value interface{}, redact map[string]struct{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if _, ok := redact[strings.ToLower(key)]; ok {
+				v[key] = maskValue(fmt.Sprint(nested))
+				continue
+			}
+			v[key] = redactValue(nested, redact)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = redactValue(nested, redact)
+		}
+	}
+	return value
+}
+
+func maskValue(value string) string {
+	return utils.MaskString(value, 0, 0, '*')
+}
@@ -0,0 +1,107 @@
+package customfiber
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/logger"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bodyCaptureLogger records the fields of debug and info entries
+type bodyCaptureLogger struct {
+	logger.Logger
+	debug bool
+
+	mu      sync.Mutex
+	entries []map[string]interface{}
+}
+
+func (l *bodyCaptureLogger) DebugEnabled() bool { return l.debug }
+
+func (l *bodyCaptureLogger) DebugFields(msg string, fields ...logger.Field) { l.record(fields) }
+
+func (l *bodyCaptureLogger) InfoFields(msg string, fields ...logger.Field) { l.record(fields) }
+
+func (l *bodyCaptureLogger) record(fields []logger.Field) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry := map[string]interface{}{}
+	for _, field := range fields {
+		entry[field.Key] = field.Value
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func newBodyLoggerTestApp(cfg BodyLoggerConfig) *fiber.App {
+	app := newTestApp()
+	app.Use(BodyLogger(cfg))
+	app.Post("/login", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"access_token": "tok-123", "user": fiber.Map{"name": "ann"}})
+	})
+	return app
+}
+
+func Test_BodyLogger_Redacts_Password(t *testing.T) {
+	log := &bodyCaptureLogger{Logger: empty.EmptyLogger, debug: true}
+	app := newBodyLoggerTestApp(BodyLoggerConfig{Logger: log})
+
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(`{"email":"ann@example.com","password":"hunter2","profile":{"Secret":"s"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+
+	require.Len(t, log.entries, 1)
+	entry := log.entries[0]
+	requestBody := entry["request_body"].(string)
+	assert.NotContains(t, requestBody, "hunter2")
+	assert.Contains(t, requestBody, `"password":"*******"`)
+	assert.Contains(t, requestBody, `"Secret":"*"`)
+	assert.Contains(t, requestBody, "ann@example.com")
+
+	responseBody := entry["response_body"].(string)
+	assert.NotContains(t, responseBody, "tok-123")
+	assert.Contains(t, responseBody, `"name":"ann"`)
+	assert.Equal(t, 200, entry["status"])
+}
+
+func Test_BodyLogger_Redacts_Form_Fields(t *testing.T) {
+	log := &bodyCaptureLogger{Logger: empty.EmptyLogger, debug: true}
+	app := newBodyLoggerTestApp(BodyLoggerConfig{Logger: log})
+
+	req := httptest.NewRequest("POST", "/login", strings.NewReader("user=ann&password=hunter2"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+
+	require.Len(t, log.entries, 1)
+	assert.Equal(t, "password=%2A%2A%2A%2A%2A%2A%2A&user=ann", log.entries[0]["request_body"])
+}
+
+func Test_BodyLogger_Inactive_Unless_Debug_Or_Enabled(t *testing.T) {
+	log := &bodyCaptureLogger{Logger: empty.EmptyLogger}
+	_, err := newBodyLoggerTestApp(BodyLoggerConfig{Logger: log}).Test(httptest.NewRequest("POST", "/login", nil))
+	require.NoError(t, err)
+	assert.Empty(t, log.entries)
+
+	_, err = newBodyLoggerTestApp(BodyLoggerConfig{Logger: log, Enabled: true}).Test(httptest.NewRequest("POST", "/login", nil))
+	require.NoError(t, err)
+	assert.Len(t, log.entries, 1)
+}
+
+func Test_FormatBody(t *testing.T) {
+	redact := map[string]struct{}{"password": {}}
+
+	assert.Equal(t, "[invalid JSON, 9 bytes]", formatBody("application/json", []byte(`{"passwor`), redact, 100))
+	assert.Equal(t, "[image/png body, 3 bytes]", formatBody("image/png", []byte{1, 2, 3}, redact, 100))
+	assert.Equal(t, "hello...(truncated)", formatBody("text/plain; charset=utf-8", []byte("hello world"), redact, 5))
+	assert.Equal(t, "ok\nforged", formatBody("text/plain", []byte("\x1b[31mok\r\n\x00forged"), redact, 100))
+	assert.Equal(t, `[{"password":"***"}]`, formatBody("application/problem+json", []byte(`[{"password":"abc"}]`), redact, 100))
+	assert.Equal(t, "", formatBody("application/json", nil, redact, 100))
+}
@@ -0,0 +1,183 @@
+package customfiber
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	// BulkheadInUseMetricName is the gauge reporting the bulkhead slots in use
+	BulkheadInUseMetricName = "http.server.bulkhead.in_use"
+	// BulkheadQueuedMetricName is the gauge reporting the requests waiting for a slot
+	BulkheadQueuedMetricName = "http.server.bulkhead.queued"
+)
+
+// BulkheadLimiter bounds the number of requests served simultaneously. Requests beyond
+// the limit wait up to the queue timeout for a slot to free up and are then
+// rejected with a `CodeServiceUnavailable` AppError (503), so a burst of slow
+// requests cannot exhaust the workers or downstream connection pools.
+//
+// Health, metrics and swagger endpoints bypass the bulkhead so that probes keep
+// answering while the service is saturated.
+type BulkheadLimiter struct {
+	slots        chan struct{}
+	queueTimeout time.Duration
+	queued       atomic.Int64
+	skip         func(*fiber.Ctx) bool
+}
+
+// Bulkhead returns a middleware admitting max concurrent requests, queueing the
+// excess for up to queueTimeout. It records no metrics; use NewBulkhead to
+// register the gauges. It panics when max is not positive.
+//
+// Example:
+//
+//	app.Use(customfiber.Bulkhead(100, 2*time.Second))
+func Bulkhead(max int, queueTimeout time.Duration) fiber.Handler {
+	if max < 1 {
+		panic(fmt.Sprintf("customfiber: bulkhead max must be positive, got %d", max))
+	}
+
+	b, err := NewBulkhead(max, queueTimeout, noop.NewMeterProvider().Meter("customfiber"))
+	if err != nil {
+		panic(fmt.Sprintf("customfiber: bulkhead: %v", err))
+	}
+	return b.Middleware()
+}
+
+// NewBulkhead creates a bulkhead admitting max concurrent requests (at least 1)
+// and, when meter is not nil, registers the BulkheadInUseMetricName and
+// BulkheadQueuedMetricName gauges observing it. A non-positive queueTimeout
+// rejects excess requests immediately.
+//
+// Example:
+//
+//	bulkhead, err := customfiber.NewBulkhead(100, 2*time.Second, meter)
+//	app.Use(bulkhead.Middleware())
+func NewBulkhead(max int, queueTimeout time.Duration, meter metric.Meter) (*BulkheadLimiter, error) {
+	if max < 1 {
+		max = 1
+	}
+
+	b := &BulkheadLimiter{
+		slots:        make(chan struct{}, max),
+		queueTimeout: queueTimeout,
+		skip:         createSkipper("swagger", "metrics", "health"),
+	}
+	if meter == nil {
+		return b, nil
+	}
+
+	inUse, err := meter.Int64ObservableGauge(
+		BulkheadInUseMetricName,
+		metric.WithDescription("Number of bulkhead slots held by requests being served"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return b, err
+	}
+
+	queued, err := meter.Int64ObservableGauge(
+		BulkheadQueuedMetricName,
+		metric.WithDescription("Number of HTTP requests waiting for a bulkhead slot"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return b, err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		observer.ObserveInt64(inUse, int64(b.InUse()))
+		observer.ObserveInt64(queued, int64(b.Queued()))
+		return nil
+	}, inUse, queued)
+	if err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// Middleware returns the Fiber middleware enforcing the bulkhead. Register it
+// after the logging and recovery middlewares so rejections are still logged.
+func (b *BulkheadLimiter) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if b.skip(c) {
+			return c.Next()
+		}
+
+		if err := b.acquire(c.UserContext()); err != nil {
+			return err.
+				WithContext("max_concurrent", b.Max()).
+				WithContext("path", c.Path())
+		}
+		defer b.release()
+
+		return c.Next()
+	}
+}
+
+// Max returns the number of requests the bulkhead admits at once
+func (b *BulkheadLimiter) Max() int {
+	return cap(b.slots)
+}
+
+// InUse returns the number of requests currently holding a slot
+func (b *BulkheadLimiter) InUse() int {
+	return len(b.slots)
+}
+
+// Queued returns the number of requests currently waiting for a slot
+func (b *BulkheadLimiter) Queued() int {
+	return int(b.queued.Load())
+}
+
+// Utilization returns the fraction of slots in use, between 0 and 1
+func (b *BulkheadLimiter) Utilization() float64 {
+	return float64(b.InUse()) / float64(b.Max())
+}
+
+// acquire takes a slot, waiting up to the queue timeout or until ctx is done
+func (b *BulkheadLimiter) acquire(ctx context.Context) *errors.AppError {
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if b.queueTimeout <= 0 {
+		return errors.New(errors.CodeServiceUnavailable).
+			WithDetails("too many concurrent requests")
+	}
+
+	b.queued.Add(1)
+	defer b.queued.Add(-1)
+
+	timer := time.NewTimer(b.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return errors.New(errors.CodeServiceUnavailable).
+			WithDetails("too many concurrent requests").
+			WithContext("queue_timeout", b.queueTimeout.String())
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), errors.CodeServiceUnavailable).
+			WithDetails("request cancelled while waiting for capacity")
+	}
+}
+
+// release frees the slot taken by acquire
+func (b *BulkheadLimiter) release() {
+	<-b.slots
+}
@@ -0,0 +1,135 @@
+package customfiber
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// newBulkheadTestApp serves /slow, which blocks until release is closed, behind bulkhead
+func newBulkheadTestApp(bulkhead fiber.Handler, started chan<- struct{}, release <-chan struct{}) *fiber.App {
+	app := newTestApp()
+	app.Use(bulkhead)
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		started <- struct{}{}
+		<-release
+		return c.SendStatus(http.StatusOK)
+	})
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+	return app
+}
+
+// serveAsync runs a request against app in the background and reports its status
+func serveAsync(app *fiber.App, path string) <-chan int {
+	done := make(chan int, 1)
+	go func() {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil), 5000)
+		if err != nil {
+			done <- 0
+			return
+		}
+		done <- resp.StatusCode
+	}()
+	return done
+}
+
+func Test_Bulkhead_Rejects_Request_After_Queue_Timeout(t *testing.T) {
+	b, err := NewBulkhead(1, 20*time.Millisecond, nil)
+	require.NoError(t, err)
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	app := newBulkheadTestApp(b.Middleware(), started, release)
+
+	first := serveAsync(app, "/slow")
+	<-started
+	assert.Equal(t, 1, b.InUse())
+	assert.Equal(t, 1.0, b.Utilization())
+
+	second := serveAsync(app, "/slow")
+	assert.Equal(t, http.StatusServiceUnavailable, <-second)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "health checks bypass the bulkhead")
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-first)
+	assert.Equal(t, 0, b.InUse())
+}
+
+func Test_Bulkhead_Queues_Request_Until_Slot_Frees(t *testing.T) {
+	b, err := NewBulkhead(1, 5*time.Second, nil)
+	require.NoError(t, err)
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	app := newBulkheadTestApp(b.Middleware(), started, release)
+
+	first := serveAsync(app, "/slow")
+	<-started
+
+	second := serveAsync(app, "/slow")
+	require.Eventually(t, func() bool { return b.Queued() == 1 }, time.Second, time.Millisecond)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-first)
+	<-started
+	assert.Equal(t, http.StatusOK, <-second)
+	assert.Equal(t, 0, b.Queued())
+	assert.Equal(t, 0, b.InUse())
+}
+
+func Test_Bulkhead_Middleware_Rejects_Excess_Requests(t *testing.T) {
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	app := newBulkheadTestApp(Bulkhead(1, 0), started, release)
+
+	first := serveAsync(app, "/slow")
+	<-started
+
+	second := serveAsync(app, "/slow")
+	assert.Equal(t, http.StatusServiceUnavailable, <-second)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-first)
+}
+
+func Test_Bulkhead_Middleware_Panics_On_Invalid_Max(t *testing.T) {
+	assert.Panics(t, func() { Bulkhead(0, time.Second) })
+	assert.Panics(t, func() { Bulkhead(-1, time.Second) })
+}
+
+func Test_Bulkhead_Registers_Gauges(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	_, err := NewBulkhead(4, time.Second, meter)
+	require.NoError(t, err)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	names := map[string]int64{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			gauge := m.Data.(metricdata.Gauge[int64])
+			require.Len(t, gauge.DataPoints, 1)
+			names[m.Name] = gauge.DataPoints[0].Value
+		}
+	}
+	assert.Equal(t, map[string]int64{
+		BulkheadInUseMetricName:  0,
+		BulkheadQueuedMetricName: 0,
+	}, names)
+}
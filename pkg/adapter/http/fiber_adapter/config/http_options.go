@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"net/url"
+	"os"
 
 	"github.com/phatnt199/go-infra/pkg/application/config"
 	"github.com/phatnt199/go-infra/pkg/application/environment"
@@ -14,14 +15,70 @@ import (
 var optionName = strcase.ToLowerCamel(typeMapper.GetGenericTypeNameByT[FiberHttpOptions]())
 
 type FiberHttpOptions struct {
-	Port                string   `mapstructure:"port"                validate:"required" env:"TcpPort"`
-	Development         bool     `mapstructure:"development"                             env:"Development"`
-	BasePath            string   `mapstructure:"basePath"            validate:"required" env:"BasePath"`
-	DebugErrorsResponse bool     `mapstructure:"debugErrorsResponse"                     env:"DebugErrorsResponse"`
-	IgnoreLogUrls       []string `mapstructure:"ignoreLogUrls"`
-	Timeout             int      `mapstructure:"timeout"                                 env:"Timeout"`
-	Host                string   `mapstructure:"host"                                    env:"Host"`
-	Name                string   `mapstructure:"name"                                    env:"ShortTypeName"`
+	Port                string     `mapstructure:"port"                validate:"required" env:"TcpPort"`
+	Development         bool       `mapstructure:"development"                             env:"Development"`
+	BasePath            string     `mapstructure:"basePath"            validate:"required" env:"BasePath"`
+	DebugErrorsResponse bool       `mapstructure:"debugErrorsResponse"                     env:"DebugErrorsResponse"`
+	IgnoreLogUrls       []string   `mapstructure:"ignoreLogUrls"`
+	Timeout             int        `mapstructure:"timeout"                                 env:"Timeout"`
+	Host                string     `mapstructure:"host"                                    env:"Host"`
+	Name                string     `mapstructure:"name"                                    env:"ShortTypeName"`
+	BodyLimit           int        `mapstructure:"bodyLimit"                               env:"BodyLimit"` // bytes, server-wide; see GetBodyLimit
+	TLS                 TLSOptions `mapstructure:"tls"`
+}
+
+// defaultBodyLimit is the server-wide request body limit when BodyLimit is unset
+const defaultBodyLimit = 2 * 1024 * 1024 // 2MB
+
+// GetBodyLimit returns the server-wide request body limit in bytes, 2MB when unset.
+// Use customfiber.BodyLimit to lower it for specific groups or routes.
+func (c *FiberHttpOptions) GetBodyLimit() int {
+	if c.BodyLimit > 0 {
+		return c.BodyLimit
+	}
+	return defaultBodyLimit
+}
+
+// TLSOptions configures HTTPS for the Fiber server. Certificates are reloaded when the
+// files change, so they can be rotated without a restart.
+type TLSOptions struct {
+	Enabled  bool   `mapstructure:"enabled"  env:"TLSEnabled"`
+	CertFile string `mapstructure:"certFile" env:"TLSCertFile"`
+	KeyFile  string `mapstructure:"keyFile"  env:"TLSKeyFile"`
+	// ClientCAFile enables mutual TLS: clients must present a certificate signed by this CA
+	ClientCAFile string `mapstructure:"clientCaFile" env:"TLSClientCAFile"`
+}
+
+// IsMutual reports whether client certificates are required
+func (t *TLSOptions) IsMutual() bool {
+	return t.ClientCAFile != ""
+}
+
+// Validate checks that the configured certificate, key and CA files exist
+func (t *TLSOptions) Validate() error {
+	if !t.Enabled {
+		return nil
+	}
+
+	files := map[string]string{"certFile": t.CertFile, "keyFile": t.KeyFile}
+	if t.IsMutual() {
+		files["clientCaFile"] = t.ClientCAFile
+	}
+
+	for _, name := range []string{"certFile", "keyFile", "clientCaFile"} {
+		path, ok := files[name]
+		if !ok {
+			continue
+		}
+		if path == "" {
+			return fmt.Errorf("tls.%s is required when TLS is enabled", name)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("tls.%s: %w", name, err)
+		}
+	}
+
+	return nil
 }
 
 func (c *FiberHttpOptions) GetPort() string {
@@ -5,10 +5,15 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+	appErrors "github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/utils"
+	"github.com/phatnt199/go-infra/pkg/validator"
 
 	"github.com/gofiber/fiber/v2"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
 )
 
 // fiberContextAdapter adapts fiber.Ctx to contracts.Context
@@ -34,7 +39,8 @@ func (f *fiberContextAdapter) Request() *http.Request {
 	f.ctx.Request().Header.VisitAll(func(key, value []byte) {
 		req.Header.Add(string(key), string(value))
 	})
-	return req
+	// Carry the user context (deadline, correlation ID, ...) like a net/http request would
+	return req.WithContext(f.ctx.UserContext())
 }
 
 func (f *fiberContextAdapter) ResponseWriter() http.ResponseWriter {
@@ -77,18 +83,63 @@ func (f *fiberContextAdapter) Set(key string, val interface{}) {
 	f.ctx.Locals(key, val)
 }
 
+func (f *fiberContextAdapter) SetHeader(name, value string) {
+	f.ctx.Set(name, value)
+}
+
+// Bind decodes the request body into i with the BodyParser of the app, so JSON bodies
+// are decoded once by its JSONDecoder: json.UnmarshalUseNumber for servers created by
+// NewFiberHttpServer, so that large integers bound to interface values keep their
+// precision (see utils.ToInt64). Their keys are matched to fields as set by
+// BindFieldNaming.
 func (f *fiberContextAdapter) Bind(i interface{}) error {
+	if fieldNaming(f.ctx) == FieldNamingLenient && isJSONBody(f.ctx) {
+		body, err := utils.MatchJSONKeys(f.ctx.Body(), i)
+		if err != nil {
+			return err
+		}
+		return f.ctx.App().Config().JSONDecoder(body, i)
+	}
 	return f.ctx.BodyParser(i)
 }
 
+// isJSONBody reports whether the request body is JSON, as BodyParser decides it
+func isJSONBody(c *fiber.Ctx) bool {
+	contentType := fiberUtils.ParseVendorSpecificContentType(strings.ToLower(c.Get(fiber.HeaderContentType)))
+	contentType, _, _ = strings.Cut(contentType, ";")
+	return strings.HasSuffix(contentType, "json")
+}
+
+func (f *fiberContextAdapter) BindQuery(i interface{}) error {
+	if err := utils.BindQueryValues(f.QueryParams(), i); err != nil {
+		return appErrors.Wrap(err, appErrors.CodeInvalidInput, err.Error())
+	}
+	if err := validator.Struct(i); err != nil {
+		return appErrors.Wrap(err, appErrors.CodeValidation, err.Error())
+	}
+	return nil
+}
+
 func (f *fiberContextAdapter) Validate(i interface{}) error {
 	return nil
 }
 
+func (f *fiberContextAdapter) Accepts(offers ...string) string {
+	return f.ctx.Accepts(offers...)
+}
+
 func (f *fiberContextAdapter) JSON(code int, i interface{}) error {
 	return f.ctx.Status(code).JSON(i)
 }
 
+func (f *fiberContextAdapter) Paginated(code int, page contracts.Pageable) error {
+	// Same shape as contracts.Paginated, whose element type isn't known here
+	return f.JSON(code, struct {
+		Data interface{}        `json:"data"`
+		Meta contracts.PageMeta `json:"meta"`
+	}{Data: page.PageData(), Meta: page.PageMeta()})
+}
+
 func (f *fiberContextAdapter) JSONBlob(code int, b []byte) error {
 	f.ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
 	f.ctx.Status(code)
@@ -143,7 +194,7 @@ func (f *fiberContextAdapter) SetHandler(h interface{}) {
 }
 
 func (f *fiberContextAdapter) Path() string {
-	return f.ctx.Path()
+	return f.ctx.Route().Path
 }
 
 func (f *fiberContextAdapter) RealIP() string {
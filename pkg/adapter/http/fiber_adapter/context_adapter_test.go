@@ -0,0 +1,277 @@
+package customfiber
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+	appErrors "github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newNegotiationTestApp() *fiber.App {
+	app := newTestApp()
+	app.Get("/users/:id", ConvertFiberHandler(func(c contracts.Context) error {
+		return appErrors.RespondWithErrorNegotiated(c, appErrors.NotFound("User"), appErrors.DefaultConfig())
+	}))
+	return app
+}
+
+func Test_Respond_With_Error_Negotiated_Renders_XML(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set(fiber.HeaderAccept, "application/xml")
+
+	resp, err := newNegotiationTestApp().Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, appErrors.ContentTypeProblemXML, resp.Header.Get(fiber.HeaderContentType))
+
+	var problem appErrors.ProblemDetail
+	require.NoError(t, xml.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, "urn:ietf:rfc:7807", problem.XMLName.Space)
+	assert.Equal(t, "Not Found", problem.Title)
+	assert.Equal(t, "/users/42", problem.Instance)
+}
+
+func Test_Respond_With_Error_Negotiated_Defaults_To_JSON(t *testing.T) {
+	for _, accept := range []string{"application/json", "", "text/html"} {
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		if accept != "" {
+			req.Header.Set(fiber.HeaderAccept, accept)
+		}
+
+		resp, err := newNegotiationTestApp().Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, accept)
+		assert.Equal(t, appErrors.ContentTypeProblemJSON, resp.Header.Get(fiber.HeaderContentType), accept)
+
+		var problem appErrors.ProblemDetail
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem), accept)
+		assert.Equal(t, string(appErrors.CodeNotFound), problem.Code, accept)
+	}
+}
+
+type testUserQuery struct {
+	Page   int    `query:"page" validate:"min=1"`
+	Status string `query:"status" validate:"omitempty,oneof=active inactive"`
+}
+
+func Test_Bind_Query(t *testing.T) {
+	app := newTestApp()
+	app.Get("/users", ConvertFiberHandler(func(c contracts.Context) error {
+		var q testUserQuery
+		if err := c.BindQuery(&q); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, q)
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/users?page=3&status=active", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var q testUserQuery
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&q))
+	assert.Equal(t, testUserQuery{Page: 3, Status: "active"}, q)
+
+	// conversion and validation failures are client errors
+	for _, target := range []string{"/users?page=abc", "/users?page=0", "/users?page=1&status=deleted"} {
+		resp, err = app.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, target)
+	}
+}
+
+func Test_Get_List_Query_From_Context_Uses_Bind_Query(t *testing.T) {
+	var query *utils.ListQuery
+	app := newTestApp()
+	app.Get("/items", ConvertFiberHandler(func(c contracts.Context) error {
+		var err error
+		query, err = utils.GetListQueryFromContext(c)
+		if err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/items?size=25&orderBy=name", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, 25, query.Size)
+	assert.Equal(t, 1, query.Page)
+	assert.Equal(t, "name", query.OrderBy)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/items?page=first", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Write_Paginated_JSON_Sets_Link_Headers_For_Middle_Page(t *testing.T) {
+	app := newTestApp()
+	app.Get("/users", ConvertFiberHandler(func(c contracts.Context) error {
+		result := utils.NewListResult([]string{"c", "d"}, 2, 2, 6)
+		return utils.WritePaginatedJSON(c, result, "https://api.example.com/users")
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/users?page=2&size=2&status=active", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, "6", resp.Header.Get(utils.HeaderTotalCount))
+	assert.Equal(t,
+		`<https://api.example.com/users?page=1&size=2&status=active>; rel="first", `+
+			`<https://api.example.com/users?page=1&size=2&status=active>; rel="prev", `+
+			`<https://api.example.com/users?page=3&size=2&status=active>; rel="next", `+
+			`<https://api.example.com/users?page=3&size=2&status=active>; rel="last"`,
+		resp.Header.Get("Link"),
+	)
+}
+
+func Test_Bind_Preserves_Large_Integers(t *testing.T) {
+	var payload map[string]interface{}
+	app := newTestApp()
+	app.Post("/events", ConvertFiberHandler(func(c contracts.Context) error {
+		if err := c.Bind(&payload); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(`{"id":1234567890123456789,"score":1.5}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	assert.Equal(t, int64(1234567890123456789), utils.ToInt64(payload["id"]))
+	id, err := utils.ToInt64E(payload["id"])
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234567890123456789), id)
+	assert.Equal(t, 1.5, utils.ToFloat64(payload["score"]))
+}
+
+func Test_Bind_Uses_The_App_JSON_Decoder(t *testing.T) {
+	var decoded int
+	app := fiber.New(fiber.Config{
+		JSONDecoder: func(data []byte, v interface{}) error {
+			decoded++
+			return json.Unmarshal(data, v)
+		},
+	})
+
+	var payload struct {
+		FirstName string `json:"first_name"`
+	}
+	handler := ConvertFiberHandler(func(c contracts.Context) error {
+		if err := c.Bind(&payload); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+	app.Post("/users", handler)
+	app.Post("/lenient/users", BindFieldNaming(FieldNamingLenient), handler)
+
+	for path, body := range map[string]string{"/users": `{"first_name":"Ann"}`, "/lenient/users": `{"firstName":"Bo"}`} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/problem+json; charset=utf-8")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode, path)
+	}
+
+	assert.Equal(t, "Bo", payload.FirstName)
+	assert.Equal(t, 2, decoded)
+}
+
+func Test_Bind_Field_Naming(t *testing.T) {
+	type address struct {
+		PostalCode string `json:"postal_code"`
+	}
+	type createUser struct {
+		FirstName string    `json:"first_name"`
+		LastName  string    // no json tag
+		Addresses []address `json:"addresses"`
+	}
+
+	var bound createUser
+	handler := ConvertFiberHandler(func(c contracts.Context) error {
+		bound = createUser{}
+		if err := c.Bind(&bound); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	app := newTestApp()
+	app.Post("/strict/users", handler)
+	lenient := app.Group("/lenient", BindFieldNaming(FieldNamingLenient))
+	lenient.Post("/users", handler)
+	lenient.Post("/strict", BindFieldNaming(FieldNamingStrict), handler)
+
+	body := `{"firstName":"Ann","last_name":"Lee","addresses":[{"postalCode":"70000"}]}`
+	bind := func(path string) createUser {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode, path)
+		return bound
+	}
+
+	assert.Equal(t, createUser{
+		FirstName: "Ann",
+		LastName:  "Lee",
+		Addresses: []address{{PostalCode: "70000"}},
+	}, bind("/lenient/users"))
+
+	// Strict binding, the default, only matches json tags and field names
+	assert.Equal(t, createUser{Addresses: []address{{}}}, bind("/strict/users"))
+	assert.Equal(t, createUser{Addresses: []address{{}}}, bind("/lenient/strict"))
+}
+
+func Test_Paginated_Renders_Standard_Envelope(t *testing.T) {
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	app := newTestApp()
+	app.Get("/users", ConvertFiberHandler(func(c contracts.Context) error {
+		result := utils.NewListResult([]user{{ID: 3, Name: "Cy"}, {ID: 4, Name: "Di"}}, 2, 2, 5)
+		return c.Paginated(http.StatusOK, result)
+	}))
+	app.Get("/empty", ConvertFiberHandler(func(c contracts.Context) error {
+		return c.Paginated(http.StatusOK, contracts.NewPaginated[user](nil, 1, 20, 0))
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/users", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"data": [{"id": 3, "name": "Cy"}, {"id": 4, "name": "Di"}],
+		"meta": {"page": 2, "size": 2, "total": 5, "totalPages": 3, "hasNext": true, "hasPrev": true}
+	}`, string(body))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/empty", nil))
+	require.NoError(t, err)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"data": [],
+		"meta": {"page": 1, "size": 20, "total": 0, "totalPages": 0, "hasNext": false, "hasPrev": false}
+	}`, string(body))
+}
@@ -0,0 +1,27 @@
+package customfiber
+
+import (
+	"strings"
+
+	"github.com/phatnt199/go-infra/pkg/correlation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CorrelationMiddleware propagates the correlation ID of each request. The ID is read
+// from the correlation.HeaderName request header (a new one is generated when it is
+// missing or invalid), echoed in the response header, stored in the user context for
+// correlation.FromContext and in c.Locals(correlation.FieldName).
+func CorrelationMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// c.Get returns a view of the request buffer, which fasthttp reuses; the ID
+		// outlives the request in contexts and sinks, so copy it
+		id := correlation.FromHeader(strings.Clone(c.Get(correlation.HeaderName)))
+
+		c.Set(correlation.HeaderName, id)
+		c.Locals(correlation.FieldName, id)
+		c.SetUserContext(correlation.NewContext(c.UserContext(), id))
+
+		return c.Next()
+	}
+}
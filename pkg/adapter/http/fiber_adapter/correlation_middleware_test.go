@@ -0,0 +1,51 @@
+package customfiber
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/correlation"
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Correlation_Middleware_Propagates_Incoming_ID(t *testing.T) {
+	app := newTestApp()
+	app.Use(CorrelationMiddleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		assert.Equal(t, "req-1", correlation.FromContext(c.UserContext()))
+		assert.Equal(t, "req-1", correlation.FromContext(NewFiberContextAdapter(c).Request().Context()))
+		return c.SendStatus(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(correlation.HeaderName, "req-1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "req-1", resp.Header.Get(correlation.HeaderName))
+}
+
+func Test_Correlation_Middleware_Sets_Request_ID_On_Problem_Details(t *testing.T) {
+	app := newTestApp()
+	app.Use(CorrelationMiddleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return errors.NotFound("order")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+
+	id := resp.Header.Get(correlation.HeaderName)
+	require.NotEmpty(t, id)
+
+	var problem errors.ProblemDetail
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, http.StatusNotFound, problem.Status)
+	assert.Equal(t, id, problem.RequestID)
+}
@@ -0,0 +1,88 @@
+package customfiber
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/correlation"
+	"github.com/phatnt199/go-infra/pkg/logger"
+	defaultLogger "github.com/phatnt199/go-infra/pkg/logger/default_logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// HeaderDeprecation marks a response as coming from a deprecated endpoint
+	HeaderDeprecation = "Deprecation"
+	// HeaderSunset carries the date after which an endpoint stops working (RFC 8594)
+	HeaderSunset = "Sunset"
+)
+
+type deprecationConfig struct {
+	log logger.Logger
+}
+
+// DeprecationOption configures Deprecated
+type DeprecationOption func(*deprecationConfig)
+
+// WithDeprecationLogger sets the logger recording calls to the deprecated route,
+// replacing the default logger
+func WithDeprecationLogger(log logger.Logger) DeprecationOption {
+	return func(cfg *deprecationConfig) {
+		cfg.log = log
+	}
+}
+
+// Deprecated returns a Fiber middleware marking the routes it decorates as
+// deprecated. Every response carries `Deprecation: true`, a `Sunset` header with
+// the removal date (RFC 8594) and a `Link: <successor>; rel="successor-version"`
+// header pointing clients at the replacement. A zero sunset or an empty successor
+// omits the corresponding header.
+//
+// Each call is logged at warn level with the route, user agent, authenticated user
+// and request ID, so remaining callers can be tracked down before the sunset.
+//
+// Example:
+//
+//	sunset := time.Date(2026, time.June, 30, 0, 0, 0, 0, time.UTC)
+//	v1.Get("/orders", customfiber.Deprecated(sunset, "/v2/orders"), listOrders)
+func Deprecated(sunset time.Time, successor string, opts ...DeprecationOption) fiber.Handler {
+	cfg := &deprecationConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.log == nil {
+		cfg.log = defaultLogger.GetLogger()
+	}
+
+	var sunsetHeader, linkHeader string
+	if !sunset.IsZero() {
+		sunsetHeader = sunset.UTC().Format(http.TimeFormat)
+	}
+	if successor != "" {
+		linkHeader = fmt.Sprintf(`<%s>; rel="successor-version"`, successor)
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Set(HeaderDeprecation, "true")
+		if sunsetHeader != "" {
+			c.Set(HeaderSunset, sunsetHeader)
+		}
+		if linkHeader != "" {
+			c.Append(fiber.HeaderLink, linkHeader)
+		}
+
+		cfg.log.WarnFields("deprecated endpoint called",
+			logger.String("method", c.Method()),
+			logger.String("route", c.Route().Path),
+			logger.String("user_agent", c.Get(fiber.HeaderUserAgent)),
+			logger.String("user_id", claimsUserID(c)),
+			logger.String("sunset", sunsetHeader),
+			logger.String(correlation.FieldName, correlation.FromContext(c.UserContext())),
+		)
+
+		return c.Next()
+	}
+}
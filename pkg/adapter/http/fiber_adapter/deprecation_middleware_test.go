@@ -0,0 +1,79 @@
+package customfiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/logger"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deprecationCaptureLogger records the fields of warn entries
+type deprecationCaptureLogger struct {
+	logger.Logger
+
+	mu      sync.Mutex
+	entries []map[string]interface{}
+}
+
+func (l *deprecationCaptureLogger) WarnFields(msg string, fields ...logger.Field) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry := map[string]interface{}{"msg": msg}
+	for _, field := range fields {
+		entry[field.Key] = field.Value
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func Test_Deprecated_Sets_Headers_On_Decorated_Route(t *testing.T) {
+	log := &deprecationCaptureLogger{Logger: empty.EmptyLogger}
+	sunset := time.Date(2026, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	app := newTestApp()
+	app.Get("/v1/orders", Deprecated(sunset, "/v2/orders", WithDeprecationLogger(log)), func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+	app.Get("/v2/orders", func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+	req.Header.Set(fiber.HeaderUserAgent, "legacy-client/1.0")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get(HeaderDeprecation))
+	assert.Equal(t, "Tue, 30 Jun 2026 00:00:00 GMT", resp.Header.Get(HeaderSunset))
+	assert.Equal(t, `</v2/orders>; rel="successor-version"`, resp.Header.Get(fiber.HeaderLink))
+
+	require.Len(t, log.entries, 1)
+	assert.Equal(t, "/v1/orders", log.entries[0]["route"])
+	assert.Equal(t, "legacy-client/1.0", log.entries[0]["user_agent"])
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/v2/orders", nil))
+	require.NoError(t, err)
+	assert.Empty(t, resp.Header.Get(HeaderDeprecation))
+	assert.Empty(t, resp.Header.Get(HeaderSunset))
+	assert.Len(t, log.entries, 1)
+}
+
+func Test_Deprecated_Omits_Unset_Headers(t *testing.T) {
+	app := newTestApp()
+	app.Get("/legacy", Deprecated(time.Time{}, "", WithDeprecationLogger(empty.EmptyLogger)), func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/legacy", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "true", resp.Header.Get(HeaderDeprecation))
+	assert.Empty(t, resp.Header.Get(HeaderSunset))
+	assert.Empty(t, resp.Header.Get(fiber.HeaderLink))
+}
@@ -0,0 +1,33 @@
+package customfiber
+
+import (
+	"github.com/phatnt199/go-infra/pkg/featureflag"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FeatureFlagMiddleware exposes provider to handlers through the user context, read
+// with featureflag.FromContext or featureflag.Bool. Percentage rollouts are keyed by
+// the UserID of the authenticated claims (see crypto.ContextWithClaims), so register
+// it after authentication.
+//
+// Example:
+//
+//	app.Use(customfiber.FeatureFlagMiddleware(flags))
+//	app.Get("/checkout", func(c *fiber.Ctx) error {
+//	    if featureflag.Bool(c.UserContext(), "new_checkout", false) {
+//	        return newCheckout(c)
+//	    }
+//	    return checkout(c)
+//	})
+func FeatureFlagMiddleware(provider featureflag.Provider) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := featureflag.NewContext(c.UserContext(), provider)
+		if userID := claimsUserID(c); userID != "" {
+			ctx = featureflag.WithUserID(ctx, userID)
+		}
+		c.SetUserContext(ctx)
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package customfiber
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/crypto"
+	"github.com/phatnt199/go-infra/pkg/featureflag"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FeatureFlagMiddleware_Exposes_Flags_And_User(t *testing.T) {
+	flags := featureflag.NewConfigProvider(map[string]string{"theme": "dark", "beta": "100%"})
+
+	app := newTestApp()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(crypto.ContextWithClaims(c.UserContext(), &crypto.Claims{UserID: "user-1"}))
+		return c.Next()
+	})
+	app.Use(FeatureFlagMiddleware(flags))
+	app.Get("/", func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		assert.Equal(t, "user-1", featureflag.UserIDFromContext(ctx))
+		assert.True(t, featureflag.Bool(ctx, "beta", false))
+		return c.SendString(featureflag.String(ctx, "theme", "light"))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "dark", string(body))
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/phatnt199/go-infra/pkg/logger"
 
 	"github.com/phatnt199/go-infra/pkg/adapter/http/httperrors/problemdetails"
+	appErrors "github.com/phatnt199/go-infra/pkg/errors"
 
 	"emperror.dev/errors"
 	"github.com/gofiber/fiber/v2"
@@ -14,6 +15,21 @@ func ProblemDetailErrorHandlerFunc(
 	c *fiber.Ctx,
 	logger logger.Logger,
 ) error {
+	// AppErrors render as the standard errors.ProblemDetail shared with the stdlib handler
+	if _, ok := appErrors.As(err); ok {
+		config := appErrors.DefaultConfig()
+		if header := c.Get(fiber.HeaderAcceptLanguage); header != "" {
+			config.Locale = appErrors.LocaleFromAcceptLanguage(header)
+		}
+
+		problem := appErrors.ToProblemDetail(err, config)
+		problem.Instance = c.Path()
+		problem.WithRequestIDFrom(c.UserContext())
+
+		c.Status(problem.Status)
+		return c.JSON(problem, appErrors.ContentTypeProblemJSON)
+	}
+
 	var problem problemdetails.ProblemDetailErr
 
 	// if error was not problem detail we will convert the error to a problem detail
@@ -23,9 +39,8 @@ func ProblemDetailErrorHandlerFunc(
 
 	if problem != nil {
 		// Write problem detail to response
-		c.Set(fiber.HeaderContentType, "application/problem+json")
 		c.Status(problem.GetStatus())
-		return c.JSON(problem)
+		return c.JSON(problem, appErrors.ContentTypeProblemJSON)
 	}
 
 	return err
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appErrors "github.com/phatnt199/go-infra/pkg/errors"
+	defaultLogger "github.com/phatnt199/go-infra/pkg/logger/default_logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Problem_Detail_Error_Handler_Renders_App_Errors(t *testing.T) {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return ProblemDetailErrorHandlerFunc(err, c, defaultLogger.GetLogger())
+		},
+	})
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return appErrors.NotFound("User")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, appErrors.ContentTypeProblemJSON, resp.Header.Get(fiber.HeaderContentType))
+
+	var problem appErrors.ProblemDetail
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, "/problems/not-found", problem.Type)
+	assert.Equal(t, "Not Found", problem.Title)
+	assert.Equal(t, "/users/42", problem.Instance)
+	assert.Equal(t, string(appErrors.CodeNotFound), problem.Code)
+}
@@ -0,0 +1,85 @@
+package customfiber
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// InFlightMetricName is the gauge reporting the number of requests being served
+const InFlightMetricName = "http.server.in_flight_requests"
+
+// inFlightPollInterval is how often Wait checks whether requests have drained
+const inFlightPollInterval = 10 * time.Millisecond
+
+// InFlightCounter counts the requests currently being served. Its middleware
+// increments the count when a request starts and decrements it once the handler
+// chain returns; the count is exposed as an OpenTelemetry gauge and used by
+// GracefulShutdown to wait for in-flight requests to drain.
+type InFlightCounter struct {
+	count atomic.Int64
+}
+
+// NewInFlightCounter creates a counter and, when meter is not nil, registers the
+// InFlightMetricName gauge observing it.
+//
+// Example:
+//
+//	counter, err := customfiber.NewInFlightCounter(meter)
+//	app.Use(counter.Middleware())
+func NewInFlightCounter(meter metric.Meter) (*InFlightCounter, error) {
+	counter := &InFlightCounter{}
+	if meter == nil {
+		return counter, nil
+	}
+
+	_, err := meter.Int64ObservableGauge(
+		InFlightMetricName,
+		metric.WithDescription("Number of HTTP requests currently being served"),
+		metric.WithUnit("{request}"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			observer.Observe(counter.count.Load())
+			return nil
+		}),
+	)
+	if err != nil {
+		return counter, err
+	}
+
+	return counter, nil
+}
+
+// Middleware returns the Fiber middleware tracking in-flight requests. Register it
+// first so the count covers the whole handler chain.
+func (c *InFlightCounter) Middleware() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		c.count.Add(1)
+		defer c.count.Add(-1)
+
+		return ctx.Next()
+	}
+}
+
+// Count returns the number of requests currently being served
+func (c *InFlightCounter) Count() int {
+	return int(c.count.Load())
+}
+
+// Wait blocks until no request is in flight or ctx is done, returning ctx.Err() in the latter case
+func (c *InFlightCounter) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(inFlightPollInterval)
+	defer ticker.Stop()
+
+	for c.count.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package customfiber
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// inFlightGaugeValue collects the in-flight gauge from reader
+func inFlightGaugeValue(t *testing.T, reader *sdkmetric.ManualReader) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == InFlightMetricName {
+				gauge := m.Data.(metricdata.Gauge[int64])
+				require.Len(t, gauge.DataPoints, 1)
+				return gauge.DataPoints[0].Value
+			}
+		}
+	}
+	t.Fatalf("metric %s not found", InFlightMetricName)
+	return 0
+}
+
+func Test_InFlight_Counter_Tracks_Blocking_Request(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	counter, err := NewInFlightCounter(meter)
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	app := newTestApp()
+	app.Use(counter.Middleware())
+	app.Get("/blocking", func(c *fiber.Ctx) error {
+		close(started)
+		<-release
+		return c.SendStatus(http.StatusOK)
+	})
+
+	done := make(chan int)
+	go func() {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/blocking", nil), 5000)
+		if err != nil {
+			done <- 0
+			return
+		}
+		done <- resp.StatusCode
+	}()
+
+	<-started
+	assert.Equal(t, 1, counter.Count())
+	assert.Equal(t, int64(1), inFlightGaugeValue(t, reader))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, counter.Wait(ctx), context.DeadlineExceeded)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+
+	require.NoError(t, counter.Wait(context.Background()))
+	assert.Equal(t, 0, counter.Count())
+	assert.Equal(t, int64(0), inFlightGaugeValue(t, reader))
+}
+
+func Test_InFlight_Counter_Without_Meter(t *testing.T) {
+	counter, err := NewInFlightCounter(nil)
+	require.NoError(t, err)
+
+	app := newTestApp()
+	app.Use(counter.Middleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		assert.Equal(t, 1, counter.Count())
+		return c.SendStatus(http.StatusNoContent)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, 0, counter.Count())
+}
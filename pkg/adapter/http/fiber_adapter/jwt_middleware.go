@@ -0,0 +1,148 @@
+package customfiber
+
+import (
+	"strings"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contextkey"
+	appConfig "github.com/phatnt199/go-infra/pkg/application/config"
+	"github.com/phatnt199/go-infra/pkg/crypto"
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultTokenCookieName mirrors the SESSION_COOKIE_NAME default used by config.Load
+const defaultTokenCookieName = "session"
+
+// TokenExtractor returns the raw token carried by a request, or "" when there is none
+type TokenExtractor func(c *fiber.Ctx) string
+
+// TokenFromCookie extracts the token from the cookie name
+func TokenFromCookie(name string) TokenExtractor {
+	return func(c *fiber.Ctx) string {
+		return c.Cookies(name)
+	}
+}
+
+// TokenFromHeader extracts the token from an `Authorization: Bearer <token>` header
+func TokenFromHeader() TokenExtractor {
+	return func(c *fiber.Ctx) string {
+		scheme, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return ""
+		}
+		return strings.TrimSpace(token)
+	}
+}
+
+// TokenFromQuery extracts the token from the query parameter name. URLs end up in
+// logs and browser history, so only use it where headers can't be set (e.g. WebSocket
+// handshakes from browsers).
+func TokenFromQuery(name string) TokenExtractor {
+	return func(c *fiber.Ctx) string {
+		return c.Query(name)
+	}
+}
+
+// TokenFromFirst returns the token of the first extractor finding one
+func TokenFromFirst(extractors ...TokenExtractor) TokenExtractor {
+	return func(c *fiber.Ctx) string {
+		for _, extract := range extractors {
+			if token := extract(c); token != "" {
+				return token
+			}
+		}
+		return ""
+	}
+}
+
+// TokenParser verifies a token and returns its claims. *crypto.JWTManager implements it.
+type TokenParser interface {
+	ParseToken(token string) (*crypto.Claims, error)
+}
+
+type jwtConfig struct {
+	cookieName string
+	queryParam string
+	extractor  TokenExtractor
+}
+
+// JWTOption configures JWTMiddleware
+type JWTOption func(*jwtConfig)
+
+// WithTokenCookie sets the cookie the token is read from, replacing the session
+// cookie name of the global configuration
+func WithTokenCookie(name string) JWTOption {
+	return func(cfg *jwtConfig) {
+		cfg.cookieName = name
+	}
+}
+
+// WithTokenQuery also reads the token from the query parameter name, after the
+// cookie and the Authorization header. See TokenFromQuery.
+func WithTokenQuery(name string) JWTOption {
+	return func(cfg *jwtConfig) {
+		cfg.queryParam = name
+	}
+}
+
+// WithTokenExtractor replaces the cookie, header and query lookup with extractor
+func WithTokenExtractor(extractor TokenExtractor) JWTOption {
+	return func(cfg *jwtConfig) {
+		cfg.extractor = extractor
+	}
+}
+
+// JWTMiddleware authenticates requests with a JWT verified by parser and stores its
+// claims with crypto.ContextWithClaims for downstream handlers and middlewares, and
+// under contextkey.Claims.
+//
+// The token is read from the session cookie (config.AuthConfig.Session.CookieName,
+// "session" when no configuration is loaded), then from an `Authorization: Bearer`
+// header, then, when enabled with WithTokenQuery, from a query parameter. Requests
+// without a token fail with a `CodeUnauthorized` AppError (401); invalid tokens fail
+// with the error returned by parser.
+//
+// Example:
+//
+//	manager, err := crypto.NewJWTManager(jwtConfig)
+//	api.Use(customfiber.JWTMiddleware(manager))
+func JWTMiddleware(parser TokenParser, opts ...JWTOption) fiber.Handler {
+	cfg := &jwtConfig{cookieName: tokenCookieNameFromConfig()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.extractor == nil {
+		extractors := []TokenExtractor{TokenFromCookie(cfg.cookieName), TokenFromHeader()}
+		if cfg.queryParam != "" {
+			extractors = append(extractors, TokenFromQuery(cfg.queryParam))
+		}
+		cfg.extractor = TokenFromFirst(extractors...)
+	}
+
+	return func(c *fiber.Ctx) error {
+		token := cfg.extractor(c)
+		if token == "" {
+			return errors.Unauthorized("missing authentication token")
+		}
+
+		claims, err := parser.ParseToken(token)
+		if err != nil {
+			return err
+		}
+
+		c.Locals(contextkey.Claims.Name(), claims)
+		c.SetUserContext(crypto.ContextWithClaims(c.UserContext(), claims))
+		return c.Next()
+	}
+}
+
+// tokenCookieNameFromConfig returns the session cookie name from the global config
+func tokenCookieNameFromConfig() string {
+	if cfg := appConfig.Get(); cfg != nil && cfg.Auth.Session.CookieName != "" {
+		return cfg.Auth.Session.CookieName
+	}
+
+	return defaultTokenCookieName
+}
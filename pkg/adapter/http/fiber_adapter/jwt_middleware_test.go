@@ -0,0 +1,147 @@
+package customfiber
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contextkey"
+	"github.com/phatnt199/go-infra/pkg/crypto"
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newJWTTestManager(t *testing.T) *crypto.JWTManager {
+	t.Helper()
+	cfg := crypto.DefaultJWTConfig()
+	cfg.Secret = "test-secret-with-enough-entropy"
+	manager, err := crypto.NewJWTManager(cfg)
+	require.NoError(t, err)
+	return manager
+}
+
+func newJWTTestToken(t *testing.T, manager *crypto.JWTManager, userID string) string {
+	t.Helper()
+	token, err := manager.GenerateToken(&crypto.Claims{UserID: userID}, crypto.AccessToken)
+	require.NoError(t, err)
+	return token
+}
+
+// newJWTTestApp responds to /me with the authenticated user ID
+func newJWTTestApp(manager *crypto.JWTManager, opts ...JWTOption) *fiber.App {
+	app := newTestApp()
+	app.Use(JWTMiddleware(manager, opts...))
+	app.Get("/me", func(c *fiber.Ctx) error {
+		claims, _ := crypto.ClaimsFromContext(c.UserContext())
+		return c.SendString(claims.UserID)
+	})
+	return app
+}
+
+// authenticatedUser returns the status of req and the user ID the handler saw
+func authenticatedUser(t *testing.T, app *fiber.App, req *http.Request) (int, string) {
+	t.Helper()
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, ""
+	}
+	var body [64]byte
+	n, _ := resp.Body.Read(body[:])
+	return resp.StatusCode, string(body[:n])
+}
+
+func Test_JWT_Middleware_Reads_Token_From_Cookie(t *testing.T) {
+	manager := newJWTTestManager(t)
+	app := newJWTTestApp(manager)
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: newJWTTestToken(t, manager, "cookie-user")})
+
+	status, user := authenticatedUser(t, app, req)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "cookie-user", user)
+}
+
+func Test_JWT_Middleware_Reads_Token_From_Header(t *testing.T) {
+	manager := newJWTTestManager(t)
+	app := newJWTTestApp(manager)
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+newJWTTestToken(t, manager, "header-user"))
+
+	status, user := authenticatedUser(t, app, req)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "header-user", user)
+}
+
+func Test_JWT_Middleware_Prefers_Cookie_Over_Header(t *testing.T) {
+	manager := newJWTTestManager(t)
+	app := newJWTTestApp(manager, WithTokenCookie("access_token"))
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: newJWTTestToken(t, manager, "cookie-user")})
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+newJWTTestToken(t, manager, "header-user"))
+
+	status, user := authenticatedUser(t, app, req)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "cookie-user", user)
+}
+
+func Test_JWT_Middleware_Query_Token_Is_Opt_In(t *testing.T) {
+	manager := newJWTTestManager(t)
+	target := "/me?token=" + newJWTTestToken(t, manager, "query-user")
+
+	status, _ := authenticatedUser(t, newJWTTestApp(manager), httptest.NewRequest(http.MethodGet, target, nil))
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, user := authenticatedUser(t, newJWTTestApp(manager, WithTokenQuery("token")), httptest.NewRequest(http.MethodGet, target, nil))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "query-user", user)
+}
+
+func Test_JWT_Middleware_Rejects_Missing_And_Invalid_Tokens(t *testing.T) {
+	manager := newJWTTestManager(t)
+	app := newJWTTestApp(manager)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/me", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	var problem errors.ProblemDetail
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, string(errors.CodeUnauthorized), problem.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer not-a-jwt")
+	status, _ := authenticatedUser(t, app, req)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Basic dXNlcjpwYXNz")
+	status, _ = authenticatedUser(t, app, req)
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func Test_JWT_Middleware_Sets_Claims_Key(t *testing.T) {
+	manager := newJWTTestManager(t)
+	app := newTestApp()
+	app.Use(JWTMiddleware(manager))
+	app.Get("/me", func(c *fiber.Ctx) error {
+		claims := contextkey.Claims.MustGet(NewFiberContextAdapter(c))
+		return c.SendString(claims.UserID)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+newJWTTestToken(t, manager, "key-user"))
+
+	status, user := authenticatedUser(t, app, req)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "key-user", user)
+}
@@ -0,0 +1,56 @@
+package customfiber
+
+import (
+	"slices"
+
+	"github.com/phatnt199/go-infra/pkg/crypto"
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LogLevelRequest is the body accepted by LogLevelHandler
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// LogLevelHandler changes the level of log at runtime. The request must carry
+// authenticated claims (see crypto.ContextWithClaims), and when roles are given the
+// claims must hold at least one of them. Unknown levels are rejected with
+// CodeBadRequest.
+//
+// Example:
+//
+//	admin := app.Group("/admin", authMiddleware)
+//	admin.Put("/loglevel", customfiber.LogLevelHandler(log, "admin"))
+//
+//	// curl -X PUT /admin/loglevel -d '{"level":"debug"}'
+func LogLevelHandler(log logger.Logger, roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := crypto.ClaimsFromContext(c.UserContext())
+		if !ok {
+			return errors.Unauthorized("authentication required")
+		}
+		if len(roles) > 0 && !slices.ContainsFunc(roles, func(role string) bool {
+			return slices.Contains(claims.Roles, role)
+		}) {
+			return errors.Forbidden("insufficient role to change log level")
+		}
+
+		var req LogLevelRequest
+		if err := c.BodyParser(&req); err != nil {
+			return errors.BadRequest("invalid log level request body")
+		}
+		if err := log.SetLevel(req.Level); err != nil {
+			return errors.BadRequest(err.Error())
+		}
+
+		log.InfoFields("log level changed",
+			logger.String("level", req.Level),
+			logger.String("user_id", claims.UserID),
+		)
+
+		return c.JSON(LogLevelRequest{Level: req.Level})
+	}
+}
@@ -0,0 +1,91 @@
+package customfiber
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/application/config"
+	"github.com/phatnt199/go-infra/pkg/crypto"
+	"github.com/phatnt199/go-infra/pkg/logger"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// levelRecordingLogger accepts the levels a zap logger does and records the last one
+type levelRecordingLogger struct {
+	logger.Logger
+	level string
+}
+
+func newLevelRecordingLogger() *levelRecordingLogger {
+	return &levelRecordingLogger{Logger: empty.EmptyLogger, level: "info"}
+}
+
+func (l *levelRecordingLogger) SetLevel(level string) error {
+	if err := config.ValidateLogLevel(level); err != nil {
+		return err
+	}
+	l.level = level
+	return nil
+}
+
+func newLogLevelApp(log *levelRecordingLogger, claims *crypto.Claims) *fiber.App {
+	app := newTestApp()
+	app.Use(func(c *fiber.Ctx) error {
+		if claims != nil {
+			c.SetUserContext(crypto.ContextWithClaims(c.UserContext(), claims))
+		}
+		return c.Next()
+	})
+	app.Put("/admin/loglevel", LogLevelHandler(log, "admin"))
+	return app
+}
+
+func putLogLevel(t *testing.T, app *fiber.App, body string) *http.Response {
+	req := httptest.NewRequest(http.MethodPut, "/admin/loglevel", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func Test_LogLevelHandler_Sets_Level(t *testing.T) {
+	log := newLevelRecordingLogger()
+	app := newLogLevelApp(log, &crypto.Claims{UserID: "ops-1", Roles: []string{"admin"}})
+
+	resp := putLogLevel(t, app, `{"level":"debug"}`)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"level":"debug"}`, string(body))
+	assert.Equal(t, "debug", log.level)
+}
+
+func Test_LogLevelHandler_Rejects_Unknown_Level(t *testing.T) {
+	log := newLevelRecordingLogger()
+	app := newLogLevelApp(log, &crypto.Claims{UserID: "ops-1", Roles: []string{"admin"}})
+
+	resp := putLogLevel(t, app, `{"level":"trace"}`)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "info", log.level)
+}
+
+func Test_LogLevelHandler_Requires_Auth_And_Role(t *testing.T) {
+	log := newLevelRecordingLogger()
+
+	resp := putLogLevel(t, newLogLevelApp(log, nil), `{"level":"debug"}`)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = putLogLevel(t, newLogLevelApp(log, &crypto.Claims{UserID: "u-1", Roles: []string{"viewer"}}), `{"level":"debug"}`)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	assert.Equal(t, "info", log.level)
+}
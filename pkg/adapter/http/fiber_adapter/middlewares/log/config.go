@@ -1,9 +1,17 @@
 package log
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
 
 type config struct {
 	Skipper func(c *fiber.Ctx) bool
+
+	// SlowThreshold logs an extra "slow request" warning for requests taking longer;
+	// disabled when 0
+	SlowThreshold time.Duration
 }
 
 type Option interface {
@@ -21,3 +29,17 @@ func (o skipperOption) apply(c *config) {
 func WithSkipper(skipper func(c *fiber.Ctx) bool) Option {
 	return skipperOption{skipper: skipper}
 }
+
+type slowThresholdOption struct {
+	threshold time.Duration
+}
+
+func (o slowThresholdOption) apply(c *config) {
+	c.SlowThreshold = o.threshold
+}
+
+// WithSlowThreshold logs a distinct "slow request" warning, with the route and status,
+// for every request whose latency exceeds threshold
+func WithSlowThreshold(threshold time.Duration) Option {
+	return slowThresholdOption{threshold: threshold}
+}
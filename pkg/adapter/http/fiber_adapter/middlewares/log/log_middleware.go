@@ -9,7 +9,9 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-// FiberLogger returns a Fiber middleware which will log incoming requests
+// FiberLogger returns a Fiber middleware which will log incoming requests. With
+// WithSlowThreshold, requests slower than the threshold are also logged as a
+// "slow request" warning so that latency SLO violations are easy to alert on.
 func FiberLogger(l logger.Logger, opts ...Option) fiber.Handler {
 	cfg := config{}
 	for _, opt := range opts {
@@ -41,7 +43,9 @@ func FiberLogger(l logger.Logger, opts ...Option) fiber.Handler {
 			"status":     c.Response().StatusCode(),
 			"size":       len(c.Response().Body()),
 			"user_agent": c.Get(fiber.HeaderUserAgent),
-			"request_id": c.Get(fiber.HeaderXRequestID),
+		}
+		for key, value := range logger.ContextFields(c.UserContext()) {
+			fields[key] = value
 		}
 
 		if err != nil {
@@ -60,6 +64,16 @@ func FiberLogger(l logger.Logger, opts ...Option) fiber.Handler {
 			l.Infow("FiberServer logger middleware: Success", fields)
 		}
 
+		if cfg.SlowThreshold > 0 && latency > cfg.SlowThreshold {
+			slowFields := logger.ContextFields(c.UserContext())
+			slowFields["method"] = c.Method()
+			slowFields["route"] = c.Route().Path
+			slowFields["status"] = status
+			slowFields["latency"] = latency.String()
+			slowFields["threshold"] = cfg.SlowThreshold.String()
+			l.Warnw("slow request", slowFields)
+		}
+
 		return err
 	}
 }
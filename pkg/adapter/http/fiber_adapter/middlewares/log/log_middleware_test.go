@@ -0,0 +1,116 @@
+package log
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/correlation"
+	"github.com/phatnt199/go-infra/pkg/logger"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type capturedLog struct {
+	level  string
+	msg    string
+	fields logger.Fields
+}
+
+type captureLogger struct {
+	logger.Logger
+	mu      sync.Mutex
+	entries []capturedLog
+}
+
+func newCaptureLogger() *captureLogger {
+	return &captureLogger{Logger: empty.EmptyLogger}
+}
+
+func (l *captureLogger) record(level, msg string, fields logger.Fields) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, capturedLog{level: level, msg: msg, fields: fields})
+}
+
+func (l *captureLogger) Infow(msg string, fields logger.Fields)  { l.record("info", msg, fields) }
+func (l *captureLogger) Warnw(msg string, fields logger.Fields)  { l.record("warn", msg, fields) }
+func (l *captureLogger) Errorw(msg string, fields logger.Fields) { l.record("error", msg, fields) }
+
+func newSlowApp(l logger.Logger, opts ...Option) *fiber.App {
+	app := fiber.New()
+	app.Use(FiberLogger(l, opts...))
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		if c.Params("id") == "slow" {
+			time.Sleep(30 * time.Millisecond)
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func Test_FiberLogger_Warns_On_Slow_Request(t *testing.T) {
+	l := newCaptureLogger()
+	app := newSlowApp(l, WithSlowThreshold(10*time.Millisecond))
+
+	_, err := app.Test(httptest.NewRequest("GET", "/users/slow", nil))
+	require.NoError(t, err)
+
+	require.Len(t, l.entries, 2)
+	assert.Equal(t, "info", l.entries[0].level)
+	assert.Equal(t, "FiberServer logger middleware: Success", l.entries[0].msg)
+
+	slow := l.entries[1]
+	assert.Equal(t, "warn", slow.level)
+	assert.Equal(t, "slow request", slow.msg)
+	assert.Equal(t, "/users/:id", slow.fields["route"])
+	assert.Equal(t, fiber.StatusOK, slow.fields["status"])
+	assert.Equal(t, "10ms", slow.fields["threshold"])
+}
+
+func Test_FiberLogger_Slow_Request_Has_Context_Fields(t *testing.T) {
+	l := newCaptureLogger()
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(correlation.NewContext(c.UserContext(), "req-123"))
+		return c.Next()
+	})
+	app.Use(FiberLogger(l, WithSlowThreshold(time.Nanosecond)))
+	app.Get("/", func(c *fiber.Ctx) error {
+		time.Sleep(time.Millisecond)
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	_, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+
+	require.Len(t, l.entries, 2)
+	assert.Equal(t, "slow request", l.entries[1].msg)
+	assert.Equal(t, "req-123", l.entries[1].fields[correlation.FieldName])
+	assert.Equal(t, "req-123", l.entries[0].fields[correlation.FieldName])
+}
+
+func Test_FiberLogger_No_Warning_Below_Threshold(t *testing.T) {
+	l := newCaptureLogger()
+	app := newSlowApp(l, WithSlowThreshold(time.Second))
+
+	_, err := app.Test(httptest.NewRequest("GET", "/users/slow", nil))
+	require.NoError(t, err)
+
+	require.Len(t, l.entries, 1)
+	assert.Equal(t, "info", l.entries[0].level)
+}
+
+func Test_FiberLogger_Slow_Threshold_Disabled_By_Default(t *testing.T) {
+	l := newCaptureLogger()
+	app := newSlowApp(l)
+
+	_, err := app.Test(httptest.NewRequest("GET", "/users/slow", nil))
+	require.NoError(t, err)
+
+	require.Len(t, l.entries, 1)
+}
@@ -0,0 +1,112 @@
+package customfiber
+
+import (
+	"sync"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// RequestDurationMetricName is the histogram of request latencies, in seconds
+	RequestDurationMetricName = "http.server.request.duration"
+
+	// DefaultMaxRouteLabels caps the distinct route labels a RouteLabeler hands out
+	DefaultMaxRouteLabels = 200
+
+	// OverflowRouteLabel replaces routes seen after the label cap is reached
+	OverflowRouteLabel = "other"
+)
+
+// RouteLabeler turns requests into low-cardinality metric labels. It labels a request
+// with its registered route pattern (e.g. "/users/:id") rather than the raw path, and
+// falls back to OverflowRouteLabel once a maximum number of distinct routes was seen.
+type RouteLabeler struct {
+	mu     sync.RWMutex
+	max    int
+	routes map[string]struct{}
+}
+
+// NewRouteLabeler creates a labeler handing out at most max distinct route labels
+// (DefaultMaxRouteLabels when max <= 0)
+func NewRouteLabeler(max int) *RouteLabeler {
+	if max <= 0 {
+		max = DefaultMaxRouteLabels
+	}
+	return &RouteLabeler{max: max, routes: map[string]struct{}{}}
+}
+
+// Label returns the route label of c, taken from c.Path(). Call it once the handler
+// chain has run, so the matched route is known.
+//
+// Example:
+//
+//	labeler.Label(ctx) // "/users/:id" for both /users/123 and /users/456
+func (l *RouteLabeler) Label(c contracts.Context) string {
+	route := c.Path()
+
+	l.mu.RLock()
+	_, seen := l.routes[route]
+	l.mu.RUnlock()
+	if seen {
+		return route
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, seen := l.routes[route]; seen {
+		return route
+	}
+	if len(l.routes) >= l.max {
+		return OverflowRouteLabel
+	}
+	l.routes[route] = struct{}{}
+	return route
+}
+
+// RequestMetrics records the duration of HTTP requests as an OpenTelemetry histogram,
+// labelled with the method, the normalized route and the response status.
+type RequestMetrics struct {
+	labeler  *RouteLabeler
+	duration metric.Float64Histogram
+}
+
+// NewRequestMetrics registers the RequestDurationMetricName histogram on meter. Route
+// labels are capped at maxRoutes (DefaultMaxRouteLabels when maxRoutes <= 0).
+//
+// Example:
+//
+//	requestMetrics, err := customfiber.NewRequestMetrics(meter, 0)
+//	app.Use(requestMetrics.Middleware())
+func NewRequestMetrics(meter metric.Meter, maxRoutes int) (*RequestMetrics, error) {
+	duration, err := meter.Float64Histogram(
+		RequestDurationMetricName,
+		metric.WithDescription("Duration of HTTP requests"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestMetrics{labeler: NewRouteLabeler(maxRoutes), duration: duration}, nil
+}
+
+// Middleware returns the Fiber middleware recording request durations
+func (m *RequestMetrics) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		m.duration.Record(c.UserContext(), time.Since(start).Seconds(), metric.WithAttributes(
+			attribute.String("http.request.method", c.Method()),
+			attribute.String("http.route", m.labeler.Label(NewFiberContextAdapter(c))),
+			attribute.Int("http.response.status_code", responseStatus(c, err)),
+		))
+
+		return err
+	}
+}
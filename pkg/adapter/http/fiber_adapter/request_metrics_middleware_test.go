@@ -0,0 +1,86 @@
+package customfiber
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// requestDurationPoints collects the request duration data points from reader
+func requestDurationPoints(t *testing.T, reader *sdkmetric.ManualReader) []metricdata.HistogramDataPoint[float64] {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == RequestDurationMetricName {
+				return m.Data.(metricdata.Histogram[float64]).DataPoints
+			}
+		}
+	}
+	t.Fatalf("metric %s not found", RequestDurationMetricName)
+	return nil
+}
+
+func routeLabel(t *testing.T, point metricdata.HistogramDataPoint[float64]) string {
+	t.Helper()
+	value, ok := point.Attributes.Value("http.route")
+	require.True(t, ok)
+	return value.AsString()
+}
+
+func Test_RequestMetrics_Labels_By_Route_Pattern(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	requestMetrics, err := NewRequestMetrics(meter, 0)
+	require.NoError(t, err)
+
+	app := newTestApp()
+	app.Use(requestMetrics.Middleware())
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+
+	for _, path := range []string{"/users/123", "/users/456"} {
+		_, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+	}
+
+	points := requestDurationPoints(t, reader)
+	require.Len(t, points, 1)
+	assert.Equal(t, "/users/:id", routeLabel(t, points[0]))
+	assert.Equal(t, uint64(2), points[0].Count)
+
+	status, _ := points[0].Attributes.Value("http.response.status_code")
+	assert.Equal(t, attribute.IntValue(http.StatusOK), status)
+}
+
+func Test_RouteLabeler_Caps_Distinct_Routes(t *testing.T) {
+	labeler := NewRouteLabeler(2)
+
+	app := newTestApp()
+	var labels []string
+	for _, route := range []string{"/a/:id", "/b/:id", "/c/:id"} {
+		app.Get(route, func(c *fiber.Ctx) error {
+			labels = append(labels, labeler.Label(NewFiberContextAdapter(c)))
+			return nil
+		})
+	}
+
+	for _, path := range []string{"/a/1", "/b/1", "/c/1", "/a/2"} {
+		_, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"/a/:id", "/b/:id", OverflowRouteLabel, "/a/:id"}, labels)
+}
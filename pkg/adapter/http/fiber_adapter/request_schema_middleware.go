@@ -0,0 +1,152 @@
+package customfiber
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/json"
+	"github.com/phatnt199/go-infra/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestSchema is the expected shape of a route's request, as field→tag rules validated
+// with validator.Map, e.g. {"limit": "numeric,gt=0"}. Query and path values are strings:
+// rules including numeric or number validate them as numbers, so "numeric,gt=0" requires
+// a positive number. Body rules apply to the top-level fields of a JSON object. Empty
+// and missing values only fail required rules.
+type RequestSchema struct {
+	Query  map[string]string
+	Params map[string]string
+	Body   map[string]string
+}
+
+// ValidateRequest returns a Fiber middleware rejecting requests not matching schema with
+// a CodeValidation AppError, rendered as a problem response listing every failed field
+// as "query.<name>", "params.<name>" or "body.<name>". It centralizes the validation of
+// simple routes before they reach their handler, which can still bind the request as
+// usual.
+//
+// Example:
+//
+//	app.Get("/users/:id/orders", customfiber.ValidateRequest(customfiber.RequestSchema{
+//	    Params: map[string]string{"id": "required,uuid"},
+//	    Query:  map[string]string{"limit": "numeric,gt=0,lte=100", "status": "oneof=open closed"},
+//	}), listOrders)
+func ValidateRequest(schema RequestSchema) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := schema.validate(c.Query, c.Params, c.Body()); err != nil {
+			return err
+		}
+		return c.Next()
+	}
+}
+
+// WithRequestSchema is ValidateRequest for the framework-agnostic route builder.
+//
+// Example:
+//
+//	routes.POST("/users", createUser, customfiber.WithRequestSchema(customfiber.RequestSchema{
+//	    Body: map[string]string{"email": "required,email", "age": "gte=18"},
+//	}))
+func WithRequestSchema(schema RequestSchema) contracts.MiddlewareFunc {
+	return func(next contracts.HandlerFunc) contracts.HandlerFunc {
+		return func(ctx contracts.Context) error {
+			var body []byte
+			if adapter, ok := ctx.(*fiberContextAdapter); ok {
+				body = adapter.ctx.Body()
+			} else if len(schema.Body) > 0 && ctx.Request().Body != nil {
+				var err error
+				if body, err = io.ReadAll(ctx.Request().Body); err != nil {
+					return errors.Wrap(err, errors.CodeBadRequest, "failed to read request body")
+				}
+				// Let the handler read the body again
+				ctx.Request().Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			query := func(key string, _ ...string) string { return ctx.QueryParam(key) }
+			param := func(key string, _ ...string) string { return ctx.Param(key) }
+			if err := schema.validate(query, param, body); err != nil {
+				return err
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// validate checks the request parts against the schema, returning a CodeValidation
+// AppError with the failed fields
+func (s RequestSchema) validate(query, param func(key string, defaultValue ...string) string, body []byte) error {
+	var failures validator.ValidationErrors
+	failures = append(failures, validateStrings("query", s.Query, query)...)
+	failures = append(failures, validateStrings("params", s.Params, param)...)
+	failures = append(failures, validateBody(s.Body, body)...)
+
+	if !failures.HasErrors() {
+		return nil
+	}
+	return errors.Wrap(failures, errors.CodeValidation).WithFieldErrors(failures.Fields()...)
+}
+
+// validateStrings validates the values returned by get against rules
+func validateStrings(part string, rules map[string]string, get func(key string, defaultValue ...string) string) validator.ValidationErrors {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	data := make(map[string]any, len(rules))
+	checked := make(map[string]string, len(rules))
+	for field, rule := range rules {
+		checked[field] = rule
+		value := get(field)
+		if value == "" {
+			continue
+		}
+		data[field] = value
+
+		// A value that is present satisfies required, even when it is "0"
+		tags := slices.DeleteFunc(strings.Split(rule, ","), func(tag string) bool { return tag == "required" })
+		checked[field] = strings.Join(tags, ",")
+
+		// Numeric rules such as gt=0 compare numbers, rather than lengths of strings
+		if slices.Contains(tags, "numeric") || slices.Contains(tags, "number") {
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				data[field] = n
+			} else if f, err := strconv.ParseFloat(value, 64); err == nil {
+				data[field] = f
+			}
+		}
+	}
+
+	return prefixed(part, validator.Map(data, checked).Errors())
+}
+
+// validateBody validates the top-level fields of the JSON object body against rules. An
+// empty body is an empty object.
+func validateBody(rules map[string]string, body []byte) validator.ValidationErrors {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	data := map[string]any{}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &data); err != nil {
+			return validator.ValidationErrors{{Field: "body", Message: "must be a JSON object"}}
+		}
+	}
+
+	return prefixed("body", validator.Map(data, rules).Errors())
+}
+
+// prefixed returns errs with their fields prefixed with part
+func prefixed(part string, errs validator.ValidationErrors) validator.ValidationErrors {
+	for i := range errs {
+		errs[i].Field = part + "." + errs[i].Field
+	}
+	return errs
+}
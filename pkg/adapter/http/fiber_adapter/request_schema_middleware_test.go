@@ -0,0 +1,122 @@
+package customfiber
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/json"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sendProblem sends req and decodes the problem response, if any
+func sendProblem(t *testing.T, app *fiber.App, req *http.Request) (int, errors.ProblemDetail) {
+	t.Helper()
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var problem errors.ProblemDetail
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &problem))
+	}
+	return resp.StatusCode, problem
+}
+
+func Test_ValidateRequest_Rejects_Non_Positive_Integer_Query_Param(t *testing.T) {
+	app := newTestApp()
+	reached := false
+	app.Get("/orders", ValidateRequest(RequestSchema{
+		Query: map[string]string{"limit": "required,numeric,gt=0"},
+	}), func(c *fiber.Ctx) error {
+		reached = true
+		return c.SendStatus(http.StatusNoContent)
+	})
+
+	for _, limit := range []string{"0", "-3", "ten"} {
+		status, problem := sendProblem(t, app, httptest.NewRequest(http.MethodGet, "/orders?limit="+limit, nil))
+		assert.Equal(t, http.StatusBadRequest, status, limit)
+		assert.Equal(t, string(errors.CodeValidation), problem.Code)
+		require.Len(t, problem.Errors, 1, limit)
+		assert.Equal(t, "query.limit", problem.Errors[0].Field)
+	}
+	assert.False(t, reached)
+
+	_, problem := sendProblem(t, app, httptest.NewRequest(http.MethodGet, "/orders?limit=ten", nil))
+	assert.Equal(t, "must be a number", problem.Errors[0].Message)
+	_, problem = sendProblem(t, app, httptest.NewRequest(http.MethodGet, "/orders?limit=0", nil))
+	assert.Equal(t, "must be greater than 0", problem.Errors[0].Message)
+	_, problem = sendProblem(t, app, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	assert.Equal(t, "is required", problem.Errors[0].Message)
+
+	status, _ := sendProblem(t, app, httptest.NewRequest(http.MethodGet, "/orders?limit=25", nil))
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.True(t, reached)
+}
+
+func Test_ValidateRequest_Reports_Every_Failed_Field(t *testing.T) {
+	app := newTestApp()
+	app.Post("/users/:id", ValidateRequest(RequestSchema{
+		Params: map[string]string{"id": "required,uuid"},
+		Query:  map[string]string{"notify": "boolean"},
+		Body:   map[string]string{"email": "required,email", "name": "required,min=2", "age": "gte=18"},
+	}), func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users/42?notify=yes", strings.NewReader(`{"email":"nope","age":16}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	status, problem := sendProblem(t, app, req)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, []errors.ValidationField{
+		{Field: "query.notify", Message: "must be a boolean"},
+		{Field: "params.id", Message: "must be a valid UUID"},
+		{Field: "body.age", Message: "must be at least 18"},
+		{Field: "body.email", Message: "must be a valid email"},
+		{Field: "body.name", Message: "is required"},
+	}, problem.Errors)
+
+	req = httptest.NewRequest(http.MethodPost, "/users/6f1c2a8e-3b4d-4e5f-9a6b-7c8d9e0f1a2b", strings.NewReader(`[1, 2]`))
+	_, problem = sendProblem(t, app, req)
+	assert.Equal(t, []errors.ValidationField{{Field: "body", Message: "must be a JSON object"}}, problem.Errors)
+
+	req = httptest.NewRequest(http.MethodPost, "/users/6f1c2a8e-3b4d-4e5f-9a6b-7c8d9e0f1a2b",
+		strings.NewReader(`{"email":"ann@example.com","name":"Ann"}`))
+	status, _ = sendProblem(t, app, req)
+	assert.Equal(t, http.StatusNoContent, status)
+}
+
+func Test_WithRequestSchema_On_Route_Builder(t *testing.T) {
+	app := newTestApp()
+	routes := NewFiberRouteBuilder(app)
+	routes.POST("/items", func(ctx contracts.Context) error {
+		var item struct {
+			Quantity int `json:"quantity"`
+		}
+		if err := ctx.Bind(&item); err != nil {
+			return err
+		}
+		return ctx.JSON(http.StatusCreated, item)
+	}, WithRequestSchema(RequestSchema{
+		Body: map[string]string{"quantity": "required,gt=0"},
+	}))
+
+	status, problem := sendProblem(t, app, httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"quantity":0}`)))
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, []errors.ValidationField{{Field: "body.quantity", Message: "is required"}}, problem.Errors)
+
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"quantity":3}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	status, _ = sendProblem(t, app, req)
+	assert.Equal(t, http.StatusCreated, status)
+}
@@ -0,0 +1,101 @@
+package customfiber
+
+import (
+	"fmt"
+
+	appConfig "github.com/phatnt199/go-infra/pkg/application/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultHSTSMaxAge     = 31536000 // 1 year
+	defaultFrameOptions   = "DENY"
+	defaultReferrerPolicy = "strict-origin-when-cross-origin"
+)
+
+// SecureHeaderOptions configures the SecureHeaders middleware.
+// The zero value is a sensible production default: every header is enabled and
+// HSTS is only sent when TLS is enabled (either here or in the application config).
+type SecureHeaderOptions struct {
+	// Next defines a function to skip this middleware when it returns true
+	Next func(c *fiber.Ctx) bool
+
+	// TLSEnabled enables HSTS; it is also enabled when config.HTTPConfig.TLS.Enabled is set
+	TLSEnabled            bool
+	HSTSMaxAge            int // seconds, defaults to 1 year
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	DisableHSTS           bool
+
+	DisableContentTypeNosniff bool
+
+	FrameOptions        string // defaults to DENY
+	DisableFrameOptions bool
+
+	ReferrerPolicy        string // defaults to strict-origin-when-cross-origin
+	DisableReferrerPolicy bool
+
+	// ContentSecurityPolicy is sent as-is; it is omitted when empty
+	ContentSecurityPolicy string
+}
+
+// SecureHeaders returns a Fiber middleware that sets common security headers:
+// Strict-Transport-Security, X-Content-Type-Options, X-Frame-Options,
+// Referrer-Policy and (optionally) Content-Security-Policy.
+func SecureHeaders(opts SecureHeaderOptions) fiber.Handler {
+	hsts := ""
+	if !opts.DisableHSTS && (opts.TLSEnabled || tlsEnabledInConfig()) {
+		maxAge := opts.HSTSMaxAge
+		if maxAge <= 0 {
+			maxAge = defaultHSTSMaxAge
+		}
+		hsts = fmt.Sprintf("max-age=%d", maxAge)
+		if opts.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if opts.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	frameOptions := opts.FrameOptions
+	if frameOptions == "" {
+		frameOptions = defaultFrameOptions
+	}
+
+	referrerPolicy := opts.ReferrerPolicy
+	if referrerPolicy == "" {
+		referrerPolicy = defaultReferrerPolicy
+	}
+
+	return func(c *fiber.Ctx) error {
+		if opts.Next != nil && opts.Next(c) {
+			return c.Next()
+		}
+
+		if hsts != "" {
+			c.Set(fiber.HeaderStrictTransportSecurity, hsts)
+		}
+		if !opts.DisableContentTypeNosniff {
+			c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+		}
+		if !opts.DisableFrameOptions {
+			c.Set(fiber.HeaderXFrameOptions, frameOptions)
+		}
+		if !opts.DisableReferrerPolicy {
+			c.Set(fiber.HeaderReferrerPolicy, referrerPolicy)
+		}
+		if opts.ContentSecurityPolicy != "" {
+			c.Set(fiber.HeaderContentSecurityPolicy, opts.ContentSecurityPolicy)
+		}
+
+		return c.Next()
+	}
+}
+
+// tlsEnabledInConfig reports whether TLS is enabled in the global application config
+func tlsEnabledInConfig() bool {
+	cfg := appConfig.Get()
+	return cfg != nil && cfg.Server.HTTP.TLS.Enabled
+}
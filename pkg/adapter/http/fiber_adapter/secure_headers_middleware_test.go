@@ -0,0 +1,45 @@
+package customfiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Secure_Headers_Default_Header_Set(t *testing.T) {
+	app := newTestApp()
+	app.Use(SecureHeaders(SecureHeaderOptions{}))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, "nosniff", resp.Header.Get(fiber.HeaderXContentTypeOptions))
+	assert.Equal(t, "DENY", resp.Header.Get(fiber.HeaderXFrameOptions))
+	assert.Equal(t, "strict-origin-when-cross-origin", resp.Header.Get(fiber.HeaderReferrerPolicy))
+	// HSTS must not be sent over plain HTTP
+	assert.Empty(t, resp.Header.Get(fiber.HeaderStrictTransportSecurity))
+	assert.Empty(t, resp.Header.Get(fiber.HeaderContentSecurityPolicy))
+}
+
+func Test_Secure_Headers_With_TLS_And_Custom_Options(t *testing.T) {
+	app := newTestApp()
+	app.Use(SecureHeaders(SecureHeaderOptions{
+		TLSEnabled:            true,
+		HSTSIncludeSubdomains: true,
+		DisableFrameOptions:   true,
+		ContentSecurityPolicy: "default-src 'self'",
+	}))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, "max-age=31536000; includeSubDomains", resp.Header.Get(fiber.HeaderStrictTransportSecurity))
+	assert.Equal(t, "default-src 'self'", resp.Header.Get(fiber.HeaderContentSecurityPolicy))
+	assert.Empty(t, resp.Header.Get(fiber.HeaderXFrameOptions))
+}
@@ -0,0 +1,33 @@
+package customfiber
+
+import (
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/servertiming"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ServerTimingMiddleware times each request: it stores a servertiming.Timing in the
+// user context, where handlers, repositories and the httpclient record their phases,
+// and writes them with the "total" duration of the handler chain in the Server-Timing
+// response header. The header reveals how requests are served, so register the
+// middleware for internal APIs or while debugging, e.g. behind a feature flag.
+//
+// Example:
+//
+//	app.Use(customfiber.ServerTimingMiddleware())
+//	// Server-Timing: db;dur=12.5;desc="3 calls", external;dur=80.1, total;dur=95.3
+func ServerTimingMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		timing := servertiming.New()
+		c.SetUserContext(servertiming.NewContext(c.UserContext(), timing))
+
+		err := c.Next()
+
+		timing.Record("total", time.Since(start))
+		c.Set(servertiming.HeaderName, timing.Header())
+		return err
+	}
+}
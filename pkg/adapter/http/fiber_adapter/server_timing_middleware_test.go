@@ -0,0 +1,51 @@
+package customfiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/servertiming"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Server_Timing_Middleware_Reports_Recorded_Phases(t *testing.T) {
+	app := newTestApp()
+	app.Use(ServerTimingMiddleware())
+	app.Get("/orders", func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		for i := 0; i < 2; i++ {
+			span := servertiming.Start(ctx, "db")
+			time.Sleep(2 * time.Millisecond)
+			span.Stop()
+		}
+		servertiming.FromContext(ctx).Record("cache", 500*time.Microsecond)
+		return c.SendString("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/orders", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	header := resp.Header.Get(servertiming.HeaderName)
+	assert.Regexp(t, `^db;dur=[0-9.]+;desc="2 calls", cache;dur=0\.5, total;dur=[0-9.]+$`, header)
+}
+
+func Test_Server_Timing_Middleware_Reports_Failed_Requests(t *testing.T) {
+	app := newTestApp()
+	app.Use(ServerTimingMiddleware())
+	app.Get("/missing", func(c *fiber.Ctx) error {
+		servertiming.Start(c.UserContext(), "db").Stop()
+		return errors.NotFound("order")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/missing", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Regexp(t, `^db;dur=[0-9.]+, total;dur=[0-9.]+$`, resp.Header.Get(servertiming.HeaderName))
+}
@@ -0,0 +1,119 @@
+package customfiber
+
+import (
+	"bufio"
+	"context"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SSEHeartbeatInterval is how often an idle SSE stream sends a comment line, keeping
+// proxies from closing the connection and detecting disconnected clients
+var SSEHeartbeatInterval = 15 * time.Second
+
+// SSE streams events as Server-Sent Events. The producer of events is not told when
+// the client disconnects; use SSEFrom to stop it.
+//
+// Stream cannot be used here: fasthttp only flushes a streamed reader at the end, so
+// the events are written through a body stream writer flushing every frame instead.
+// The stream is written after the handler returns, so SSE returns immediately and
+// ctx must not be tied to the Fiber context, which is released by then.
+func (f *fiberContextAdapter) SSE(ctx context.Context, events <-chan contracts.SSEEvent) error {
+	f.setSSEHeaders()
+
+	heartbeat := SSEHeartbeatInterval
+	f.ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		writeSSE(ctx, w, events, heartbeat)
+	})
+
+	return nil
+}
+
+// SSEFrom streams the events of produce as Server-Sent Events. produce starts with the
+// stream and its context is canceled when the stream ends: ctx is done, produce
+// returns or the client disconnects. As with SSE, ctx must not be tied to the Fiber
+// context.
+//
+// Example:
+//
+//	func (h *DashboardHandler) Stream(c contracts.Context) error {
+//	    return c.SSEFrom(context.Background(), func(ctx context.Context, events chan<- contracts.SSEEvent) {
+//	        for {
+//	            select {
+//	            case <-ctx.Done():
+//	                return
+//	            case snapshot := <-h.metrics.Updates():
+//	                select {
+//	                case events <- contracts.SSEEvent{Event: "metrics", Data: snapshot}:
+//	                case <-ctx.Done():
+//	                    return
+//	                }
+//	            }
+//	        }
+//	    })
+//	}
+func (f *fiberContextAdapter) SSEFrom(ctx context.Context, produce contracts.SSEProducer) error {
+	f.setSSEHeaders()
+
+	heartbeat := SSEHeartbeatInterval
+	f.ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		streamSSE(ctx, w, produce, heartbeat)
+	})
+
+	return nil
+}
+
+func (f *fiberContextAdapter) setSSEHeaders() {
+	f.ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	f.ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	f.ctx.Set(fiber.HeaderConnection, "keep-alive")
+	f.ctx.Set("X-Accel-Buffering", "no")
+	f.ctx.Status(fiber.StatusOK)
+}
+
+// streamSSE runs produce and writes its events to w, canceling its context when the
+// writing stops
+func streamSSE(ctx context.Context, w *bufio.Writer, produce contracts.SSEProducer, heartbeat time.Duration) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events := make(chan contracts.SSEEvent)
+	go func() {
+		defer close(events)
+		produce(ctx, events)
+	}()
+
+	writeSSE(ctx, w, events, heartbeat)
+}
+
+// writeSSE writes events to w until ctx is done, events is closed or a write fails
+func writeSSE(ctx context.Context, w *bufio.Writer, events <-chan contracts.SSEEvent, heartbeat time.Duration) {
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if _, err := event.WriteTo(w); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := w.WriteString(": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		// a failed flush means the client is gone
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
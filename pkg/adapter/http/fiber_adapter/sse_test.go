@@ -0,0 +1,127 @@
+package customfiber
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SSE_Streams_Events(t *testing.T) {
+	app := newTestApp()
+	app.Get("/events", ConvertFiberHandler(func(c contracts.Context) error {
+		events := make(chan contracts.SSEEvent, 2)
+		events <- contracts.SSEEvent{Event: "tick", Data: `{"n":1}`, ID: "1"}
+		events <- contracts.SSEEvent{Data: "line one\nline two"}
+		close(events)
+		return c.SSE(context.Background(), events)
+	}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/events", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+	assert.Equal(t,
+		"id: 1\nevent: tick\ndata: {\"n\":1}\n\n"+
+			"data: line one\ndata: line two\n\n",
+		string(body),
+	)
+}
+
+func Test_SSE_Stops_When_Context_Done(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	app := newTestApp()
+	app.Get("/events", ConvertFiberHandler(func(c contracts.Context) error {
+		return c.SSE(ctx, make(chan contracts.SSEEvent))
+	}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/events", nil), 2000)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Empty(t, body)
+}
+
+func Test_WriteSSE_Sends_Heartbeats(t *testing.T) {
+	var buf bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Millisecond)
+	defer cancel()
+
+	writeSSE(ctx, bufio.NewWriter(&buf), make(chan contracts.SSEEvent), 10*time.Millisecond)
+
+	assert.Contains(t, buf.String(), ": heartbeat\n\n")
+}
+
+func Test_SSEFrom_Streams_Produced_Events(t *testing.T) {
+	app := newTestApp()
+	app.Get("/events", ConvertFiberHandler(func(c contracts.Context) error {
+		return c.SSEFrom(context.Background(), func(ctx context.Context, events chan<- contracts.SSEEvent) {
+			for _, data := range []string{"1", "2"} {
+				select {
+				case events <- contracts.SSEEvent{Data: data}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/events", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "data: 1\n\ndata: 2\n\n", string(body))
+}
+
+// disconnectedWriter fails every write, like a connection closed by the client
+type disconnectedWriter struct{}
+
+func (disconnectedWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func Test_StreamSSE_Stops_Producer_When_Client_Disconnects(t *testing.T) {
+	exited := make(chan struct{})
+	produce := func(ctx context.Context, events chan<- contracts.SSEEvent) {
+		defer close(exited)
+		for {
+			select {
+			case events <- contracts.SSEEvent{Data: "tick"}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	streamSSE(context.Background(), bufio.NewWriter(disconnectedWriter{}), produce, time.Minute)
+
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("producer still running after the client disconnected")
+	}
+}
+
+func Test_SSEEvent_WriteTo_Strips_Line_Breaks_From_Fields(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := contracts.SSEEvent{Event: "a\nb", ID: "1\r\n2", Data: "x"}.WriteTo(&buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, "id: 12\nevent: ab\ndata: x\n\n", buf.String())
+}
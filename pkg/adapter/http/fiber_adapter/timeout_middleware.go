@@ -0,0 +1,55 @@
+package customfiber
+
+import (
+	"context"
+	stdErrors "errors"
+	"time"
+
+	appConfig "github.com/phatnt199/go-infra/pkg/application/config"
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultRequestTimeout mirrors the APP_TIMEOUT default used by config.Load
+const defaultRequestTimeout = 30 * time.Second
+
+// TimeoutMiddleware bounds every request with a deadline, independent of the server
+// read/write timeouts. The deadline is attached to the request's user context, so
+// downstream calls using `WithContext(c.UserContext())` are cancelled once it expires.
+// When the deadline is exceeded the request fails with a `CodeTimeout` AppError (503).
+//
+// If d is not positive, the timeout is sourced from config.AppConfig.Timeout of the
+// global configuration, falling back to 30 seconds when no configuration is loaded.
+func TimeoutMiddleware(d time.Duration) fiber.Handler {
+	if d <= 0 {
+		d = requestTimeoutFromConfig()
+	}
+
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), d)
+		defer cancel()
+
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		if stdErrors.Is(ctx.Err(), context.DeadlineExceeded) ||
+			stdErrors.Is(err, context.DeadlineExceeded) {
+			return errors.Wrap(context.DeadlineExceeded, errors.CodeTimeout).
+				WithContext("timeout", d.String()).
+				WithContext("path", c.Path())
+		}
+
+		return err
+	}
+}
+
+// requestTimeoutFromConfig returns the application timeout from the global config
+func requestTimeoutFromConfig() time.Duration {
+	if cfg := appConfig.Get(); cfg != nil && cfg.App.Timeout > 0 {
+		return cfg.App.Timeout
+	}
+
+	return defaultRequestTimeout
+}
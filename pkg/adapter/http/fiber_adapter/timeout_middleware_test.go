@@ -0,0 +1,60 @@
+package customfiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/fiber_adapter/handlers"
+	"github.com/phatnt199/go-infra/pkg/json"
+	defaultLogger "github.com/phatnt199/go-infra/pkg/logger/default_logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestApp() *fiber.App {
+	return fiber.New(fiber.Config{
+		JSONDecoder: json.UnmarshalUseNumber, // as NewFiberHttpServer
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return handlers.ProblemDetailErrorHandlerFunc(err, c, defaultLogger.GetLogger())
+		},
+	})
+}
+
+func Test_Timeout_Middleware_Returns_Service_Unavailable_For_Sleepy_Handler(t *testing.T) {
+	app := newTestApp()
+	app.Use(TimeoutMiddleware(50 * time.Millisecond))
+	app.Get("/sleepy", func(c *fiber.Ctx) error {
+		select {
+		case <-c.UserContext().Done():
+			return c.UserContext().Err()
+		case <-time.After(2 * time.Second):
+			return c.SendString("too late")
+		}
+	})
+
+	start := time.Now()
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/sleepy", nil), 5000)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func Test_Timeout_Middleware_Passes_Fast_Handler(t *testing.T) {
+	app := newTestApp()
+	app.Use(TimeoutMiddleware(time.Second))
+	app.Get("/fast", func(c *fiber.Ctx) error {
+		_, hasDeadline := c.UserContext().Deadline()
+		assert.True(t, hasDeadline)
+		return c.SendString("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/fast", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
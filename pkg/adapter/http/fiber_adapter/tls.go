@@ -0,0 +1,167 @@
+package customfiber
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/fiber_adapter/config"
+	appConfig "github.com/phatnt199/go-infra/pkg/application/config"
+)
+
+// certCheckInterval bounds how often CertReloader looks for changed certificate files
+const certCheckInterval = 10 * time.Second
+
+// CertReloader serves a certificate/key pair from disk and reloads it when either file
+// changes, so certificates can be rotated without restarting the server. If a reload
+// fails (e.g. the files are mid-rotation) the previous certificate keeps being served.
+type CertReloader struct {
+	certFile      string
+	keyFile       string
+	checkInterval time.Duration
+
+	mu          sync.RWMutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+	lastCheck   time.Time
+}
+
+// NewCertReloader loads the certificate/key pair, failing when it cannot be read.
+//
+// Example:
+//
+//	reloader, err := customfiber.NewCertReloader("/etc/tls/tls.crt", "/etc/tls/tls.key")
+//	tlsConfig := &tls.Config{GetCertificate: reloader.GetCertificate}
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile, checkInterval: certCheckInterval}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.reloadIfChanged()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// reloadIfChanged reloads the pair if a file changed since the last load
+func (r *CertReloader) reloadIfChanged() {
+	r.mu.RLock()
+	due := time.Since(r.lastCheck) >= r.checkInterval
+	certModTime, keyModTime := r.certModTime, r.keyModTime
+	r.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	certInfo, certErr := os.Stat(r.certFile)
+	keyInfo, keyErr := os.Stat(r.keyFile)
+	if certErr == nil && keyErr == nil &&
+		(!certInfo.ModTime().Equal(certModTime) || !keyInfo.ModTime().Equal(keyModTime)) {
+		// keep serving the previous certificate if the new pair is not loadable yet
+		_ = r.reload()
+	}
+
+	r.mu.Lock()
+	r.lastCheck = time.Now()
+	r.mu.Unlock()
+}
+
+// reload loads the pair from disk, recording the files' modification times
+func (r *CertReloader) reload() error {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return fmt.Errorf("tls cert file: %w", err)
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return fmt.Errorf("tls key file: %w", err)
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load tls key pair: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.certModTime = certInfo.ModTime()
+	r.keyModTime = keyInfo.ModTime()
+	r.lastCheck = time.Now()
+	return nil
+}
+
+// NewTLSConfig builds a server TLS configuration from opts: TLS 1.2+, certificates
+// served by a CertReloader and, when opts.ClientCAFile is set, mutual TLS requiring
+// client certificates signed by that CA.
+//
+// Example:
+//
+//	tlsConfig, err := customfiber.NewTLSConfig(config.TLSOptions{
+//	    Enabled:      true,
+//	    CertFile:     "/etc/tls/tls.crt",
+//	    KeyFile:      "/etc/tls/tls.key",
+//	    ClientCAFile: "/etc/tls/ca.crt",
+//	})
+func NewTLSConfig(opts config.TLSOptions) (*tls.Config, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	reloader, err := NewCertReloader(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if opts.IsMutual() {
+		caPEM, err := os.ReadFile(opts.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls client CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("tls client CA file %s contains no certificates", opts.ClientCAFile)
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// tlsOptions returns the server TLS options, falling back to the TLS settings of the
+// global application config (server.http.tls) when the Fiber options do not enable TLS
+func (s *fiberHttpServer) tlsOptions() config.TLSOptions {
+	if s.config.TLS.Enabled {
+		return s.config.TLS
+	}
+
+	if cfg := appConfig.Get(); cfg != nil && cfg.Server.HTTP.TLS.Enabled {
+		tlsCfg := cfg.Server.HTTP.TLS
+		return config.TLSOptions{
+			Enabled:      true,
+			CertFile:     tlsCfg.CertFile,
+			KeyFile:      tlsCfg.KeyFile,
+			ClientCAFile: tlsCfg.ClientCAFile,
+		}
+	}
+
+	return config.TLSOptions{}
+}
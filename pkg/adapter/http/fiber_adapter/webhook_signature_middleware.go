@@ -0,0 +1,34 @@
+package customfiber
+
+import (
+	"github.com/phatnt199/go-infra/pkg/crypto"
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// VerifyWebhookSignature returns a Fiber middleware that verifies the timestamped
+// HMAC signature of incoming webhooks. The header named headerName must hold a
+// "t=<unix>,v1=<hex signature>" value as produced by crypto.SignHMACWithTimestamp
+// over the raw request body. Requests with a missing, stale or mismatching
+// signature fail with a `CodeUnauthorized` AppError (401).
+//
+// Example:
+//
+//	app.Post("/webhooks/payments", VerifyWebhookSignature("X-Signature", secret), handler)
+func VerifyWebhookSignature(headerName string, secret []byte) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(headerName)
+		if header == "" {
+			return errors.Unauthorized("missing webhook signature").
+				WithContext("header", headerName)
+		}
+
+		if err := crypto.VerifyHMACWithTimestamp(secret, c.Body(), header, crypto.DefaultSignatureTolerance); err != nil {
+			return errors.Wrap(err, errors.CodeUnauthorized).
+				WithContext("header", headerName)
+		}
+
+		return c.Next()
+	}
+}
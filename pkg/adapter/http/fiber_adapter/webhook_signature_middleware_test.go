@@ -0,0 +1,58 @@
+package customfiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/crypto"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebhookTestApp(secret []byte) *fiber.App {
+	app := newTestApp()
+	app.Post("/webhooks", VerifyWebhookSignature("X-Signature", secret), func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusNoContent)
+	})
+	return app
+}
+
+func Test_Verify_Webhook_Signature_Accepts_Signed_Payload(t *testing.T) {
+	secret := []byte("webhook-secret")
+	body := `{"event":"payment.succeeded"}`
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set("X-Signature", crypto.SignHMACWithTimestamp(secret, []byte(body), time.Now()))
+
+	resp, err := newWebhookTestApp(secret).Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func Test_Verify_Webhook_Signature_Rejects_Tampered_Payload(t *testing.T) {
+	secret := []byte("webhook-secret")
+	signature := crypto.SignHMACWithTimestamp(secret, []byte(`{"amount":1000}`), time.Now())
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"amount":9999}`))
+	req.Header.Set("X-Signature", signature)
+
+	resp, err := newWebhookTestApp(secret).Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func Test_Verify_Webhook_Signature_Rejects_Missing_Header(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{}`))
+
+	resp, err := newWebhookTestApp([]byte("webhook-secret")).Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
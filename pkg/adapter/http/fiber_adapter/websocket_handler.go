@@ -0,0 +1,73 @@
+package customfiber
+
+import (
+	"net/http"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/logger"
+	defaultLogger "github.com/phatnt199/go-infra/pkg/logger/default_logger"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebSocketHandler returns a Fiber handler upgrading the request to a WebSocket and
+// serving it with handler through the framework-agnostic contracts.WSConn. Requests
+// that are not WebSocket upgrades fail with a `CodeBadRequest` AppError and status
+// 426. Errors returned by handler, other than the client closing the connection, are
+// logged.
+//
+// Example:
+//
+//	app.Get("/ws/notifications/:userID", customfiber.WebSocketHandler(func(conn contracts.WSConn) error {
+//	    for event := range notifications.Subscribe(conn.Param("userID")) {
+//	        if err := conn.WriteMessage(contracts.TextMessage, event); err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return nil
+//	}))
+func WebSocketHandler(handler contracts.WSHandler, config ...websocket.Config) fiber.Handler {
+	upgrade := websocket.New(func(conn *websocket.Conn) {
+		err := handler(&wsConnAdapter{conn: conn})
+		if err != nil && !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			defaultLogger.GetLogger().ErrorFields("websocket handler failed", logger.Err(err))
+		}
+	}, config...)
+
+	return func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return errors.BadRequest("websocket upgrade required").WithHTTPStatus(http.StatusUpgradeRequired)
+		}
+		return upgrade(c)
+	}
+}
+
+// wsConnAdapter adapts a Fiber WebSocket connection to contracts.WSConn
+type wsConnAdapter struct {
+	conn *websocket.Conn
+}
+
+// Compile-time assertion that wsConnAdapter implements contracts.WSConn
+var _ contracts.WSConn = (*wsConnAdapter)(nil)
+
+func (a *wsConnAdapter) ReadMessage() (int, []byte, error) {
+	return a.conn.ReadMessage()
+}
+
+func (a *wsConnAdapter) WriteMessage(messageType int, data []byte) error {
+	return a.conn.WriteMessage(messageType, data)
+}
+
+func (a *wsConnAdapter) Close() error {
+	return a.conn.Close()
+}
+
+func (a *wsConnAdapter) Param(name string) string {
+	return a.conn.Params(name)
+}
+
+func (a *wsConnAdapter) QueryParam(name string) string {
+	return a.conn.Query(name)
+}
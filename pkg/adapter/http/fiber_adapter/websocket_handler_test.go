@@ -0,0 +1,56 @@
+package customfiber
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WebSocketHandler_Echoes_Messages(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/ws/:room", WebSocketHandler(func(conn contracts.WSConn) error {
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return err
+			}
+			reply := append([]byte(conn.Param("room")+"/"+conn.QueryParam("user")+": "), data...)
+			if err := conn.WriteMessage(messageType, reply); err != nil {
+				return err
+			}
+		}
+	}))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = app.Listener(listener) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	conn, _, err := fastws.DefaultDialer.Dial("ws://"+listener.Addr().String()+"/ws/lobby?user=ann", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteMessage(fastws.TextMessage, []byte("hello")))
+
+	messageType, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, contracts.TextMessage, messageType)
+	assert.Equal(t, "lobby/ann: hello", string(data))
+}
+
+func Test_WebSocketHandler_Rejects_Plain_Requests(t *testing.T) {
+	app := newTestApp()
+	app.Get("/ws", WebSocketHandler(func(conn contracts.WSConn) error { return nil }))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/ws", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, 426, resp.StatusCode)
+}
@@ -8,6 +8,7 @@ import (
 
 	customErrors "github.com/phatnt199/go-infra/pkg/adapter/http/httperrors/customerrors"
 	"github.com/phatnt199/go-infra/pkg/application/constants"
+	appErrors "github.com/phatnt199/go-infra/pkg/errors"
 	typeMapper "github.com/phatnt199/go-infra/pkg/reflection/typemapper"
 	utils "github.com/phatnt199/go-infra/pkg/utils"
 
@@ -96,6 +97,15 @@ func ParseError(err error) ProblemDetailErr {
 			return NewInternalServerProblemDetail(err.Error(), stackTrace)
 		}
 	} else if err != nil && customErr == nil {
+		// AppError carries its own HTTP status derived from its error code
+		if appErr, ok := appErrors.As(err); ok {
+			return NewProblemDetailFromCodeAndDetail(
+				appErr.GetHTTPStatus(),
+				appErr.Error(),
+				stackTrace,
+			)
+		}
+
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			return NewNotFoundErrorProblemDetail(err.Error(), stackTrace)
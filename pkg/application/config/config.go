@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
-	"os"
+	"maps"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,16 +21,17 @@ type Config struct {
 	Storage  StorageConfig  `json:"storage"`
 	Logger   LoggerConfig   `json:"logger"`
 	Auth     AuthConfig     `json:"auth"`
+	Features FeaturesConfig `json:"features"`
 }
 
 // AppConfig contains general application settings
 type AppConfig struct {
-	Name        string        `json:"name"`
-	Version     string        `json:"version"`
-	Environment string        `json:"environment"` // development, staging, production
-	Debug       bool          `json:"debug"`
-	Timezone    string        `json:"timezone"`
-	Timeout     time.Duration `json:"timeout"`
+	Name        string        `json:"name" env:"APP_NAME" default:"go-app"`
+	Version     string        `json:"version" env:"APP_VERSION" default:"1.0.0"`
+	Environment string        `json:"environment" env:"APP_ENV" default:"development"` // development, staging, production
+	Debug       bool          `json:"debug" env:"APP_DEBUG" default:"false"`
+	Timezone    string        `json:"timezone" env:"APP_TIMEZONE" default:"UTC"`
+	Timeout     time.Duration `json:"timeout" env:"APP_TIMEOUT" default:"30s"`
 }
 
 // ServerConfig contains HTTP/gRPC server settings
@@ -39,105 +42,111 @@ type ServerConfig struct {
 
 // HTTPConfig contains HTTP server settings
 type HTTPConfig struct {
-	Host            string        `json:"host"`
-	Port            int           `json:"port"`
-	ReadTimeout     time.Duration `json:"read_timeout"`
-	WriteTimeout    time.Duration `json:"write_timeout"`
-	IdleTimeout     time.Duration `json:"idle_timeout"`
-	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
+	Host            string        `json:"host" env:"HTTP_HOST" default:"0.0.0.0"`
+	Port            int           `json:"port" env:"HTTP_PORT" default:"8080"`
+	ReadTimeout     time.Duration `json:"read_timeout" env:"HTTP_READ_TIMEOUT" default:"10s"`
+	WriteTimeout    time.Duration `json:"write_timeout" env:"HTTP_WRITE_TIMEOUT" default:"10s"`
+	IdleTimeout     time.Duration `json:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" default:"120s"`
+	ShutdownTimeout time.Duration `json:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT" default:"15s"`
 	CORS            CORSConfig    `json:"cors"`
 	TLS             TLSConfig     `json:"tls"`
 }
 
 // GRPCConfig contains gRPC server settings
 type GRPCConfig struct {
-	Host                  string        `json:"host"`
-	Port                  int           `json:"port"`
-	MaxConnectionIdle     time.Duration `json:"max_connection_idle"`
-	MaxConnectionAge      time.Duration `json:"max_connection_age"`
-	MaxConnectionAgeGrace time.Duration `json:"max_connection_age_grace"`
-	KeepAliveTime         time.Duration `json:"keepalive_time"`
-	KeepAliveTimeout      time.Duration `json:"keepalive_timeout"`
+	Host                  string        `json:"host" env:"GRPC_HOST" default:"0.0.0.0"`
+	Port                  int           `json:"port" env:"GRPC_PORT" default:"9090"`
+	MaxConnectionIdle     time.Duration `json:"max_connection_idle" env:"GRPC_MAX_CONNECTION_IDLE" default:"5m"`
+	MaxConnectionAge      time.Duration `json:"max_connection_age" env:"GRPC_MAX_CONNECTION_AGE" default:"30m"`
+	MaxConnectionAgeGrace time.Duration `json:"max_connection_age_grace" env:"GRPC_MAX_CONNECTION_AGE_GRACE" default:"5m"`
+	KeepAliveTime         time.Duration `json:"keepalive_time" env:"GRPC_KEEPALIVE_TIME" default:"2h"`
+	KeepAliveTimeout      time.Duration `json:"keepalive_timeout" env:"GRPC_KEEPALIVE_TIMEOUT" default:"20s"`
 }
 
 // CORSConfig contains CORS settings
 type CORSConfig struct {
-	Enabled          bool     `json:"enabled"`
-	AllowedOrigins   []string `json:"allowed_origins"`
-	AllowedMethods   []string `json:"allowed_methods"`
-	AllowedHeaders   []string `json:"allowed_headers"`
-	ExposedHeaders   []string `json:"exposed_headers"`
-	AllowCredentials bool     `json:"allow_credentials"`
-	MaxAge           int      `json:"max_age"`
+	Enabled          bool     `json:"enabled" env:"CORS_ENABLED" default:"true"`
+	AllowedOrigins   []string `json:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" default:"*"`
+	AllowedMethods   []string `json:"allowed_methods" env:"CORS_ALLOWED_METHODS" default:"GET,POST,PUT,DELETE,OPTIONS"`
+	AllowedHeaders   []string `json:"allowed_headers" env:"CORS_ALLOWED_HEADERS" default:"*"`
+	ExposedHeaders   []string `json:"exposed_headers" env:"CORS_EXPOSED_HEADERS"`
+	AllowCredentials bool     `json:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS" default:"true"`
+	MaxAge           int      `json:"max_age" env:"CORS_MAX_AGE" default:"86400"`
 }
 
 // TLSConfig contains TLS/SSL settings
 type TLSConfig struct {
-	Enabled  bool   `json:"enabled"`
-	CertFile string `json:"cert_file"`
-	KeyFile  string `json:"key_file"`
+	Enabled      bool   `json:"enabled" env:"TLS_ENABLED" default:"false"`
+	CertFile     string `json:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile      string `json:"key_file" env:"TLS_KEY_FILE"`
+	ClientCAFile string `json:"client_ca_file" env:"TLS_CLIENT_CA_FILE"` // enables mutual TLS when set
 }
 
 // DatabaseConfig contains database connection settings
 type DatabaseConfig struct {
-	Driver          string        `json:"driver"` // postgres, mysql, sqlite
-	Host            string        `json:"host"`
-	Port            int           `json:"port"`
-	Username        string        `json:"username"`
-	Password        string        `json:"-"` // Never log passwords
-	Database        string        `json:"database"`
-	SSLMode         string        `json:"ssl_mode"`
-	MaxOpenConns    int           `json:"max_open_conns"`
-	MaxIdleConns    int           `json:"max_idle_conns"`
-	ConnMaxLifetime time.Duration `json:"conn_max_lifetime"`
-	ConnMaxIdleTime time.Duration `json:"conn_max_idle_time"`
-	MigrationPath   string        `json:"migration_path"`
+	Driver          string        `json:"driver" env:"DB_DRIVER" default:"postgres"` // postgres, mysql, sqlite
+	Host            string        `json:"host" env:"DB_HOST" default:"localhost"`
+	Port            int           `json:"port" env:"DB_PORT" default:"5432"`
+	Username        string        `json:"username" env:"DB_USERNAME" default:"postgres"`
+	Password        string        `json:"-" env:"DB_PASSWORD"` // Never log passwords
+	Database        string        `json:"database" env:"DB_DATABASE" default:"myapp"`
+	SSLMode         string        `json:"ssl_mode" env:"DB_SSL_MODE" default:"disable"`
+	MaxOpenConns    int           `json:"max_open_conns" env:"DB_MAX_OPEN_CONNS" default:"25"`
+	MaxIdleConns    int           `json:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" default:"5"`
+	ConnMaxLifetime time.Duration `json:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" default:"5m"`
+	ConnMaxIdleTime time.Duration `json:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" default:"10m"`
+	SlowThreshold   time.Duration `json:"slow_threshold" env:"DB_SLOW_THRESHOLD" default:"200ms"` // Queries slower than this are logged as warnings
+	MigrationPath   string        `json:"migration_path" env:"DB_MIGRATION_PATH" default:"migrations"`
+
+	// Params are extra driver parameters appended to the DSN, e.g. from the query
+	// string of DATABASE_URL
+	Params map[string]string `json:"params,omitempty"`
 }
 
 // RedisConfig contains Redis connection settings
 type RedisConfig struct {
-	Host         string        `json:"host"`
-	Port         int           `json:"port"`
-	Password     string        `json:"-"` // Never log passwords
-	DB           int           `json:"db"`
-	MaxRetries   int           `json:"max_retries"`
-	DialTimeout  time.Duration `json:"dial_timeout"`
-	ReadTimeout  time.Duration `json:"read_timeout"`
-	WriteTimeout time.Duration `json:"write_timeout"`
-	PoolSize     int           `json:"pool_size"`
-	MinIdleConns int           `json:"min_idle_conns"`
-	TLS          bool          `json:"tls"`
+	Host         string        `json:"host" env:"REDIS_HOST" default:"localhost"`
+	Port         int           `json:"port" env:"REDIS_PORT" default:"6379"`
+	Password     string        `json:"-" env:"REDIS_PASSWORD"` // Never log passwords
+	DB           int           `json:"db" env:"REDIS_DB" default:"0"`
+	MaxRetries   int           `json:"max_retries" env:"REDIS_MAX_RETRIES" default:"3"`
+	DialTimeout  time.Duration `json:"dial_timeout" env:"REDIS_DIAL_TIMEOUT" default:"5s"`
+	ReadTimeout  time.Duration `json:"read_timeout" env:"REDIS_READ_TIMEOUT" default:"3s"`
+	WriteTimeout time.Duration `json:"write_timeout" env:"REDIS_WRITE_TIMEOUT" default:"3s"`
+	PoolSize     int           `json:"pool_size" env:"REDIS_POOL_SIZE" default:"10"`
+	MinIdleConns int           `json:"min_idle_conns" env:"REDIS_MIN_IDLE_CONNS" default:"2"`
+	TLS          bool          `json:"tls" env:"REDIS_TLS" default:"false"`
 }
 
 // QueueConfig contains message queue settings
 type QueueConfig struct {
-	Driver      string `json:"driver"` // rabbitmq, kafka, sqs, redis
-	URL         string `json:"url"`
-	MaxRetries  int    `json:"max_retries"`
-	Concurrency int    `json:"concurrency"`
-	Prefetch    int    `json:"prefetch"`
+	Driver      string `json:"driver" env:"QUEUE_DRIVER" default:"redis"` // rabbitmq, kafka, sqs, redis
+	URL         string `json:"url" env:"QUEUE_URL"`
+	MaxRetries  int    `json:"max_retries" env:"QUEUE_MAX_RETRIES" default:"3"`
+	Concurrency int    `json:"concurrency" env:"QUEUE_CONCURRENCY" default:"10"`
+	Prefetch    int    `json:"prefetch" env:"QUEUE_PREFETCH" default:"10"`
 }
 
 // StorageConfig contains object storage settings (S3, MinIO, etc.)
 type StorageConfig struct {
-	Driver          string `json:"driver"` // s3, minio, gcs, local
-	Endpoint        string `json:"endpoint"`
-	Region          string `json:"region"`
-	Bucket          string `json:"bucket"`
-	AccessKeyID     string `json:"access_key_id"`
-	SecretAccessKey string `json:"-"` // Never log secrets
-	UseSSL          bool   `json:"use_ssl"`
-	BasePath        string `json:"base_path"`
+	Driver          string `json:"driver" env:"STORAGE_DRIVER" default:"local"` // s3, minio, gcs, local
+	Endpoint        string `json:"endpoint" env:"STORAGE_ENDPOINT"`
+	Region          string `json:"region" env:"STORAGE_REGION" default:"us-east-1"`
+	Bucket          string `json:"bucket" env:"STORAGE_BUCKET"`
+	AccessKeyID     string `json:"access_key_id" env:"STORAGE_ACCESS_KEY_ID"`
+	SecretAccessKey string `json:"-" env:"STORAGE_SECRET_ACCESS_KEY"` // Never log secrets
+	UseSSL          bool   `json:"use_ssl" env:"STORAGE_USE_SSL" default:"true"`
+	BasePath        string `json:"base_path" env:"STORAGE_BASE_PATH" default:"uploads"`
 }
 
 // LoggerConfig contains logging settings
 type LoggerConfig struct {
-	Level            string   `json:"level"`  // debug, info, warn, error
-	Format           string   `json:"format"` // json, console
-	OutputPaths      []string `json:"output_paths"`
-	ErrorOutputPaths []string `json:"error_output_paths"`
-	EnableCaller     bool     `json:"enable_caller"`
-	EnableStacktrace bool     `json:"enable_stacktrace"`
+	Level            string   `json:"level" env:"LOG_LEVEL" default:"info"`   // debug, info, warn, error
+	Format           string   `json:"format" env:"LOG_FORMAT" default:"json"` // json, console
+	OutputPaths      []string `json:"output_paths" env:"LOG_OUTPUT_PATHS" default:"stdout"`
+	ErrorOutputPaths []string `json:"error_output_paths" env:"LOG_ERROR_OUTPUT_PATHS" default:"stderr"`
+	EnableCaller     bool     `json:"enable_caller" env:"LOG_ENABLE_CALLER" default:"true"`
+	EnableStacktrace bool     `json:"enable_stacktrace" env:"LOG_ENABLE_STACKTRACE" default:"true"`
 }
 
 // AuthConfig contains authentication and authorization settings
@@ -150,69 +159,75 @@ type AuthConfig struct {
 
 // JWTConfig contains JWT token settings
 type JWTConfig struct {
-	Secret         string        `json:"-"` // Never log secrets
-	Issuer         string        `json:"issuer"`
-	Audience       string        `json:"audience"`
-	AccessExpiry   time.Duration `json:"access_expiry"`
-	RefreshExpiry  time.Duration `json:"refresh_expiry"`
-	Algorithm      string        `json:"algorithm"` // HS256, RS256
-	PrivateKeyPath string        `json:"private_key_path"`
-	PublicKeyPath  string        `json:"public_key_path"`
+	Secret         string        `json:"-" env:"JWT_SECRET"` // Never log secrets
+	Issuer         string        `json:"issuer" env:"JWT_ISSUER" default:"go-infra"`
+	Audience       string        `json:"audience" env:"JWT_AUDIENCE" default:"go-infra-api"`
+	AccessExpiry   time.Duration `json:"access_expiry" env:"JWT_ACCESS_EXPIRY" default:"15m"`
+	RefreshExpiry  time.Duration `json:"refresh_expiry" env:"JWT_REFRESH_EXPIRY" default:"168h"`
+	Algorithm      string        `json:"algorithm" env:"JWT_ALGORITHM" default:"HS256"` // HS256, RS256
+	PrivateKeyPath string        `json:"private_key_path" env:"JWT_PRIVATE_KEY_PATH"`
+	PublicKeyPath  string        `json:"public_key_path" env:"JWT_PUBLIC_KEY_PATH"`
 }
 
 // OAuthConfig contains OAuth settings
 type OAuthConfig struct {
-	Google   OAuthProvider `json:"google"`
-	GitHub   OAuthProvider `json:"github"`
-	Facebook OAuthProvider `json:"facebook"`
+	Google   OAuthProvider `json:"google" envPrefix:"OAUTH_GOOGLE_" default:"SCOPES=email,profile"`
+	GitHub   OAuthProvider `json:"github" envPrefix:"OAUTH_GITHUB_" default:"SCOPES=user:email"`
+	Facebook OAuthProvider `json:"facebook" envPrefix:"OAUTH_FACEBOOK_" default:"SCOPES=email"`
 }
 
 // OAuthProvider contains OAuth provider settings
 type OAuthProvider struct {
-	Enabled      bool     `json:"enabled"`
-	ClientID     string   `json:"client_id"`
-	ClientSecret string   `json:"-"` // Never log secrets
-	RedirectURL  string   `json:"redirect_url"`
-	Scopes       []string `json:"scopes"`
+	Enabled      bool     `json:"enabled" env:"ENABLED" default:"false"`
+	ClientID     string   `json:"client_id" env:"CLIENT_ID"`
+	ClientSecret string   `json:"-" env:"CLIENT_SECRET"` // Never log secrets
+	RedirectURL  string   `json:"redirect_url" env:"REDIRECT_URL"`
+	Scopes       []string `json:"scopes" env:"SCOPES"`
 }
 
 // SessionConfig contains session settings
 type SessionConfig struct {
-	CookieName string        `json:"cookie_name"`
-	Secret     string        `json:"-"` // Never log secrets
-	MaxAge     time.Duration `json:"max_age"`
-	Secure     bool          `json:"secure"`
-	HTTPOnly   bool          `json:"http_only"`
-	SameSite   string        `json:"same_site"` // strict, lax, none
+	CookieName string        `json:"cookie_name" env:"SESSION_COOKIE_NAME" default:"session"`
+	Secret     string        `json:"-" env:"SESSION_SECRET"` // Never log secrets
+	MaxAge     time.Duration `json:"max_age" env:"SESSION_MAX_AGE" default:"24h"`
+	Secure     bool          `json:"secure" env:"SESSION_SECURE" default:"false"`
+	HTTPOnly   bool          `json:"http_only" env:"SESSION_HTTP_ONLY" default:"true"`
+	SameSite   string        `json:"same_site" env:"SESSION_SAME_SITE" default:"lax"` // strict, lax, none
 }
 
 // PasswordConfig contains password hashing settings
 type PasswordConfig struct {
-	MinLength      int  `json:"min_length"`
-	RequireUpper   bool `json:"require_upper"`
-	RequireLower   bool `json:"require_lower"`
-	RequireNumber  bool `json:"require_number"`
-	RequireSpecial bool `json:"require_special"`
-	BcryptCost     int  `json:"bcrypt_cost"`
+	MinLength      int  `json:"min_length" env:"PASSWORD_MIN_LENGTH" default:"8"`
+	RequireUpper   bool `json:"require_upper" env:"PASSWORD_REQUIRE_UPPER" default:"true"`
+	RequireLower   bool `json:"require_lower" env:"PASSWORD_REQUIRE_LOWER" default:"true"`
+	RequireNumber  bool `json:"require_number" env:"PASSWORD_REQUIRE_NUMBER" default:"true"`
+	RequireSpecial bool `json:"require_special" env:"PASSWORD_REQUIRE_SPECIAL" default:"true"`
+	BcryptCost     int  `json:"bcrypt_cost" env:"PASSWORD_BCRYPT_COST" default:"12"`
+	MinStrength    int  `json:"min_strength" env:"PASSWORD_MIN_STRENGTH" default:"0"` // crypto.PasswordPolicy score, 0 disables
+}
+
+// FeaturesConfig contains runtime feature flags, see pkg/featureflag
+type FeaturesConfig struct {
+	// Flags maps flag keys to values: "true"/"false", a percentage rollout such as
+	// "25%", or any string
+	Flags map[string]string `json:"flags" env:"FEATURE_FLAGS"`
 }
 
 var (
 	globalConfig *Config
 	configOnce   sync.Once
 	configMu     sync.RWMutex
+
+	changeListeners  = map[int]func(previous, current *Config){}
+	nextListenerID   int
+	changeListenerMu sync.Mutex
 )
 
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
-	config := &Config{
-		App:      loadAppConfig(),
-		Server:   loadServerConfig(),
-		Database: loadDatabaseConfig(),
-		Redis:    loadRedisConfig(),
-		Queue:    loadQueueConfig(),
-		Storage:  loadStorageConfig(),
-		Logger:   loadLoggerConfig(),
-		Auth:     loadAuthConfig(),
+	config, err := loadEnvConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
 	// Validate configuration
@@ -223,6 +238,30 @@ func Load() (*Config, error) {
 	return config, nil
 }
 
+// loadEnvConfig loads configuration from environment variables, without validating it
+func loadEnvConfig() (*Config, error) {
+	if err := checkInterpolation(); err != nil {
+		return nil, err
+	}
+
+	database, err := loadDatabaseConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{
+		App:      loadAppConfig(),
+		Server:   loadServerConfig(),
+		Database: database,
+		Redis:    loadRedisConfig(),
+		Queue:    loadQueueConfig(),
+		Storage:  loadStorageConfig(),
+		Logger:   loadLoggerConfig(),
+		Auth:     loadAuthConfig(),
+		Features: loadFeaturesConfig(),
+	}, nil
+}
+
 // LoadOnce loads configuration once and caches it
 func LoadOnce() (*Config, error) {
 	var err error
@@ -239,11 +278,51 @@ func Get() *Config {
 	return globalConfig
 }
 
-// Set sets the global configuration (useful for testing)
+// Set sets the global configuration (useful for testing, or to apply a reloaded
+// configuration) and notifies the OnChange listeners
 func Set(config *Config) {
 	configMu.Lock()
-	defer configMu.Unlock()
+	old := globalConfig
 	globalConfig = config
+	configMu.Unlock()
+
+	changeListenerMu.Lock()
+	listeners := make([]func(previous, current *Config), 0, len(changeListeners))
+	for _, fn := range changeListeners {
+		listeners = append(listeners, fn)
+	}
+	changeListenerMu.Unlock()
+
+	for _, fn := range listeners {
+		fn(old, config)
+	}
+}
+
+// OnChange registers fn to be called with the previous and new configuration whenever
+// Set replaces the global configuration, e.g. to hot reload values after re-running
+// Load. It returns a function removing the listener.
+//
+// Example:
+//
+//	stop := config.OnChange(func(previous, current *config.Config) {
+//	    for _, change := range previous.Diff(current) {
+//	        log.Printf("config changed: %s", change.Path)
+//	    }
+//	})
+//	defer stop()
+func OnChange(fn func(previous, current *Config)) func() {
+	changeListenerMu.Lock()
+	defer changeListenerMu.Unlock()
+
+	id := nextListenerID
+	nextListenerID++
+	changeListeners[id] = fn
+
+	return func() {
+		changeListenerMu.Lock()
+		defer changeListenerMu.Unlock()
+		delete(changeListeners, id)
+	}
 }
 
 // loadAppConfig loads application configuration from environment
@@ -278,9 +357,10 @@ func loadServerConfig() ServerConfig {
 				MaxAge:           getEnvAsInt("CORS_MAX_AGE", 86400),
 			},
 			TLS: TLSConfig{
-				Enabled:  getEnvAsBool("TLS_ENABLED", false),
-				CertFile: getEnv("TLS_CERT_FILE", ""),
-				KeyFile:  getEnv("TLS_KEY_FILE", ""),
+				Enabled:      getEnvAsBool("TLS_ENABLED", false),
+				CertFile:     getEnv("TLS_CERT_FILE", ""),
+				KeyFile:      getEnv("TLS_KEY_FILE", ""),
+				ClientCAFile: getEnv("TLS_CLIENT_CA_FILE", ""),
 			},
 		},
 		GRPC: GRPCConfig{
@@ -296,8 +376,8 @@ func loadServerConfig() ServerConfig {
 }
 
 // loadDatabaseConfig loads database configuration from environment
-func loadDatabaseConfig() DatabaseConfig {
-	return DatabaseConfig{
+func loadDatabaseConfig() (DatabaseConfig, error) {
+	cfg := DatabaseConfig{
 		Driver:          getEnv("DB_DRIVER", "postgres"),
 		Host:            getEnv("DB_HOST", "localhost"),
 		Port:            getEnvAsInt("DB_PORT", 5432),
@@ -309,8 +389,38 @@ func loadDatabaseConfig() DatabaseConfig {
 		MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
 		ConnMaxLifetime: getEnvAsDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
 		ConnMaxIdleTime: getEnvAsDuration("DB_CONN_MAX_IDLE_TIME", 10*time.Minute),
+		SlowThreshold:   getEnvAsDuration("DB_SLOW_THRESHOLD", 200*time.Millisecond),
 		MigrationPath:   getEnv("DB_MIGRATION_PATH", "migrations"),
 	}
+
+	// DATABASE_URL takes precedence over the discrete connection variables
+	databaseURL := getEnv("DATABASE_URL", "")
+	if databaseURL == "" {
+		return cfg, nil
+	}
+
+	parsed, err := ParseDatabaseURL(databaseURL)
+	if err != nil {
+		return DatabaseConfig{}, err
+	}
+
+	cfg.applyURL(parsed)
+	return cfg, nil
+}
+
+// applyURL overrides the connection settings with the ones parsed from a
+// DATABASE_URL, keeping the pool settings
+func (d *DatabaseConfig) applyURL(parsed DatabaseConfig) {
+	d.Driver = parsed.Driver
+	d.Host = parsed.Host
+	d.Port = parsed.Port
+	d.Username = parsed.Username
+	d.Password = parsed.Password
+	d.Database = parsed.Database
+	d.Params = parsed.Params
+	if parsed.SSLMode != "" {
+		d.SSLMode = parsed.SSLMode
+	}
 }
 
 // loadRedisConfig loads Redis configuration from environment
@@ -431,15 +541,32 @@ func loadAuthConfig() AuthConfig {
 			RequireNumber:  getEnvAsBool("PASSWORD_REQUIRE_NUMBER", true),
 			RequireSpecial: getEnvAsBool("PASSWORD_REQUIRE_SPECIAL", true),
 			BcryptCost:     getEnvAsInt("PASSWORD_BCRYPT_COST", 12),
+			MinStrength:    getEnvAsInt("PASSWORD_MIN_STRENGTH", 0),
 		},
 	}
 }
 
+// loadFeaturesConfig loads feature flags from FEATURE_FLAGS, a comma separated list
+// of key=value pairs (e.g. "new_checkout=true,beta_banner=25%")
+func loadFeaturesConfig() FeaturesConfig {
+	flags := map[string]string{}
+	for _, pair := range getEnvAsSlice("FEATURE_FLAGS", nil) {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		flags[key] = strings.TrimSpace(value)
+	}
+
+	return FeaturesConfig{Flags: flags}
+}
+
 // Helper functions for environment variable parsing
 
 // getEnv gets an environment variable or returns a default value
 func getEnv(key string, defaultValue string) string {
-	value := os.Getenv(key)
+	value := lookupEnv(key)
 	if value == "" {
 		return defaultValue
 	}
@@ -448,7 +575,7 @@ func getEnv(key string, defaultValue string) string {
 
 // getEnvAsInt gets an environment variable as an integer
 func getEnvAsInt(key string, defaultValue int) int {
-	valueStr := os.Getenv(key)
+	valueStr := lookupEnv(key)
 	if valueStr == "" {
 		return defaultValue
 	}
@@ -461,7 +588,7 @@ func getEnvAsInt(key string, defaultValue int) int {
 
 // getEnvAsBool gets an environment variable as a boolean
 func getEnvAsBool(key string, defaultValue bool) bool {
-	valueStr := os.Getenv(key)
+	valueStr := lookupEnv(key)
 	if valueStr == "" {
 		return defaultValue
 	}
@@ -474,7 +601,7 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 
 // getEnvAsDuration gets an environment variable as a duration
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
-	valueStr := os.Getenv(key)
+	valueStr := lookupEnv(key)
 	if valueStr == "" {
 		return defaultValue
 	}
@@ -487,7 +614,7 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 
 // getEnvAsSlice gets an environment variable as a slice (comma-separated)
 func getEnvAsSlice(key string, defaultValue []string) []string {
-	valueStr := os.Getenv(key)
+	valueStr := lookupEnv(key)
 	if valueStr == "" {
 		return defaultValue
 	}
@@ -504,19 +631,28 @@ func (g GRPCConfig) Address() string {
 	return fmt.Sprintf("%s:%d", g.Host, g.Port)
 }
 
-// DSN returns the database connection string
+// DSN returns the database connection string. Params values are quoted for libpq, or
+// query-escaped for MySQL, so they can't add or change other settings.
 func (d DatabaseConfig) DSN() string {
 	switch d.Driver {
 	case "postgres":
-		return fmt.Sprintf(
+		dsn := fmt.Sprintf(
 			"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 			d.Host, d.Port, d.Username, d.Password, d.Database, d.SSLMode,
 		)
+		for _, key := range slices.Sorted(maps.Keys(d.Params)) {
+			dsn += fmt.Sprintf(" %s=%s", key, quoteLibpqValue(d.Params[key]))
+		}
+		return dsn
 	case "mysql":
-		return fmt.Sprintf(
+		dsn := fmt.Sprintf(
 			"%s:%s@tcp(%s:%d)/%s?parseTime=true",
 			d.Username, d.Password, d.Host, d.Port, d.Database,
 		)
+		for _, key := range slices.Sorted(maps.Keys(d.Params)) {
+			dsn += fmt.Sprintf("&%s=%s", key, url.QueryEscape(d.Params[key]))
+		}
+		return dsn
 	case "sqlite":
 		return d.Database
 	default:
@@ -524,6 +660,12 @@ func (d DatabaseConfig) DSN() string {
 	}
 }
 
+// quoteLibpqValue quotes value for a libpq keyword/value connection string, escaping
+// backslashes and single quotes
+func quoteLibpqValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
 // Address returns the Redis address (host:port)
 func (r RedisConfig) Address() string {
 	return fmt.Sprintf("%s:%d", r.Host, r.Port)
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// databaseURLSchemes maps URL schemes to database drivers
+var databaseURLSchemes = map[string]string{
+	"postgres":   "postgres",
+	"postgresql": "postgres",
+	"mysql":      "mysql",
+	"sqlite":     "sqlite",
+	"sqlite3":    "sqlite",
+}
+
+// defaultDatabasePorts are used when the URL does not specify a port
+var defaultDatabasePorts = map[string]int{
+	"postgres": 5432,
+	"mysql":    3306,
+}
+
+// ParseDatabaseURL parses a connection URL into the connection fields of a
+// DatabaseConfig: driver, host, port, credentials and database name. The sslmode
+// query parameter sets SSLMode; any other query parameters are kept in Params and
+// passed through by DSN. Pool settings are left zero.
+//
+// SQLite URLs carry the database file path: "sqlite:///var/lib/app.db" or
+// "sqlite://app.db" for a relative path.
+//
+// Example:
+//
+//	db, err := config.ParseDatabaseURL("postgres://app:secret@db:5432/orders?sslmode=require")
+//	// db.Driver == "postgres", db.Host == "db", db.SSLMode == "require"
+func ParseDatabaseURL(rawURL string) (DatabaseConfig, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return DatabaseConfig{}, &ValidationError{Field: "database.url", Message: fmt.Sprintf("invalid URL: %v", err)}
+	}
+
+	driver, ok := databaseURLSchemes[strings.ToLower(u.Scheme)]
+	if !ok {
+		return DatabaseConfig{}, &ValidationError{
+			Field:   "database.url",
+			Message: fmt.Sprintf("unsupported scheme %q, must be one of: postgres, postgresql, mysql, sqlite", u.Scheme),
+		}
+	}
+
+	cfg := DatabaseConfig{Driver: driver}
+
+	if driver == "sqlite" {
+		cfg.Database = u.Host + u.Path
+		if cfg.Database == "" {
+			return DatabaseConfig{}, &ValidationError{Field: "database.url", Message: "sqlite URL must include a database path"}
+		}
+		return cfg, nil
+	}
+
+	cfg.Host = u.Hostname()
+	if cfg.Host == "" {
+		return DatabaseConfig{}, &ValidationError{Field: "database.url", Message: "host is required"}
+	}
+
+	cfg.Port = defaultDatabasePorts[driver]
+	if port := u.Port(); port != "" {
+		cfg.Port, err = strconv.Atoi(port)
+		if err != nil || cfg.Port < 1 || cfg.Port > 65535 {
+			return DatabaseConfig{}, &ValidationError{Field: "database.url", Message: fmt.Sprintf("invalid port %q", port)}
+		}
+	}
+
+	if u.User != nil {
+		cfg.Username = u.User.Username()
+		cfg.Password, _ = u.User.Password()
+	}
+
+	cfg.Database = strings.TrimPrefix(u.Path, "/")
+	if cfg.Database == "" {
+		return DatabaseConfig{}, &ValidationError{Field: "database.url", Message: "database name is required"}
+	}
+
+	for key, values := range u.Query() {
+		if len(values) == 0 {
+			continue
+		}
+		if key == "sslmode" {
+			cfg.SSLMode = values[len(values)-1]
+			continue
+		}
+		if cfg.Params == nil {
+			cfg.Params = map[string]string{}
+		}
+		cfg.Params[key] = values[len(values)-1]
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// RedactedValue replaces secret values in config output such as Diff
+const RedactedValue = "[REDACTED]"
+
+// ConfigChange describes a single changed configuration value
+type ConfigChange struct {
+	Path string      // Dotted path, e.g. "server.http.port"
+	Old  interface{} // Previous value (RedactedValue for secrets)
+	New  interface{} // New value (RedactedValue for secrets)
+}
+
+// Diff returns the values that differ between c and other, in field order.
+// Paths use the json names of the fields (e.g. "server.http.port"). Secret fields,
+// i.e. the ones tagged `json:"-"`, are reported as changed without revealing values.
+//
+// Example:
+//
+//	for _, change := range oldCfg.Diff(newCfg) {
+//	    log.Infow("config changed", logger.Fields{"path": change.Path, "old": change.Old, "new": change.New})
+//	}
+func (c *Config) Diff(other *Config) []ConfigChange {
+	if c == nil {
+		c = &Config{}
+	}
+	if other == nil {
+		other = &Config{}
+	}
+
+	var changes []ConfigChange
+	diffValues("", reflect.ValueOf(*c), reflect.ValueOf(*other), false, &changes)
+	return changes
+}
+
+// diffValues recursively compares two values of the same type, recording changed leaves
+func diffValues(path string, oldValue, newValue reflect.Value, secret bool, changes *[]ConfigChange) {
+	if oldValue.Kind() == reflect.Struct && !secret {
+		t := oldValue.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name, isSecret := configFieldName(field)
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			diffValues(fieldPath, oldValue.Field(i), newValue.Field(i), isSecret, changes)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(oldValue.Interface(), newValue.Interface()) {
+		return
+	}
+
+	change := ConfigChange{Path: path, Old: oldValue.Interface(), New: newValue.Interface()}
+	if secret {
+		change.Old, change.New = RedactedValue, RedactedValue
+	}
+	*changes = append(*changes, change)
+}
+
+// configFieldName returns the json name of a field and whether it holds a secret.
+// Fields tagged `json:"-"` are secrets and are named after their snake_cased Go name.
+func configFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return toSnakeCase(field.Name), true
+	}
+
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, false
+	}
+	return toSnakeCase(field.Name), false
+}
+
+// toSnakeCase converts a Go field name such as SecretAccessKey to secret_access_key
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word unless we're inside an acronym (e.g. the "D" in "ID")
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
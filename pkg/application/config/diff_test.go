@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newDiffTestConfig() *Config {
+	return &Config{
+		App:      AppConfig{Name: "app", Environment: "development", Timeout: 30 * time.Second},
+		Server:   ServerConfig{HTTP: HTTPConfig{Port: 8080, CORS: CORSConfig{AllowedOrigins: []string{"*"}}}},
+		Database: DatabaseConfig{Host: "localhost", Password: "old-password"},
+		Storage:  StorageConfig{SecretAccessKey: "old-key"},
+	}
+}
+
+func Test_Config_Diff(t *testing.T) {
+	oldCfg := newDiffTestConfig()
+	newCfg := newDiffTestConfig()
+	newCfg.Server.HTTP.Port = 9090
+	newCfg.Server.HTTP.CORS.AllowedOrigins = []string{"https://example.com"}
+	newCfg.App.Timeout = time.Minute
+	newCfg.Database.Password = "new-password"
+	newCfg.Storage.SecretAccessKey = "new-key"
+
+	assert.Equal(t, []ConfigChange{
+		{Path: "app.timeout", Old: 30 * time.Second, New: time.Minute},
+		{Path: "server.http.port", Old: 8080, New: 9090},
+		{Path: "server.http.cors.allowed_origins", Old: []string{"*"}, New: []string{"https://example.com"}},
+		{Path: "database.password", Old: RedactedValue, New: RedactedValue},
+		{Path: "storage.secret_access_key", Old: RedactedValue, New: RedactedValue},
+	}, oldCfg.Diff(newCfg))
+}
+
+func Test_Config_Diff_Identical_And_Nil(t *testing.T) {
+	assert.Empty(t, newDiffTestConfig().Diff(newDiffTestConfig()))
+
+	changes := newDiffTestConfig().Diff(nil)
+	assert.Contains(t, changes, ConfigChange{Path: "app.name", Old: "app", New: ""})
+	assert.Contains(t, changes, ConfigChange{Path: "database.password", Old: RedactedValue, New: RedactedValue})
+}
+
+func Test_To_Snake_Case(t *testing.T) {
+	assert.Equal(t, "password", toSnakeCase("Password"))
+	assert.Equal(t, "secret_access_key", toSnakeCase("SecretAccessKey"))
+	assert.Equal(t, "client_id", toSnakeCase("ClientID"))
+	assert.Equal(t, "http_only", toSnakeCase("HTTPOnly"))
+}
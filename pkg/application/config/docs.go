@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/json"
+)
+
+// DocsFormat selects the output format of WriteEnvDocs
+type DocsFormat string
+
+const (
+	DocsFormatMarkdown DocsFormat = "markdown"
+	DocsFormatJSON     DocsFormat = "json"
+)
+
+// EnvVar documents a single environment variable read by Load
+type EnvVar struct {
+	Name    string `json:"name"`              // Environment variable, e.g. "HTTP_PORT"
+	Path    string `json:"path"`              // Dotted json path of the field, e.g. "server.http.port"
+	Type    string `json:"type"`              // string, int, bool, duration, list or map
+	Default string `json:"default,omitempty"` // Value used when the variable is unset
+	Secret  bool   `json:"secret,omitempty"`  // Value is never logged
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// EnvVars returns every environment variable read by Load, in field order. Names and
+// defaults come from the `env` and `default` struct tags on Config; nested structs that
+// are reused (such as OAuthProvider) get their names from an `envPrefix` tag and can
+// override child defaults with `default:"SUFFIX=value"` pairs separated by ";".
+//
+// Example:
+//
+//	for _, v := range config.EnvVars() {
+//	    fmt.Printf("%s=%s\n", v.Name, v.Default)
+//	}
+func EnvVars() []EnvVar {
+	var vars []EnvVar
+	collectEnvVars(reflect.TypeOf(Config{}), "", "", nil, &vars)
+
+	// DATABASE_URL overrides the discrete DB_* connection variables and has no field
+	vars = append(vars, EnvVar{
+		Name:   "DATABASE_URL",
+		Path:   "database",
+		Type:   "string",
+		Secret: true,
+	})
+
+	return vars
+}
+
+// collectEnvVars walks the fields of t, appending the ones tagged with `env`
+func collectEnvVars(t reflect.Type, path, prefix string, defaults map[string]string, vars *[]EnvVar) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, secret := configFieldName(field)
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			childPrefix := prefix + field.Tag.Get("envPrefix")
+			var childDefaults map[string]string
+			if _, ok := field.Tag.Lookup("envPrefix"); ok {
+				childDefaults = parseDefaultOverrides(field.Tag.Get("default"))
+			}
+			collectEnvVars(field.Type, fieldPath, childPrefix, childDefaults, vars)
+			continue
+		}
+
+		env, ok := field.Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+
+		value := field.Tag.Get("default")
+		if override, ok := defaults[env]; ok {
+			value = override
+		}
+
+		*vars = append(*vars, EnvVar{
+			Name:    prefix + env,
+			Path:    fieldPath,
+			Type:    envTypeName(field.Type),
+			Default: value,
+			Secret:  secret,
+		})
+	}
+}
+
+// parseDefaultOverrides parses "KEY=value;KEY=value" default overrides
+func parseDefaultOverrides(tag string) map[string]string {
+	overrides := map[string]string{}
+	for _, pair := range strings.Split(tag, ";") {
+		if key, value, ok := strings.Cut(pair, "="); ok {
+			overrides[strings.TrimSpace(key)] = value
+		}
+	}
+	return overrides
+}
+
+// envTypeName describes how the value of a field is parsed from the environment
+func envTypeName(t reflect.Type) string {
+	if t == durationType {
+		return "duration"
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		return "list"
+	case reflect.Map:
+		return "map"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// WriteEnvDocs writes the table of environment variables returned by EnvVars to w,
+// as a Markdown table or a JSON array. Wire it into a small command so operators can
+// list the available settings without reading the source.
+//
+// Example:
+//
+//	func main() {
+//	    if err := config.WriteEnvDocs(os.Stdout, config.DocsFormatMarkdown); err != nil {
+//	        log.Fatal(err)
+//	    }
+//	}
+func WriteEnvDocs(w io.Writer, format DocsFormat) error {
+	vars := EnvVars()
+
+	switch format {
+	case DocsFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(vars)
+	case DocsFormatMarkdown:
+		var b strings.Builder
+		b.WriteString("| Variable | Field | Type | Default |\n")
+		b.WriteString("|----------|-------|------|---------|\n")
+		for _, v := range vars {
+			value := "`" + v.Default + "`"
+			if v.Default == "" {
+				value = "-"
+			}
+			if v.Secret {
+				value += " (secret)"
+			}
+			fmt.Fprintf(&b, "| `%s` | `%s` | %s | %s |\n", v.Name, v.Path, v.Type, value)
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	default:
+		return fmt.Errorf("unsupported docs format %q", format)
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findEnvVar(t *testing.T, vars []EnvVar, name string) EnvVar {
+	t.Helper()
+	for _, v := range vars {
+		if v.Name == name {
+			return v
+		}
+	}
+	t.Fatalf("env var %s not found", name)
+	return EnvVar{}
+}
+
+func Test_EnvVars_Key_Fields(t *testing.T) {
+	vars := EnvVars()
+
+	assert.Equal(t, EnvVar{Name: "HTTP_PORT", Path: "server.http.port", Type: "int", Default: "8080"}, findEnvVar(t, vars, "HTTP_PORT"))
+	assert.Equal(t, EnvVar{Name: "APP_TIMEOUT", Path: "app.timeout", Type: "duration", Default: "30s"}, findEnvVar(t, vars, "APP_TIMEOUT"))
+	assert.Equal(t, EnvVar{Name: "DB_PASSWORD", Path: "database.password", Type: "string", Secret: true}, findEnvVar(t, vars, "DB_PASSWORD"))
+	assert.Equal(t, "GET,POST,PUT,DELETE,OPTIONS", findEnvVar(t, vars, "CORS_ALLOWED_METHODS").Default)
+	assert.Equal(t, "list", findEnvVar(t, vars, "CORS_ALLOWED_METHODS").Type)
+	assert.Equal(t, "email,profile", findEnvVar(t, vars, "OAUTH_GOOGLE_SCOPES").Default)
+	assert.Equal(t, "auth.oauth.github.client_id", findEnvVar(t, vars, "OAUTH_GITHUB_CLIENT_ID").Path)
+	assert.Equal(t, "map", findEnvVar(t, vars, "FEATURE_FLAGS").Type)
+	findEnvVar(t, vars, "DATABASE_URL")
+}
+
+// Test_EnvVars_Defaults_Match_Loaders keeps the default tags authoritative: loading with
+// every variable set to its documented default must match loading with none set.
+func Test_EnvVars_Defaults_Match_Loaders(t *testing.T) {
+	load := func() Config {
+		database, err := loadDatabaseConfig()
+		require.NoError(t, err)
+		return Config{
+			App:      loadAppConfig(),
+			Server:   loadServerConfig(),
+			Database: database,
+			Redis:    loadRedisConfig(),
+			Queue:    loadQueueConfig(),
+			Storage:  loadStorageConfig(),
+			Logger:   loadLoggerConfig(),
+			Auth:     loadAuthConfig(),
+			Features: loadFeaturesConfig(),
+		}
+	}
+
+	vars := EnvVars()
+	for _, v := range vars {
+		t.Setenv(v.Name, "")
+	}
+	// The logger defaults documented are the non-development ones
+	t.Setenv("APP_ENV", "production")
+	unset := load()
+
+	for _, v := range vars {
+		if v.Default != "" && v.Name != "APP_ENV" {
+			t.Setenv(v.Name, v.Default)
+		}
+	}
+	assert.Equal(t, unset, load())
+}
+
+func Test_WriteEnvDocs(t *testing.T) {
+	var markdown bytes.Buffer
+	require.NoError(t, WriteEnvDocs(&markdown, DocsFormatMarkdown))
+	assert.Contains(t, markdown.String(), "| Variable | Field | Type | Default |")
+	assert.Contains(t, markdown.String(), "| `HTTP_PORT` | `server.http.port` | int | `8080` |")
+	assert.Contains(t, markdown.String(), "| `JWT_SECRET` | `auth.jwt.secret` | string | - (secret) |")
+
+	var out bytes.Buffer
+	require.NoError(t, WriteEnvDocs(&out, DocsFormatJSON))
+	var decoded []EnvVar
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, EnvVars(), decoded)
+
+	assert.Error(t, WriteEnvDocs(&out, "yaml"))
+}
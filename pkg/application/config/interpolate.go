@@ -0,0 +1,164 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envNamePattern matches the variable names accepted in ${VAR} references
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Interpolate expands the ${VAR} references in value with lookup, so that a variable can
+// be built from others:
+//
+//	STORAGE_BUCKET=${APP_NAME}-uploads
+//	REDIS_URL=redis://${REDIS_HOST:-localhost}:${REDIS_PORT:-6379}
+//
+// Referenced values are expanded too. A reference to an unset variable is an error
+// unless it has a default (${VAR:-default}), used when the variable is unset or empty;
+// defaults may contain references themselves. Cycles are reported as errors instead of
+// recursing forever. "$${" escapes a literal "${"; other "$" characters are kept as is.
+// Errors never contain value, which may be a secret.
+//
+// Load expands every variable it reads with the process environment, secrets included:
+// a generated password containing "${" must be written with "$${".
+//
+// Example:
+//
+//	value, err := config.Interpolate("${APP_NAME}-uploads", os.LookupEnv)
+func Interpolate(value string, lookup func(string) (string, bool)) (string, error) {
+	in := &interpolator{lookup: lookup}
+	return in.expand(value)
+}
+
+// interpolator expands references, tracking the variables being expanded to detect cycles
+type interpolator struct {
+	lookup    func(string) (string, bool)
+	expanding []string
+}
+
+// expand replaces the references in value
+func (in *interpolator) expand(value string) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(value); {
+		if strings.HasPrefix(value[i:], "$${") {
+			out.WriteString("${")
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(value[i:], "${") {
+			out.WriteByte(value[i])
+			i++
+			continue
+		}
+
+		end := closingBrace(value, i+2)
+		if end < 0 {
+			// The value may be a secret, so errors never quote it
+			return "", errors.New("unterminated reference")
+		}
+
+		resolved, err := in.resolve(value[i+2 : end])
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(resolved)
+		i = end + 1
+	}
+
+	return out.String(), nil
+}
+
+// resolve returns the expanded value of a reference body, "VAR" or "VAR:-default"
+func (in *interpolator) resolve(reference string) (string, error) {
+	name, fallback, hasDefault := strings.Cut(reference, ":-")
+	if !envNamePattern.MatchString(name) {
+		return "", errors.New("invalid variable name in reference")
+	}
+
+	value, ok := in.lookup(name)
+	if !ok || (value == "" && hasDefault) {
+		if !hasDefault {
+			return "", fmt.Errorf("undefined variable %s", name)
+		}
+		return in.expand(fallback)
+	}
+
+	return in.expandVar(name, value)
+}
+
+// expandVar expands the value of the variable name, failing when name is already being
+// expanded
+func (in *interpolator) expandVar(name, value string) (string, error) {
+	for i, expanding := range in.expanding {
+		if expanding == name {
+			cycle := append(append([]string(nil), in.expanding[i:]...), name)
+			return "", fmt.Errorf("cyclic reference %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	in.expanding = append(in.expanding, name)
+	defer func() { in.expanding = in.expanding[:len(in.expanding)-1] }()
+
+	return in.expand(value)
+}
+
+// closingBrace returns the index of the brace closing the reference whose body starts at
+// start, skipping nested references, or -1
+func closingBrace(value string, start int) int {
+	depth := 1
+	for i := start; i < len(value); i++ {
+		switch {
+		case strings.HasPrefix(value[i:], "${"):
+			depth++
+			i++
+		case value[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// lookupEnv returns the environment variable key with its references expanded. Values
+// that fail to expand are returned as is; Load reports their errors with
+// checkInterpolation before reading them.
+func lookupEnv(key string) string {
+	value := os.Getenv(key)
+	in := &interpolator{lookup: os.LookupEnv}
+	expanded, err := in.expandVar(key, value)
+	if err != nil {
+		return value
+	}
+	return expanded
+}
+
+// checkInterpolation expands every variable read by Load and returns their errors, which
+// name the variables only. The errors of secrets leave out the reason too, as it may
+// quote part of the value (the name of an undefined variable).
+func checkInterpolation() error {
+	var errs []error
+	for _, v := range EnvVars() {
+		value, ok := os.LookupEnv(v.Name)
+		if !ok {
+			continue
+		}
+		in := &interpolator{lookup: os.LookupEnv}
+		if _, err := in.expandVar(v.Name, value); err != nil {
+			if v.Secret {
+				err = errors.New(`invalid reference, write a literal "${" as "$${"`)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", v.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapLookup looks variables up in env
+func mapLookup(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func Test_Interpolate_Expands_Nested_References(t *testing.T) {
+	lookup := mapLookup(map[string]string{
+		"APP_NAME":    "orders",
+		"ENVIRONMENT": "staging",
+		"PREFIX":      "${APP_NAME}-${ENVIRONMENT}",
+		"BUCKET":      "${PREFIX}-uploads",
+	})
+
+	value, err := Interpolate("s3://${BUCKET}/files", lookup)
+	require.NoError(t, err)
+	assert.Equal(t, "s3://orders-staging-uploads/files", value)
+
+	value, err = Interpolate("no references, $HOME stays", lookup)
+	require.NoError(t, err)
+	assert.Equal(t, "no references, $HOME stays", value)
+}
+
+func Test_Interpolate_Defaults(t *testing.T) {
+	lookup := mapLookup(map[string]string{
+		"REDIS_HOST": "cache.internal",
+		"EMPTY":      "",
+		"FALLBACK":   "${REDIS_HOST}",
+	})
+
+	value, err := Interpolate("redis://${REDIS_HOST:-localhost}:${REDIS_PORT:-6379}", lookup)
+	require.NoError(t, err)
+	assert.Equal(t, "redis://cache.internal:6379", value)
+
+	// Empty variables use the default, which may reference other variables
+	value, err = Interpolate("${EMPTY:-${MISSING:-${FALLBACK}}}", lookup)
+	require.NoError(t, err)
+	assert.Equal(t, "cache.internal", value)
+
+	// An explicitly empty default
+	value, err = Interpolate("[${MISSING:-}]", lookup)
+	require.NoError(t, err)
+	assert.Equal(t, "[]", value)
+}
+
+func Test_Interpolate_Escapes_References(t *testing.T) {
+	value, err := Interpolate("$${APP_NAME} is ${APP_NAME}", mapLookup(map[string]string{"APP_NAME": "orders"}))
+	require.NoError(t, err)
+	assert.Equal(t, "${APP_NAME} is orders", value)
+}
+
+func Test_Interpolate_Errors(t *testing.T) {
+	lookup := mapLookup(map[string]string{
+		"A":      "${B}",
+		"B":      "${C}-suffix",
+		"C":      "${A}",
+		"SELF":   "${SELF}",
+		"BUCKET": "${APP_NAME}-uploads",
+	})
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"undefined", "${BUCKET}", "undefined variable APP_NAME"},
+		{"cycle", "${A}", "cyclic reference A -> B -> C -> A"},
+		{"self reference", "${SELF}", "cyclic reference SELF -> SELF"},
+		{"unterminated", "${APP_NAME", "unterminated reference"},
+		{"invalid name", "${APP-NAME}", "invalid variable name in reference"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Interpolate(tt.value, lookup)
+			require.Error(t, err)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func Test_Load_Interpolates_Environment_Variables(t *testing.T) {
+	t.Setenv("APP_NAME", "orders")
+	t.Setenv("STORAGE_BUCKET", "${APP_NAME}-uploads")
+	t.Setenv("HTTP_PORT", "${PORT:-9090}")
+	t.Setenv("APP_SECRET", "s3cr3t")
+	t.Setenv("JWT_SECRET", "${APP_SECRET}")
+	t.Setenv("SESSION_SECRET", "${APP_SECRET}")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "orders-uploads", cfg.Storage.Bucket)
+	assert.Equal(t, 9090, cfg.Server.HTTP.Port)
+	assert.Equal(t, "s3cr3t", cfg.Auth.JWT.Secret)
+}
+
+func Test_Load_Reports_Interpolation_Errors(t *testing.T) {
+	t.Setenv("STORAGE_BUCKET", "${BUCKET_NAME}")
+	t.Setenv("APP_NAME", "${APP_NAME}")
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "APP_NAME: cyclic reference APP_NAME -> APP_NAME")
+	assert.Contains(t, err.Error(), "STORAGE_BUCKET: undefined variable BUCKET_NAME")
+}
+
+func Test_Load_Does_Not_Leak_Secrets_In_Interpolation_Errors(t *testing.T) {
+	t.Setenv("JWT_SECRET", "jwt-secret")
+	t.Setenv("SESSION_SECRET", "session-secret")
+	t.Setenv("DB_PASSWORD", "hunter2${x7")
+	t.Setenv("DATABASE_URL", "postgres://app:pa${WORD_PART}@db:5432/orders")
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DB_PASSWORD: invalid reference")
+	assert.Contains(t, err.Error(), "DATABASE_URL: invalid reference")
+	assert.NotContains(t, err.Error(), "hunter2")
+	assert.NotContains(t, err.Error(), "WORD_PART")
+
+	// Escaped, the secret is read literally
+	t.Setenv("DB_PASSWORD", "hunter2$${x7")
+	t.Setenv("DATABASE_URL", "")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2${x7", cfg.Database.Password)
+}
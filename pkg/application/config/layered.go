@@ -0,0 +1,184 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/phatnt199/go-infra/pkg/application/environment"
+
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadLayered loads configuration from YAML files and environment variables. The
+// layers, from lowest to highest precedence, are:
+//
+//  1. the defaults of Load
+//  2. baseFile, e.g. config.base.yaml
+//  3. the file of env next to it, e.g. config.production.yaml, when it exists
+//  4. the environment variables read by Load, when set
+//
+// The result is then validated. Keys are the json names of the fields (server.http.port);
+// unknown keys are errors. Files are deep-merged: nested mappings overlay each other key
+// by key, while lists and scalars replace the lower value, so an override of
+// cors.allowed_origins lists every origin. Durations are strings such as "30s".
+// Secrets (fields with json:"-", such as database.password) are only read from
+// environment variables.
+//
+// Example:
+//
+//	cfg, err := config.LoadLayered("config/config.base.yaml", environment.Production)
+func LoadLayered(baseFile string, env environment.Environment) (*Config, error) {
+	values, err := readConfigFile(baseFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if env != "" {
+		overrides, err := readConfigFile(environmentConfigFile(baseFile, env))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			// Not every environment overrides the base
+		case err != nil:
+			return nil, fmt.Errorf("invalid configuration: %w", err)
+		default:
+			values = mergeConfigValues(values, overrides)
+		}
+	}
+
+	envConfig, err := loadEnvConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// Decode the files over the defaults, then restore the variables that are set.
+	// envConfig holds a default wherever no variable is set.
+	config := *envConfig
+	if err := decodeConfigValues(values, &config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	applyEnvOverrides(&config, envConfig)
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &config, nil
+}
+
+// environmentConfigFile returns the file of env next to baseFile:
+// config.base.yaml and config.yaml both give config.<env>.yaml
+func environmentConfigFile(baseFile string, env environment.Environment) string {
+	ext := filepath.Ext(baseFile)
+	stem := strings.TrimSuffix(strings.TrimSuffix(baseFile, ext), ".base")
+	return stem + "." + env.GetEnvironmentName() + ext
+}
+
+// readConfigFile parses a YAML config file into nested maps
+func readConfigFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]any{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return values, nil
+}
+
+// mergeConfigValues returns base with overrides deep-merged over it: mappings are
+// merged key by key, any other value replaces the one in base
+func mergeConfigValues(base, overrides map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(overrides))
+	for key, value := range base {
+		merged[key] = value
+	}
+
+	for key, value := range overrides {
+		baseMap, baseIsMap := merged[key].(map[string]any)
+		overrideMap, overrideIsMap := value.(map[string]any)
+		if baseIsMap && overrideIsMap {
+			merged[key] = mergeConfigValues(baseMap, overrideMap)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// decodeConfigValues decodes values over config. Structs keep the fields missing from
+// values, while lists and maps are replaced.
+func decodeConfigValues(values map[string]any, config *Config) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:     "json",
+		ZeroFields:  true,
+		ErrorUnused: true,
+		Result:      config,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+			scalarToStringHookFunc,
+		),
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(values)
+}
+
+// scalarToStringHookFunc decodes YAML booleans and numbers into strings, such as the
+// feature flag `beta_banner: true`
+func scalarToStringHookFunc(from, to reflect.Type, data any) (any, error) {
+	if to.Kind() != reflect.String {
+		return data, nil
+	}
+
+	switch from.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int64, reflect.Uint64, reflect.Float64:
+		return fmt.Sprint(data), nil
+	}
+	return data, nil
+}
+
+// applyEnvOverrides copies from envConfig into config the fields whose environment
+// variable is set
+func applyEnvOverrides(config, envConfig *Config) {
+	target := reflect.ValueOf(config).Elem()
+	source := reflect.ValueOf(envConfig).Elem()
+
+	for _, v := range EnvVars() {
+		if lookupEnv(v.Name) == "" {
+			continue
+		}
+
+		if v.Name == "DATABASE_URL" {
+			// Override only the settings the URL carries
+			if parsed, err := ParseDatabaseURL(lookupEnv(v.Name)); err == nil {
+				config.Database.applyURL(parsed)
+			}
+			continue
+		}
+
+		configField(target, v.Path).Set(configField(source, v.Path))
+	}
+}
+
+// configField returns the field of the Config value at the dotted path of EnvVar.Path
+func configField(value reflect.Value, path string) reflect.Value {
+	for _, name := range strings.Split(path, ".") {
+		for i := 0; i < value.NumField(); i++ {
+			if fieldName, _ := configFieldName(value.Type().Field(i)); fieldName == name {
+				value = value.Field(i)
+				break
+			}
+		}
+	}
+	return value
+}
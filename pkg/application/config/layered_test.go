@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/application/environment"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const layeredBaseYAML = `
+app:
+  name: orders
+  timeout: 45s
+server:
+  http:
+    port: 8000
+    read_timeout: 20s
+    cors:
+      allowed_origins: [https://app.example.com, https://admin.example.com]
+      max_age: 600
+database:
+  host: db.internal
+  max_open_conns: 50
+features:
+  flags:
+    new_checkout: true
+    beta_banner: 10%
+`
+
+const layeredProductionYAML = `
+server:
+  http:
+    port: 80
+    cors:
+      allowed_origins: [https://example.com]
+database:
+  max_open_conns: 100
+features:
+  flags:
+    beta_banner: 50%
+`
+
+// writeLayeredConfig writes the base and production files to a temporary directory and
+// returns the base file
+func writeLayeredConfig(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	return filepath.Join(dir, "config.base.yaml")
+}
+
+// setRequiredSecrets sets the secrets validation requires, which never come from files
+func setRequiredSecrets(t *testing.T) {
+	t.Setenv("JWT_SECRET", "jwt-secret")
+	t.Setenv("SESSION_SECRET", "session-secret")
+}
+
+func Test_LoadLayered_Precedence(t *testing.T) {
+	setRequiredSecrets(t)
+	baseFile := writeLayeredConfig(t, map[string]string{
+		"config.base.yaml":       layeredBaseYAML,
+		"config.production.yaml": layeredProductionYAML,
+	})
+	t.Setenv("DB_MAX_OPEN_CONNS", "200")
+
+	cfg, err := LoadLayered(baseFile, environment.Production)
+	require.NoError(t, err)
+
+	// Defaults
+	assert.Equal(t, "0.0.0.0", cfg.Server.HTTP.Host)
+	assert.Equal(t, 10*time.Second, cfg.Server.HTTP.WriteTimeout)
+	assert.Equal(t, 5*time.Minute, cfg.Database.ConnMaxLifetime)
+	// Base file, nested fields next to overridden ones are kept
+	assert.Equal(t, "orders", cfg.App.Name)
+	assert.Equal(t, 45*time.Second, cfg.App.Timeout)
+	assert.Equal(t, 20*time.Second, cfg.Server.HTTP.ReadTimeout)
+	assert.Equal(t, 600, cfg.Server.HTTP.CORS.MaxAge)
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	// Environment file
+	assert.Equal(t, 80, cfg.Server.HTTP.Port)
+	// Environment variables
+	assert.Equal(t, 200, cfg.Database.MaxOpenConns)
+	assert.Equal(t, "jwt-secret", cfg.Auth.JWT.Secret)
+}
+
+func Test_LoadLayered_Replaces_Slices_And_Merges_Maps(t *testing.T) {
+	setRequiredSecrets(t)
+	baseFile := writeLayeredConfig(t, map[string]string{
+		"config.base.yaml":       layeredBaseYAML,
+		"config.production.yaml": layeredProductionYAML,
+	})
+
+	cfg, err := LoadLayered(baseFile, environment.Production)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"https://example.com"}, cfg.Server.HTTP.CORS.AllowedOrigins)
+	assert.Equal(t, map[string]string{"new_checkout": "true", "beta_banner": "50%"}, cfg.Features.Flags)
+
+	// Environment variables replace slices too
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.example.com,https://b.example.com")
+	cfg, err = LoadLayered(baseFile, environment.Production)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.Server.HTTP.CORS.AllowedOrigins)
+}
+
+func Test_LoadLayered_Without_Environment_File_Uses_Base(t *testing.T) {
+	setRequiredSecrets(t)
+	baseFile := writeLayeredConfig(t, map[string]string{"config.base.yaml": layeredBaseYAML})
+
+	cfg, err := LoadLayered(baseFile, environment.Staging)
+	require.NoError(t, err)
+	assert.Equal(t, 8000, cfg.Server.HTTP.Port)
+	assert.Equal(t, []string{"https://app.example.com", "https://admin.example.com"}, cfg.Server.HTTP.CORS.AllowedOrigins)
+}
+
+func Test_LoadLayered_Errors(t *testing.T) {
+	setRequiredSecrets(t)
+
+	_, err := LoadLayered(filepath.Join(t.TempDir(), "config.base.yaml"), environment.Production)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// Unknown keys and secrets in files are rejected
+	baseFile := writeLayeredConfig(t, map[string]string{
+		"config.base.yaml": "server:\n  htp:\n    port: 80\ndatabase:\n  password: hunter2\n",
+	})
+	_, err = LoadLayered(baseFile, environment.Production)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'server' has invalid keys: htp")
+	assert.Contains(t, err.Error(), "'database' has invalid keys: password")
+
+	// The merged configuration is validated
+	baseFile = writeLayeredConfig(t, map[string]string{
+		"config.base.yaml":       "server:\n  http:\n    port: 8080\n",
+		"config.production.yaml": "server:\n  http:\n    port: 70000\n",
+	})
+	_, err = LoadLayered(baseFile, environment.Production)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.http.port")
+}
+
+func Test_EnvironmentConfigFile(t *testing.T) {
+	assert.Equal(t, filepath.Join("config", "config.production.yaml"),
+		environmentConfigFile(filepath.Join("config", "config.base.yaml"), environment.Production))
+	assert.Equal(t, "config.staging.yml", environmentConfigFile("config.yml", environment.Staging))
+}
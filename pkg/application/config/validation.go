@@ -3,6 +3,19 @@ package config
 import (
 	"fmt"
 	"strings"
+
+	"github.com/phatnt199/go-infra/pkg/utils/enum"
+)
+
+// Allowed values for enum-like configuration fields
+var (
+	validEnvironments    = enum.New("development", "local", "staging", "production")
+	validDatabaseDrivers = enum.New("postgres", "mysql", "sqlite")
+	validQueueDrivers    = enum.New("rabbitmq", "kafka", "sqs", "redis")
+	validStorageDrivers  = enum.New("s3", "minio", "gcs", "local")
+	validLogLevels       = enum.New("debug", "info", "warn", "error", "fatal", "panic")
+	validLogFormats      = enum.New("json", "console")
+	validSameSite        = enum.New("strict", "lax", "none")
 )
 
 // ValidationError represents a configuration validation error
@@ -124,9 +137,8 @@ func (a *AppConfig) Validate() error {
 		errs.Add("app.environment", "environment is required")
 	}
 
-	validEnvs := []string{"development", "local", "staging", "production"}
-	if !contains(validEnvs, a.Environment) {
-		errs.Add("app.environment", fmt.Sprintf("environment must be one of: %s", strings.Join(validEnvs, ", ")))
+	if !validEnvironments.Valid(a.Environment) {
+		errs.Add("app.environment", validEnvironments.MustBeOneOf("environment"))
 	}
 
 	if a.Timeout <= 0 {
@@ -217,9 +229,8 @@ func (d *DatabaseConfig) Validate() error {
 		errs.Add("database.driver", "driver is required")
 	}
 
-	validDrivers := []string{"postgres", "mysql", "sqlite"}
-	if !contains(validDrivers, d.Driver) {
-		errs.Add("database.driver", fmt.Sprintf("driver must be one of: %s", strings.Join(validDrivers, ", ")))
+	if !validDatabaseDrivers.Valid(d.Driver) {
+		errs.Add("database.driver", validDatabaseDrivers.MustBeOneOf("driver"))
 	}
 
 	// SQLite doesn't need host/port validation
@@ -293,9 +304,8 @@ func (q *QueueConfig) Validate() error {
 		errs.Add("queue.driver", "driver is required")
 	}
 
-	validDrivers := []string{"rabbitmq", "kafka", "sqs", "redis"}
-	if !contains(validDrivers, q.Driver) {
-		errs.Add("queue.driver", fmt.Sprintf("driver must be one of: %s", strings.Join(validDrivers, ", ")))
+	if !validQueueDrivers.Valid(q.Driver) {
+		errs.Add("queue.driver", validQueueDrivers.MustBeOneOf("driver"))
 	}
 
 	if q.Concurrency <= 0 {
@@ -316,9 +326,8 @@ func (s *StorageConfig) Validate() error {
 		errs.Add("storage.driver", "driver is required")
 	}
 
-	validDrivers := []string{"s3", "minio", "gcs", "local"}
-	if !contains(validDrivers, s.Driver) {
-		errs.Add("storage.driver", fmt.Sprintf("driver must be one of: %s", strings.Join(validDrivers, ", ")))
+	if !validStorageDrivers.Valid(s.Driver) {
+		errs.Add("storage.driver", validStorageDrivers.MustBeOneOf("driver"))
 	}
 
 	// Cloud storage providers require credentials
@@ -340,6 +349,15 @@ func (s *StorageConfig) Validate() error {
 	return nil
 }
 
+// ValidateLogLevel returns a *ValidationError unless level is one of the
+// accepted logger levels
+func ValidateLogLevel(level string) error {
+	if !validLogLevels.Valid(level) {
+		return &ValidationError{Field: "logger.level", Message: validLogLevels.MustBeOneOf("level")}
+	}
+	return nil
+}
+
 // Validate validates logger configuration
 func (l *LoggerConfig) Validate() error {
 	var errs ValidationErrors
@@ -348,18 +366,16 @@ func (l *LoggerConfig) Validate() error {
 		errs.Add("logger.level", "level is required")
 	}
 
-	validLevels := []string{"debug", "info", "warn", "error", "fatal", "panic"}
-	if !contains(validLevels, l.Level) {
-		errs.Add("logger.level", fmt.Sprintf("level must be one of: %s", strings.Join(validLevels, ", ")))
+	if !validLogLevels.Valid(l.Level) {
+		errs.Add("logger.level", validLogLevels.MustBeOneOf("level"))
 	}
 
 	if l.Format == "" {
 		errs.Add("logger.format", "format is required")
 	}
 
-	validFormats := []string{"json", "console"}
-	if !contains(validFormats, l.Format) {
-		errs.Add("logger.format", fmt.Sprintf("format must be one of: %s", strings.Join(validFormats, ", ")))
+	if !validLogFormats.Valid(l.Format) {
+		errs.Add("logger.format", validLogFormats.MustBeOneOf("format"))
 	}
 
 	if len(l.OutputPaths) == 0 {
@@ -456,9 +472,8 @@ func (s *SessionConfig) Validate() error {
 		errs.Add("auth.session.max_age", "max age must be greater than 0")
 	}
 
-	validSameSite := []string{"strict", "lax", "none"}
-	if !contains(validSameSite, s.SameSite) {
-		errs.Add("auth.session.same_site", fmt.Sprintf("same_site must be one of: %s", strings.Join(validSameSite, ", ")))
+	if !validSameSite.Valid(s.SameSite) {
+		errs.Add("auth.session.same_site", validSameSite.MustBeOneOf("same_site"))
 	}
 
 	if errs.HasErrors() {
@@ -479,18 +494,12 @@ func (p *PasswordConfig) Validate() error {
 		errs.Add("auth.password.bcrypt_cost", "bcrypt cost must be between 4 and 31")
 	}
 
+	if p.MinStrength < 0 || p.MinStrength > 4 {
+		errs.Add("auth.password.min_strength", "minimum strength must be between 0 and 4")
+	}
+
 	if errs.HasErrors() {
 		return errs
 	}
 	return nil
 }
-
-// Helper function to check if a slice contains a string
-func contains(slice []string, str string) bool {
-	for _, s := range slice {
-		if s == str {
-			return true
-		}
-	}
-	return false
-}
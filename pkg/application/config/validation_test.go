@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Enum_Validation_Messages(t *testing.T) {
+	app := AppConfig{Name: "app", Environment: "qa", Timeout: 30}
+	err := app.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "app.environment: environment must be one of: development, local, staging, production", err.Error())
+
+	logger := LoggerConfig{Level: "trace", Format: "text", OutputPaths: []string{"stdout"}}
+	err = logger.Validate()
+	require.Error(t, err)
+	assert.Equal(t,
+		"logger.level: level must be one of: debug, info, warn, error, fatal, panic; "+
+			"logger.format: format must be one of: json, console",
+		err.Error(),
+	)
+
+	logger = LoggerConfig{Level: "info", Format: "json", OutputPaths: []string{"stdout"}}
+	assert.NoError(t, logger.Validate())
+}
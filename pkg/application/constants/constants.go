@@ -4,6 +4,7 @@ import "time"
 
 const (
 	ConfigPath    = "CONFIG_PATH"
+	ENV_FILE      = "ENV_FILE"
 	APP_ENV       = "APP_ENV"
 	APP_NAME      = "Go Project"
 	APP_ROOT_PATH = "APP_ROOT"
@@ -1,10 +1,14 @@
 package environment
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/phatnt199/go-infra/pkg/application/constants"
 
@@ -34,7 +38,15 @@ func ConfigAppEnv(environments ...Environment) Environment {
 
 	// https://articles.wesionary.team/environment-variable-configuration-in-your-golang-project-using-viper-4e8289ef664d
 	// load environment variables form .env files to system environment variables, it just finds `.env` file in our current `executing working directory` in our app for example `catalogs_service`
-	err := loadEnvFilesRecursive()
+	envFileEnvironment := environment
+	if manualEnv := os.Getenv(constants.APP_ENV); manualEnv != "" {
+		envFileEnvironment = Environment(manualEnv)
+	}
+
+	_, err := LoadEnvFiles(EnvFileOptions{
+		StopAtModuleRoot: true,
+		Environment:      envFileEnvironment,
+	})
 	if err != nil {
 		log.Printf(".env file cannot be found, err: %v", err)
 	}
@@ -76,21 +88,128 @@ func EnvString(key, fallback string) string {
 	return fallback
 }
 
-func loadEnvFilesRecursive() error {
-	// Start from the current working directory
-	dir, err := os.Getwd()
+// EnvInt returns the environment variable as an int, or fallback when it is unset or invalid
+func EnvInt(key string, fallback int) int {
+	if value, ok := syscall.Getenv(key); ok {
+		if parsed, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			return parsed
+		}
+	}
+
+	return fallback
+}
+
+// EnvBool returns the environment variable as a bool, or fallback when it is unset or invalid
+func EnvBool(key string, fallback bool) bool {
+	if value, ok := syscall.Getenv(key); ok {
+		if parsed, err := strconv.ParseBool(strings.TrimSpace(value)); err == nil {
+			return parsed
+		}
+	}
+
+	return fallback
+}
+
+// EnvDuration returns the environment variable as a time.Duration (e.g. "30s"),
+// or fallback when it is unset or invalid
+func EnvDuration(key string, fallback time.Duration) time.Duration {
+	if value, ok := syscall.Getenv(key); ok {
+		if parsed, err := time.ParseDuration(strings.TrimSpace(value)); err == nil {
+			return parsed
+		}
+	}
+
+	return fallback
+}
+
+// MissingEnvError reports every required environment variable that is unset or empty
+type MissingEnvError struct {
+	Keys []string
+}
+
+func (e *MissingEnvError) Error() string {
+	return fmt.Sprintf("missing required environment variables: %s", strings.Join(e.Keys, ", "))
+}
+
+// MustEnv returns the values of all required environment variables.
+// Instead of failing on the first missing key it checks every key, so startup can
+// report all missing variables at once through a single *MissingEnvError.
+func MustEnv(keys ...string) (map[string]string, error) {
+	values := make(map[string]string, len(keys))
+	var missing []string
+
+	for _, key := range keys {
+		value, ok := syscall.Getenv(key)
+		if !ok || strings.TrimSpace(value) == "" {
+			missing = append(missing, key)
+			continue
+		}
+		values[key] = value
+	}
+
+	if len(missing) > 0 {
+		return values, &MissingEnvError{Keys: missing}
+	}
+
+	return values, nil
+}
+
+// EnvFileOptions controls how LoadEnvFiles discovers `.env` files
+type EnvFileOptions struct {
+	// StartDir is the directory the search starts from, defaults to the working directory
+	StartDir string
+	// StopAtModuleRoot stops the upward search at the directory containing `go.mod`.
+	// When no module root exists (e.g. a deployed binary) the search continues up to the filesystem root.
+	StopAtModuleRoot bool
+	// Environment enables layering of `.env.<environment>` (e.g. `.env.production`) over `.env`
+	Environment Environment
+}
+
+// LoadEnvFiles loads environment variables from `.env` files into the process environment
+// and returns the loaded file paths.
+//
+// If `ENV_FILE` is set, only that file is loaded. Otherwise the search walks up from
+// StartDir and stops at the first directory containing `.env` or `.env.<environment>`.
+// The environment-specific file takes precedence over `.env`, and variables already
+// present in the process environment are never overridden.
+func LoadEnvFiles(opts EnvFileOptions) ([]string, error) {
+	if explicitFile := os.Getenv(constants.ENV_FILE); explicitFile != "" {
+		if err := godotenv.Load(explicitFile); err != nil {
+			return nil, errors.WrapIff(err, "failed to load env file `%s` from %s", explicitFile, constants.ENV_FILE)
+		}
+		return []string{explicitFile}, nil
+	}
+
+	dir := opts.StartDir
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		dir = wd
+	}
+
+	dir, err := filepath.Abs(dir)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var stopDir string
+	if opts.StopAtModuleRoot {
+		if moduleRoot, err := searchRootDirectory(dir); err == nil {
+			stopDir = moduleRoot
+		}
 	}
 
 	// Keep traversing up the directory hierarchy until you find an ".env" file
 	for {
-		envFilePath := filepath.Join(dir, ".env")
-		err := godotenv.Load(envFilePath)
+		if loaded := loadEnvFilesInDir(dir, opts.Environment); len(loaded) > 0 {
+			return loaded, nil
+		}
 
-		if err == nil {
-			// .env file found and loaded
-			return nil
+		if dir == stopDir {
+			// Reached the module root, stop searching
+			break
 		}
 
 		// Move up one directory level
@@ -103,7 +222,29 @@ func loadEnvFilesRecursive() error {
 		dir = parentDir
 	}
 
-	return errors.New(".env file not found in the project hierarchy")
+	return nil, errors.New(".env file not found in the project hierarchy")
+}
+
+// loadEnvFilesInDir loads `.env.<environment>` and then `.env` from dir. Because godotenv
+// never overrides variables that are already set, the environment-specific file wins.
+func loadEnvFilesInDir(dir string, env Environment) []string {
+	var candidates []string
+	if env != "" {
+		candidates = append(candidates, filepath.Join(dir, fmt.Sprintf(".env.%s", env)))
+	}
+	candidates = append(candidates, filepath.Join(dir, ".env"))
+
+	var loaded []string
+	for _, envFilePath := range candidates {
+		if _, err := os.Stat(envFilePath); err != nil {
+			continue
+		}
+		if err := godotenv.Load(envFilePath); err == nil {
+			loaded = append(loaded, envFilePath)
+		}
+	}
+
+	return loaded
 }
 
 func setRootWorkingDirectoryEnvironment() {
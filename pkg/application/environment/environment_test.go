@@ -0,0 +1,121 @@
+package environment
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/application/constants"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Typed_Env_Getters(t *testing.T) {
+	t.Setenv("TEST_ENV_INT", "42")
+	t.Setenv("TEST_ENV_BOOL", "true")
+	t.Setenv("TEST_ENV_DURATION", "1m30s")
+	t.Setenv("TEST_ENV_INVALID", "not-a-value")
+
+	assert.Equal(t, 42, EnvInt("TEST_ENV_INT", 1))
+	assert.True(t, EnvBool("TEST_ENV_BOOL", false))
+	assert.Equal(t, 90*time.Second, EnvDuration("TEST_ENV_DURATION", time.Second))
+
+	// unset or invalid values fall back
+	assert.Equal(t, 7, EnvInt("TEST_ENV_UNSET", 7))
+	assert.Equal(t, 7, EnvInt("TEST_ENV_INVALID", 7))
+	assert.True(t, EnvBool("TEST_ENV_INVALID", true))
+	assert.Equal(t, time.Second, EnvDuration("TEST_ENV_INVALID", time.Second))
+}
+
+func Test_MustEnv_Reports_All_Missing_Keys(t *testing.T) {
+	t.Setenv("TEST_REQUIRED_A", "a")
+	t.Setenv("TEST_REQUIRED_EMPTY", "")
+
+	values, err := MustEnv("TEST_REQUIRED_A", "TEST_REQUIRED_MISSING", "TEST_REQUIRED_EMPTY")
+	require.Error(t, err)
+
+	var missingErr *MissingEnvError
+	require.True(t, errors.As(err, &missingErr))
+	assert.Equal(t, []string{"TEST_REQUIRED_MISSING", "TEST_REQUIRED_EMPTY"}, missingErr.Keys)
+	assert.Contains(t, err.Error(), "TEST_REQUIRED_MISSING, TEST_REQUIRED_EMPTY")
+	assert.Equal(t, "a", values["TEST_REQUIRED_A"])
+}
+
+func Test_MustEnv_Returns_Values(t *testing.T) {
+	t.Setenv("TEST_REQUIRED_A", "a")
+	t.Setenv("TEST_REQUIRED_B", "b")
+
+	values, err := MustEnv("TEST_REQUIRED_A", "TEST_REQUIRED_B")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"TEST_REQUIRED_A": "a", "TEST_REQUIRED_B": "b"}, values)
+}
+
+// writeFile creates a file (and its parent directories) for env discovery tests
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+// unsetAfterTest removes variables loaded by godotenv once the test finishes
+func unsetAfterTest(t *testing.T, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		key := key
+		t.Cleanup(func() { _ = os.Unsetenv(key) })
+	}
+}
+
+func Test_LoadEnvFiles_Stops_At_Module_Root(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".env"), "TEST_OUTER_ENV=outer\n")
+	writeFile(t, filepath.Join(root, "service", "go.mod"), "module service\n")
+	start := filepath.Join(root, "service", "cmd", "app")
+	require.NoError(t, os.MkdirAll(start, 0o755))
+	unsetAfterTest(t, "TEST_OUTER_ENV")
+
+	_, err := LoadEnvFiles(EnvFileOptions{StartDir: start, StopAtModuleRoot: true})
+	assert.Error(t, err)
+	assert.Empty(t, os.Getenv("TEST_OUTER_ENV"))
+
+	// without the module-root guard the outer `.env` is picked up
+	loaded, err := LoadEnvFiles(EnvFileOptions{StartDir: start})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, ".env")}, loaded)
+	assert.Equal(t, "outer", os.Getenv("TEST_OUTER_ENV"))
+}
+
+func Test_LoadEnvFiles_Layers_Environment_File_Over_Base(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module service\n")
+	writeFile(t, filepath.Join(root, ".env"), "TEST_LAYER_SHARED=base\nTEST_LAYER_BASE_ONLY=base\n")
+	writeFile(t, filepath.Join(root, ".env.production"), "TEST_LAYER_SHARED=production\n")
+	start := filepath.Join(root, "internal")
+	require.NoError(t, os.MkdirAll(start, 0o755))
+	unsetAfterTest(t, "TEST_LAYER_SHARED", "TEST_LAYER_BASE_ONLY")
+
+	loaded, err := LoadEnvFiles(EnvFileOptions{StartDir: start, StopAtModuleRoot: true, Environment: Production})
+	require.NoError(t, err)
+
+	assert.Len(t, loaded, 2)
+	assert.Equal(t, "production", os.Getenv("TEST_LAYER_SHARED"))
+	assert.Equal(t, "base", os.Getenv("TEST_LAYER_BASE_ONLY"))
+}
+
+func Test_LoadEnvFiles_Uses_Explicit_Env_File(t *testing.T) {
+	root := t.TempDir()
+	explicitFile := filepath.Join(root, "config", "custom.env")
+	writeFile(t, explicitFile, "TEST_EXPLICIT_ENV=explicit\n")
+	writeFile(t, filepath.Join(root, ".env"), "TEST_EXPLICIT_ENV=discovered\n")
+	t.Setenv(constants.ENV_FILE, explicitFile)
+	unsetAfterTest(t, "TEST_EXPLICIT_ENV")
+
+	loaded, err := LoadEnvFiles(EnvFileOptions{StartDir: root})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{explicitFile}, loaded)
+	assert.Equal(t, "explicit", os.Getenv("TEST_EXPLICIT_ENV"))
+}
@@ -0,0 +1,102 @@
+// Package application provides application-wide startup helpers.
+package application
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/application/config"
+	"github.com/phatnt199/go-infra/pkg/health"
+)
+
+// DefaultPreflightTimeout bounds each preflight check
+const DefaultPreflightTimeout = 10 * time.Second
+
+// Pinger is implemented by connections that can verify connectivity, such as *sql.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// PreflightCheck runs checks concurrently, each bounded by DefaultPreflightTimeout, and
+// returns every failure joined with errors.Join (nil when all pass). Each failure is
+// prefixed with the name of its check; degraded checks (see health.Degrade) and
+// optional ones (see health.Optional) don't fail the preflight. Run it before accepting
+// traffic so that all misconfigurations are reported at once.
+//
+// Example:
+//
+//	sqlDB, _ := pgClient.DB().DB()
+//	if err := application.PreflightCheck(ctx,
+//		application.ConfigCheck(cfg),
+//		application.RequiredEnvCheck("JWT_SECRET", "STRIPE_API_KEY"),
+//		application.DatabaseCheck("postgres", sqlDB),
+//		health.TCPCheck("redis", cfg.Redis.Addr(), time.Second),
+//	); err != nil {
+//		log.Fatal(err)
+//	}
+func PreflightCheck(ctx context.Context, checks ...health.Check) error {
+	errs := make([]error, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check health.Check) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, DefaultPreflightTimeout)
+			defer cancel()
+
+			err := check.Check(checkCtx)
+			if err != nil && !health.IsDegraded(err) && !health.IsOptional(check) {
+				errs[i] = fmt.Errorf("%s: %w", check.Name(), err)
+			}
+		}(i, check)
+	}
+	wg.Wait()
+
+	return stdErrors.Join(errs...)
+}
+
+// DatabaseCheck creates a check that pings db.
+//
+// Example:
+//
+//	sqlDB, _ := pgClient.DB().DB()
+//	check := application.DatabaseCheck("postgres", sqlDB)
+func DatabaseCheck(name string, db Pinger) health.Check {
+	return health.NewCheck(name, func(ctx context.Context) error {
+		if err := db.PingContext(ctx); err != nil {
+			return fmt.Errorf("ping failed: %w", err)
+		}
+		return nil
+	})
+}
+
+// ConfigCheck creates a check that validates cfg.
+func ConfigCheck(cfg *config.Config) health.Check {
+	return health.NewCheck("config", func(context.Context) error {
+		return cfg.Validate()
+	})
+}
+
+// RequiredEnvCheck creates a check that fails when any of keys is unset or empty,
+// listing all of the missing keys.
+func RequiredEnvCheck(keys ...string) health.Check {
+	return health.NewCheck("env", func(context.Context) error {
+		var missing []string
+		for _, key := range keys {
+			if strings.TrimSpace(os.Getenv(key)) == "" {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("missing required environment variables: %s", strings.Join(missing, ", "))
+		}
+		return nil
+	})
+}
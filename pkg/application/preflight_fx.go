@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+
+	"github.com/phatnt199/go-infra/pkg/health"
+
+	"go.uber.org/fx"
+)
+
+// preflightGroup is the fx value group collecting preflight checks
+const preflightGroup = `group:"preflight_checks"`
+
+// PreflightModule runs the checks provided with AsPreflightCheck while the application
+// is built, so a failing check aborts startup before any OnStart hook (and thus any
+// server) runs.
+//
+// Example:
+//
+//	fx.New(
+//		application.PreflightModule,
+//		fx.Provide(application.AsPreflightCheck(func(cfg *config.Config) health.Check {
+//			return application.ConfigCheck(cfg)
+//		})),
+//	)
+var PreflightModule = fx.Module(
+	"preflightfx",
+	fx.Invoke(runPreflightChecks),
+)
+
+// AsPreflightCheck annotates a constructor returning a health.Check to add it to the
+// checks run by PreflightModule.
+func AsPreflightCheck(constructor interface{}) interface{} {
+	return fx.Annotate(
+		constructor,
+		fx.ResultTags(preflightGroup),
+	)
+}
+
+type preflightParams struct {
+	fx.In
+
+	Checks []health.Check `group:"preflight_checks"`
+}
+
+// runPreflightChecks fails the fx invoke when any preflight check fails
+func runPreflightChecks(params preflightParams) error {
+	return PreflightCheck(context.Background(), params.Checks...)
+}
@@ -0,0 +1,73 @@
+package application
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/health"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type fakePinger struct {
+	err error
+}
+
+func (p fakePinger) PingContext(context.Context) error {
+	return p.err
+}
+
+func Test_PreflightCheck_Passes_When_All_Checks_Pass(t *testing.T) {
+	err := PreflightCheck(context.Background(),
+		DatabaseCheck("postgres", fakePinger{}),
+		health.NewCheck("noop", func(context.Context) error { return nil }),
+		health.NewCheck("pool", func(context.Context) error {
+			return health.Degrade(stdErrors.New("connection pool 9/10 in use"))
+		}),
+		health.DegradeWhenSlow(health.Optional(health.NewCheck("recommendations", func(context.Context) error {
+			return stdErrors.New("down")
+		})), time.Second),
+	)
+
+	assert.NoError(t, err)
+}
+
+func Test_PreflightCheck_Reports_All_Failures(t *testing.T) {
+	errRefused := stdErrors.New("connection refused")
+	t.Setenv("PREFLIGHT_TEST_PRESENT", "value")
+
+	err := PreflightCheck(context.Background(),
+		DatabaseCheck("postgres", fakePinger{err: errRefused}),
+		RequiredEnvCheck("PREFLIGHT_TEST_PRESENT", "PREFLIGHT_TEST_MISSING_A", "PREFLIGHT_TEST_MISSING_B"),
+		health.NewCheck("noop", func(context.Context) error { return nil }),
+	)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errRefused)
+	assert.Contains(t, err.Error(), "postgres: ping failed: connection refused")
+	assert.Contains(t, err.Error(), "env: missing required environment variables: PREFLIGHT_TEST_MISSING_A, PREFLIGHT_TEST_MISSING_B")
+	assert.NotContains(t, err.Error(), "noop")
+}
+
+func Test_PreflightModule_Blocks_Startup_On_Failure(t *testing.T) {
+	started := false
+	app := fx.New(
+		fx.NopLogger,
+		PreflightModule,
+		fx.Provide(AsPreflightCheck(func() health.Check {
+			return DatabaseCheck("postgres", fakePinger{err: stdErrors.New("connection refused")})
+		})),
+		fx.Invoke(func(lc fx.Lifecycle) {
+			lc.Append(fx.StartHook(func() { started = true }))
+		}),
+	)
+
+	require.Error(t, app.Err())
+	assert.Contains(t, app.Err().Error(), "postgres: ping failed")
+	assert.Error(t, app.Start(context.Background()))
+	assert.False(t, started)
+}
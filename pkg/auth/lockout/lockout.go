@@ -0,0 +1,236 @@
+// Package lockout guards login endpoints against brute force by locking a key, such
+// as an account or an IP, after too many failed attempts within a sliding window.
+// It pairs with crypto.Hasher: check the guard before verifying the password, record
+// the failure when verification fails and reset it on success.
+//
+// State lives in Redis so every instance sees the same counters. Failures are kept in
+// a sorted set scored by time, so only the failures of the last window count. Each
+// lockout of the same key lasts twice as long as the previous one, up to a maximum,
+// until the key goes a full decay period without being locked.
+package lockout
+
+import (
+	"context"
+	stdErrors "errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/utils"
+)
+
+// ErrLocked is the cause of the CodeTooManyRequests error returned for locked keys. The
+// error's "retry_after" context holds the remaining lockout as a time.Duration.
+var ErrLocked = stdErrors.New("too many failed attempts")
+
+// recordScript records a failure and locks the key once the window holds max failures.
+// It returns the lockout duration in milliseconds, the remaining one when the key was
+// already locked, or 0 when the key is not locked.
+//
+// KEYS: failures, locked, strikes
+// ARGV: now (ms), member, window (ms), max, base lockout (ms), max lockout (ms), decay (ms)
+var recordScript = redis.NewScript(`
+local remaining = redis.call("PTTL", KEYS[2])
+if remaining > 0 then
+	return remaining
+end
+
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+redis.call("ZADD", KEYS[1], now, ARGV[2])
+redis.call("PEXPIRE", KEYS[1], window)
+
+if redis.call("ZCARD", KEYS[1]) < tonumber(ARGV[4]) then
+	return 0
+end
+
+local strikes = redis.call("INCR", KEYS[3])
+redis.call("PEXPIRE", KEYS[3], ARGV[7])
+
+local duration = tonumber(ARGV[5]) * 2 ^ (strikes - 1)
+duration = math.min(duration, tonumber(ARGV[6]))
+redis.call("SET", KEYS[2], strikes, "PX", duration)
+redis.call("DEL", KEYS[1])
+return duration
+`)
+
+// Guard counts failed attempts in Redis. It is safe for concurrent use.
+type Guard struct {
+	client      redis.Cmdable
+	prefix      string
+	maxAttempts int
+	window      time.Duration
+	baseLockout time.Duration
+	maxLockout  time.Duration
+	decay       time.Duration
+	now         func() time.Time
+}
+
+// Option configures a Guard
+type Option func(*Guard)
+
+// WithPrefix namespaces keys, defaulting to "lockout:"
+func WithPrefix(prefix string) Option {
+	return func(g *Guard) {
+		g.prefix = prefix
+	}
+}
+
+// WithMaxAttempts sets the failures within the window that lock a key, defaulting to 5
+func WithMaxAttempts(attempts int) Option {
+	return func(g *Guard) {
+		g.maxAttempts = attempts
+	}
+}
+
+// WithWindow sets the sliding window failures are counted in, defaulting to 15 minutes
+func WithWindow(window time.Duration) Option {
+	return func(g *Guard) {
+		g.window = window
+	}
+}
+
+// WithLockoutDuration sets the duration of the first lockout and the maximum the
+// doubling stops at, defaulting to 1 minute and 1 hour
+func WithLockoutDuration(base, max time.Duration) Option {
+	return func(g *Guard) {
+		g.baseLockout = base
+		g.maxLockout = max
+	}
+}
+
+// WithDecay sets how long a key must go without a lockout for the next one to start
+// from the base duration again, defaulting to 24 hours
+func WithDecay(decay time.Duration) Option {
+	return func(g *Guard) {
+		g.decay = decay
+	}
+}
+
+// New creates a Guard using client, e.g. a *redis.Client or *redis.ClusterClient
+//
+// Example:
+//
+//	guard := lockout.New(redisClient, lockout.WithMaxAttempts(5), lockout.WithWindow(15*time.Minute))
+func New(client redis.Cmdable, opts ...Option) *Guard {
+	g := &Guard{
+		client:      client,
+		prefix:      "lockout:",
+		maxAttempts: 5,
+		window:      15 * time.Minute,
+		baseLockout: time.Minute,
+		maxLockout:  time.Hour,
+		decay:       24 * time.Hour,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.maxAttempts < 1 {
+		g.maxAttempts = 1
+	}
+	if g.maxLockout < g.baseLockout {
+		g.maxLockout = g.baseLockout
+	}
+	return g
+}
+
+// RecordFailure records a failed attempt for key. When the failure locks the key, or
+// the key was already locked, it returns true with a CodeTooManyRequests error caused
+// by ErrLocked, which handlers can return as is.
+//
+// Example:
+//
+//	if err := guard.Check(ctx, email); err != nil {
+//	    return err
+//	}
+//	if ok, _ := hasher.ComparePassword(password, user.PasswordHash); !ok {
+//	    if _, err := guard.RecordFailure(ctx, email); err != nil {
+//	        return err
+//	    }
+//	    return errors.Unauthorized("invalid credentials")
+//	}
+//	return guard.Reset(ctx, email)
+func (g *Guard) RecordFailure(ctx context.Context, key string) (bool, error) {
+	keys := []string{g.failuresKey(key), g.lockedKey(key), g.strikesKey(key)}
+	duration, err := recordScript.Run(ctx, g.client, keys,
+		g.now().UnixMilli(),
+		utils.NewUUID(),
+		g.window.Milliseconds(),
+		g.maxAttempts,
+		g.baseLockout.Milliseconds(),
+		g.maxLockout.Milliseconds(),
+		g.decay.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return false, errors.Wrap(err, errors.CodeServiceUnavailable, "failed to record failed attempt").
+			WithContext("key", key)
+	}
+	if duration <= 0 {
+		return false, nil
+	}
+	return true, locked(key, time.Duration(duration)*time.Millisecond)
+}
+
+// IsLocked reports whether key is locked
+func (g *Guard) IsLocked(ctx context.Context, key string) (bool, error) {
+	remaining, err := g.remaining(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return remaining > 0, nil
+}
+
+// Check returns a CodeTooManyRequests error caused by ErrLocked when key is locked
+func (g *Guard) Check(ctx context.Context, key string) error {
+	remaining, err := g.remaining(ctx, key)
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return locked(key, remaining)
+	}
+	return nil
+}
+
+// Reset forgets the failures and lockouts of key, typically after a successful login
+func (g *Guard) Reset(ctx context.Context, key string) error {
+	err := g.client.Del(ctx, g.failuresKey(key), g.lockedKey(key), g.strikesKey(key)).Err()
+	if err != nil {
+		return errors.Wrap(err, errors.CodeServiceUnavailable, "failed to reset failed attempts").
+			WithContext("key", key)
+	}
+	return nil
+}
+
+// remaining returns how long key stays locked, 0 when it is not
+func (g *Guard) remaining(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := g.client.PTTL(ctx, g.lockedKey(key)).Result()
+	if err != nil {
+		return 0, errors.Wrap(err, errors.CodeServiceUnavailable, "failed to check lockout").
+			WithContext("key", key)
+	}
+	// PTTL reports missing keys with negative durations
+	return max(ttl, 0), nil
+}
+
+// The key is hash-tagged so the script's keys share a Redis Cluster slot
+func (g *Guard) failuresKey(key string) string {
+	return g.prefix + "{" + key + "}:failures"
+}
+
+func (g *Guard) lockedKey(key string) string {
+	return g.prefix + "{" + key + "}:locked"
+}
+
+func (g *Guard) strikesKey(key string) string {
+	return g.prefix + "{" + key + "}:strikes"
+}
+
+func locked(key string, remaining time.Duration) *errors.AppError {
+	return errors.Wrap(ErrLocked, errors.CodeTooManyRequests, "too many failed attempts, try again later").
+		WithContext("key", key).
+		WithContext("retry_after", remaining)
+}
@@ -0,0 +1,155 @@
+package lockout
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// testClock drives both the Guard's window and miniredis' TTLs
+type testClock struct {
+	server *miniredis.Miniredis
+	now    time.Time
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+	c.server.FastForward(d)
+}
+
+func newTestGuard(t *testing.T, opts ...Option) (*Guard, *testClock) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	clock := &testClock{server: server, now: time.Unix(1_700_000_000, 0)}
+	guard := New(client, opts...)
+	guard.now = func() time.Time { return clock.now }
+	return guard, clock
+}
+
+// fail records attempts failures and returns the result of the last one
+func fail(t *testing.T, guard *Guard, key string, attempts int) (bool, error) {
+	t.Helper()
+	var locked bool
+	var err error
+	for i := 0; i < attempts; i++ {
+		locked, err = guard.RecordFailure(context.Background(), key)
+	}
+	return locked, err
+}
+
+func Test_Record_Failure_Locks_After_Max_Attempts(t *testing.T) {
+	guard, _ := newTestGuard(t, WithMaxAttempts(3))
+	ctx := context.Background()
+
+	locked, err := fail(t, guard, "alice", 2)
+	require.NoError(t, err)
+	assert.False(t, locked)
+	require.NoError(t, guard.Check(ctx, "alice"))
+
+	locked, err = guard.RecordFailure(ctx, "alice")
+	assert.True(t, locked)
+	assert.True(t, stdErrors.Is(err, ErrLocked))
+	assert.True(t, errors.Is(err, errors.CodeTooManyRequests))
+
+	isLocked, err := guard.IsLocked(ctx, "alice")
+	require.NoError(t, err)
+	assert.True(t, isLocked)
+
+	err = guard.Check(ctx, "alice")
+	assert.True(t, errors.Is(err, errors.CodeTooManyRequests))
+	appErr, ok := errors.As(err)
+	require.True(t, ok)
+	assert.Equal(t, time.Minute, appErr.Context["retry_after"])
+
+	isLocked, err = guard.IsLocked(ctx, "bob")
+	require.NoError(t, err)
+	assert.False(t, isLocked)
+}
+
+func Test_Record_Failure_Counts_Only_The_Sliding_Window(t *testing.T) {
+	guard, clock := newTestGuard(t, WithMaxAttempts(3), WithWindow(time.Minute))
+
+	_, err := fail(t, guard, "alice", 2)
+	require.NoError(t, err)
+
+	// The first two failures leave the window before the third one
+	clock.advance(61 * time.Second)
+	locked, err := fail(t, guard, "alice", 2)
+	require.NoError(t, err)
+	assert.False(t, locked)
+
+	locked, err = guard.RecordFailure(context.Background(), "alice")
+	assert.True(t, locked)
+	assert.Error(t, err)
+}
+
+func Test_Lockout_Duration_Doubles_Up_To_Max(t *testing.T) {
+	guard, clock := newTestGuard(t, WithMaxAttempts(2), WithLockoutDuration(time.Minute, 3*time.Minute))
+	ctx := context.Background()
+
+	for _, expected := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute} {
+		locked, err := fail(t, guard, "alice", 2)
+		require.True(t, locked)
+		appErr, ok := errors.As(err)
+		require.True(t, ok)
+		assert.Equal(t, expected, appErr.Context["retry_after"])
+
+		clock.advance(expected - time.Second)
+		isLocked, err := guard.IsLocked(ctx, "alice")
+		require.NoError(t, err)
+		assert.True(t, isLocked)
+
+		clock.advance(time.Second)
+		isLocked, err = guard.IsLocked(ctx, "alice")
+		require.NoError(t, err)
+		assert.False(t, isLocked)
+	}
+}
+
+func Test_Failures_While_Locked_Do_Not_Extend_The_Lockout(t *testing.T) {
+	guard, clock := newTestGuard(t, WithMaxAttempts(2))
+
+	_, err := fail(t, guard, "alice", 2)
+	require.Error(t, err)
+
+	clock.advance(30 * time.Second)
+	locked, err := fail(t, guard, "alice", 10)
+	assert.True(t, locked)
+	appErr, ok := errors.As(err)
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Second, appErr.Context["retry_after"])
+
+	// The attempts made while locked were not counted
+	clock.advance(30 * time.Second)
+	locked, err = guard.RecordFailure(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.False(t, locked)
+}
+
+func Test_Reset_Clears_Failures_And_Lockouts(t *testing.T) {
+	guard, _ := newTestGuard(t, WithMaxAttempts(2))
+	ctx := context.Background()
+
+	_, err := fail(t, guard, "alice", 2)
+	require.Error(t, err)
+
+	require.NoError(t, guard.Reset(ctx, "alice"))
+	require.NoError(t, guard.Check(ctx, "alice"))
+
+	// The next lockout starts from the base duration again
+	_, err = fail(t, guard, "alice", 2)
+	appErr, ok := errors.As(err)
+	require.True(t, ok)
+	assert.Equal(t, time.Minute, appErr.Context["retry_after"])
+}
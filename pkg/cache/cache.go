@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	stdErrors "errors"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// ErrMiss is the cause of the CodeNotFound error returned by Get for a key that isn't
+// cached, or has expired
+var ErrMiss = stdErrors.New("cache miss")
+
+// Cache stores values by key, encoded with the cache's Codec. Implementations are safe
+// for concurrent use.
+type Cache interface {
+	// Get decodes the value cached for key into v, a pointer. It returns a CodeNotFound
+	// error caused by ErrMiss when there is none.
+	Get(ctx context.Context, key string, v any) error
+
+	// Set caches v for key for ttl, or without expiry when ttl is zero
+	Set(ctx context.Context, key string, v any, ttl time.Duration) error
+
+	// Delete removes the value cached for key, if any
+	Delete(ctx context.Context, key string) error
+}
+
+// Option configures a cache
+type Option func(*options)
+
+type options struct {
+	codec  Codec
+	prefix string
+}
+
+// WithCodec sets the codec encoding cached values, defaulting to DefaultCodec
+//
+// Example:
+//
+//	orders := cache.NewRedis(client, cache.WithCodec(cache.MsgpackCodec{}))
+func WithCodec(codec Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+// WithPrefix namespaces cache keys, defaulting to "cache:"
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{codec: DefaultCodec, prefix: "cache:"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func miss(key string) *errors.AppError {
+	return errors.Wrap(ErrMiss, errors.CodeNotFound, "cache miss").
+		WithContext("key", key)
+}
+
+func checkTTL(ttl time.Duration) error {
+	if ttl < 0 {
+		return errors.BadRequest("cache ttl must not be negative")
+	}
+	return nil
+}
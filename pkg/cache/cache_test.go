@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+func newTestRedisCache(t *testing.T, opts ...Option) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedis(client, opts...), server
+}
+
+// newTestCaches returns a cache of each kind configured with opts
+func newTestCaches(t *testing.T, opts ...Option) map[string]Cache {
+	t.Helper()
+	redisCache, _ := newTestRedisCache(t, opts...)
+	return map[string]Cache{
+		"redis":  redisCache,
+		"memory": NewMemory(opts...),
+	}
+}
+
+func Test_Caches_Round_Trip_With_Each_Codec(t *testing.T) {
+	ctx := context.Background()
+	order := newCachedOrder(3)
+
+	for codecName, codec := range codecs {
+		for cacheName, cache := range newTestCaches(t, WithCodec(codec)) {
+			t.Run(cacheName+"/"+codecName, func(t *testing.T) {
+				require.NoError(t, cache.Set(ctx, "order:1", order, time.Minute))
+
+				var cached cachedOrder
+				require.NoError(t, cache.Get(ctx, "order:1", &cached))
+				assert.True(t, order.CreatedAt.Equal(cached.CreatedAt))
+				cached.CreatedAt = order.CreatedAt
+				assert.Equal(t, order, cached)
+			})
+		}
+	}
+}
+
+func Test_RedisCache_Stores_Values_With_Its_Codec(t *testing.T) {
+	ctx := context.Background()
+	order := newCachedOrder(3)
+
+	cache, server := newTestRedisCache(t, WithCodec(MsgpackCodec{}), WithPrefix("orders:"))
+	require.NoError(t, cache.Set(ctx, "1", order, time.Minute))
+
+	stored, err := server.Get("orders:1")
+	require.NoError(t, err)
+	encoded, err := MsgpackCodec{}.Marshal(order)
+	require.NoError(t, err)
+	assert.Equal(t, string(encoded), stored)
+	assert.Equal(t, time.Minute, server.TTL("orders:1"))
+
+	// Without WithCodec values are JSON
+	cache, server = newTestRedisCache(t)
+	require.NoError(t, cache.Set(ctx, "1", order, 0))
+	stored, err = server.Get("cache:1")
+	require.NoError(t, err)
+	assert.Contains(t, stored, `"Customer":"acme@example.com"`)
+}
+
+func Test_Caches_Report_Misses(t *testing.T) {
+	ctx := context.Background()
+
+	for name, cache := range newTestCaches(t) {
+		t.Run(name, func(t *testing.T) {
+			var cached cachedOrder
+			err := cache.Get(ctx, "missing", &cached)
+			assert.True(t, stdErrors.Is(err, ErrMiss))
+			assert.True(t, errors.Is(err, errors.CodeNotFound))
+
+			require.NoError(t, cache.Set(ctx, "order", newCachedOrder(1), 0))
+			require.NoError(t, cache.Delete(ctx, "order"))
+			assert.True(t, stdErrors.Is(cache.Get(ctx, "order", &cached), ErrMiss))
+
+			assert.True(t, errors.Is(cache.Set(ctx, "order", newCachedOrder(1), -time.Second), errors.CodeBadRequest))
+		})
+	}
+}
+
+func Test_Caches_Expire_Values(t *testing.T) {
+	ctx := context.Background()
+	var cached cachedOrder
+
+	redisCache, server := newTestRedisCache(t)
+	require.NoError(t, redisCache.Set(ctx, "order", newCachedOrder(1), time.Minute))
+	server.FastForward(time.Minute)
+	assert.True(t, stdErrors.Is(redisCache.Get(ctx, "order", &cached), ErrMiss))
+
+	now := time.Now()
+	memoryCache := NewMemory()
+	memoryCache.now = func() time.Time { return now }
+	require.NoError(t, memoryCache.Set(ctx, "order", newCachedOrder(1), time.Minute))
+	require.NoError(t, memoryCache.Set(ctx, "forever", newCachedOrder(1), 0))
+
+	now = now.Add(time.Minute)
+	assert.True(t, stdErrors.Is(memoryCache.Get(ctx, "order", &cached), ErrMiss))
+	assert.NoError(t, memoryCache.Get(ctx, "forever", &cached))
+	assert.Len(t, memoryCache.entries, 1)
+}
@@ -0,0 +1,91 @@
+// Package cache provides key-value caches backed by Redis or by memory. Codecs encode the
+// values stored in a cache backend, so that each cache instance can trade JSON's
+// readability for the speed and size of a binary format (see WithCodec).
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/json"
+)
+
+// Codec encodes cached values to bytes and back. Implementations must be safe for
+// concurrent use.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// DefaultCodec is the codec used by caches that don't configure one
+var DefaultCodec Codec = JSONCodec{}
+
+// JSONCodec encodes values as JSON with pkg/json. Values stay readable with redis-cli and
+// from other languages.
+type JSONCodec struct{}
+
+// Marshal encodes v as JSON
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to encode cached value")
+	}
+	return data, nil
+}
+
+// Unmarshal decodes JSON data into v
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "failed to decode cached value")
+	}
+	return nil
+}
+
+// GobCodec encodes values with encoding/gob. Encoded values are much smaller than JSON
+// for large structs and keep exact Go types, but each value carries its type description,
+// making it slower than JSON for single values, and values can only be read back by Go
+// programs. Interface-typed fields need gob.Register. See BenchmarkCodecs.
+type GobCodec struct{}
+
+// Marshal encodes v with gob
+func (GobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to encode cached value")
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes gob data into v
+func (GobCodec) Unmarshal(data []byte, v any) error {
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(v); err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "failed to decode cached value")
+	}
+	return nil
+}
+
+// MsgpackCodec encodes values as MessagePack, a compact binary JSON. Values are smaller
+// and faster to encode and decode than JSON, and can still be read from other
+// languages. Struct fields are named by their `msgpack` tag, else their Go name, and
+// times decode in local time.
+type MsgpackCodec struct{}
+
+// Marshal encodes v as MessagePack
+func (MsgpackCodec) Marshal(v any) ([]byte, error) {
+	data, err := msgpack.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInternal, "failed to encode cached value")
+	}
+	return data, nil
+}
+
+// Unmarshal decodes MessagePack data into v
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	if err := msgpack.Unmarshal(data, v); err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "failed to decode cached value")
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+type cachedOrder struct {
+	ID        int64
+	Customer  string
+	Total     int64
+	Tags      []string
+	Lines     []cachedOrderLine
+	CreatedAt time.Time
+}
+
+type cachedOrderLine struct {
+	SKU      string
+	Quantity int
+	Price    int64
+}
+
+// newCachedOrder returns a representative cached object with lines order lines
+func newCachedOrder(lines int) cachedOrder {
+	order := cachedOrder{
+		ID:        9007199254740993,
+		Customer:  "acme@example.com",
+		Total:     1250_00,
+		Tags:      []string{"priority", "wholesale"},
+		CreatedAt: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+	}
+	for i := 0; i < lines; i++ {
+		order.Lines = append(order.Lines, cachedOrderLine{SKU: fmt.Sprintf("SKU-%05d", i), Quantity: i%7 + 1, Price: 19_99})
+	}
+	return order
+}
+
+var codecs = map[string]Codec{
+	"json":    JSONCodec{},
+	"gob":     GobCodec{},
+	"msgpack": MsgpackCodec{},
+}
+
+func Test_Codecs_Round_Trip(t *testing.T) {
+	order := newCachedOrder(3)
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			data, err := codec.Marshal(order)
+			require.NoError(t, err)
+
+			var decoded cachedOrder
+			require.NoError(t, codec.Unmarshal(data, &decoded))
+			if name == "msgpack" {
+				// MessagePack timestamps carry no zone and decode in local time
+				decoded.CreatedAt = decoded.CreatedAt.UTC()
+			}
+			assert.Equal(t, order, decoded)
+		})
+	}
+}
+
+func Test_Codecs_Return_AppErrors(t *testing.T) {
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			var decoded cachedOrder
+			err := codec.Unmarshal([]byte("not encoded"), &decoded)
+			assert.True(t, errors.Is(err, errors.CodeInternal))
+
+			_, err = codec.Marshal(make(chan int))
+			assert.True(t, errors.Is(err, errors.CodeInternal))
+		})
+	}
+}
+
+func Test_Default_Codec_Is_JSON(t *testing.T) {
+	assert.Equal(t, JSONCodec{}, DefaultCodec)
+}
+
+// BenchmarkCodecs compares encoding and decoding a large cached object
+func BenchmarkCodecs(b *testing.B) {
+	order := newCachedOrder(200)
+
+	for _, name := range []string{"json", "gob", "msgpack"} {
+		codec := codecs[name]
+		data, err := codec.Marshal(order)
+		require.NoError(b, err)
+
+		b.Run(name+"/marshal", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.Marshal(order); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/value")
+		})
+
+		b.Run(name+"/unmarshal", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var decoded cachedOrder
+				if err := codec.Unmarshal(data, &decoded); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
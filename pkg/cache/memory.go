@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryCache is a Cache storing values in the memory of the process, e.g. for tests or
+// single-instance deployments. Values are stored encoded, so callers never share them,
+// and expired entries are dropped when read or overwritten.
+type MemoryCache struct {
+	options
+
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+var _ Cache = (*MemoryCache)(nil)
+
+// NewMemory creates an empty MemoryCache
+func NewMemory(opts ...Option) *MemoryCache {
+	return &MemoryCache{
+		options: newOptions(opts),
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// Get decodes the value cached for key into v
+func (c *MemoryCache) Get(_ context.Context, key string, v any) error {
+	c.mu.RLock()
+	entry, ok := c.entries[c.prefix+key]
+	c.mu.RUnlock()
+
+	if !ok || c.expired(entry) {
+		if ok {
+			c.mu.Lock()
+			if entry, ok := c.entries[c.prefix+key]; ok && c.expired(entry) {
+				delete(c.entries, c.prefix+key)
+			}
+			c.mu.Unlock()
+		}
+		return miss(key)
+	}
+	return c.codec.Unmarshal(entry.data, v)
+}
+
+// Set caches v for key for ttl, or without expiry when ttl is zero
+func (c *MemoryCache) Set(_ context.Context, key string, v any, ttl time.Duration) error {
+	if err := checkTTL(ttl); err != nil {
+		return err
+	}
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	entry := memoryEntry{data: data}
+	if ttl > 0 {
+		entry.expiresAt = c.now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	c.entries[c.prefix+key] = entry
+	c.mu.Unlock()
+	return nil
+}
+
+// Delete removes the value cached for key, if any
+func (c *MemoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	delete(c.entries, c.prefix+key)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *MemoryCache) expired(entry memoryEntry) bool {
+	return !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt)
+}
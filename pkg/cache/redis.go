@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	stdErrors "errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// RedisCache is a Cache storing values in Redis, shared by every instance of the
+// application
+type RedisCache struct {
+	client redis.Cmdable
+	options
+}
+
+var _ Cache = (*RedisCache)(nil)
+
+// NewRedis creates a RedisCache using client, e.g. a *redis.Client or
+// *redis.ClusterClient
+//
+// Example:
+//
+//	sessions := cache.NewRedis(redis.NewClient(&redis.Options{Addr: cfg.Redis.Address()}),
+//	    cache.WithPrefix("sessions:"), cache.WithCodec(cache.MsgpackCodec{}))
+func NewRedis(client redis.Cmdable, opts ...Option) *RedisCache {
+	return &RedisCache{client: client, options: newOptions(opts)}
+}
+
+// Get decodes the value cached for key into v
+func (c *RedisCache) Get(ctx context.Context, key string, v any) error {
+	data, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if stdErrors.Is(err, redis.Nil) {
+		return miss(key)
+	}
+	if err != nil {
+		return errors.Wrap(err, errors.CodeServiceUnavailable, "failed to read cache").
+			WithContext("key", key)
+	}
+	return c.codec.Unmarshal(data, v)
+}
+
+// Set caches v for key for ttl, or without expiry when ttl is zero
+func (c *RedisCache) Set(ctx context.Context, key string, v any, ttl time.Duration) error {
+	if err := checkTTL(ttl); err != nil {
+		return err
+	}
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := c.client.Set(ctx, c.prefix+key, data, ttl).Err(); err != nil {
+		return errors.Wrap(err, errors.CodeServiceUnavailable, "failed to write cache").
+			WithContext("key", key)
+	}
+	return nil
+}
+
+// Delete removes the value cached for key, if any
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	if err := c.client.Del(ctx, c.prefix+key).Err(); err != nil {
+		return errors.Wrap(err, errors.CodeServiceUnavailable, "failed to delete from cache").
+			WithContext("key", key)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Package correlation propagates a request/correlation ID through context.Context,
+// so logs, errors and traces of a single request can be tied together.
+//
+// The HTTP adapters read the ID from the HeaderName request header (generating one
+// when it is missing), store it with NewContext and echo it in the response.
+// Logs and error responses use FieldName as the key.
+package correlation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	// HeaderName is the HTTP header carrying the correlation ID
+	HeaderName = "X-Request-ID"
+
+	// FieldName is the key used for the correlation ID in log fields and error context
+	FieldName = "request_id"
+
+	// maxIDLength bounds IDs accepted from incoming headers
+	maxIDLength = 128
+)
+
+type contextKey struct{}
+
+// NewID generates a new random correlation ID (a version 4 UUID)
+func NewID() string {
+	var u [16]byte
+	_, _ = rand.Read(u[:])
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// NewContext returns a copy of ctx carrying the correlation ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the correlation ID carried by ctx, or an empty string
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// FromHeader returns a usable correlation ID from an incoming header value,
+// generating a new one when it is missing or invalid
+func FromHeader(value string) string {
+	if isValidID(value) {
+		return value
+	}
+	return NewID()
+}
+
+// isValidID rejects empty, oversized or non-printable IDs, which could be used
+// to inject content into logs
+func isValidID(id string) bool {
+	if id == "" || len(id) > maxIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// Middleware is a net/http middleware that propagates the correlation ID from the
+// HeaderName request header (or a new one) into the request context and the response
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := FromHeader(r.Header.Get(HeaderName))
+
+		w.Header().Set(HeaderName, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
@@ -0,0 +1,56 @@
+package correlation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewID_Is_Unique(t *testing.T) {
+	first, second := NewID(), NewID()
+
+	assert.Len(t, first, 36)
+	assert.NotEqual(t, first, second)
+}
+
+func Test_Context_Round_Trip(t *testing.T) {
+	ctx := NewContext(context.Background(), "req-1")
+
+	assert.Equal(t, "req-1", FromContext(ctx))
+	assert.Empty(t, FromContext(context.Background()))
+}
+
+func Test_FromHeader_Rejects_Invalid_IDs(t *testing.T) {
+	assert.Equal(t, "req-1", FromHeader("req-1"))
+
+	for _, value := range []string{"", "line\nbreak", "with space", strings.Repeat("a", 200)} {
+		id := FromHeader(value)
+		assert.NotEqual(t, value, id)
+		assert.Len(t, id, 36)
+	}
+}
+
+func Test_Middleware_Propagates_ID(t *testing.T) {
+	var seen string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderName, "req-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "req-1", seen)
+	assert.Equal(t, "req-1", rec.Header().Get(HeaderName))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.NotEmpty(t, seen)
+	assert.Equal(t, seen, rec.Header().Get(HeaderName))
+}
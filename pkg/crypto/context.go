@@ -0,0 +1,26 @@
+package crypto
+
+import "context"
+
+type claimsContextKey struct{}
+
+// ContextWithClaims returns a copy of ctx carrying the authenticated token claims.
+// Authentication middlewares call it after verifying a token, so downstream code
+// (handlers, audit logging) can read the caller with ClaimsFromContext.
+//
+// Example:
+//
+//	claims, err := manager.ParseToken(token)
+//	if err != nil {
+//	    return err
+//	}
+//	c.SetUserContext(crypto.ContextWithClaims(c.UserContext(), claims))
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims stored by ContextWithClaims, if any
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
@@ -0,0 +1,122 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// DefaultSignatureTolerance is the maximum age of a timestamped signature
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// Timestamped signature header elements, e.g. "t=1700000000,v1=5257a869..."
+const (
+	signatureTimestampKey = "t"
+	signatureV1Key        = "v1"
+)
+
+// computeHMAC returns the HMAC-SHA256 of payload
+func computeHMAC(secret, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// SignHMAC returns the hex encoded HMAC-SHA256 signature of payload
+func SignHMAC(secret, payload []byte) string {
+	return hex.EncodeToString(computeHMAC(secret, payload))
+}
+
+// SignHMACBase64 returns the base64 encoded HMAC-SHA256 signature of payload
+func SignHMACBase64(secret, payload []byte) string {
+	return base64.StdEncoding.EncodeToString(computeHMAC(secret, payload))
+}
+
+// VerifyHMAC reports whether signature (hex or base64 encoded) is the HMAC-SHA256
+// signature of payload. The comparison is constant-time.
+func VerifyHMAC(secret, payload []byte, signature string) bool {
+	expected := computeHMAC(secret, payload)
+
+	decoded, err := hex.DecodeString(signature)
+	if err != nil {
+		if decoded, err = base64.StdEncoding.DecodeString(signature); err != nil {
+			return false
+		}
+	}
+
+	return hmac.Equal(expected, decoded)
+}
+
+// SignHMACWithTimestamp returns a timestamped signature header value
+// ("t=<unix>,v1=<hex signature>"). The signed content is "<unix>.<payload>",
+// so the timestamp can't be changed without invalidating the signature.
+func SignHMACWithTimestamp(secret, payload []byte, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("%s=%s,%s=%s",
+		signatureTimestampKey, timestamp,
+		signatureV1Key, SignHMAC(secret, timestampedPayload(timestamp, payload)))
+}
+
+// VerifyHMACWithTimestamp verifies a header produced by SignHMACWithTimestamp and
+// rejects signatures older (or further in the future) than tolerance to prevent
+// replays. Several v1 signatures are accepted, e.g. while rotating secrets.
+// All failures are CodeUnauthorized errors.
+func VerifyHMACWithTimestamp(secret, payload []byte, header string, tolerance time.Duration) error {
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case signatureTimestampKey:
+			timestamp = value
+		case signatureV1Key:
+			signatures = append(signatures, value)
+		}
+	}
+
+	if timestamp == "" || len(signatures) == 0 {
+		return errors.Unauthorized("invalid signature header")
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.Unauthorized("invalid signature timestamp")
+	}
+
+	age := time.Since(time.Unix(unix, 0))
+	if age > tolerance || age < -tolerance {
+		return errors.Unauthorized("signature timestamp outside tolerance").
+			WithDetails(fmt.Sprintf("tolerance: %s", tolerance))
+	}
+
+	signed := timestampedPayload(timestamp, payload)
+	for _, signature := range signatures {
+		if VerifyHMAC(secret, signed, signature) {
+			return nil
+		}
+	}
+
+	return errors.Unauthorized("signature mismatch")
+}
+
+// timestampedPayload returns the content signed by timestamped signatures
+func timestampedPayload(timestamp string, payload []byte) []byte {
+	signed := make([]byte, 0, len(timestamp)+1+len(payload))
+	signed = append(signed, timestamp...)
+	signed = append(signed, '.')
+	return append(signed, payload...)
+}
@@ -0,0 +1,73 @@
+package crypto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+func Test_SignHMAC_Known_Vector(t *testing.T) {
+	// RFC 4231 test case 2
+	signature := SignHMAC([]byte("Jefe"), []byte("what do ya want for nothing?"))
+	assert.Equal(t, "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", signature)
+}
+
+func Test_VerifyHMAC_Accepts_Hex_And_Base64(t *testing.T) {
+	secret := []byte("webhook-secret")
+	payload := []byte(`{"event":"payment.succeeded","amount":1000}`)
+
+	assert.True(t, VerifyHMAC(secret, payload, SignHMAC(secret, payload)))
+	assert.True(t, VerifyHMAC(secret, payload, SignHMACBase64(secret, payload)))
+}
+
+func Test_VerifyHMAC_Rejects_Tampered_Payload(t *testing.T) {
+	secret := []byte("webhook-secret")
+	signature := SignHMAC(secret, []byte(`{"amount":1000}`))
+
+	assert.False(t, VerifyHMAC(secret, []byte(`{"amount":9999}`), signature))
+	assert.False(t, VerifyHMAC([]byte("other-secret"), []byte(`{"amount":1000}`), signature))
+	assert.False(t, VerifyHMAC(secret, []byte(`{"amount":1000}`), "not a signature"))
+}
+
+func Test_VerifyHMACWithTimestamp(t *testing.T) {
+	secret := []byte("webhook-secret")
+	payload := []byte(`{"amount":1000}`)
+
+	header := SignHMACWithTimestamp(secret, payload, time.Now())
+	require.NoError(t, VerifyHMACWithTimestamp(secret, payload, header, time.Minute))
+
+	tests := []struct {
+		name    string
+		payload []byte
+		header  string
+	}{
+		{"tampered payload", []byte(`{"amount":9999}`), header},
+		{"stale timestamp", payload, SignHMACWithTimestamp(secret, payload, time.Now().Add(-time.Hour))},
+		{"missing signature", payload, "t=1700000000"},
+		{"malformed header", payload, "garbage"},
+		{"wrong secret", payload, SignHMACWithTimestamp([]byte("other"), payload, time.Now())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyHMACWithTimestamp(secret, tt.payload, tt.header, time.Minute)
+			assert.True(t, errors.Is(err, errors.CodeUnauthorized), "got %v", err)
+		})
+	}
+}
+
+func Test_VerifyHMACWithTimestamp_Accepts_Any_Signature_During_Rotation(t *testing.T) {
+	payload := []byte(`{"amount":1000}`)
+	now := time.Now()
+
+	oldHeader := SignHMACWithTimestamp([]byte("old-secret"), payload, now)
+	newHeader := SignHMACWithTimestamp([]byte("new-secret"), payload, now)
+	header := oldHeader + "," + newHeader[len("t=1700000000,"):]
+
+	assert.NoError(t, VerifyHMACWithTimestamp([]byte("new-secret"), payload, header, time.Minute))
+	assert.NoError(t, VerifyHMACWithTimestamp([]byte("old-secret"), payload, header, time.Minute))
+}
@@ -0,0 +1,368 @@
+package crypto
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/json"
+)
+
+// JWKSPath is the well-known path where the JWKS is served
+const JWKSPath = "/.well-known/jwks.json"
+
+const (
+	// DefaultJWKSCacheTTL is how long a remote JWKS is cached before being refetched
+	DefaultJWKSCacheTTL = 5 * time.Minute
+
+	// jwksMinRefreshInterval is the least time between refetches, failed ones
+	// included, so tokens with random "kid" headers or an unreachable issuer can't
+	// turn each verification into a fetch
+	jwksMinRefreshInterval = 30 * time.Second
+
+	// jwksMaxBodySize bounds the size of a fetched JWKS document
+	jwksMaxBodySize = 1 << 20
+)
+
+// JWK is a JSON Web Key (RFC 7517) holding an RSA or ECDSA public key
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// RSA parameters
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// ECDSA parameters
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet is a JSON Web Key Set
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeySource resolves the key used to verify a token signed with alg by the key kid
+type KeySource interface {
+	VerificationKey(kid, alg string) (interface{}, error)
+}
+
+// NewJWK creates a signing JWK from an *rsa.PublicKey or *ecdsa.PublicKey
+func NewJWK(kid string, alg JWTAlgorithm, publicKey interface{}) (JWK, error) {
+	jwk := JWK{Use: "sig", Kid: kid, Alg: string(alg)}
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		point, err := key.Bytes()
+		if err != nil {
+			return JWK{}, errors.Wrap(err, errors.CodeInternal, "failed to encode ECDSA public key")
+		}
+		// Uncompressed point: 0x04 || X || Y
+		size := (len(point) - 1) / 2
+		jwk.Kty = "EC"
+		jwk.Crv = key.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(point[1 : 1+size])
+		jwk.Y = base64.RawURLEncoding.EncodeToString(point[1+size:])
+	default:
+		return JWK{}, errors.BadRequest("unsupported public key type").
+			WithDetails(fmt.Sprintf("type: %T", publicKey))
+	}
+
+	return jwk, nil
+}
+
+// PublicKey decodes the JWK into an *rsa.PublicKey or *ecdsa.PublicKey
+func (k JWK) PublicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil || len(n) == 0 {
+			return nil, errors.BadRequest("invalid RSA modulus in JWK")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, errors.BadRequest("invalid RSA exponent in JWK")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		curve, ok := curveByName(k.Crv)
+		if !ok {
+			return nil, errors.BadRequest("unsupported JWK curve").
+				WithDetails(fmt.Sprintf("crv: %s", k.Crv))
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		size := (curve.Params().BitSize + 7) / 8
+		if errX != nil || errY != nil || len(x) != size || len(y) != size {
+			return nil, errors.BadRequest("invalid EC coordinates in JWK")
+		}
+
+		point := append([]byte{4}, append(x, y...)...)
+		key, err := ecdsa.ParseUncompressedPublicKey(curve, point)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.CodeBadRequest, "invalid EC public key in JWK")
+		}
+		return key, nil
+	default:
+		return nil, errors.BadRequest("unsupported JWK key type").
+			WithDetails(fmt.Sprintf("kty: %s", k.Kty))
+	}
+}
+
+// curveByName returns the elliptic curve for a JWK "crv" value
+func curveByName(name string) (elliptic.Curve, bool) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), true
+	case "P-384":
+		return elliptic.P384(), true
+	case "P-521":
+		return elliptic.P521(), true
+	default:
+		return nil, false
+	}
+}
+
+// Key returns the key with the given ID. An empty kid matches a set with a single key.
+func (s *JWKSet) Key(kid string) (JWK, bool) {
+	if kid == "" && len(s.Keys) == 1 {
+		return s.Keys[0], true
+	}
+	for _, key := range s.Keys {
+		if key.Kid == kid {
+			return key, true
+		}
+	}
+	return JWK{}, false
+}
+
+// VerificationKey implements KeySource
+func (s *JWKSet) VerificationKey(kid, alg string) (interface{}, error) {
+	jwk, ok := s.Key(kid)
+	if !ok {
+		return nil, errors.Unauthorized("unknown token signing key").
+			WithDetails(fmt.Sprintf("kid: %s", kid))
+	}
+	if jwk.Alg != "" && jwk.Alg != alg {
+		return nil, errors.Unauthorized("invalid token algorithm").
+			WithDetails(fmt.Sprintf("key %s expects %s, got %s", kid, jwk.Alg, alg))
+	}
+	return jwk.PublicKey()
+}
+
+// JWKS returns the manager's public key as a JWK set.
+// HMAC managers have no public key and return an error.
+func (m *JWTManager) JWKS() (*JWKSet, error) {
+	var publicKey interface{}
+	switch m.config.Algorithm {
+	case AlgorithmRS256, AlgorithmRS384, AlgorithmRS512:
+		publicKey = m.config.PublicKey
+	case AlgorithmES256, AlgorithmES384, AlgorithmES512:
+		publicKey = m.config.ECDSAPublicKey
+	default:
+		return nil, errors.BadRequest("JWKS is only available for asymmetric algorithms")
+	}
+	if m.keys != nil || publicKey == nil {
+		return nil, errors.BadRequest("JWT manager has no public key to publish")
+	}
+
+	jwk, err := NewJWK(m.config.KeyID, m.config.Algorithm, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &JWKSet{Keys: []JWK{jwk}}, nil
+}
+
+// JWKSHandler serves the manager's JWKS, typically at JWKSPath.
+//
+// Example:
+//
+//	routes.RegisterHttpHandler(http.MethodGet, crypto.JWKSPath, crypto.JWKSHandler(manager))
+func JWKSHandler(m *JWTManager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		set, err := m.JWKS()
+		if err != nil {
+			errors.RespondWithProblem(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(DefaultJWKSCacheTTL.Seconds())))
+		_ = json.NewEncoder(w).Encode(set)
+	})
+}
+
+// RemoteJWKS is an issuer's JWKS fetched over HTTP and cached.
+// It refetches the set when the cache expires or when a token references an unknown key.
+type RemoteJWKS struct {
+	url    string
+	client *http.Client
+	ttl    time.Duration
+
+	mu          sync.RWMutex
+	set         *JWKSet
+	fetchedAt   time.Time
+	attemptedAt time.Time // last refresh, successful or not
+
+	// refreshing is held by the refresh VerificationKey runs, so that concurrent
+	// verifications share one fetch
+	refreshing sync.Mutex
+	now        func() time.Time
+}
+
+// LoadJWKSFromURL fetches an issuer's JWKS and caches it for DefaultJWKSCacheTTL.
+//
+// Example:
+//
+//	keys, err := crypto.LoadJWKSFromURL("https://auth.example.com/.well-known/jwks.json")
+//	verifier, err := crypto.NewJWTVerifier(&crypto.JWTConfig{
+//	    Algorithm: crypto.AlgorithmRS256,
+//	    Issuer:    "auth.example.com",
+//	    Audience:  "orders-api",
+//	}, keys)
+func LoadJWKSFromURL(url string) (*RemoteJWKS, error) {
+	remote := &RemoteJWKS{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		ttl:    DefaultJWKSCacheTTL,
+		now:    time.Now,
+	}
+
+	if err := remote.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	return remote, nil
+}
+
+// Refresh refetches the key set
+func (r *RemoteJWKS) Refresh(ctx context.Context) error {
+	r.mu.Lock()
+	r.attemptedAt = r.now()
+	r.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.CodeBadRequest, "invalid JWKS URL")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errors.CodeServiceUnavailable, "failed to fetch JWKS")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(errors.CodeServiceUnavailable, "failed to fetch JWKS").
+			WithDetails(fmt.Sprintf("status: %d", resp.StatusCode))
+	}
+
+	var set JWKSet
+	if err := json.NewDecoder(io.LimitReader(resp.Body, jwksMaxBodySize)).Decode(&set); err != nil {
+		return errors.Wrap(err, errors.CodeInternal, "failed to decode JWKS")
+	}
+
+	r.mu.Lock()
+	r.set = &set
+	r.fetchedAt = r.now()
+	r.mu.Unlock()
+	return nil
+}
+
+// Keys returns the cached key set
+func (r *RemoteJWKS) Keys() *JWKSet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.set
+}
+
+// VerificationKey implements KeySource, refetching the set when it has expired
+// or doesn't contain kid. Refetches are at least jwksMinRefreshInterval apart, failed
+// ones included, and run one at a time: while the issuer is unreachable, or while
+// another verification refetches, keys of the cached set keep verifying.
+func (r *RemoteJWKS) VerificationKey(kid, alg string) (interface{}, error) {
+	set, refresh := r.needsRefresh(kid)
+	if !refresh {
+		return set.VerificationKey(kid, alg)
+	}
+
+	if _, known := set.Key(kid); known {
+		if !r.refreshing.TryLock() {
+			return set.VerificationKey(kid, alg)
+		}
+	} else {
+		// Unknown keys wait for a refetch in progress, which may bring them
+		r.refreshing.Lock()
+	}
+	defer r.refreshing.Unlock()
+
+	// Another verification may have refetched while we waited
+	if _, refresh := r.needsRefresh(kid); refresh {
+		// A failure keeps the cached set, so there is nothing else to do about it
+		_ = r.Refresh(context.Background())
+	}
+	return r.Keys().VerificationKey(kid, alg)
+}
+
+// needsRefresh returns the cached set and whether verifying kid needs a refetch
+func (r *RemoteJWKS) needsRefresh(kid string) (*JWKSet, bool) {
+	r.mu.RLock()
+	set, age, sinceAttempt := r.set, r.now().Sub(r.fetchedAt), r.now().Sub(r.attemptedAt)
+	r.mu.RUnlock()
+
+	if sinceAttempt <= jwksMinRefreshInterval {
+		return set, false
+	}
+	_, known := set.Key(kid)
+	return set, age > r.ttl || !known
+}
+
+// NewJWTVerifier creates a verification-only JWT manager that resolves public keys
+// from keys (a JWKSet or RemoteJWKS). It validates algorithm, issuer and audience
+// like NewJWTManager but cannot generate tokens.
+func NewJWTVerifier(config *JWTConfig, keys KeySource) (*JWTManager, error) {
+	if config == nil {
+		return nil, errors.BadRequest("JWT config cannot be nil")
+	}
+	if keys == nil {
+		return nil, errors.BadRequest("key source is required for a JWT verifier")
+	}
+
+	switch config.Algorithm {
+	case AlgorithmRS256, AlgorithmRS384, AlgorithmRS512,
+		AlgorithmES256, AlgorithmES384, AlgorithmES512:
+	default:
+		return nil, errors.BadRequest("JWT verifier requires an asymmetric algorithm").
+			WithDetails(fmt.Sprintf("algorithm: %s", config.Algorithm))
+	}
+
+	return &JWTManager{config: config, keys: keys}, nil
+}
+
+// JWKS returns the default JWT manager's public key as a JWK set
+func JWKS() (*JWKSet, error) {
+	if defaultJWTManager == nil {
+		return nil, errors.Internal("JWT manager not initialized")
+	}
+	return defaultJWTManager.JWKS()
+}
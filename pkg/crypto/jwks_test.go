@@ -0,0 +1,269 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+func newRSAManager(t *testing.T, kid string) *JWTManager {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	config := DefaultJWTConfig()
+	config.Algorithm = AlgorithmRS256
+	config.PrivateKey = key
+	config.PublicKey = &key.PublicKey
+	config.KeyID = kid
+
+	manager, err := NewJWTManager(config)
+	require.NoError(t, err)
+	return manager
+}
+
+func Test_JWK_Round_Trips_RSA_Key(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwk, err := NewJWK("rsa-1", AlgorithmRS256, &key.PublicKey)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(JWKSet{Keys: []JWK{jwk}})
+	require.NoError(t, err)
+
+	var set JWKSet
+	require.NoError(t, json.Unmarshal(data, &set))
+	require.Len(t, set.Keys, 1)
+	assert.Equal(t, "RSA", set.Keys[0].Kty)
+	assert.Equal(t, "sig", set.Keys[0].Use)
+	assert.Equal(t, "AQAB", set.Keys[0].E)
+
+	decoded, err := set.Keys[0].PublicKey()
+	require.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(decoded))
+}
+
+func Test_JWK_Round_Trips_ECDSA_Key(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		require.NoError(t, err)
+
+		jwk, err := NewJWK("ec-1", AlgorithmES256, &key.PublicKey)
+		require.NoError(t, err)
+		assert.Equal(t, "EC", jwk.Kty)
+		assert.Equal(t, curve.Params().Name, jwk.Crv)
+
+		decoded, err := jwk.PublicKey()
+		require.NoError(t, err)
+		assert.True(t, key.PublicKey.Equal(decoded), curve.Params().Name)
+	}
+}
+
+func Test_JWK_Rejects_Invalid_Keys(t *testing.T) {
+	_, err := NewJWK("hmac", AlgorithmHS256, []byte("secret"))
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	_, err = JWK{Kty: "oct"}.PublicKey()
+	assert.Error(t, err)
+
+	_, err = JWK{Kty: "EC", Crv: "P-256", X: "AAAA", Y: "AAAA"}.PublicKey()
+	assert.Error(t, err)
+}
+
+func Test_JWKS_Is_Unavailable_For_HMAC(t *testing.T) {
+	config := DefaultJWTConfig()
+	config.Secret = "secret"
+	manager, err := NewJWTManager(config)
+	require.NoError(t, err)
+
+	_, err = manager.JWKS()
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
+
+func Test_JWKSHandler_Serves_Public_Key(t *testing.T) {
+	manager := newRSAManager(t, "key-1")
+
+	rec := httptest.NewRecorder()
+	JWKSHandler(manager).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, JWKSPath, nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var set JWKSet
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &set))
+	require.Len(t, set.Keys, 1)
+	assert.Equal(t, "key-1", set.Keys[0].Kid)
+	assert.Equal(t, "RS256", set.Keys[0].Alg)
+	assert.NotContains(t, rec.Body.String(), `"d"`)
+}
+
+func Test_JWTVerifier_Verifies_Tokens_With_Remote_JWKS(t *testing.T) {
+	issuer := newRSAManager(t, "key-1")
+
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		JWKSHandler(issuer).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	keys, err := LoadJWKSFromURL(server.URL + JWKSPath)
+	require.NoError(t, err)
+
+	verifier, err := NewJWTVerifier(&JWTConfig{
+		Algorithm: AlgorithmRS256,
+		Issuer:    issuer.config.Issuer,
+		Audience:  issuer.config.Audience,
+	}, keys)
+	require.NoError(t, err)
+
+	token, err := issuer.GenerateToken(&Claims{UserID: "user-1"}, AccessToken)
+	require.NoError(t, err)
+
+	claims, err := verifier.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+
+	// Keys are cached between verifications
+	_, err = verifier.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// Verification-only managers cannot sign
+	_, err = verifier.GenerateToken(&Claims{}, AccessToken)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
+
+func Test_RemoteJWKS_Refetches_Once_Per_Interval_When_The_Issuer_Fails(t *testing.T) {
+	issuer := newRSAManager(t, "key-1")
+
+	var fetches atomic.Int32
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		JWKSHandler(issuer).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	keys, err := LoadJWKSFromURL(server.URL + JWKSPath)
+	require.NoError(t, err)
+	var mu sync.Mutex
+	now := time.Now()
+	keys.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+
+	failing.Store(true)
+	advance(DefaultJWKSCacheTTL + time.Second)
+
+	// Concurrent verifications of an expired set share a single fetch, and keep
+	// verifying with the cached key when it fails
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := keys.VerificationKey("key-1", "RS256")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// Nor do later ones refetch before the interval has passed
+	for i := 0; i < 10; i++ {
+		_, err := keys.VerificationKey("key-1", "RS256")
+		require.NoError(t, err)
+		_, err = keys.VerificationKey("unknown", "RS256")
+		require.Error(t, err)
+	}
+	assert.Equal(t, int32(2), fetches.Load())
+
+	advance(jwksMinRefreshInterval + time.Second)
+	for i := 0; i < 10; i++ {
+		_, err := keys.VerificationKey("key-1", "RS256")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), fetches.Load())
+}
+
+func Test_JWTVerifier_Rejects_Tokens_From_Unknown_Keys(t *testing.T) {
+	issuer := newRSAManager(t, "key-1")
+	other := newRSAManager(t, "key-2")
+
+	set, err := issuer.JWKS()
+	require.NoError(t, err)
+
+	verifier, err := NewJWTVerifier(&JWTConfig{
+		Algorithm: AlgorithmRS256,
+		Issuer:    issuer.config.Issuer,
+		Audience:  issuer.config.Audience,
+	}, set)
+	require.NoError(t, err)
+
+	token, err := other.GenerateToken(&Claims{UserID: "user-1"}, AccessToken)
+	require.NoError(t, err)
+
+	_, err = verifier.ParseToken(token)
+	assert.True(t, errors.Is(err, errors.CodeInvalidToken))
+}
+
+func Test_NewJWTVerifier_Requires_Asymmetric_Algorithm(t *testing.T) {
+	_, err := NewJWTVerifier(&JWTConfig{Algorithm: AlgorithmHS256}, &JWKSet{})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
+
+func Test_JWTVerifier_Verifies_ES256_Tokens(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	config := DefaultJWTConfig()
+	config.Algorithm = AlgorithmES256
+	config.ECDSAPrivateKey = key
+	config.ECDSAPublicKey = &key.PublicKey
+	issuer, err := NewJWTManager(config)
+	require.NoError(t, err)
+
+	set, err := issuer.JWKS()
+	require.NoError(t, err)
+
+	verifier, err := NewJWTVerifier(&JWTConfig{
+		Algorithm: AlgorithmES256,
+		Issuer:    config.Issuer,
+		Audience:  config.Audience,
+	}, set)
+	require.NoError(t, err)
+
+	token, err := issuer.GenerateToken(&Claims{UserID: "user-1"}, AccessToken)
+	require.NoError(t, err)
+
+	claims, err := verifier.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+}
@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"crypto/ecdsa"
 	"crypto/rsa"
 	"fmt"
 	"os"
@@ -24,6 +25,11 @@ const (
 	AlgorithmRS256 JWTAlgorithm = "RS256"
 	AlgorithmRS384 JWTAlgorithm = "RS384"
 	AlgorithmRS512 JWTAlgorithm = "RS512"
+
+	// Asymmetric algorithms (ECDSA)
+	AlgorithmES256 JWTAlgorithm = "ES256"
+	AlgorithmES384 JWTAlgorithm = "ES384"
+	AlgorithmES512 JWTAlgorithm = "ES512"
 )
 
 // JWTConfig holds JWT configuration
@@ -37,6 +43,15 @@ type JWTConfig struct {
 	// PublicKey is used for RSA algorithms (verification)
 	PublicKey *rsa.PublicKey
 
+	// ECDSAPrivateKey is used for ECDSA algorithms (signing)
+	ECDSAPrivateKey *ecdsa.PrivateKey
+
+	// ECDSAPublicKey is used for ECDSA algorithms (verification)
+	ECDSAPublicKey *ecdsa.PublicKey
+
+	// KeyID is set as the "kid" header of signed tokens and published in the JWKS
+	KeyID string
+
 	// Algorithm specifies the signing algorithm
 	Algorithm JWTAlgorithm
 
@@ -77,6 +92,9 @@ type Claims struct {
 // JWTManager handles JWT token operations
 type JWTManager struct {
 	config *JWTConfig
+
+	// keys resolves verification keys by "kid" for verification-only managers
+	keys KeySource
 }
 
 // NewJWTManager creates a new JWT manager
@@ -108,6 +126,13 @@ func validateJWTConfig(config *JWTConfig) error {
 		if config.PublicKey == nil {
 			return errors.BadRequest("public key is required for RSA algorithms")
 		}
+	case AlgorithmES256, AlgorithmES384, AlgorithmES512:
+		if config.ECDSAPrivateKey == nil {
+			return errors.BadRequest("private key is required for ECDSA algorithms")
+		}
+		if config.ECDSAPublicKey == nil {
+			return errors.BadRequest("public key is required for ECDSA algorithms")
+		}
 	default:
 		return errors.BadRequest("unsupported JWT algorithm").
 			WithDetails(fmt.Sprintf("algorithm: %s", config.Algorithm))
@@ -129,6 +154,9 @@ func (m *JWTManager) GenerateToken(claims *Claims, tokenType TokenType) (string,
 	if claims == nil {
 		return "", errors.BadRequest("claims cannot be nil")
 	}
+	if m.keys != nil {
+		return "", errors.BadRequest("verification-only JWT manager cannot sign tokens")
+	}
 
 	// Set standard claims
 	now := time.Now()
@@ -148,6 +176,9 @@ func (m *JWTManager) GenerateToken(claims *Claims, tokenType TokenType) (string,
 
 	// Create token
 	token := jwt.NewWithClaims(m.getSigningMethod(), claims)
+	if m.config.KeyID != "" {
+		token.Header["kid"] = m.config.KeyID
+	}
 
 	// Sign token
 	signedToken, err := token.SignedString(m.getSigningKey())
@@ -174,6 +205,10 @@ func (m *JWTManager) ParseToken(tokenString string) (*Claims, error) {
 				return nil, errors.Unauthorized("invalid token algorithm").
 					WithDetails(fmt.Sprintf("expected %s, got %s", m.config.Algorithm, token.Method.Alg()))
 			}
+			if m.keys != nil {
+				kid, _ := token.Header["kid"].(string)
+				return m.keys.VerificationKey(kid, token.Method.Alg())
+			}
 			return m.getVerificationKey(), nil
 		},
 	)
@@ -266,6 +301,12 @@ func (m *JWTManager) getSigningMethod() jwt.SigningMethod {
 		return jwt.SigningMethodRS384
 	case AlgorithmRS512:
 		return jwt.SigningMethodRS512
+	case AlgorithmES256:
+		return jwt.SigningMethodES256
+	case AlgorithmES384:
+		return jwt.SigningMethodES384
+	case AlgorithmES512:
+		return jwt.SigningMethodES512
 	default:
 		return jwt.SigningMethodHS256
 	}
@@ -278,6 +319,8 @@ func (m *JWTManager) getSigningKey() interface{} {
 		return []byte(m.config.Secret)
 	case AlgorithmRS256, AlgorithmRS384, AlgorithmRS512:
 		return m.config.PrivateKey
+	case AlgorithmES256, AlgorithmES384, AlgorithmES512:
+		return m.config.ECDSAPrivateKey
 	default:
 		return []byte(m.config.Secret)
 	}
@@ -290,6 +333,8 @@ func (m *JWTManager) getVerificationKey() interface{} {
 		return []byte(m.config.Secret)
 	case AlgorithmRS256, AlgorithmRS384, AlgorithmRS512:
 		return m.config.PublicKey
+	case AlgorithmES256, AlgorithmES384, AlgorithmES512:
+		return m.config.ECDSAPublicKey
 	default:
 		return []byte(m.config.Secret)
 	}
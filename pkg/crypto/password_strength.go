@@ -0,0 +1,559 @@
+package crypto
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// 🎓 LEARNING: Strength is about guesses, not character classes
+// "P@ssw0rd" has an uppercase letter, a digit and a symbol, yet it is among the first
+// passwords attackers try. Like zxcvbn, the estimator splits a password into the
+// patterns attackers guess first (common passwords, l33t and capitalized variants,
+// sequences, keyboard rows, repeats, years, the user's own name or email), estimates
+// the guesses each needs, and scores the cheapest way to guess the whole password.
+
+// StrengthResult is the estimated strength of a password
+type StrengthResult struct {
+	// Score is 0 (too guessable) to 4 (very unguessable). 3 is a sensible minimum.
+	Score int `json:"score"`
+	// Guesses is the estimated number of guesses needed to find the password
+	Guesses float64 `json:"guesses"`
+	// Warning explains what makes a weak password guessable, if known
+	Warning string `json:"warning,omitempty"`
+	// Suggestions help choosing a stronger password
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// StrengthEstimator estimates password strength. EstimatePasswordStrength is the
+// built-in one; ports of zxcvbn or other estimators can be plugged into PasswordPolicy.
+type StrengthEstimator func(password string, userInputs []string) StrengthResult
+
+// PasswordPolicy rejects passwords whose strength is below a minimum score, typically
+// auth.password.min_strength of the application config (PASSWORD_MIN_STRENGTH)
+type PasswordPolicy struct {
+	// MinScore is the lowest accepted score, from 0 (accept everything) to 4
+	MinScore int
+	// Estimator estimates strength, EstimatePasswordStrength when nil
+	Estimator StrengthEstimator
+}
+
+// Check estimates the strength of password, returning a CodeValidation error with a
+// "password" field error when it scores below MinScore. userInputs are values the
+// password must not be built from, such as the user's name and email.
+//
+// Example:
+//
+//	policy := crypto.PasswordPolicy{MinScore: cfg.Auth.Password.MinStrength}
+//	if _, err := policy.Check(req.Password, req.Email, req.Name); err != nil {
+//	    return err // 400 with the warning and suggestions
+//	}
+func (p PasswordPolicy) Check(password string, userInputs ...string) (StrengthResult, error) {
+	estimate := p.Estimator
+	if estimate == nil {
+		estimate = EstimatePasswordStrength
+	}
+
+	result := estimate(password, userInputs)
+	if result.Score >= p.MinScore {
+		return result, nil
+	}
+
+	message := result.Warning
+	if message == "" {
+		message = "is too easy to guess"
+	}
+	return result, errors.Validation("password is too weak").
+		WithFieldErrors(errors.ValidationField{Field: "password", Message: message}).
+		WithContext("score", result.Score).
+		WithContext("suggestions", result.Suggestions)
+}
+
+// EstimatePasswordStrength estimates how many guesses password needs, zxcvbn-style,
+// and scores it from 0 to 4. userInputs, such as the user's name and email, are
+// treated as the first words an attacker tries. Feedback is given for scores up to 2.
+//
+// Example:
+//
+//	result := crypto.EstimatePasswordStrength("P@ssw0rd", []string{user.Email})
+//	// result.Score == 0, result.Warning == "This is similar to a commonly used password"
+func EstimatePasswordStrength(password string, userInputs []string) StrengthResult {
+	ranked := rankedWords(userInputs)
+	guesses, matches := mostGuessableSplit([]rune(password), ranked)
+
+	result := StrengthResult{Score: guessesScore(guesses), Guesses: guesses}
+	if result.Score <= 2 {
+		result.Warning, result.Suggestions = strengthFeedback(password, matches)
+	}
+	return result
+}
+
+// guessesScore converts guesses to a 0-4 score, with the thresholds of zxcvbn
+func guessesScore(guesses float64) int {
+	switch {
+	case guesses < 1e3+5:
+		return 0
+	case guesses < 1e6+5:
+		return 1
+	case guesses < 1e8+5:
+		return 2
+	case guesses < 1e10+5:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// matchKind is the pattern of a part of a password
+type matchKind int
+
+const (
+	matchBruteforce matchKind = iota
+	matchDictionary
+	matchUserInput
+	matchSequence
+	matchKeyboard
+	matchRepeat
+	matchYear
+)
+
+// strengthMatch is a guessable part of a password, runes [start, end)
+type strengthMatch struct {
+	kind       matchKind
+	start, end int
+	guesses    float64
+	rank       int  // for dictionary matches
+	l33t       bool // for dictionary matches
+	capitalize bool // for dictionary matches
+	reversed   bool // for dictionary matches
+}
+
+const (
+	// bruteforceCardinality is the guesses per character not part of a pattern
+	bruteforceCardinality = 10
+	// maxStrengthRunes bounds the matching work: longer passwords are strong anyway
+	maxStrengthRunes = 64
+)
+
+// mostGuessableSplit returns the guesses of the cheapest split of password into
+// patterns and unmatched characters, and the matches of that split
+func mostGuessableSplit(password []rune, ranked map[string]rankedWord) (float64, []strengthMatch) {
+	if len(password) > maxStrengthRunes {
+		return math.Pow(bruteforceCardinality, float64(len(password))), nil
+	}
+
+	return cheapestSplit(password, findMatches(password, ranked))
+}
+
+// cheapestSplit returns the guesses of the cheapest split of password into the
+// candidate matches and unmatched characters, and the matches of that split
+func cheapestSplit(password []rune, candidates []strengthMatch) (float64, []strengthMatch) {
+	// best[i] is the fewest guesses for the first i runes, reached with last[i]
+	best := make([]float64, len(password)+1)
+	last := make([]*strengthMatch, len(password)+1)
+	best[0] = 1
+	for end := 1; end <= len(password); end++ {
+		best[end] = best[end-1] * bruteforceCardinality
+		for i := range candidates {
+			m := &candidates[i]
+			if m.end == end && best[m.start]*m.guesses < best[end] {
+				best[end] = best[m.start] * m.guesses
+				last[end] = m
+			}
+		}
+	}
+
+	var matches []strengthMatch
+	for end := len(password); end > 0; {
+		if m := last[end]; m != nil {
+			matches = append(matches, *m)
+			end = m.start
+		} else {
+			end--
+		}
+	}
+	return best[len(password)], matches
+}
+
+// findMatches returns every pattern found in password
+func findMatches(password []rune, ranked map[string]rankedWord) []strengthMatch {
+	var matches []strengthMatch
+	matches = append(matches, dictionaryMatches(password, ranked)...)
+	matches = append(matches, sequenceMatches(password)...)
+	matches = append(matches, repeatMatches(password, ranked)...)
+	matches = append(matches, yearMatches(password)...)
+	return withMinimumGuesses(matches)
+}
+
+// unitMatches returns the patterns found in the unit of a repeat, which are the
+// patterns of findMatches other than repeats
+func unitMatches(unit []rune, ranked map[string]rankedWord) []strengthMatch {
+	var matches []strengthMatch
+	matches = append(matches, dictionaryMatches(unit, ranked)...)
+	matches = append(matches, sequenceMatches(unit)...)
+	matches = append(matches, yearMatches(unit)...)
+	return withMinimumGuesses(matches)
+}
+
+// withMinimumGuesses raises the guesses of matches to the minimum of their length
+func withMinimumGuesses(matches []strengthMatch) []strengthMatch {
+	for i := range matches {
+		minimum := 50.0
+		if matches[i].end-matches[i].start == 1 {
+			minimum = bruteforceCardinality
+		}
+		matches[i].guesses = math.Max(matches[i].guesses, minimum)
+	}
+	return matches
+}
+
+// rankedWord is a guessable word, with its rank among the words attackers try
+type rankedWord struct {
+	rank      int
+	userInput bool
+}
+
+// rankedWords returns the common passwords and the user inputs, which guessers try
+// first, by lowercase word
+func rankedWords(userInputs []string) map[string]rankedWord {
+	ranked := make(map[string]rankedWord, len(commonPasswords)+len(userInputs))
+	for i, word := range commonPasswords {
+		ranked[word] = rankedWord{rank: i + 1}
+	}
+
+	rank := 1
+	add := func(word string) {
+		if len([]rune(word)) >= 3 {
+			ranked[word] = rankedWord{rank: rank, userInput: true}
+			rank++
+		}
+	}
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		add(input)
+		// "ann.lee@example.com" also gives "ann", "lee" and "example"
+		for _, part := range strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			add(part)
+		}
+	}
+	return ranked
+}
+
+// l33tTable maps the common substitutions back to letters
+var l33tTable = map[rune][]rune{
+	'4': {'a'}, '@': {'a'}, '8': {'b'}, '(': {'c'}, '3': {'e'}, '6': {'g'}, '9': {'g'},
+	'1': {'i', 'l'}, '!': {'i'}, '|': {'i', 'l'}, '0': {'o'}, '$': {'s'}, '5': {'s'},
+	'7': {'t'}, '+': {'t'}, '2': {'z'},
+}
+
+// dictionaryMatches returns the ranked words found in password, possibly capitalized,
+// with l33t substitutions or reversed
+func dictionaryMatches(password []rune, ranked map[string]rankedWord) []strengthMatch {
+	var matches []strengthMatch
+	for start := 0; start < len(password); start++ {
+		for end := start + 3; end <= len(password); end++ {
+			token := password[start:end]
+			lower := []rune(strings.ToLower(string(token)))
+
+			for _, reversed := range []bool{false, true} {
+				candidate := lower
+				if reversed {
+					candidate = reverseRunes(lower)
+				}
+				for _, word := range unl33t(candidate) {
+					entry, ok := ranked[string(word)]
+					if !ok {
+						continue
+					}
+					m := strengthMatch{
+						kind: matchDictionary, start: start, end: end, rank: entry.rank,
+						l33t: string(word) != string(candidate), reversed: reversed,
+						capitalize: string(lower) != string(token),
+					}
+					if entry.userInput {
+						m.kind = matchUserInput
+					}
+					m.guesses = float64(entry.rank) * uppercaseVariations(token) * l33tVariations(candidate, word)
+					if reversed {
+						m.guesses *= 2
+					}
+					matches = append(matches, m)
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// unl33t returns token and its readings with l33t substitutions replaced, at most one
+// per ambiguous character
+func unl33t(token []rune) [][]rune {
+	readings := [][]rune{token}
+	for _, choice := range []int{0, 1} {
+		reading := make([]rune, len(token))
+		substituted := false
+		for i, r := range token {
+			reading[i] = r
+			if letters, ok := l33tTable[r]; ok {
+				reading[i] = letters[min(choice, len(letters)-1)]
+				substituted = true
+			}
+		}
+		if substituted && (choice == 0 || string(reading) != string(readings[len(readings)-1])) {
+			readings = append(readings, reading)
+		}
+	}
+	return readings
+}
+
+// uppercaseVariations is how many capitalizations of token a guesser tries before it
+func uppercaseVariations(token []rune) float64 {
+	upper, lower := 0, 0
+	for _, r := range token {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return 1
+	case lower == 0, upper == 1 && (unicode.IsUpper(token[0]) || unicode.IsUpper(token[len(token)-1])):
+		// ALL CAPS, Capitalized and lasT letter
+		return 2
+	}
+
+	variations := 0.0
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += binomial(upper+lower, i)
+	}
+	return variations
+}
+
+// l33tVariations is how many substitution variants of word a guesser tries before the
+// token
+func l33tVariations(token, word []rune) float64 {
+	substituted := 0
+	for i := range token {
+		if token[i] != word[i] {
+			substituted++
+		}
+	}
+	if substituted == 0 {
+		return 1
+	}
+	return math.Pow(2, float64(substituted))
+}
+
+// keyboardRows are the rows of a QWERTY keyboard, typed left to right or right to left
+var keyboardRows = []string{"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// sequenceMatches returns the runs of at least 3 consecutive characters, such as
+// "abc", "9876" or the keyboard row "qwerty"
+func sequenceMatches(password []rune) []strengthMatch {
+	lower := []rune(strings.ToLower(string(password)))
+	var matches []strengthMatch
+
+	for start := 0; start < len(lower)-2; start++ {
+		// Alphabetical and numerical sequences
+		delta := lower[start+1] - lower[start]
+		if delta == 1 || delta == -1 {
+			end := start + 2
+			for end < len(lower) && lower[end]-lower[end-1] == delta && sameClass(lower[end], lower[start]) {
+				end++
+			}
+			if end-start >= 3 && sameClass(lower[start+1], lower[start]) {
+				base := 26.0
+				switch {
+				case strings.ContainsRune("az019", lower[start]):
+					base = 4 // obvious starting points
+				case unicode.IsDigit(lower[start]):
+					base = 10
+				}
+				guesses := base * float64(end-start)
+				if delta < 0 {
+					guesses *= 2
+				}
+				matches = append(matches, strengthMatch{kind: matchSequence, start: start, end: end, guesses: guesses})
+			}
+		}
+
+		// Keyboard rows
+		for _, row := range keyboardRows {
+			for _, direction := range []string{row, string(reverseRunes([]rune(row)))} {
+				end := start
+				offset := strings.IndexRune(direction, lower[start])
+				for offset >= 0 && end < len(lower) && offset+end-start < len(direction) &&
+					rune(direction[offset+end-start]) == lower[end] {
+					end++
+				}
+				if offset >= 0 && end-start >= 3 {
+					matches = append(matches, strengthMatch{
+						kind: matchKeyboard, start: start, end: end, guesses: 2 * float64(len(row)) * float64(end-start),
+					})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// sameClass reports whether a and b are both letters or both digits
+func sameClass(a, b rune) bool {
+	return unicode.IsLetter(a) == unicode.IsLetter(b) && unicode.IsDigit(a) == unicode.IsDigit(b)
+}
+
+// repeatMatches returns the repetitions of a character or a unit, such as "aaa" or
+// "abcabc", guessed as the unit times the number of repeats. Units are scored without
+// looking for repeats inside them, which keeps the work polynomial in the length.
+func repeatMatches(password []rune, ranked map[string]rankedWord) []strengthMatch {
+	var matches []strengthMatch
+	unitGuesses := map[string]float64{}
+	for start := 0; start < len(password); start++ {
+		for unit := 1; start+2*unit <= len(password); unit++ {
+			end := start + unit
+			for end+unit <= len(password) && string(password[end:end+unit]) == string(password[start:start+unit]) {
+				end += unit
+			}
+			count := (end - start) / unit
+			if count < 2 || (unit == 1 && count < 3) {
+				continue
+			}
+
+			unitRunes := password[start : start+unit]
+			guesses, ok := unitGuesses[string(unitRunes)]
+			if !ok {
+				guesses, _ = cheapestSplit(unitRunes, unitMatches(unitRunes, ranked))
+				unitGuesses[string(unitRunes)] = guesses
+			}
+			matches = append(matches, strengthMatch{
+				kind: matchRepeat, start: start, end: end, guesses: guesses * float64(count),
+			})
+		}
+	}
+	return matches
+}
+
+// yearMatches returns the years from 1900 to 2099, guessed by their distance to now
+func yearMatches(password []rune) []strengthMatch {
+	now := time.Now().Year()
+	var matches []strengthMatch
+	for start := 0; start+4 <= len(password); start++ {
+		token := password[start : start+4]
+		year := 0
+		for _, r := range token {
+			if r < '0' || r > '9' {
+				year = -1
+				break
+			}
+			year = year*10 + int(r-'0')
+		}
+		if year >= 1900 && year <= 2099 {
+			distance := math.Abs(float64(year - now))
+			matches = append(matches, strengthMatch{
+				kind: matchYear, start: start, end: start + 4, guesses: math.Max(distance, 20),
+			})
+		}
+	}
+	return matches
+}
+
+// strengthFeedback explains a weak password from the longest pattern it was split into
+func strengthFeedback(password string, matches []strengthMatch) (string, []string) {
+	if password == "" {
+		return "", []string{"Use a few words, avoid common phrases", "No need for symbols, digits, or uppercase letters"}
+	}
+
+	suggestions := []string{"Add another word or two. Uncommon words are better."}
+	if len(matches) == 0 {
+		return "", suggestions
+	}
+
+	longest := matches[0]
+	for _, m := range matches[1:] {
+		if m.end-m.start > longest.end-longest.start {
+			longest = m
+		}
+	}
+
+	switch longest.kind {
+	case matchDictionary:
+		warning := "This is similar to a commonly used password"
+		wholePassword := len(matches) == 1 && longest.start == 0 && longest.end == len([]rune(password))
+		if wholePassword && !longest.l33t && !longest.reversed {
+			warning = "This is a very common password"
+			if longest.rank <= 10 {
+				warning = "This is a top-10 common password"
+			}
+		}
+		if longest.capitalize {
+			suggestions = append(suggestions, "Capitalization doesn't help very much")
+		}
+		if longest.reversed {
+			suggestions = append(suggestions, "Reversed words aren't much harder to guess")
+		}
+		if longest.l33t {
+			suggestions = append(suggestions, "Predictable substitutions like '@' instead of 'a' don't help very much")
+		}
+		return warning, suggestions
+	case matchUserInput:
+		return "Passwords based on your name or email are easy to guess", suggestions
+	case matchSequence:
+		return "Sequences like abc or 6543 are easy to guess", append(suggestions, "Avoid sequences")
+	case matchKeyboard:
+		return "Straight rows of keys are easy to guess", append(suggestions, "Use a longer keyboard pattern with more turns")
+	case matchRepeat:
+		return `Repeats like "aaa" or "abcabc" are easy to guess`, append(suggestions, "Avoid repeated words and characters")
+	case matchYear:
+		return "Recent years are easy to guess", append(suggestions, "Avoid years that are associated with you")
+	}
+	return "", suggestions
+}
+
+func reverseRunes(runes []rune) []rune {
+	reversed := make([]rune, len(runes))
+	for i, r := range runes {
+		reversed[len(runes)-1-i] = r
+	}
+	return reversed
+}
+
+// binomial returns n choose k
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
+
+// commonPasswords are frequent passwords and words from leaked password lists, most
+// frequent first
+var commonPasswords = []string{
+	"123456", "password", "12345678", "qwerty", "123456789", "12345", "1234", "111111",
+	"1234567", "dragon", "123123", "baseball", "abc123", "football", "monkey", "letmein",
+	"696969", "shadow", "master", "666666", "qwertyuiop", "123321", "mustang", "1234567890",
+	"michael", "654321", "superman", "1qaz2wsx", "7777777", "121212", "000000", "qazwsx",
+	"123qwe", "killer", "trustno1", "jordan", "jennifer", "zxcvbnm", "asdfgh", "hunter",
+	"buster", "soccer", "harley", "batman", "andrew", "tigger", "sunshine", "iloveyou",
+	"2000", "charlie", "robert", "thomas", "hockey", "ranger", "daniel", "starwars",
+	"klaster", "112233", "george", "computer", "michelle", "jessica", "pepper", "1111",
+	"zxcvbn", "555555", "11111111", "131313", "freedom", "777777", "pass", "maggie",
+	"159753", "aaaaaa", "ginger", "princess", "joshua", "cheese", "amanda", "summer",
+	"love", "ashley", "nicole", "chelsea", "biteme", "matthew", "access", "yankees",
+	"987654321", "dallas", "austin", "thunder", "taylor", "matrix", "admin", "welcome",
+	"login", "passw0rd", "hello", "secret", "whatever", "winter", "spring", "autumn",
+	"flower", "pokemon", "football1", "orange", "banana", "chocolate", "cookie", "samsung",
+	"google", "internet", "london", "america", "canada", "qwerty123", "password1",
+	"iloveu", "angel", "lovely", "baby", "money", "family", "friends", "forever",
+	"liverpool", "arsenal", "chelsea1", "purple", "yellow", "silver", "golden", "diamond",
+	"changeme", "default", "root", "test", "guest", "user", "letmein1", "qwe123",
+}
@@ -0,0 +1,155 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// passesClassRules reports whether password satisfies the default PasswordConfig rules
+func passesClassRules(password string) bool {
+	var upper, lower, number, special bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			number = true
+		default:
+			special = true
+		}
+	}
+	return len(password) >= 8 && upper && lower && number && special
+}
+
+func Test_EstimatePasswordStrength_Scores_Common_Passwords_Low_Despite_Class_Rules(t *testing.T) {
+	for _, password := range []string{"P@ssw0rd", "Passw0rd!", "Qwerty123!", "Welcome1!"} {
+		require.True(t, passesClassRules(password), password)
+
+		result := EstimatePasswordStrength(password, nil)
+		assert.LessOrEqual(t, result.Score, 1, password)
+		assert.NotEmpty(t, result.Warning, password)
+		assert.NotEmpty(t, result.Suggestions, password)
+	}
+
+	result := EstimatePasswordStrength("P@ssw0rd", nil)
+	assert.Equal(t, 0, result.Score)
+	assert.Equal(t, "This is similar to a commonly used password", result.Warning)
+	assert.Contains(t, result.Suggestions, "Predictable substitutions like '@' instead of 'a' don't help very much")
+}
+
+func Test_EstimatePasswordStrength_Scores_Unpredictable_Passwords_High(t *testing.T) {
+	for _, password := range []string{"xK9#mQ2$vL7@pW4n", "correct horse battery staple", "Tr0ub4dor&3"} {
+		result := EstimatePasswordStrength(password, nil)
+		assert.Equal(t, 4, result.Score, password)
+		assert.Empty(t, result.Warning, password)
+		assert.Empty(t, result.Suggestions, password)
+	}
+}
+
+func Test_EstimatePasswordStrength_Detects_Patterns(t *testing.T) {
+	tests := []struct {
+		password string
+		warning  string
+	}{
+		{"password", "This is a top-10 common password"},
+		{"abcdefgh", "Sequences like abc or 6543 are easy to guess"},
+		{"98765432", "Sequences like abc or 6543 are easy to guess"},
+		{"asdfghjkl", "Straight rows of keys are easy to guess"},
+		{"zzzzzzzzzz", `Repeats like "aaa" or "abcabc" are easy to guess`},
+		{"xyzxyzxyz", `Repeats like "aaa" or "abcabc" are easy to guess`},
+		{"1987", "Recent years are easy to guess"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			result := EstimatePasswordStrength(tt.password, nil)
+			assert.Equal(t, 0, result.Score)
+			assert.Equal(t, tt.warning, result.Warning)
+		})
+	}
+}
+
+func Test_EstimatePasswordStrength_Penalizes_User_Inputs(t *testing.T) {
+	password := "Nguyenvan!"
+	without := EstimatePasswordStrength(password, nil)
+	with := EstimatePasswordStrength(password, []string{"nguyenvan.an@example.com", "An Nguyen Van"})
+
+	assert.Less(t, with.Guesses, without.Guesses)
+	assert.LessOrEqual(t, with.Score, 1)
+	assert.Equal(t, "Passwords based on your name or email are easy to guess", with.Warning)
+}
+
+func Test_EstimatePasswordStrength_Bounds_Long_Passwords(t *testing.T) {
+	result := EstimatePasswordStrength(strings.Repeat("a", 10000), nil)
+	assert.Equal(t, 4, result.Score)
+
+	empty := EstimatePasswordStrength("", nil)
+	assert.Equal(t, 0, empty.Score)
+	assert.NotEmpty(t, empty.Suggestions)
+}
+
+func Test_PasswordPolicy_Rejects_Passwords_Below_Min_Score(t *testing.T) {
+	policy := PasswordPolicy{MinScore: 3}
+
+	result, err := policy.Check("P@ssw0rd", "user@example.com")
+	require.Error(t, err)
+	assert.Equal(t, 0, result.Score)
+	assert.True(t, errors.Is(err, errors.CodeValidation))
+
+	appErr, ok := errors.As(err)
+	require.True(t, ok)
+	assert.Equal(t, 0, appErr.Context["score"])
+	assert.Equal(t, []errors.ValidationField{
+		{Field: "password", Message: "This is similar to a commonly used password"},
+	}, errors.ToProblemDetail(err, errors.HandlerConfig{}).Errors)
+
+	_, err = policy.Check("xK9#mQ2$vL7@pW4n", "user@example.com")
+	assert.NoError(t, err)
+
+	// Disabled policies accept everything
+	_, err = PasswordPolicy{}.Check("123456")
+	assert.NoError(t, err)
+}
+
+func Test_PasswordPolicy_Uses_Custom_Estimator(t *testing.T) {
+	var inputs []string
+	policy := PasswordPolicy{
+		MinScore: 2,
+		Estimator: func(password string, userInputs []string) StrengthResult {
+			inputs = userInputs
+			return StrengthResult{Score: 1}
+		},
+	}
+
+	_, err := policy.Check("anything", "alice")
+	require.Error(t, err)
+	assert.Equal(t, []string{"alice"}, inputs)
+
+	assert.Equal(t, []errors.ValidationField{{Field: "password", Message: "is too easy to guess"}},
+		errors.ToProblemDetail(err, errors.HandlerConfig{}).Errors)
+}
+
+func Test_EstimatePasswordStrength_Scores_Long_Repeats_Quickly(t *testing.T) {
+	for _, password := range []string{
+		strings.Repeat("a", maxStrengthRunes),
+		strings.Repeat("ab", maxStrengthRunes/2),
+		strings.Repeat("p@ssw0rd", maxStrengthRunes/8),
+		strings.Repeat("a", 100),
+	} {
+		start := time.Now()
+		result := EstimatePasswordStrength(password, nil)
+		assert.Less(t, time.Since(start), 250*time.Millisecond, password)
+		if len(password) <= maxStrengthRunes {
+			assert.LessOrEqual(t, result.Score, 1, password)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// TOTPAlgorithm represents the HMAC hash used by TOTP (RFC 6238)
+type TOTPAlgorithm string
+
+const (
+	TOTPAlgorithmSHA1   TOTPAlgorithm = "SHA1"
+	TOTPAlgorithmSHA256 TOTPAlgorithm = "SHA256"
+	TOTPAlgorithmSHA512 TOTPAlgorithm = "SHA512"
+)
+
+// totpSecretSize is the size of generated secrets (160 bits, as recommended by RFC 4226)
+const totpSecretSize = 20
+
+// totpEncoding is the base32 encoding used for secrets (RFC 4648, without padding)
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPConfig holds TOTP configuration
+type TOTPConfig struct {
+	// Digits is the code length (6 or 8)
+	Digits int
+
+	// Period is the time step a code is valid for
+	Period time.Duration
+
+	// Skew is the number of periods before and after the current one that are accepted,
+	// to tolerate clock drift between server and authenticator
+	Skew uint
+
+	// Algorithm is the HMAC hash (authenticator apps mostly support SHA1 only)
+	Algorithm TOTPAlgorithm
+}
+
+// DefaultTOTPConfig returns the configuration used by common authenticator apps
+func DefaultTOTPConfig() *TOTPConfig {
+	return &TOTPConfig{
+		Digits:    6,
+		Period:    30 * time.Second,
+		Skew:      1,
+		Algorithm: TOTPAlgorithmSHA1,
+	}
+}
+
+// TOTP generates and validates time-based one-time passwords (RFC 6238)
+type TOTP struct {
+	config *TOTPConfig
+}
+
+// NewTOTP creates a new TOTP generator
+func NewTOTP(config *TOTPConfig) (*TOTP, error) {
+	if config == nil {
+		config = DefaultTOTPConfig()
+	}
+
+	if config.Digits < 6 || config.Digits > 8 {
+		return nil, errors.BadRequest("TOTP digits must be between 6 and 8")
+	}
+	if config.Period < time.Second {
+		return nil, errors.BadRequest("TOTP period must be at least one second")
+	}
+	if _, err := config.Algorithm.hash(); err != nil {
+		return nil, err
+	}
+
+	return &TOTP{config: config}, nil
+}
+
+// hash returns the hash constructor for the algorithm
+func (a TOTPAlgorithm) hash() (func() hash.Hash, error) {
+	switch a {
+	case TOTPAlgorithmSHA1:
+		return sha1.New, nil
+	case TOTPAlgorithmSHA256:
+		return sha256.New, nil
+	case TOTPAlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, errors.BadRequest("unsupported TOTP algorithm").
+			WithDetails(fmt.Sprintf("algorithm: %s", a))
+	}
+}
+
+// Generate returns the code for secret at time t
+func (o *TOTP) Generate(secret string, t time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return o.code(key, o.counter(t)), nil
+}
+
+// Validate reports whether code is valid for secret at time t, accepting
+// config.Skew periods before and after t
+func (o *TOTP) Validate(secret, code string, t time.Time) (bool, error) {
+	return o.validate(secret, code, t, o.config.Skew)
+}
+
+func (o *TOTP) validate(secret, code string, t time.Time, skew uint) (bool, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return false, err
+	}
+	if len(code) != o.config.Digits {
+		return false, nil
+	}
+
+	counter := o.counter(t)
+	valid := false
+	for offset := -int64(skew); offset <= int64(skew); offset++ {
+		if int64(counter)+offset < 0 {
+			continue
+		}
+		expected := o.code(key, uint64(int64(counter)+offset))
+		// Constant-time comparison, and check every step to avoid leaking which one matched
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			valid = true
+		}
+	}
+
+	return valid, nil
+}
+
+// URI builds an otpauth:// key URI, usually rendered as a QR code for authenticator apps.
+//
+// Example:
+//
+//	uri := totp.URI(secret, "Acme", "alice@example.com")
+//	// otpauth://totp/Acme:alice@example.com?algorithm=SHA1&digits=6&issuer=Acme&period=30&secret=...
+func (o *TOTP) URI(secret, issuer, accountName string) string {
+	label := accountName
+	if issuer != "" {
+		label = issuer + ":" + accountName
+	}
+
+	query := url.Values{}
+	query.Set("secret", strings.ToUpper(strings.TrimRight(secret, "=")))
+	if issuer != "" {
+		query.Set("issuer", issuer)
+	}
+	query.Set("algorithm", string(o.config.Algorithm))
+	query.Set("digits", strconv.Itoa(o.config.Digits))
+	query.Set("period", strconv.Itoa(int(o.config.Period/time.Second)))
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + label,
+		RawQuery: strings.ReplaceAll(query.Encode(), "+", "%20"),
+	}
+	return uri.String()
+}
+
+// counter returns the RFC 6238 time step counter for t
+func (o *TOTP) counter(t time.Time) uint64 {
+	return uint64(t.Unix()) / uint64(o.config.Period/time.Second)
+}
+
+// code computes the HOTP value (RFC 4226) for counter
+func (o *TOTP) code(key []byte, counter uint64) string {
+	newHash, _ := o.config.Algorithm.hash()
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(newHash, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < o.config.Digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", o.config.Digits, value%modulo)
+}
+
+// decodeTOTPSecret decodes a base32 secret, ignoring case, spaces and padding
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	normalized = strings.TrimRight(normalized, "=")
+
+	key, err := totpEncoding.DecodeString(normalized)
+	if err != nil || len(key) == 0 {
+		return nil, errors.BadRequest("invalid TOTP secret").
+			WithDetails("secret must be base32 encoded")
+	}
+	return key, nil
+}
+
+// GenerateTOTPSecret generates a random base32 encoded TOTP secret
+func GenerateTOTPSecret() (string, error) {
+	key := make([]byte, totpSecretSize)
+	if _, err := rand.Read(key); err != nil {
+		return "", errors.Wrap(err, errors.CodeInternal, "failed to generate TOTP secret")
+	}
+	return totpEncoding.EncodeToString(key), nil
+}
+
+// Package-level convenience functions (using default configuration)
+var defaultTOTP = &TOTP{config: DefaultTOTPConfig()}
+
+// GenerateTOTPCode returns the 6 digit, 30 second code for secret at time t
+func GenerateTOTPCode(secret string, t time.Time) (string, error) {
+	return defaultTOTP.Generate(secret, t)
+}
+
+// ValidateTOTPCode validates a 6 digit, 30 second code against the current time,
+// accepting window periods before and after it
+func ValidateTOTPCode(secret, code string, window uint) (bool, error) {
+	return defaultTOTP.validate(secret, code, time.Now(), window)
+}
+
+// TOTPURI builds an otpauth:// key URI using the default configuration
+func TOTPURI(secret, issuer, accountName string) string {
+	return defaultTOTP.URI(secret, issuer, accountName)
+}
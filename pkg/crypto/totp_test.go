@@ -0,0 +1,115 @@
+package crypto
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// RFC 6238 Appendix B test vectors
+func Test_TOTP_RFC6238_Test_Vectors(t *testing.T) {
+	seeds := map[TOTPAlgorithm]string{
+		TOTPAlgorithmSHA1:   "12345678901234567890",
+		TOTPAlgorithmSHA256: "12345678901234567890123456789012",
+		TOTPAlgorithmSHA512: "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+
+	vectors := []struct {
+		unix     int64
+		expected map[TOTPAlgorithm]string
+	}{
+		{59, map[TOTPAlgorithm]string{TOTPAlgorithmSHA1: "94287082", TOTPAlgorithmSHA256: "46119246", TOTPAlgorithmSHA512: "90693936"}},
+		{1111111109, map[TOTPAlgorithm]string{TOTPAlgorithmSHA1: "07081804", TOTPAlgorithmSHA256: "68084774", TOTPAlgorithmSHA512: "25091201"}},
+		{1111111111, map[TOTPAlgorithm]string{TOTPAlgorithmSHA1: "14050471", TOTPAlgorithmSHA256: "67062674", TOTPAlgorithmSHA512: "99943326"}},
+		{1234567890, map[TOTPAlgorithm]string{TOTPAlgorithmSHA1: "89005924", TOTPAlgorithmSHA256: "91819424", TOTPAlgorithmSHA512: "93441116"}},
+		{2000000000, map[TOTPAlgorithm]string{TOTPAlgorithmSHA1: "69279037", TOTPAlgorithmSHA256: "90698825", TOTPAlgorithmSHA512: "38618901"}},
+		{20000000000, map[TOTPAlgorithm]string{TOTPAlgorithmSHA1: "65353130", TOTPAlgorithmSHA256: "77737706", TOTPAlgorithmSHA512: "47863826"}},
+	}
+
+	for algorithm, seed := range seeds {
+		totp, err := NewTOTP(&TOTPConfig{Digits: 8, Period: 30 * time.Second, Algorithm: algorithm})
+		require.NoError(t, err)
+
+		secret := base32.StdEncoding.EncodeToString([]byte(seed))
+		for _, vector := range vectors {
+			code, err := totp.Generate(secret, time.Unix(vector.unix, 0))
+			require.NoError(t, err)
+			assert.Equal(t, vector.expected[algorithm], code, "%s at %d", algorithm, vector.unix)
+		}
+	}
+}
+
+func Test_TOTP_Validate_Accepts_Skew_Window(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	totp, err := NewTOTP(nil)
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	previous, err := totp.Generate(secret, now.Add(-30*time.Second))
+	require.NoError(t, err)
+	tooOld, err := totp.Generate(secret, now.Add(-90*time.Second))
+	require.NoError(t, err)
+
+	valid, err := totp.Validate(secret, previous, now)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = totp.Validate(secret, tooOld, now)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	valid, err = totp.Validate(secret, "12345", now)
+	require.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func Test_ValidateTOTPCode_Uses_Current_Time(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	code, err := GenerateTOTPCode(secret, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, code, 6)
+
+	valid, err := ValidateTOTPCode(secret, code, 1)
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func Test_TOTP_Rejects_Invalid_Secret_And_Config(t *testing.T) {
+	_, err := GenerateTOTPCode("not base32!", time.Now())
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	_, err = NewTOTP(&TOTPConfig{Digits: 4, Period: 30 * time.Second, Algorithm: TOTPAlgorithmSHA1})
+	assert.Error(t, err)
+
+	_, err = NewTOTP(&TOTPConfig{Digits: 6, Period: 30 * time.Second, Algorithm: "MD5"})
+	assert.Error(t, err)
+}
+
+func Test_TOTPURI_Builds_Key_URI(t *testing.T) {
+	uri := TOTPURI("JBSWY3DPEHPK3PXP", "Acme Co", "alice@example.com")
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Acme Co:alice@example.com", parsed.Path)
+
+	query := parsed.Query()
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", query.Get("secret"))
+	assert.Equal(t, "Acme Co", query.Get("issuer"))
+	assert.Equal(t, "SHA1", query.Get("algorithm"))
+	assert.Equal(t, "6", query.Get("digits"))
+	assert.Equal(t, "30", query.Get("period"))
+	assert.NotContains(t, uri, "+")
+}
@@ -0,0 +1,289 @@
+// Package dataloader coalesces the individual loads of a request into batches, to solve
+// N+1 query problems in GraphQL resolvers and BFF handlers. Load calls made within a
+// short window are collected and passed to a single batch function call, and results
+// are cached for the rest of the request.
+//
+// Loaders are shared, e.g. provided once with fx, while batches and caches live in the
+// request context created by NewContext, so that keys of different requests (and
+// users) are never loaded together:
+//
+//	users := dataloader.New(func(ctx context.Context, ids []string) (map[string]*User, error) {
+//	    return userRepo.FindByIDs(ctx, ids)
+//	})
+//
+//	ctx = dataloader.NewContext(ctx) // per request, in a middleware
+//	author, err := users.Load(ctx, post.AuthorID)
+package dataloader
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+const (
+	// DefaultWait is how long a batch collects keys before it is dispatched
+	DefaultWait = 2 * time.Millisecond
+
+	// DefaultMaxBatch is the number of keys dispatching a batch before the wait ends
+	DefaultMaxBatch = 100
+)
+
+// BatchFunc loads the values of keys, unique within a batch. Keys missing from the
+// returned map fail with CodeNotFound. Returning KeyErrors fails only the keys it
+// contains; any other error fails every key of the batch.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// KeyErrors is returned by a BatchFunc to fail individual keys, the others getting the
+// values of the map returned along with it
+type KeyErrors[K comparable] map[K]error
+
+// Error implements the error interface
+func (e KeyErrors[K]) Error() string {
+	messages := make([]string, 0, len(e))
+	for key, err := range e {
+		messages = append(messages, fmt.Sprintf("%v: %v", key, err))
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "; ")
+}
+
+// Loader batches and caches the loads of BatchFunc. It is safe for concurrent use.
+type Loader[K comparable, V any] struct {
+	batch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+}
+
+// Option configures a Loader
+type Option func(*loaderConfig)
+
+type loaderConfig struct {
+	wait     time.Duration
+	maxBatch int
+}
+
+// WithWait sets how long a batch collects keys before it is dispatched (DefaultWait).
+// Longer waits make larger batches at the cost of latency.
+func WithWait(wait time.Duration) Option {
+	return func(c *loaderConfig) {
+		c.wait = wait
+	}
+}
+
+// WithMaxBatch sets the number of keys dispatching a batch immediately
+// (DefaultMaxBatch), e.g. to stay under the parameter limit of an IN query
+func WithMaxBatch(n int) Option {
+	return func(c *loaderConfig) {
+		if n > 0 {
+			c.maxBatch = n
+		}
+	}
+}
+
+// New creates a loader for batch
+func New[K comparable, V any](batch BatchFunc[K, V], opts ...Option) *Loader[K, V] {
+	cfg := loaderConfig{wait: DefaultWait, maxBatch: DefaultMaxBatch}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Loader[K, V]{batch: batch, wait: cfg.wait, maxBatch: cfg.maxBatch}
+}
+
+// Load returns the value of key, loaded in a batch with the other keys requested with
+// ctx meanwhile, or cached by a previous Load with ctx. Errors are cached too, until
+// Clear. Without NewContext each call loads its key alone and nothing is cached.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	entry := l.state(ctx).entry(ctx, key)
+
+	select {
+	case <-entry.done:
+		return entry.value, entry.err
+	case <-ctx.Done():
+		var zero V
+		return zero, contextError(ctx)
+	}
+}
+
+// LoadMany loads keys like Load, returning their values and errors in the order of keys
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, []error) {
+	values := make([]V, len(keys))
+	errs := make([]error, len(keys))
+
+	state := l.state(ctx)
+	entries := make([]*entry[V], len(keys))
+	for i, key := range keys {
+		entries[i] = state.entry(ctx, key)
+	}
+	for i, entry := range entries {
+		select {
+		case <-entry.done:
+			values[i], errs[i] = entry.value, entry.err
+		case <-ctx.Done():
+			errs[i] = contextError(ctx)
+		}
+	}
+	return values, errs
+}
+
+// Clear removes key from the cache of ctx, e.g. after updating it, so that the next
+// Load fetches it again
+func (l *Loader[K, V]) Clear(ctx context.Context, key K) {
+	if store, ok := ctx.Value(storeContextKey{}).(*store); ok {
+		state := store.state(l, l.newState).(*loaderState[K, V])
+		state.mu.Lock()
+		defer state.mu.Unlock()
+		delete(state.cache, key)
+	}
+}
+
+// state returns the batching state of the loader for the request of ctx
+func (l *Loader[K, V]) state(ctx context.Context) *loaderState[K, V] {
+	if store, ok := ctx.Value(storeContextKey{}).(*store); ok {
+		return store.state(l, l.newState).(*loaderState[K, V])
+	}
+	return l.newState().(*loaderState[K, V])
+}
+
+func (l *Loader[K, V]) newState() any {
+	return &loaderState[K, V]{loader: l, cache: make(map[K]*entry[V])}
+}
+
+// entry is the result of loading a key, available once done is closed
+type entry[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// loaderState is the cache and pending batch of a loader for one request
+type loaderState[K comparable, V any] struct {
+	loader *Loader[K, V]
+
+	mu      sync.Mutex
+	cache   map[K]*entry[V]
+	pending *batch[K, V]
+}
+
+// batch is a set of keys waiting to be dispatched together
+type batch[K comparable, V any] struct {
+	keys    []K
+	entries []*entry[V]
+	timer   *time.Timer
+}
+
+// entry returns the cached entry of key, or adds key to the pending batch
+func (s *loaderState[K, V]) entry(ctx context.Context, key K) *entry[V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.cache[key]; ok {
+		return e
+	}
+
+	e := &entry[V]{done: make(chan struct{})}
+	s.cache[key] = e
+
+	if s.pending == nil {
+		b := &batch[K, V]{}
+		// The batch outlives the first caller leaving early, so that the others still get
+		// their results
+		batchCtx := context.WithoutCancel(ctx)
+		b.timer = time.AfterFunc(s.loader.wait, func() {
+			s.mu.Lock()
+			if s.pending == b {
+				s.pending = nil
+			}
+			s.mu.Unlock()
+			s.loader.dispatch(batchCtx, b)
+		})
+		s.pending = b
+	}
+
+	b := s.pending
+	b.keys = append(b.keys, key)
+	b.entries = append(b.entries, e)
+
+	// Full batches are dispatched right away, unless the timer already fired
+	if len(b.keys) >= s.loader.maxBatch && b.timer.Stop() {
+		s.pending = nil
+		go s.loader.dispatch(context.WithoutCancel(ctx), b)
+	}
+	return e
+}
+
+// dispatch calls the batch function and completes the entries of b
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	values, err := l.call(ctx, b.keys)
+
+	var keyErrs KeyErrors[K]
+	if stdErrors.As(err, &keyErrs) {
+		err = nil
+	}
+
+	for i, key := range b.keys {
+		e := b.entries[i]
+		switch value, ok := values[key]; {
+		case err != nil:
+			e.err = err
+		case keyErrs[key] != nil:
+			e.err = keyErrs[key]
+		case ok:
+			e.value = value
+		default:
+			e.err = errors.New(errors.CodeNotFound, fmt.Sprintf("no value loaded for key %v", key))
+		}
+		close(e.done)
+	}
+}
+
+// call runs the batch function, converting panics into errors so waiters never block
+func (l *Loader[K, V]) call(ctx context.Context, keys []K) (values map[K]V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.FromPanic(r)
+		}
+	}()
+	return l.batch(ctx, keys)
+}
+
+// contextError is the error of a Load whose context is done before its batch completes
+func contextError(ctx context.Context) error {
+	if stdErrors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.Wrap(ctx.Err(), errors.CodeTimeout)
+	}
+	return errors.Wrap(ctx.Err(), errors.CodeCanceled)
+}
+
+type storeContextKey struct{}
+
+// store holds the loader states of a request
+type store struct {
+	mu     sync.Mutex
+	states map[any]any
+}
+
+func (s *store) state(loader any, newState func() any) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[loader]
+	if !ok {
+		state = newState()
+		s.states[loader] = state
+	}
+	return state
+}
+
+// NewContext returns a copy of ctx holding the batches and caches of every loader used
+// with it. Create one per request: cached values live as long as the context.
+func NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, storeContextKey{}, &store{states: make(map[any]any)})
+}
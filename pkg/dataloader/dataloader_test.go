@@ -0,0 +1,172 @@
+package dataloader
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRecorder is a BatchFunc recording the batches it receives. Values are "user-<id>".
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	failing map[int]error
+}
+
+func (r *batchRecorder) load(ctx context.Context, ids []int) (map[int]string, error) {
+	r.mu.Lock()
+	batch := append([]int(nil), ids...)
+	sort.Ints(batch)
+	r.batches = append(r.batches, batch)
+	r.mu.Unlock()
+
+	values := make(map[int]string, len(ids))
+	keyErrs := KeyErrors[int]{}
+	for _, id := range ids {
+		switch {
+		case r.failing[id] != nil:
+			keyErrs[id] = r.failing[id]
+		case id >= 0:
+			values[id] = fmt.Sprintf("user-%d", id)
+		}
+	}
+	if len(keyErrs) > 0 {
+		return values, keyErrs
+	}
+	return values, nil
+}
+
+func (r *batchRecorder) recorded() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]int(nil), r.batches...)
+}
+
+func Test_Load_Coalesces_Concurrent_Calls_Into_One_Batch(t *testing.T) {
+	rec := &batchRecorder{}
+	users := New(rec.load, WithWait(50*time.Millisecond))
+	ctx := NewContext(context.Background())
+
+	const n = 50
+	var wg sync.WaitGroup
+	results := make([]string, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := users.Load(ctx, i%10) // every key is requested 5 times
+			assert.NoError(t, err)
+			results[i] = value
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, rec.recorded(), 1)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, rec.recorded()[0])
+	for i, value := range results {
+		assert.Equal(t, fmt.Sprintf("user-%d", i%10), value)
+	}
+
+	// Loaded keys are cached for the request
+	value, err := users.Load(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "user-3", value)
+	assert.Len(t, rec.recorded(), 1)
+
+	// Other requests have their own batches and caches
+	_, err = users.Load(NewContext(context.Background()), 3)
+	require.NoError(t, err)
+	assert.Len(t, rec.recorded(), 2)
+}
+
+func Test_Load_Returns_Per_Key_Errors(t *testing.T) {
+	rec := &batchRecorder{failing: map[int]error{2: errors.Forbidden("user 2 is private")}}
+	users := New(rec.load)
+	ctx := NewContext(context.Background())
+
+	values, errs := users.LoadMany(ctx, []int{1, 2, -1})
+	require.Len(t, rec.recorded(), 1)
+
+	assert.Equal(t, "user-1", values[0])
+	assert.NoError(t, errs[0])
+	assert.True(t, errors.Is(errs[1], errors.CodeForbidden), errs[1])
+	assert.True(t, errors.Is(errs[2], errors.CodeNotFound), errs[2])
+
+	// Errors are cached until cleared
+	_, err := users.Load(ctx, 2)
+	assert.True(t, errors.Is(err, errors.CodeForbidden))
+	assert.Len(t, rec.recorded(), 1)
+
+	delete(rec.failing, 2)
+	users.Clear(ctx, 2)
+	value, err := users.Load(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "user-2", value)
+	assert.Equal(t, []int{2}, rec.recorded()[1])
+}
+
+func Test_Load_Batch_Errors_And_Panics_Fail_Every_Key(t *testing.T) {
+	ctx := NewContext(context.Background())
+
+	failing := New(func(ctx context.Context, ids []int) (map[int]string, error) {
+		return nil, stdErrors.New("database is down")
+	})
+	_, errs := failing.LoadMany(ctx, []int{1, 2})
+	assert.EqualError(t, errs[0], "database is down")
+	assert.EqualError(t, errs[1], "database is down")
+
+	panicking := New(func(ctx context.Context, ids []int) (map[int]string, error) {
+		panic("boom")
+	})
+	_, err := panicking.Load(ctx, 1)
+	assert.True(t, errors.Is(err, errors.CodeInternal), err)
+}
+
+func Test_Load_Splits_Batches_At_Max_Batch(t *testing.T) {
+	rec := &batchRecorder{}
+	users := New(rec.load, WithWait(time.Hour), WithMaxBatch(3))
+	ctx := NewContext(context.Background())
+
+	values, errs := users.LoadMany(ctx, []int{1, 2, 3, 4, 5, 6})
+	for i, err := range errs {
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("user-%d", i+1), values[i])
+	}
+	assert.ElementsMatch(t, [][]int{{1, 2, 3}, {4, 5, 6}}, rec.recorded())
+}
+
+func Test_Load_Without_Context_Loads_Each_Key_Alone(t *testing.T) {
+	rec := &batchRecorder{}
+	users := New(rec.load, WithWait(time.Millisecond))
+
+	for i := 0; i < 2; i++ {
+		value, err := users.Load(context.Background(), 7)
+		require.NoError(t, err)
+		assert.Equal(t, "user-7", value)
+	}
+	assert.Equal(t, [][]int{{7}, {7}}, rec.recorded())
+}
+
+func Test_Load_Stops_Waiting_When_Context_Is_Done(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	slow := New(func(ctx context.Context, ids []int) (map[int]string, error) {
+		<-release
+		return nil, nil
+	}, WithWait(time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(NewContext(context.Background()), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := slow.Load(ctx, 1)
+	assert.True(t, errors.Is(err, errors.CodeTimeout), err)
+}
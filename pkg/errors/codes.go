@@ -1,6 +1,9 @@
 package errors
 
-import "net/http"
+import (
+	"net/http"
+	"sync"
+)
 
 type ErrorCode string
 
@@ -21,6 +24,7 @@ const (
 	CodeInvalidInput ErrorCode = "INVALID_INPUT"
 	CodeValidation   ErrorCode = "VALIDATION_ERROR"
 	CodeMissingField ErrorCode = "MISSING_FIELD"
+	CodeCanceled     ErrorCode = "REQUEST_CANCELED" // Client went away before completion
 
 	// Authentication & Authorization
 	CodeUnauthorized ErrorCode = "UNAUTHORIZED"
@@ -49,6 +53,10 @@ const (
 	CodeForeignKeyViolation ErrorCode = "FOREIGN_KEY_VIOLATION"
 )
 
+// StatusClientClosedRequest is the non-standard status (popularized by nginx) for
+// requests the client canceled before the server responded
+const StatusClientClosedRequest = 499
+
 // 🎓 LEARNING: Maps in Go
 // map[keyType]valueType is how you declare a map (like a dictionary/hashmap)
 // This maps our error codes to HTTP status codes
@@ -59,6 +67,9 @@ var codeToHTTPStatus = map[ErrorCode]int{
 	CodeValidation:   http.StatusBadRequest,
 	CodeMissingField: http.StatusBadRequest,
 
+	// 499 Client Closed Request
+	CodeCanceled: StatusClientClosedRequest,
+
 	// 401 Unauthorized
 	CodeUnauthorized: http.StatusUnauthorized,
 	CodeInvalidToken: http.StatusUnauthorized,
@@ -109,6 +120,7 @@ var codeToMessage = map[ErrorCode]string{
 	CodeInvalidInput: "The input provided is invalid.",
 	CodeValidation:   "Validation failed for one or more fields.",
 	CodeMissingField: "A required field is missing.",
+	CodeCanceled:     "The request was canceled.",
 
 	// Auth
 	CodeUnauthorized: "Authentication is required to access this resource.",
@@ -137,12 +149,37 @@ var codeToMessage = map[ErrorCode]string{
 	CodeForeignKeyViolation: "Cannot complete operation due to related records.",
 }
 
+// codesMu guards codeToHTTPStatus and codeToMessage against RegisterCode
+var codesMu sync.RWMutex
+
+// RegisterCode adds an application-specific error code, or overrides a built-in one,
+// so that HTTPStatus and Message resolve it. Register codes during initialization.
+//
+// Example:
+//
+//	const CodeInsufficientFunds errors.ErrorCode = "INSUFFICIENT_FUNDS"
+//
+//	func init() {
+//		errors.RegisterCode(CodeInsufficientFunds, http.StatusUnprocessableEntity,
+//			"The account balance is too low for this operation.")
+//	}
+func RegisterCode(code ErrorCode, httpStatus int, message string) {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+
+	codeToHTTPStatus[code] = httpStatus
+	codeToMessage[code] = message
+}
+
 // 🎓 LEARNING: Functions and methods
 // Functions that don't belong to a type start with func name(params) returnType
 
 // HTTPStatus returns the HTTP status code for an error code
 // If the code is not found, returns 500 Internal Server Error
 func (c ErrorCode) HTTPStatus() int {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
 	if status, ok := codeToHTTPStatus[c]; ok {
 		return status
 	}
@@ -151,6 +188,9 @@ func (c ErrorCode) HTTPStatus() int {
 
 // Message returns the default message for an error code
 func (c ErrorCode) Message() string {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
 	if msg, ok := codeToMessage[c]; ok {
 		return msg
 	}
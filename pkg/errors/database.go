@@ -0,0 +1,103 @@
+package errors
+
+import (
+	stdErrors "errors"
+	"strings"
+)
+
+// 🎓 LEARNING: Database error classification
+// PostgreSQL reports failures with a five character SQLSTATE code. Drivers expose it
+// differently (pgx: *pgconn.PgError, lib/pq: *pq.Error), but both implement
+// SQLState(), so we can read it without importing any driver.
+
+// PostgreSQL SQLSTATE codes mapped by ClassifyDBError
+const (
+	SQLStateUniqueViolation      = "23505"
+	SQLStateForeignKeyViolation  = "23503"
+	SQLStateNotNullViolation     = "23502"
+	SQLStateSerializationFailure = "40001"
+	SQLStateDeadlockDetected     = "40P01"
+	SQLStateQueryCanceled        = "57014"
+)
+
+// sqlStateCodes maps SQLSTATE codes to error codes
+var sqlStateCodes = map[string]ErrorCode{
+	SQLStateUniqueViolation:      CodeDuplicateKey,
+	SQLStateForeignKeyViolation:  CodeForeignKeyViolation,
+	SQLStateNotNullViolation:     CodeMissingField,
+	SQLStateSerializationFailure: CodeConflict,
+	SQLStateDeadlockDetected:     CodeConflict,
+	SQLStateQueryCanceled:        CodeTimeout,
+}
+
+// sqlStateError is implemented by driver errors carrying a SQLSTATE code
+type sqlStateError interface {
+	SQLState() string
+}
+
+// ClassifyDBError returns the error code for a database error. Errors carrying a
+// PostgreSQL SQLSTATE are classified by it; for drivers without codes the message is
+// matched instead (e.g. SQLite's "UNIQUE constraint failed"). Unrecognized errors are
+// CodeDatabaseError, AppErrors keep their code, and nil returns an empty code.
+//
+// Example:
+//
+//	if err := db.Create(&user).Error; err != nil {
+//	    return errors.Wrap(err, errors.ClassifyDBError(err), "failed to create user")
+//	}
+func ClassifyDBError(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+
+	if appErr, ok := As(err); ok {
+		return appErr.Code
+	}
+
+	var stateErr sqlStateError
+	if stdErrors.As(err, &stateErr) {
+		if code, ok := sqlStateCodes[stateErr.SQLState()]; ok {
+			return code
+		}
+		return CodeDatabaseError
+	}
+
+	errMsg := err.Error()
+	switch {
+	case IsUniqueViolation(err):
+		return CodeDuplicateKey
+	case strings.Contains(errMsg, "violates foreign key constraint") ||
+		strings.Contains(errMsg, "FOREIGN KEY constraint failed"):
+		return CodeForeignKeyViolation
+	case strings.Contains(errMsg, "violates not-null constraint") ||
+		strings.Contains(errMsg, "NOT NULL constraint failed"):
+		return CodeMissingField
+	case strings.Contains(errMsg, "could not serialize access"):
+		return CodeConflict
+	}
+
+	return CodeDatabaseError
+}
+
+// IsSerializationFailure reports whether err, or an error it wraps, is a PostgreSQL
+// serialization failure (40001) or deadlock (40P01). Transactions failing this way
+// under SERIALIZABLE or REPEATABLE READ isolation may succeed if retried as a whole.
+func IsSerializationFailure(err error) bool {
+	var stateErr sqlStateError
+	if stdErrors.As(err, &stateErr) {
+		state := stateErr.SQLState()
+		return state == SQLStateSerializationFailure || state == SQLStateDeadlockDetected
+	}
+
+	for ; err != nil; err = stdErrors.Unwrap(err) {
+		if _, ok := err.(*AppError); ok {
+			continue
+		}
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "could not serialize access") ||
+			strings.Contains(errMsg, "deadlock detected") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+// pgError mimics the SQLState method of pgconn.PgError and pq.Error
+type pgError struct {
+	code string
+}
+
+func (e *pgError) Error() string    { return "pg error " + e.code }
+func (e *pgError) SQLState() string { return e.code }
+
+// TestClassifyDBError tests mapping driver errors to error codes
+func TestClassifyDBError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{"nil", nil, ""},
+		{"unique violation", &pgError{SQLStateUniqueViolation}, CodeDuplicateKey},
+		{"foreign key violation", &pgError{SQLStateForeignKeyViolation}, CodeForeignKeyViolation},
+		{"not-null violation", &pgError{SQLStateNotNullViolation}, CodeMissingField},
+		{"serialization failure", &pgError{SQLStateSerializationFailure}, CodeConflict},
+		{"query canceled", &pgError{SQLStateQueryCanceled}, CodeTimeout},
+		{"wrapped sqlstate", fmt.Errorf("insert: %w", &pgError{SQLStateUniqueViolation}), CodeDuplicateKey},
+		{"unknown sqlstate", &pgError{"42P01"}, CodeDatabaseError},
+		{"sqlite unique message", fmt.Errorf("UNIQUE constraint failed: users.email"), CodeDuplicateKey},
+		{"sqlite foreign key message", fmt.Errorf("FOREIGN KEY constraint failed"), CodeForeignKeyViolation},
+		{"sqlite not null message", fmt.Errorf("NOT NULL constraint failed: users.email"), CodeMissingField},
+		{"app error keeps its code", NotFound("User"), CodeNotFound},
+		{"plain error", fmt.Errorf("connection refused"), CodeDatabaseError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyDBError(tt.err); got != tt.want {
+				t.Errorf("ClassifyDBError() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIsSerializationFailure tests detecting retryable transaction failures
+func TestIsSerializationFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"serialization failure", &pgError{SQLStateSerializationFailure}, true},
+		{"deadlock", &pgError{SQLStateDeadlockDetected}, true},
+		{"wrapped in app error", Wrap(&pgError{SQLStateSerializationFailure}, CodeDatabaseError), true},
+		{"unique violation", &pgError{SQLStateUniqueViolation}, false},
+		{"message", fmt.Errorf("ERROR: could not serialize access due to concurrent update"), true},
+		{"conflict app error", Conflict("version mismatch"), false},
+		{"plain error", fmt.Errorf("connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsSerializationFailure(tt.err); got != tt.want {
+				t.Errorf("IsSerializationFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package errors
 
 import (
+	"context"
+	stdErrors "errors"
 	"fmt"
+	"net/http"
 	"runtime"
 	"strings"
 	"time"
@@ -199,12 +202,73 @@ func Conflict(message ...string) *AppError {
 	return New(CodeConflict, message...)
 }
 
+// FromPanic converts a recovered panic value to an AppError whose Stack is the stack
+// of the panicking goroutine, starting at the panic location rather than at the
+// recover site. Call it directly from the deferred function that recovers.
+//
+// A panicking *AppError keeps its code, message and status, other errors become
+// CodeInternal errors. In both cases the panic value is kept as Cause.
+//
+// Example:
+//
+//	defer func() {
+//		if rec := recover(); rec != nil {
+//			err = errors.FromPanic(rec)
+//		}
+//	}()
+func FromPanic(rec interface{}) *AppError {
+	appErr := &AppError{
+		Code:      CodeInternal,
+		Context:   make(map[string]interface{}),
+		Stack:     panicStack(captureStack(3)), // Skip captureStack and FromPanic
+		Timestamp: time.Now(),
+	}
+
+	switch v := rec.(type) {
+	case *AppError:
+		appErr.Code = v.Code
+		appErr.Message = v.Message
+		appErr.Details = v.Details
+		appErr.HTTPStatus = v.HTTPStatus
+		appErr.Cause = v
+		for key, value := range v.Context {
+			appErr.Context[key] = value
+		}
+	case error:
+		appErr.Message = CodeInternal.Message()
+		appErr.Details = fmt.Sprintf("panic: %v", v)
+		appErr.Cause = v
+	default:
+		appErr.Message = fmt.Sprintf("panic: %v", v)
+	}
+
+	return appErr
+}
+
+// panicStack drops the frames of a stack captured during a panic that precede the
+// panicking function: the deferred recover function and the runtime panic machinery
+func panicStack(frames []StackFrame) []StackFrame {
+	for i, frame := range frames {
+		if frame.Function != "runtime.gopanic" {
+			continue
+		}
+		rest := frames[i+1:]
+		for len(rest) > 0 && strings.HasPrefix(rest[0].Function, "runtime.") {
+			rest = rest[1:]
+		}
+		return rest
+	}
+	return frames
+}
+
 // 🎓 LEARNING: Stack trace capture
 // This uses Go's runtime package to capture where the error occurred
 
 // captureStack captures the current stack trace
 func captureStack(skip int) []StackFrame {
 	const maxDepth = 32
+	// pcs stays on the stack since runtime.Callers doesn't retain it, so unlike the
+	// response buffers it isn't pooled
 	var pcs [maxDepth]uintptr
 
 	// skip: number of frames to skip (captureStack itself, and caller)
@@ -286,3 +350,35 @@ func IsUniqueViolation(err error) bool {
 		strings.Contains(errMsg, "UNIQUE constraint failed") ||
 		strings.Contains(errMsg, "violates unique constraint")
 }
+
+// IsRetryable reports whether the operation that failed with err may succeed if
+// retried: AppErrors with status 429 or 5xx (except 501 Not Implemented), and
+// timeouts such as net.Error timeouts and context.DeadlineExceeded. Other errors,
+// including 4xx AppErrors and context.Canceled, are not retryable.
+//
+// Example:
+//
+//	if errors.IsRetryable(err) {
+//	    // back off and try again
+//	}
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if appErr, ok := As(err); ok {
+		status := appErr.GetHTTPStatus()
+		return status == http.StatusTooManyRequests ||
+			(status >= http.StatusInternalServerError && status != http.StatusNotImplemented)
+	}
+
+	if stdErrors.Is(err, context.Canceled) {
+		return false
+	}
+	if stdErrors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var timeout interface{ Timeout() bool }
+	return stdErrors.As(err, &timeout) && timeout.Timeout()
+}
@@ -1,8 +1,10 @@
 package errors
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -337,9 +339,12 @@ func TestFromHTTPStatus(t *testing.T) {
 		{http.StatusNotFound, CodeNotFound},
 		{http.StatusConflict, CodeConflict},
 		{http.StatusTooManyRequests, CodeTooManyRequests},
+		{StatusClientClosedRequest, CodeCanceled},
 		{http.StatusInternalServerError, CodeInternal},
 		{http.StatusNotImplemented, CodeNotImplemented},
+		{http.StatusBadGateway, CodeExternalService},
 		{http.StatusServiceUnavailable, CodeServiceUnavailable},
+		{http.StatusGatewayTimeout, CodeTimeout},
 		{999, CodeUnknown}, // Unknown status
 	}
 
@@ -358,6 +363,73 @@ func TestFromHTTPStatus(t *testing.T) {
 	}
 }
 
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "i/o timeout" }
+func (timeoutError) Timeout() bool { return true }
+
+// TestIsRetryable tests classifying errors as retryable
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"500", FromHTTPStatus(http.StatusInternalServerError), true},
+		{"502", FromHTTPStatus(http.StatusBadGateway), true},
+		{"503", FromHTTPStatus(http.StatusServiceUnavailable), true},
+		{"429", FromHTTPStatus(http.StatusTooManyRequests), true},
+		{"501", FromHTTPStatus(http.StatusNotImplemented), false},
+		{"400", FromHTTPStatus(http.StatusBadRequest), false},
+		{"404", NotFound("User"), false},
+		{"timeout code", New(CodeTimeout), true},
+		{"wrapped app error", fmt.Errorf("call failed: %w", FromHTTPStatus(http.StatusServiceUnavailable)), true},
+		{"deadline exceeded", context.DeadlineExceeded, true},
+		{"canceled", context.Canceled, false},
+		{"net timeout", fmt.Errorf("dial: %w", timeoutError{}), true},
+		{"plain error", fmt.Errorf("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRegisterCode tests resolving application-specific error codes
+func TestRegisterCode(t *testing.T) {
+	const code ErrorCode = "TEST_INSUFFICIENT_FUNDS"
+	const message = "The account balance is too low for this operation."
+
+	if got := code.HTTPStatus(); got != http.StatusInternalServerError {
+		t.Fatalf("HTTPStatus() before registration = %d, want %d", got, http.StatusInternalServerError)
+	}
+
+	RegisterCode(code, http.StatusUnprocessableEntity, message)
+
+	if got := code.HTTPStatus(); got != http.StatusUnprocessableEntity {
+		t.Errorf("HTTPStatus() = %d, want %d", got, http.StatusUnprocessableEntity)
+	}
+	if got := code.Message(); got != message {
+		t.Errorf("Message() = %q, want %q", got, message)
+	}
+	if !code.IsClientError() {
+		t.Error("IsClientError() = false, want true")
+	}
+
+	err := New(code)
+	if got := err.GetHTTPStatus(); got != http.StatusUnprocessableEntity {
+		t.Errorf("New().GetHTTPStatus() = %d, want %d", got, http.StatusUnprocessableEntity)
+	}
+	if err.Message != message {
+		t.Errorf("New().Message = %q, want %q", err.Message, message)
+	}
+}
+
 // BenchmarkNew benchmarks error creation
 func BenchmarkNew(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -384,3 +456,66 @@ func BenchmarkWithContext(b *testing.B) {
 		err.WithContext("key", "value")
 	}
 }
+
+//go:noinline
+func panickingFunction() {
+	var m map[string]int
+	m["boom"] = 1 // panics: assignment to entry in nil map
+}
+
+func recoverFrom(fn func()) (err *AppError) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = FromPanic(rec)
+		}
+	}()
+	fn()
+	return nil
+}
+
+// TestFromPanic tests that the stack starts at the panic location
+func TestFromPanic(t *testing.T) {
+	err := recoverFrom(panickingFunction)
+	if err == nil {
+		t.Fatal("FromPanic() returned nil")
+	}
+
+	if err.Code != CodeInternal {
+		t.Errorf("Code = %s, want %s", err.Code, CodeInternal)
+	}
+	if err.Cause == nil {
+		t.Error("Cause is nil, want the runtime error")
+	}
+	if len(err.Stack) == 0 {
+		t.Fatal("Stack is empty")
+	}
+	if !strings.HasSuffix(err.Stack[0].Function, ".panickingFunction") {
+		t.Errorf("Stack[0] = %s, want panickingFunction", err.Stack[0].Function)
+	}
+	if !strings.Contains(err.GetStackTrace(), "panickingFunction") {
+		t.Error("GetStackTrace() does not contain panickingFunction")
+	}
+}
+
+// TestFromPanicValues tests the conversion of the different panic values
+func TestFromPanicValues(t *testing.T) {
+	notFound := NotFound("User")
+	err := recoverFrom(func() { panic(notFound) })
+	if err.Code != CodeNotFound || err.Message != notFound.Message {
+		t.Errorf("FromPanic(*AppError) = %s %q, want the original code and message", err.Code, err.Message)
+	}
+	if err.Cause != notFound {
+		t.Error("Cause is not the panicking AppError")
+	}
+
+	plain := fmt.Errorf("boom")
+	err = recoverFrom(func() { panic(plain) })
+	if err.Code != CodeInternal || err.Cause != plain {
+		t.Errorf("FromPanic(error) = %s cause %v, want CodeInternal caused by the error", err.Code, err.Cause)
+	}
+
+	err = recoverFrom(func() { panic("boom") })
+	if err.Message != "panic: boom" {
+		t.Errorf("Message = %q, want %q", err.Message, "panic: boom")
+	}
+}
@@ -0,0 +1,192 @@
+package errors
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 🎓 LEARNING: gRPC status codes
+// gRPC has its own fixed set of status codes instead of HTTP statuses. Mapping our error
+// codes to them lets one error model serve both HTTP and gRPC: the ErrorCode travels in
+// an ErrorInfo detail, so a Go client gets the exact same code back.
+
+// codeToGRPC maps error codes to gRPC codes. Codes missing here, such as those added with
+// RegisterCode, are mapped from their HTTP status.
+var codeToGRPC = map[ErrorCode]codes.Code{
+	CodeInternal:       codes.Internal,
+	CodeUnknown:        codes.Unknown,
+	CodeNotImplemented: codes.Unimplemented,
+
+	CodeBadRequest:   codes.InvalidArgument,
+	CodeInvalidInput: codes.InvalidArgument,
+	CodeValidation:   codes.InvalidArgument,
+	CodeMissingField: codes.InvalidArgument,
+	CodeCanceled:     codes.Canceled,
+
+	CodeUnauthorized: codes.Unauthenticated,
+	CodeForbidden:    codes.PermissionDenied,
+	CodeInvalidToken: codes.Unauthenticated,
+	CodeTokenExpired: codes.Unauthenticated,
+
+	CodeNotFound:      codes.NotFound,
+	CodeAlreadyExists: codes.AlreadyExists,
+	CodeConflict:      codes.Aborted,
+	CodeGone:          codes.NotFound,
+
+	CodeTooManyRequests:   codes.ResourceExhausted,
+	CodeRateLimitExceeded: codes.ResourceExhausted,
+
+	CodeServiceUnavailable: codes.Unavailable,
+	CodeTimeout:            codes.DeadlineExceeded,
+	CodeExternalService:    codes.Unavailable,
+
+	CodeDatabaseError:       codes.Internal,
+	CodeDuplicateKey:        codes.AlreadyExists,
+	CodeForeignKeyViolation: codes.FailedPrecondition,
+}
+
+// grpcToCode maps gRPC codes to the error code used when a status carries no ErrorInfo
+var grpcToCode = map[codes.Code]ErrorCode{
+	codes.Canceled:           CodeCanceled,
+	codes.Unknown:            CodeUnknown,
+	codes.InvalidArgument:    CodeBadRequest,
+	codes.DeadlineExceeded:   CodeTimeout,
+	codes.NotFound:           CodeNotFound,
+	codes.AlreadyExists:      CodeAlreadyExists,
+	codes.PermissionDenied:   CodeForbidden,
+	codes.ResourceExhausted:  CodeTooManyRequests,
+	codes.FailedPrecondition: CodeBadRequest,
+	codes.Aborted:            CodeConflict,
+	codes.OutOfRange:         CodeBadRequest,
+	codes.Unimplemented:      CodeNotImplemented,
+	codes.Internal:           CodeInternal,
+	codes.Unavailable:        CodeServiceUnavailable,
+	codes.DataLoss:           CodeInternal,
+	codes.Unauthenticated:    CodeUnauthorized,
+}
+
+// GRPCCode returns the gRPC code for an error code
+func (c ErrorCode) GRPCCode() codes.Code {
+	if code, ok := codeToGRPC[c]; ok {
+		return code
+	}
+
+	switch httpStatus := c.HTTPStatus(); {
+	case httpStatus == http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case httpStatus == http.StatusForbidden:
+		return codes.PermissionDenied
+	case httpStatus == http.StatusNotFound:
+		return codes.NotFound
+	case httpStatus == http.StatusConflict:
+		return codes.Aborted
+	case httpStatus == http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case httpStatus >= 400 && httpStatus < 500:
+		return codes.InvalidArgument
+	case httpStatus == http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// ToGRPCStatus converts err to a gRPC status. AppErrors keep their message, their code
+// as the Reason of an ErrorInfo detail and their field errors as a BadRequest detail.
+// gRPC status errors are returned as is, context cancellation and deadline errors
+// become Canceled and DeadlineExceeded, and other errors become Internal errors.
+// A nil err gives an OK status.
+//
+// Example:
+//
+//	func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+//	    user, err := s.users.FindByID(ctx, req.Id)
+//	    if err != nil {
+//	        return nil, errors.ToGRPCStatus(err).Err()
+//	    }
+//	    return toProto(user), nil
+//	}
+func ToGRPCStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	appErr, ok := As(err)
+	if !ok {
+		if st, ok := status.FromError(err); ok {
+			return st
+		}
+		switch {
+		case stdErrors.Is(err, context.Canceled):
+			appErr = Wrap(err, CodeCanceled)
+		case stdErrors.Is(err, context.DeadlineExceeded):
+			appErr = Wrap(err, CodeTimeout)
+		default:
+			appErr = Wrap(err, CodeInternal)
+		}
+	}
+
+	st := status.New(appErr.Code.GRPCCode(), appErr.Message)
+
+	info := &errdetails.ErrorInfo{Reason: string(appErr.Code)}
+	withDetails, detailsErr := st.WithDetails(info)
+	if fields, ok := appErr.Context[fieldErrorsContextKey].([]ValidationField); ok && len(fields) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(fields))
+		for i, field := range fields {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: field.Field, Description: field.Message}
+		}
+		withDetails, detailsErr = st.WithDetails(info, &errdetails.BadRequest{FieldViolations: violations})
+	}
+
+	if detailsErr == nil {
+		return withDetails
+	}
+	return st
+}
+
+// FromGRPCStatus converts a gRPC status, e.g. from status.FromError on a client call
+// error, back to an AppError. The error code comes from the ErrorInfo detail added by
+// ToGRPCStatus, falling back to the closest code of the gRPC code, and BadRequest
+// details become field errors. It returns nil for nil and OK statuses.
+//
+// Example:
+//
+//	user, err := client.GetUser(ctx, req)
+//	if st, ok := status.FromError(err); ok && err != nil {
+//	    return errors.FromGRPCStatus(st) // errors.Is(err, errors.CodeNotFound) works again
+//	}
+func FromGRPCStatus(st *status.Status) *AppError {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	code, ok := grpcToCode[st.Code()]
+	if !ok {
+		code = CodeUnknown
+	}
+
+	var fields []ValidationField
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if detail.Reason != "" {
+				code = ErrorCode(detail.Reason)
+			}
+		case *errdetails.BadRequest:
+			for _, violation := range detail.FieldViolations {
+				fields = append(fields, ValidationField{Field: violation.Field, Message: violation.Description})
+			}
+		}
+	}
+
+	appErr := Wrap(st.Err(), code, st.Message())
+	if len(fields) > 0 {
+		appErr.WithFieldErrors(fields...)
+	}
+	return appErr
+}
@@ -0,0 +1,169 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestToGRPCStatus tests mapping each error code to its gRPC code and back
+func TestToGRPCStatus(t *testing.T) {
+	tests := []struct {
+		code ErrorCode
+		want codes.Code
+	}{
+		{CodeInternal, codes.Internal},
+		{CodeUnknown, codes.Unknown},
+		{CodeNotImplemented, codes.Unimplemented},
+		{CodeBadRequest, codes.InvalidArgument},
+		{CodeInvalidInput, codes.InvalidArgument},
+		{CodeValidation, codes.InvalidArgument},
+		{CodeMissingField, codes.InvalidArgument},
+		{CodeCanceled, codes.Canceled},
+		{CodeUnauthorized, codes.Unauthenticated},
+		{CodeForbidden, codes.PermissionDenied},
+		{CodeInvalidToken, codes.Unauthenticated},
+		{CodeTokenExpired, codes.Unauthenticated},
+		{CodeNotFound, codes.NotFound},
+		{CodeAlreadyExists, codes.AlreadyExists},
+		{CodeConflict, codes.Aborted},
+		{CodeGone, codes.NotFound},
+		{CodeTooManyRequests, codes.ResourceExhausted},
+		{CodeRateLimitExceeded, codes.ResourceExhausted},
+		{CodeServiceUnavailable, codes.Unavailable},
+		{CodeTimeout, codes.DeadlineExceeded},
+		{CodeExternalService, codes.Unavailable},
+		{CodeDatabaseError, codes.Internal},
+		{CodeDuplicateKey, codes.AlreadyExists},
+		{CodeForeignKeyViolation, codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			st := ToGRPCStatus(New(tt.code, "something failed"))
+			if st.Code() != tt.want {
+				t.Errorf("ToGRPCStatus(%s).Code() = %v, want %v", tt.code, st.Code(), tt.want)
+			}
+			if st.Message() != "something failed" {
+				t.Errorf("ToGRPCStatus(%s).Message() = %q, want %q", tt.code, st.Message(), "something failed")
+			}
+
+			// The ErrorInfo detail restores the exact code
+			back := FromGRPCStatus(st)
+			if back.Code != tt.code {
+				t.Errorf("FromGRPCStatus(ToGRPCStatus(%s)).Code = %s", tt.code, back.Code)
+			}
+			if back.Message != "something failed" {
+				t.Errorf("FromGRPCStatus(ToGRPCStatus(%s)).Message = %q", tt.code, back.Message)
+			}
+		})
+	}
+}
+
+// TestFromGRPCStatus tests mapping statuses without an ErrorInfo detail, e.g. from
+// services not using this package
+func TestFromGRPCStatus(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want ErrorCode
+	}{
+		{codes.Canceled, CodeCanceled},
+		{codes.Unknown, CodeUnknown},
+		{codes.InvalidArgument, CodeBadRequest},
+		{codes.DeadlineExceeded, CodeTimeout},
+		{codes.NotFound, CodeNotFound},
+		{codes.AlreadyExists, CodeAlreadyExists},
+		{codes.PermissionDenied, CodeForbidden},
+		{codes.ResourceExhausted, CodeTooManyRequests},
+		{codes.FailedPrecondition, CodeBadRequest},
+		{codes.Aborted, CodeConflict},
+		{codes.OutOfRange, CodeBadRequest},
+		{codes.Unimplemented, CodeNotImplemented},
+		{codes.Internal, CodeInternal},
+		{codes.Unavailable, CodeServiceUnavailable},
+		{codes.DataLoss, CodeInternal},
+		{codes.Unauthenticated, CodeUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			appErr := FromGRPCStatus(status.New(tt.code, "remote failure"))
+			if appErr.Code != tt.want {
+				t.Errorf("FromGRPCStatus(%v).Code = %s, want %s", tt.code, appErr.Code, tt.want)
+			}
+			if appErr.Message != "remote failure" {
+				t.Errorf("FromGRPCStatus(%v).Message = %q", tt.code, appErr.Message)
+			}
+			if status.Code(appErr.Cause) != tt.code {
+				t.Errorf("FromGRPCStatus(%v).Cause = %v, want the status error", tt.code, appErr.Cause)
+			}
+		})
+	}
+
+	if appErr := FromGRPCStatus(status.New(codes.OK, "")); appErr != nil {
+		t.Errorf("FromGRPCStatus(OK) = %v, want nil", appErr)
+	}
+	if appErr := FromGRPCStatus(nil); appErr != nil {
+		t.Errorf("FromGRPCStatus(nil) = %v, want nil", appErr)
+	}
+}
+
+// TestToGRPCStatusFieldErrors tests carrying field errors in a BadRequest detail
+func TestToGRPCStatusFieldErrors(t *testing.T) {
+	err := Validation("invalid user").WithFieldErrors(
+		ValidationField{Field: "email", Message: "must be a valid email"},
+		ValidationField{Field: "age", Message: "must be positive"},
+	)
+
+	back := FromGRPCStatus(ToGRPCStatus(err))
+	fields, _ := back.Context[fieldErrorsContextKey].([]ValidationField)
+	if len(fields) != 2 || fields[0].Field != "email" || fields[1].Message != "must be positive" {
+		t.Errorf("field errors = %+v", fields)
+	}
+}
+
+// TestToGRPCStatusNonAppErrors tests converting errors that are not AppErrors
+func TestToGRPCStatusNonAppErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"nil", nil, codes.OK},
+		{"status error", status.Error(codes.DataLoss, "corrupted"), codes.DataLoss},
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), codes.Canceled},
+		{"deadline", context.DeadlineExceeded, codes.DeadlineExceeded},
+		{"plain error", fmt.Errorf("connection refused"), codes.Internal},
+		{"wrapped app error", fmt.Errorf("lookup: %w", NotFound("User")), codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToGRPCStatus(tt.err).Code(); got != tt.want {
+				t.Errorf("ToGRPCStatus(%v).Code() = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	// Plain errors don't leak their text to clients
+	if msg := ToGRPCStatus(fmt.Errorf("dial tcp 10.0.0.1:5432")).Message(); msg != CodeInternal.Message() {
+		t.Errorf("ToGRPCStatus(plain error).Message() = %q", msg)
+	}
+}
+
+// TestGRPCCodeOfRegisteredCode tests mapping application codes from their HTTP status
+func TestGRPCCodeOfRegisteredCode(t *testing.T) {
+	const code ErrorCode = "TEST_GRPC_INSUFFICIENT_FUNDS"
+	RegisterCode(code, http.StatusUnprocessableEntity, "The account balance is too low.")
+
+	if got := code.GRPCCode(); got != codes.InvalidArgument {
+		t.Errorf("GRPCCode() = %v, want %v", got, codes.InvalidArgument)
+	}
+	if back := FromGRPCStatus(ToGRPCStatus(New(code))); back.Code != code {
+		t.Errorf("round trip code = %s, want %s", back.Code, code)
+	}
+}
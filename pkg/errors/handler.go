@@ -1,11 +1,38 @@
 package errors
 
 import (
-	"encoding/json"
-	"fmt"
+	"bytes"
+	"io"
 	"net/http"
+
+	"github.com/phatnt199/go-infra/pkg/correlation"
+	"github.com/phatnt199/go-infra/pkg/json"
+	"github.com/phatnt199/go-infra/pkg/utils/pool"
 )
 
+// maxPooledBufferSize is the largest buffer returned to bufferPool; bigger ones are
+// left to the garbage collector so the pool doesn't pin their memory
+const maxPooledBufferSize = 64 << 10
+
+// bufferPool holds the scratch buffers used to encode error responses
+var bufferPool = pool.New(func() *bytes.Buffer { return new(bytes.Buffer) }, (*bytes.Buffer).Reset)
+
+// encodeJSON encodes v into a pooled buffer and writes it to w in one call
+func encodeJSON(w io.Writer, v any) error {
+	buf := bufferPool.Get()
+	defer func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
 // 🎓 LEARNING: JSON and HTTP in Go
 // JSON tags control how structs are serialized to JSON
 // The format is: `json:"field_name,options"`
@@ -42,9 +69,9 @@ type ValidationErrorDetail struct {
 
 // ValidationField represents a single field validation error
 type ValidationField struct {
-	Field   string `json:"field"`           // Field name
-	Message string `json:"message"`         // Error message for this field
-	Value   string `json:"value,omitempty"` // The invalid value (be careful with sensitive data!)
+	Field   string `json:"field" xml:"field"`                     // Field name
+	Message string `json:"message" xml:"message"`                 // Error message for this field
+	Value   string `json:"value,omitempty" xml:"value,omitempty"` // The invalid value (be careful with sensitive data!)
 }
 
 // HandlerConfig configures the error handler behavior
@@ -54,6 +81,17 @@ type HandlerConfig struct {
 	ShowContext   bool   // Show error context
 	DefaultStatus int    // Default HTTP status for unknown errors
 	RequestIDKey  string // Key to extract request ID from context
+
+	// ProblemTypeBaseURI prefixes problem detail type URIs (default: DefaultProblemTypeBaseURI)
+	ProblemTypeBaseURI string
+
+	// Locale translates default code messages (see RegisterMessages). When empty,
+	// handlers with access to the request use its Accept-Language header.
+	Locale string
+
+	// PanicReporter receives the errors built from panics recovered by Middleware and
+	// ProblemMiddleware. When nil, panics are only rendered as error responses.
+	PanicReporter PanicReporter
 }
 
 // DefaultConfig returns a production-safe configuration
@@ -63,7 +101,7 @@ func DefaultConfig() HandlerConfig {
 		ShowStack:     false,
 		ShowContext:   false,
 		DefaultStatus: http.StatusInternalServerError,
-		RequestIDKey:  "request_id",
+		RequestIDKey:  correlation.FieldName,
 	}
 }
 
@@ -74,7 +112,7 @@ func DevelopmentConfig() HandlerConfig {
 		ShowStack:     true,
 		ShowContext:   true,
 		DefaultStatus: http.StatusInternalServerError,
-		RequestIDKey:  "request_id",
+		RequestIDKey:  correlation.FieldName,
 	}
 }
 
@@ -84,6 +122,17 @@ func DevelopmentConfig() HandlerConfig {
 
 // WriteJSON writes an error response as JSON to the HTTP response writer
 func WriteJSON(w http.ResponseWriter, err error, config HandlerConfig) {
+	writeJSON(w, nil, err, config)
+}
+
+// WriteRequestJSON is WriteJSON for the error of request r: the request ID falls back
+// to the correlation ID of r's context, and messages use the locale of r.
+func WriteRequestJSON(w http.ResponseWriter, r *http.Request, err error, config HandlerConfig) {
+	writeJSON(w, r, err, config.withRequestLocale(r))
+}
+
+// writeJSON writes the JSON error response, reading the request ID from r when not nil
+func writeJSON(w http.ResponseWriter, r *http.Request, err error, config HandlerConfig) {
 	appErr, ok := As(err)
 	if !ok {
 		// Not an AppError, wrap it
@@ -94,7 +143,7 @@ func WriteJSON(w http.ResponseWriter, err error, config HandlerConfig) {
 	response := ErrorResponse{
 		Error: ErrorDetail{
 			Code:      string(appErr.Code),
-			Message:   appErr.Message,
+			Message:   appErr.LocalizedMessage(config.Locale),
 			Timestamp: appErr.Timestamp.Format("2006-01-02T15:04:05Z07:00"), // ISO 8601
 		},
 	}
@@ -109,23 +158,31 @@ func WriteJSON(w http.ResponseWriter, err error, config HandlerConfig) {
 	}
 
 	// Try to get request ID from context
-	if config.RequestIDKey != "" {
-		if reqID, ok := appErr.Context[config.RequestIDKey].(string); ok {
-			response.Error.RequestID = reqID
-		}
-	}
+	response.Error.RequestID = requestID(r, appErr, config)
 
 	// Set response headers
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(appErr.GetHTTPStatus())
 
 	// Encode and write JSON
-	// 🎓 json.NewEncoder creates an encoder that writes directly to w
-	_ = json.NewEncoder(w).Encode(response)
+	// 🎓 encodeJSON encodes into a pooled buffer, then writes the body in one call
+	_ = encodeJSON(w, response)
 }
 
 // WriteValidationJSON writes a validation error response
 func WriteValidationJSON(w http.ResponseWriter, err error, fields []ValidationField, config HandlerConfig) {
+	writeValidationJSON(w, nil, err, fields, config)
+}
+
+// WriteRequestValidationJSON is WriteValidationJSON for the error of request r, with
+// the request ID and locale of WriteRequestJSON
+func WriteRequestValidationJSON(w http.ResponseWriter, r *http.Request, err error, fields []ValidationField, config HandlerConfig) {
+	writeValidationJSON(w, r, err, fields, config.withRequestLocale(r))
+}
+
+// writeValidationJSON writes the validation error response, reading the request ID from
+// r when not nil
+func writeValidationJSON(w http.ResponseWriter, r *http.Request, err error, fields []ValidationField, config HandlerConfig) {
 	appErr, ok := As(err)
 	if !ok {
 		appErr = Wrap(err, CodeValidation)
@@ -134,22 +191,33 @@ func WriteValidationJSON(w http.ResponseWriter, err error, fields []ValidationFi
 	response := ValidationErrorResponse{
 		Error: ValidationErrorDetail{
 			Code:      string(appErr.Code),
-			Message:   appErr.Message,
+			Message:   appErr.LocalizedMessage(config.Locale),
 			Timestamp: appErr.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
 			Fields:    fields,
 		},
 	}
 
 	// Try to get request ID
-	if config.RequestIDKey != "" {
-		if reqID, ok := appErr.Context[config.RequestIDKey].(string); ok {
-			response.Error.RequestID = reqID
-		}
-	}
+	response.Error.RequestID = requestID(r, appErr, config)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(appErr.GetHTTPStatus())
-	_ = json.NewEncoder(w).Encode(response)
+	_ = encodeJSON(w, response)
+}
+
+// requestID returns the request ID of an error response: the one in the error context,
+// else the correlation ID of r (the correlation middleware doesn't write the error)
+func requestID(r *http.Request, appErr *AppError, config HandlerConfig) string {
+	if config.RequestIDKey == "" {
+		return ""
+	}
+	if reqID, ok := appErr.Context[config.RequestIDKey].(string); ok {
+		return reqID
+	}
+	if r != nil {
+		return correlation.FromContext(r.Context())
+	}
+	return ""
 }
 
 // 🎓 LEARNING: Middleware in Go
@@ -165,21 +233,30 @@ func Middleware(config HandlerConfig) func(http.Handler) http.Handler {
 			defer func() {
 				if rec := recover(); rec != nil {
 					// A panic occurred! Convert it to an error response
-					var err error
+					appErr := FromPanic(rec)
+					config.reportPanic(r, appErr)
+					WriteRequestJSON(w, r, appErr, config)
+				}
+			}()
 
-					// Check if the panic value is already an error
-					if e, ok := rec.(error); ok {
-						err = e
-					} else {
-						// Create a new error from the panic value
-						err = Internal(fmt.Sprintf("panic: %v", rec))
-					}
+			// Call the next handler
+			next.ServeHTTP(w, r)
+		})
+	}
+}
 
-					WriteJSON(w, err, config)
+// ProblemMiddleware is like Middleware but renders panics as RFC 7807 problem details
+func ProblemMiddleware(config HandlerConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					appErr := FromPanic(rec)
+					config.reportPanic(r, appErr)
+					WriteProblemJSON(w, r, appErr, config)
 				}
 			}()
 
-			// Call the next handler
 			next.ServeHTTP(w, r)
 		})
 	}
@@ -202,6 +279,12 @@ func RespondWithErrorDev(w http.ResponseWriter, err error) {
 	WriteJSON(w, err, DevelopmentConfig())
 }
 
+// RespondWithRequestError is RespondWithError for the error of request r, reporting
+// its correlation ID as the request ID
+func RespondWithRequestError(w http.ResponseWriter, r *http.Request, err error) {
+	WriteRequestJSON(w, r, err, DefaultConfig())
+}
+
 // FromHTTPStatus creates an AppError from an HTTP status code
 // Useful when working with standard HTTP errors
 func FromHTTPStatus(status int) *AppError {
@@ -220,12 +303,18 @@ func FromHTTPStatus(status int) *AppError {
 		code = CodeConflict
 	case http.StatusTooManyRequests:
 		code = CodeTooManyRequests
+	case StatusClientClosedRequest:
+		code = CodeCanceled
 	case http.StatusInternalServerError:
 		code = CodeInternal
 	case http.StatusNotImplemented:
 		code = CodeNotImplemented
+	case http.StatusBadGateway:
+		code = CodeExternalService
 	case http.StatusServiceUnavailable:
 		code = CodeServiceUnavailable
+	case http.StatusGatewayTimeout:
+		code = CodeTimeout
 	default:
 		code = CodeUnknown
 	}
@@ -0,0 +1,145 @@
+package errors
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 🎓 LEARNING: Localization
+// Error codes keep their English messages in codeToMessage. Other languages are
+// registered as catalogs keyed by locale ("es", "pt-br"), and the HTTP handlers
+// pick one from the Accept-Language header of the request.
+
+// DefaultLocale is the locale of the built-in messages returned by ErrorCode.Message
+const DefaultLocale = "en"
+
+// acceptLanguageHeader is the request header handlers read the locale from
+const acceptLanguageHeader = "Accept-Language"
+
+var (
+	catalogsMu sync.RWMutex
+	catalogs   = map[string]map[ErrorCode]string{}
+)
+
+// RegisterMessages adds translations of error code messages for locale. Calling it
+// again for the same locale merges the messages, overriding existing ones. Codes
+// without a translation fall back to the default message.
+//
+// Example:
+//
+//	errors.RegisterMessages("es", map[errors.ErrorCode]string{
+//		errors.CodeNotFound: "No se encontró el recurso solicitado.",
+//	})
+func RegisterMessages(locale string, messages map[ErrorCode]string) {
+	locale = normalizeLocale(locale)
+
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+
+	catalog, ok := catalogs[locale]
+	if !ok {
+		catalog = make(map[ErrorCode]string, len(messages))
+		catalogs[locale] = catalog
+	}
+	for code, msg := range messages {
+		catalog[code] = msg
+	}
+}
+
+// MessageFor returns the message for an error code in locale. A regional locale
+// ("es-MX") falls back to its language ("es"), and then to Message.
+func (c ErrorCode) MessageFor(locale string) string {
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	for _, candidate := range localeCandidates(normalizeLocale(locale)) {
+		if msg, ok := catalogs[candidate][c]; ok {
+			return msg
+		}
+	}
+	return c.Message()
+}
+
+// LocalizedMessage returns the error message in locale. Messages set explicitly
+// when the error was created are returned as-is, only default code messages are
+// translated.
+func (e *AppError) LocalizedMessage(locale string) string {
+	if locale == "" || e.Message != e.Code.Message() {
+		return e.Message
+	}
+	return e.Code.MessageFor(locale)
+}
+
+// LocaleFromAcceptLanguage returns the most preferred locale of an Accept-Language
+// header value that has registered messages, or DefaultLocale.
+//
+// Example:
+//
+//	locale := errors.LocaleFromAcceptLanguage("es-MX,es;q=0.9,en;q=0.8") // "es-mx" or "es"
+func LocaleFromAcceptLanguage(header string) string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = normalizeLocale(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			tags = append(tags, weighted{locale: tag, q: q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	for _, tag := range tags {
+		for _, candidate := range localeCandidates(tag.locale) {
+			if _, ok := catalogs[candidate]; ok || candidate == DefaultLocale {
+				return candidate
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+// withRequestLocale returns config with Locale taken from the Accept-Language header
+// of r, unless config already sets one
+func (config HandlerConfig) withRequestLocale(r *http.Request) HandlerConfig {
+	if config.Locale == "" && r != nil {
+		if header := r.Header.Get(acceptLanguageHeader); header != "" {
+			config.Locale = LocaleFromAcceptLanguage(header)
+		}
+	}
+	return config
+}
+
+// normalizeLocale lower-cases locale and uses "-" as the region separator
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// localeCandidates returns locale followed by its language, e.g. "es-mx", "es"
+func localeCandidates(locale string) []string {
+	if language, _, found := strings.Cut(locale, "-"); found {
+		return []string{locale, language}
+	}
+	return []string{locale}
+}
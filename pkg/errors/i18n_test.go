@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const spanishNotFound = "No se encontró el recurso solicitado."
+
+func registerSpanish() {
+	RegisterMessages("es", map[ErrorCode]string{
+		CodeNotFound: spanishNotFound,
+	})
+}
+
+// TestMessageFor tests resolving messages from a registered catalog
+func TestMessageFor(t *testing.T) {
+	registerSpanish()
+
+	tests := []struct {
+		name   string
+		code   ErrorCode
+		locale string
+		want   string
+	}{
+		{"registered translation", CodeNotFound, "es", spanishNotFound},
+		{"regional locale falls back to language", CodeNotFound, "es-MX", spanishNotFound},
+		{"missing translation falls back to default", CodeConflict, "es", CodeConflict.Message()},
+		{"unknown locale falls back to default", CodeNotFound, "fr", CodeNotFound.Message()},
+		{"default locale", CodeNotFound, DefaultLocale, CodeNotFound.Message()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.code.MessageFor(tt.locale); got != tt.want {
+				t.Errorf("MessageFor(%q) = %q, want %q", tt.locale, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLocaleFromAcceptLanguage tests picking the preferred registered locale
+func TestLocaleFromAcceptLanguage(t *testing.T) {
+	registerSpanish()
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"es", "es"},
+		{"es-MX,es;q=0.9,en;q=0.8", "es"},
+		{"fr-FR,fr;q=0.9", DefaultLocale},
+		{"fr;q=0.9,es;q=0.5", "es"},
+		{"en;q=0.4,es;q=0.8", "es"},
+		{"es;q=0,en", "en"},
+		{"*", DefaultLocale},
+		{"", DefaultLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := LocaleFromAcceptLanguage(tt.header); got != tt.want {
+				t.Errorf("LocaleFromAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLocalizedMessage tests that only default code messages are translated
+func TestLocalizedMessage(t *testing.T) {
+	registerSpanish()
+
+	if got := New(CodeNotFound).LocalizedMessage("es"); got != spanishNotFound {
+		t.Errorf("LocalizedMessage() = %q, want %q", got, spanishNotFound)
+	}
+	if got := New(CodeNotFound, "order 42 not found").LocalizedMessage("es"); got != "order 42 not found" {
+		t.Errorf("LocalizedMessage() = %q, want the explicit message", got)
+	}
+}
+
+// TestWriteProblemJSON_Uses_Accept_Language tests the handler choosing the locale
+func TestWriteProblemJSON_Uses_Accept_Language(t *testing.T) {
+	registerSpanish()
+
+	r := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+	r.Header.Set("Accept-Language", "es-ES,es;q=0.9")
+	w := httptest.NewRecorder()
+
+	WriteProblemJSON(w, r, New(CodeNotFound), DefaultConfig())
+
+	var problem ProblemDetail
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("invalid problem JSON: %v", err)
+	}
+	if problem.Detail != spanishNotFound {
+		t.Errorf("Detail = %q, want %q", problem.Detail, spanishNotFound)
+	}
+}
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"encoding/xml"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+	"github.com/phatnt199/go-infra/pkg/json"
+)
+
+// 🎓 LEARNING: Content negotiation
+// Clients say which formats they understand with the Accept header, and the server
+// picks the best one it can produce. JSON is the default when nothing matches.
+
+// negotiableProblemTypes are the media types offered to the Accept header, in order of preference
+var negotiableProblemTypes = []string{
+	"application/json",
+	ContentTypeProblemJSON,
+	"application/xml",
+	ContentTypeProblemXML,
+	"text/xml",
+}
+
+// RespondWithErrorNegotiated writes err as a problem detail in the format the client
+// accepts: XML for application/xml (and text/xml), JSON otherwise.
+func RespondWithErrorNegotiated(c contracts.Context, err error, config HandlerConfig) error {
+	problem := ToProblemDetail(err, config.withRequestLocale(c.Request()))
+	if req := c.Request(); req != nil && req.URL != nil {
+		problem.Instance = req.URL.Path
+		problem.WithRequestIDFrom(req.Context())
+	}
+
+	switch c.Accepts(negotiableProblemTypes...) {
+	case "application/xml", ContentTypeProblemXML, "text/xml":
+		body, marshalErr := xml.Marshal(problem)
+		if marshalErr != nil {
+			return marshalErr
+		}
+		return c.Blob(problem.Status, ContentTypeProblemXML, append([]byte(xml.Header), body...))
+
+	default:
+		body, marshalErr := json.Marshal(problem)
+		if marshalErr != nil {
+			return marshalErr
+		}
+		return c.Blob(problem.Status, ContentTypeProblemJSON, body)
+	}
+}
@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/phatnt199/go-infra/pkg/correlation"
+	"github.com/phatnt199/go-infra/pkg/logger"
+)
+
+// PanicReporter receives the errors built from recovered panics, so they reach an
+// alerting sink (Sentry, Slack, ...) and not only the logs. The error carries the
+// panic stack in Stack and, when known, the request ID in its Context.
+//
+// Reports are delivered asynchronously; a slow or panicking reporter never delays
+// or breaks the response. Implementations must be safe for concurrent use.
+type PanicReporter interface {
+	Report(ctx context.Context, err *AppError)
+}
+
+// PanicReporterFunc adapts a function to a PanicReporter
+type PanicReporterFunc func(ctx context.Context, err *AppError)
+
+// Report calls f(ctx, err)
+func (f PanicReporterFunc) Report(ctx context.Context, err *AppError) {
+	f(ctx, err)
+}
+
+// NopPanicReporter discards every report, like a nil HandlerConfig.PanicReporter
+type NopPanicReporter struct{}
+
+// Report does nothing
+func (NopPanicReporter) Report(context.Context, *AppError) {}
+
+// NewLoggerPanicReporter returns a reporter writing each panic as an error log line,
+// including the request ID and the stack trace
+//
+// Example:
+//
+//	config := errors.DefaultConfig()
+//	config.PanicReporter = errors.NewLoggerPanicReporter(log)
+//	handler := errors.Middleware(config)(mux)
+func NewLoggerPanicReporter(log logger.Logger) PanicReporter {
+	return PanicReporterFunc(func(_ context.Context, err *AppError) {
+		requestID, _ := err.Context[correlation.FieldName].(string)
+		log.ErrorFields("panic recovered",
+			logger.String("code", string(err.Code)),
+			logger.String("error", err.Error()),
+			logger.String("details", err.Details),
+			logger.String(correlation.FieldName, requestID),
+			logger.String("stack", err.GetStackTrace()),
+		)
+	})
+}
+
+// reportPanic attaches the request ID of r to appErr and hands it to the configured
+// reporter in a separate goroutine, recovering any panic raised by the reporter
+func (config HandlerConfig) reportPanic(r *http.Request, appErr *AppError) {
+	if config.RequestIDKey != "" {
+		if _, ok := appErr.Context[config.RequestIDKey]; !ok {
+			if id := correlation.FromContext(r.Context()); id != "" {
+				appErr.WithContext(config.RequestIDKey, id)
+			}
+		}
+	}
+
+	reporter := config.PanicReporter
+	if reporter == nil {
+		return
+	}
+
+	// The request context is cancelled once the response is written
+	ctx := context.WithoutCancel(r.Context())
+	go func() {
+		defer func() { _ = recover() }()
+		reporter.Report(ctx, appErr)
+	}()
+}
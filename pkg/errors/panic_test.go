@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/correlation"
+)
+
+// TestMiddlewareReportsPanics tests the recovery middlewares hand panics to the reporter
+func TestMiddlewareReportsPanics(t *testing.T) {
+	middlewares := map[string]func(HandlerConfig) func(http.Handler) http.Handler{
+		"json":    Middleware,
+		"problem": ProblemMiddleware,
+	}
+
+	for name, middleware := range middlewares {
+		t.Run(name, func(t *testing.T) {
+			reported := make(chan *AppError, 1)
+			config := DefaultConfig()
+			config.PanicReporter = PanicReporterFunc(func(_ context.Context, err *AppError) {
+				reported <- err
+			})
+
+			handler := middleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic("boom")
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			req = req.WithContext(correlation.NewContext(req.Context(), "req-789"))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+			}
+
+			select {
+			case err := <-reported:
+				if err.Code != CodeInternal {
+					t.Errorf("Code = %s, want %s", err.Code, CodeInternal)
+				}
+				if len(err.Stack) == 0 {
+					t.Fatal("expected the reported error to carry the panic stack")
+				}
+				if got := err.Context[correlation.FieldName]; got != "req-789" {
+					t.Errorf("request id = %v, want %q", got, "req-789")
+				}
+			case <-time.After(time.Second):
+				t.Fatal("reporter was not called")
+			}
+		})
+	}
+}
+
+// TestMiddlewareSurvivesPanickingReporter tests a failing reporter doesn't break the response
+func TestMiddlewareSurvivesPanickingReporter(t *testing.T) {
+	called := make(chan struct{})
+	config := DefaultConfig()
+	config.PanicReporter = PanicReporterFunc(func(context.Context, *AppError) {
+		close(called)
+		panic("reporter down")
+	})
+
+	handler := Middleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(NotFound("User"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("reporter was not called")
+	}
+}
@@ -0,0 +1,128 @@
+package errors
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"strings"
+
+	"github.com/phatnt199/go-infra/pkg/correlation"
+)
+
+// 🎓 LEARNING: RFC 7807 Problem Details
+// RFC 7807 defines a standard JSON body for HTTP API errors ("application/problem+json").
+// Every adapter (net/http, Fiber, ...) renders the same ProblemDetail so clients
+// only need to understand one error format.
+
+// Media types for RFC 7807 problem details
+const (
+	ContentTypeProblemJSON = "application/problem+json"
+	ContentTypeProblemXML  = "application/problem+xml"
+)
+
+// DefaultProblemTypeBaseURI prefixes problem type URIs when HandlerConfig doesn't set one.
+// RFC 7807 allows relative URIs, e.g. "/problems/not-found".
+const DefaultProblemTypeBaseURI = "/problems/"
+
+// fieldErrorsContextKey is the AppError context key holding field-level validation errors
+const fieldErrorsContextKey = "errors"
+
+// ProblemDetail is an RFC 7807 error response body
+// The XML form uses the RFC 7807 "urn:ietf:rfc:7807" namespace.
+type ProblemDetail struct {
+	XMLName   xml.Name          `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	Type      string            `json:"type" xml:"type"`                                 // URI identifying the problem type
+	Title     string            `json:"title" xml:"title"`                               // Short summary of the problem type
+	Status    int               `json:"status" xml:"status"`                             // HTTP status code
+	Detail    string            `json:"detail,omitempty" xml:"detail,omitempty"`         // Explanation of this occurrence
+	Instance  string            `json:"instance,omitempty" xml:"instance,omitempty"`     // URI of this occurrence (usually the request path)
+	Code      string            `json:"code,omitempty" xml:"code,omitempty"`             // Application error code (extension member)
+	RequestID string            `json:"request_id,omitempty" xml:"request_id,omitempty"` // Request ID for tracking (extension member)
+	Errors    []ValidationField `json:"errors,omitempty" xml:"errors>error,omitempty"`   // Field-level validation errors
+}
+
+// WithFieldErrors attaches field-level validation errors, rendered as the
+// "errors" member of the problem detail
+func (e *AppError) WithFieldErrors(fields ...ValidationField) *AppError {
+	return e.WithContext(fieldErrorsContextKey, fields)
+}
+
+// Title returns a human readable title for an error code, e.g. "Not Found" for NOT_FOUND
+func (c ErrorCode) Title() string {
+	words := strings.Split(strings.ToLower(string(c)), "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// ProblemType returns the problem type URI for an error code, e.g. "/problems/not-found"
+func (c ErrorCode) ProblemType(baseURI string) string {
+	if baseURI == "" {
+		baseURI = DefaultProblemTypeBaseURI
+	}
+	return baseURI + strings.ReplaceAll(strings.ToLower(string(c)), "_", "-")
+}
+
+// ToProblemDetail converts any error to an RFC 7807 problem detail.
+// Non-AppErrors are treated as internal errors so their messages are never leaked.
+// Instance is left empty; adapters set it to the request path.
+func ToProblemDetail(err error, config HandlerConfig) ProblemDetail {
+	appErr, ok := As(err)
+	if !ok {
+		appErr = Wrap(err, CodeInternal)
+	}
+
+	problem := ProblemDetail{
+		Type:   appErr.Code.ProblemType(config.ProblemTypeBaseURI),
+		Title:  appErr.Code.Title(),
+		Status: appErr.GetHTTPStatus(),
+		Detail: appErr.LocalizedMessage(config.Locale),
+		Code:   string(appErr.Code),
+	}
+
+	if config.ShowDetails && appErr.Details != "" {
+		problem.Detail = problem.Detail + ": " + appErr.Details
+	}
+
+	if config.RequestIDKey != "" {
+		if reqID, ok := appErr.Context[config.RequestIDKey].(string); ok {
+			problem.RequestID = reqID
+		}
+	}
+
+	if fields, ok := appErr.Context[fieldErrorsContextKey].([]ValidationField); ok {
+		problem.Errors = fields
+	}
+
+	return problem
+}
+
+// WithRequestIDFrom sets RequestID from the correlation ID carried by ctx,
+// unless the error context already provided one
+func (p *ProblemDetail) WithRequestIDFrom(ctx context.Context) *ProblemDetail {
+	if p.RequestID == "" {
+		p.RequestID = correlation.FromContext(ctx)
+	}
+	return p
+}
+
+// WriteProblemJSON writes an error as an RFC 7807 problem detail
+func WriteProblemJSON(w http.ResponseWriter, r *http.Request, err error, config HandlerConfig) {
+	problem := ToProblemDetail(err, config.withRequestLocale(r))
+	if r != nil {
+		problem.Instance = r.URL.Path
+		problem.WithRequestIDFrom(r.Context())
+	}
+
+	w.Header().Set("Content-Type", ContentTypeProblemJSON)
+	w.WriteHeader(problem.Status)
+	_ = encodeJSON(w, problem)
+}
+
+// RespondWithProblem is a convenience function to write a problem detail response
+func RespondWithProblem(w http.ResponseWriter, r *http.Request, err error) {
+	WriteProblemJSON(w, r, err, DefaultConfig())
+}
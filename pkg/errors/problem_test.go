@@ -0,0 +1,151 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/correlation"
+)
+
+// TestToProblemDetail tests the AppError to RFC 7807 conversion
+func TestToProblemDetail(t *testing.T) {
+	err := Validation("invalid user").
+		WithContext("request_id", "req-123").
+		WithFieldErrors(ValidationField{Field: "email", Message: "email is required"})
+
+	problem := ToProblemDetail(err, DefaultConfig())
+
+	if problem.Type != "/problems/validation-error" {
+		t.Errorf("Type = %q, want %q", problem.Type, "/problems/validation-error")
+	}
+	if problem.Title != "Validation Error" {
+		t.Errorf("Title = %q, want %q", problem.Title, "Validation Error")
+	}
+	if problem.Status != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", problem.Status, http.StatusBadRequest)
+	}
+	if problem.Detail != "invalid user" {
+		t.Errorf("Detail = %q, want %q", problem.Detail, "invalid user")
+	}
+	if problem.RequestID != "req-123" {
+		t.Errorf("RequestID = %q, want %q", problem.RequestID, "req-123")
+	}
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "email" {
+		t.Errorf("Errors = %+v, want one error for email", problem.Errors)
+	}
+}
+
+// TestToProblemDetailHidesUnknownErrors tests that non-AppErrors don't leak their message
+func TestToProblemDetailHidesUnknownErrors(t *testing.T) {
+	config := DefaultConfig()
+	config.ProblemTypeBaseURI = "https://api.example.com/problems/"
+
+	problem := ToProblemDetail(fmt.Errorf("pq: password authentication failed"), config)
+
+	if problem.Status != http.StatusInternalServerError {
+		t.Errorf("Status = %d, want %d", problem.Status, http.StatusInternalServerError)
+	}
+	if problem.Type != "https://api.example.com/problems/internal-error" {
+		t.Errorf("Type = %q, want custom base URI", problem.Type)
+	}
+	if problem.Detail != CodeInternal.Message() {
+		t.Errorf("Detail = %q, want the default internal message", problem.Detail)
+	}
+}
+
+// TestWriteProblemJSON tests the stdlib problem detail writer and middleware
+func TestWriteProblemJSON(t *testing.T) {
+	handler := ProblemMiddleware(DefaultConfig())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(NotFound("User"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ContentTypeProblemJSON {
+		t.Errorf("Content-Type = %q, want %q", ct, ContentTypeProblemJSON)
+	}
+
+	var problem ProblemDetail
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatalf("failed to decode problem detail: %v", err)
+	}
+	if problem.Instance != "/users/42" {
+		t.Errorf("Instance = %q, want %q", problem.Instance, "/users/42")
+	}
+	if problem.Title != "Not Found" {
+		t.Errorf("Title = %q, want %q", problem.Title, "Not Found")
+	}
+}
+
+// TestWriteProblemJSONUsesCorrelationID tests the request ID is taken from the request context
+func TestWriteProblemJSONUsesCorrelationID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+	req = req.WithContext(correlation.NewContext(req.Context(), "req-456"))
+	rec := httptest.NewRecorder()
+
+	WriteProblemJSON(rec, req, NotFound("order"), DefaultConfig())
+
+	var problem ProblemDetail
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	if problem.RequestID != "req-456" {
+		t.Errorf("RequestID = %q, want %q", problem.RequestID, "req-456")
+	}
+}
+
+// TestWriteRequestJSONUsesCorrelationID tests the JSON writers take the request ID from
+// the request context
+func TestWriteRequestJSONUsesCorrelationID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+	req = req.WithContext(correlation.NewContext(req.Context(), "req-321"))
+
+	writers := map[string]func(w http.ResponseWriter){
+		"WriteRequestJSON": func(w http.ResponseWriter) {
+			WriteRequestJSON(w, req, NotFound("order"), DefaultConfig())
+		},
+		"WriteRequestValidationJSON": func(w http.ResponseWriter) {
+			WriteRequestValidationJSON(w, req, Validation("invalid order"), []ValidationField{{Field: "id"}}, DefaultConfig())
+		},
+		"RespondWithRequestError": func(w http.ResponseWriter) {
+			RespondWithRequestError(w, req, NotFound("order"))
+		},
+		"Middleware": func(w http.ResponseWriter) {
+			Middleware(DefaultConfig())(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				panic("boom")
+			})).ServeHTTP(w, req)
+		},
+	}
+
+	for name, write := range writers {
+		rec := httptest.NewRecorder()
+		write(rec)
+
+		var response struct {
+			Error struct {
+				RequestID string `json:"request_id"`
+			} `json:"error"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatalf("%s: failed to decode response: %v", name, err)
+		}
+		if response.Error.RequestID != "req-321" {
+			t.Errorf("%s: RequestID = %q, want %q", name, response.Error.RequestID, "req-321")
+		}
+	}
+
+	// The error context wins
+	rec := httptest.NewRecorder()
+	WriteRequestJSON(rec, req, NotFound("order").WithContext(correlation.FieldName, "req-ctx"), DefaultConfig())
+	if !strings.Contains(rec.Body.String(), `"request_id":"req-ctx"`) {
+		t.Errorf("body = %s, want request_id req-ctx", rec.Body.String())
+	}
+}
@@ -0,0 +1,54 @@
+package featureflag
+
+import "context"
+
+type (
+	providerContextKey struct{}
+	userIDContextKey   struct{}
+)
+
+// NewContext returns a copy of ctx carrying provider
+func NewContext(ctx context.Context, provider Provider) context.Context {
+	return context.WithValue(ctx, providerContextKey{}, provider)
+}
+
+// FromContext returns the provider stored in ctx. When there is none, it returns a
+// provider answering every flag with its default value.
+func FromContext(ctx context.Context) Provider {
+	if provider, ok := ctx.Value(providerContextKey{}).(Provider); ok && provider != nil {
+		return provider
+	}
+	return defaultsProvider{}
+}
+
+// WithUserID returns a copy of ctx carrying the user ID that percentage rollouts are keyed by
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDContextKey{}, userID)
+}
+
+// UserIDFromContext returns the user ID stored by WithUserID, or ""
+func UserIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDContextKey{}).(string)
+	return userID
+}
+
+// Bool evaluates a boolean flag with the provider stored in ctx
+func Bool(ctx context.Context, key string, defaultValue bool) bool {
+	return FromContext(ctx).Bool(ctx, key, defaultValue)
+}
+
+// String evaluates a string flag with the provider stored in ctx
+func String(ctx context.Context, key string, defaultValue string) string {
+	return FromContext(ctx).String(ctx, key, defaultValue)
+}
+
+// defaultsProvider returns the default value of every flag
+type defaultsProvider struct{}
+
+func (defaultsProvider) Bool(_ context.Context, _ string, defaultValue bool) bool {
+	return defaultValue
+}
+
+func (defaultsProvider) String(_ context.Context, _ string, defaultValue string) string {
+	return defaultValue
+}
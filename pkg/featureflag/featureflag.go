@@ -0,0 +1,162 @@
+// Package featureflag evaluates runtime feature flags. Flags are plain strings: "true"
+// or "false", a percentage rollout such as "25%" (enabled for a stable 25% of users,
+// bucketed by user ID), or any string value for variants.
+//
+// The config-backed provider reads the features.flags section of the application
+// config (FEATURE_FLAGS) and follows config.Set through WatchConfig. Handlers read
+// flags from the request context populated by customfiber.FeatureFlagMiddleware:
+//
+//	if featureflag.Bool(ctx, "new_checkout", false) {
+//	    return newCheckout(ctx, cart)
+//	}
+package featureflag
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+
+	appConfig "github.com/phatnt199/go-infra/pkg/application/config"
+)
+
+// Provider evaluates feature flags. Boolean evaluation of percentage rollouts uses
+// the user ID stored in ctx by WithUserID.
+type Provider interface {
+	// Bool returns whether the flag is enabled, or defaultValue if it is not defined
+	Bool(ctx context.Context, key string, defaultValue bool) bool
+
+	// String returns the flag's value, or defaultValue if it is not defined
+	String(ctx context.Context, key string, defaultValue string) string
+}
+
+// ConfigProvider is a Provider backed by a map of flag values, usually the
+// features.flags section of the application config. It is safe for concurrent use.
+type ConfigProvider struct {
+	mu    sync.RWMutex
+	flags map[string]string
+}
+
+// Compile-time assertion that ConfigProvider implements Provider
+var _ Provider = (*ConfigProvider)(nil)
+
+// NewConfigProvider creates a provider serving flags
+//
+// Example:
+//
+//	flags := featureflag.NewConfigProvider(config.Get().Features.Flags)
+func NewConfigProvider(flags map[string]string) *ConfigProvider {
+	p := &ConfigProvider{}
+	p.Update(flags)
+	return p
+}
+
+// Update replaces all flags
+func (p *ConfigProvider) Update(flags map[string]string) {
+	copied := make(map[string]string, len(flags))
+	for key, value := range flags {
+		copied[key] = value
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.flags = copied
+}
+
+// WatchConfig keeps the provider in sync with the features section of the global
+// application config, hot reloading flags whenever config.Set applies a new
+// configuration. It returns a function that stops watching.
+//
+// Example:
+//
+//	flags := featureflag.NewConfigProvider(cfg.Features.Flags)
+//	stop := flags.WatchConfig()
+//	defer stop()
+func (p *ConfigProvider) WatchConfig() func() {
+	if cfg := appConfig.Get(); cfg != nil {
+		p.Update(cfg.Features.Flags)
+	}
+
+	return appConfig.OnChange(func(_, current *appConfig.Config) {
+		if current != nil {
+			p.Update(current.Features.Flags)
+		}
+	})
+}
+
+// Bool implements Provider
+func (p *ConfigProvider) Bool(ctx context.Context, key string, defaultValue bool) bool {
+	value, ok := p.lookup(key)
+	if !ok {
+		return defaultValue
+	}
+
+	if percent, ok := parsePercentage(value); ok {
+		return Rollout(key, UserIDFromContext(ctx), percent)
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue
+	}
+	return enabled
+}
+
+// String implements Provider
+func (p *ConfigProvider) String(_ context.Context, key string, defaultValue string) string {
+	if value, ok := p.lookup(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+func (p *ConfigProvider) lookup(key string) (string, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	value, ok := p.flags[key]
+	return value, ok
+}
+
+// parsePercentage parses rollout values such as "25%"
+func parsePercentage(value string) (int, bool) {
+	number, ok := strings.CutSuffix(strings.TrimSpace(value), "%")
+	if !ok {
+		return 0, false
+	}
+
+	percent, err := strconv.Atoi(strings.TrimSpace(number))
+	if err != nil || percent < 0 || percent > 100 {
+		return 0, false
+	}
+	return percent, true
+}
+
+// Rollout reports whether userID falls within the first percent of the flag's 100
+// buckets. A user always lands in the same bucket for a given key, so raising the
+// percentage only ever adds users; buckets differ across keys so the same users are
+// not always first. An empty userID is never included below 100%.
+//
+// Example:
+//
+//	if featureflag.Rollout("new_checkout", user.ID, 25) {
+//	    // enabled for 25% of users
+//	}
+func Rollout(key, userID string, percent int) bool {
+	if percent >= 100 {
+		return true
+	}
+	if percent <= 0 || userID == "" {
+		return false
+	}
+	return Bucket(key, userID) < percent
+}
+
+// Bucket returns the stable bucket, in [0, 100), of userID for the flag key
+func Bucket(key, userID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(userID))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,133 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appConfig "github.com/phatnt199/go-infra/pkg/application/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Bucket_Is_Stable_For_The_Same_User(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		userID := fmt.Sprintf("user-%d", i)
+		bucket := Bucket("new_checkout", userID)
+
+		assert.GreaterOrEqual(t, bucket, 0)
+		assert.Less(t, bucket, 100)
+		for j := 0; j < 5; j++ {
+			assert.Equal(t, bucket, Bucket("new_checkout", userID))
+		}
+	}
+}
+
+func Test_Rollout_Is_Stable_And_Monotonic(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		userID := fmt.Sprintf("user-%d", i)
+		enabledAt := -1
+		for percent := 0; percent <= 100; percent++ {
+			enabled := Rollout("new_checkout", userID, percent)
+			assert.Equal(t, enabled, Rollout("new_checkout", userID, percent))
+			if enabled && enabledAt < 0 {
+				enabledAt = percent
+			}
+			if enabledAt >= 0 {
+				assert.True(t, enabled, "user %s dropped out of the rollout at %d%%", userID, percent)
+			}
+		}
+	}
+}
+
+func Test_Rollout_Distributes_Users_Across_Buckets(t *testing.T) {
+	enabled := 0
+	for i := 0; i < 10000; i++ {
+		if Rollout("beta_banner", fmt.Sprintf("user-%d", i), 25) {
+			enabled++
+		}
+	}
+
+	assert.InDelta(t, 2500, enabled, 300)
+}
+
+func Test_Rollout_Edges(t *testing.T) {
+	assert.False(t, Rollout("flag", "user-1", 0))
+	assert.True(t, Rollout("flag", "user-1", 100))
+	assert.False(t, Rollout("flag", "", 50))
+	assert.True(t, Rollout("flag", "", 100))
+}
+
+func Test_ConfigProvider_Bool(t *testing.T) {
+	p := NewConfigProvider(map[string]string{
+		"on":      "true",
+		"off":     "false",
+		"invalid": "maybe",
+		"all":     "100%",
+		"none":    "0%",
+	})
+	ctx := context.Background()
+
+	assert.True(t, p.Bool(ctx, "on", false))
+	assert.False(t, p.Bool(ctx, "off", true))
+	assert.True(t, p.Bool(ctx, "invalid", true))
+	assert.True(t, p.Bool(ctx, "missing", true))
+	assert.True(t, p.Bool(WithUserID(ctx, "user-1"), "all", false))
+	assert.False(t, p.Bool(WithUserID(ctx, "user-1"), "none", true))
+}
+
+func Test_ConfigProvider_Bool_Rollout_Uses_Context_User(t *testing.T) {
+	p := NewConfigProvider(map[string]string{"beta": "50%"})
+
+	for i := 0; i < 50; i++ {
+		userID := fmt.Sprintf("user-%d", i)
+		ctx := WithUserID(context.Background(), userID)
+		assert.Equal(t, Rollout("beta", userID, 50), p.Bool(ctx, "beta", false))
+	}
+	assert.False(t, p.Bool(context.Background(), "beta", true))
+}
+
+func Test_ConfigProvider_String(t *testing.T) {
+	p := NewConfigProvider(map[string]string{"theme": "dark"})
+
+	assert.Equal(t, "dark", p.String(context.Background(), "theme", "light"))
+	assert.Equal(t, "light", p.String(context.Background(), "missing", "light"))
+}
+
+func Test_ConfigProvider_Update_Does_Not_Alias_Input(t *testing.T) {
+	flags := map[string]string{"on": "true"}
+	p := NewConfigProvider(flags)
+
+	flags["on"] = "false"
+
+	assert.True(t, p.Bool(context.Background(), "on", false))
+}
+
+func Test_ConfigProvider_WatchConfig_Hot_Reloads(t *testing.T) {
+	previous := appConfig.Get()
+	t.Cleanup(func() { appConfig.Set(previous) })
+
+	appConfig.Set(&appConfig.Config{Features: appConfig.FeaturesConfig{Flags: map[string]string{"on": "true"}}})
+
+	p := NewConfigProvider(nil)
+	stop := p.WatchConfig()
+	ctx := context.Background()
+	assert.True(t, p.Bool(ctx, "on", false))
+
+	appConfig.Set(&appConfig.Config{Features: appConfig.FeaturesConfig{Flags: map[string]string{"on": "false"}}})
+	assert.False(t, p.Bool(ctx, "on", true))
+
+	stop()
+	appConfig.Set(&appConfig.Config{Features: appConfig.FeaturesConfig{Flags: map[string]string{"on": "true"}}})
+	assert.False(t, p.Bool(ctx, "on", true))
+}
+
+func Test_FromContext_Defaults_Without_Provider(t *testing.T) {
+	ctx := context.Background()
+
+	assert.True(t, Bool(ctx, "anything", true))
+	assert.Equal(t, "fallback", String(ctx, "anything", "fallback"))
+
+	ctx = NewContext(ctx, NewConfigProvider(map[string]string{"anything": "false"}))
+	assert.False(t, Bool(ctx, "anything", true))
+}
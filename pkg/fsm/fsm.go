@@ -0,0 +1,116 @@
+// Package fsm enforces the status transitions of entity lifecycles, such as
+// pending → active → closed, in one declaration instead of checks scattered in services.
+// Illegal transitions are CodeConflict errors, and callbacks run when states are exited
+// and entered.
+//
+//	var orderStatus = fsm.New[OrderStatus]().
+//	    Allow(StatusPending, StatusPaid, StatusCancelled).
+//	    Allow(StatusPaid, StatusShipped, StatusRefunded).
+//	    Allow(StatusShipped, StatusDelivered).
+//	    OnEnter(StatusShipped, notifyCustomer)
+//
+//	order.Status, err = orderStatus.Transition(ctx, order.Status, StatusShipped)
+package fsm
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// ErrIllegalTransition is the cause of the CodeConflict error returned by Transition for
+// transitions the machine doesn't allow
+var ErrIllegalTransition = stdErrors.New("illegal state transition")
+
+// Hook is called during a transition from one state to another. Returning an error
+// aborts the transition.
+type Hook[S comparable] func(ctx context.Context, from, to S) error
+
+// Machine defines the allowed transitions between states of type S, typically a string
+// status enum. Configure it once, e.g. in a package variable; it is then safe for
+// concurrent use.
+type Machine[S comparable] struct {
+	transitions map[S][]S
+	onExit      map[S][]Hook[S]
+	onEnter     map[S][]Hook[S]
+}
+
+// New creates a machine without transitions
+func New[S comparable]() *Machine[S] {
+	return &Machine[S]{
+		transitions: make(map[S][]S),
+		onExit:      make(map[S][]Hook[S]),
+		onEnter:     make(map[S][]Hook[S]),
+	}
+}
+
+// Allow allows the transitions from from to each of to. Staying in the same state is a
+// transition too, only allowed when listed.
+func (m *Machine[S]) Allow(from S, to ...S) *Machine[S] {
+	for _, target := range to {
+		if !m.Can(from, target) {
+			m.transitions[from] = append(m.transitions[from], target)
+		}
+	}
+	return m
+}
+
+// OnExit registers hook to run when leaving state, before the hooks of Transition
+func (m *Machine[S]) OnExit(state S, hook Hook[S]) *Machine[S] {
+	m.onExit[state] = append(m.onExit[state], hook)
+	return m
+}
+
+// OnEnter registers hook to run when entering state, after the hooks of Transition
+func (m *Machine[S]) OnEnter(state S, hook Hook[S]) *Machine[S] {
+	m.onEnter[state] = append(m.onEnter[state], hook)
+	return m
+}
+
+// Can reports whether the machine allows the transition from from to to
+func (m *Machine[S]) Can(from, to S) bool {
+	for _, target := range m.transitions[from] {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Targets returns the states reachable from from, in the order they were allowed, e.g.
+// to offer the next actions in a UI
+func (m *Machine[S]) Targets(from S) []S {
+	return append([]S(nil), m.transitions[from]...)
+}
+
+// Transition moves from current to to, returning to. Illegal transitions fail with a
+// CodeConflict error caused by ErrIllegalTransition, carrying "from" and "to" in its
+// context. Otherwise the OnExit hooks of current run, then hooks, typically persisting
+// the new state, then the OnEnter hooks of to. The first hook failing stops the
+// transition and its error is returned along with current.
+//
+// Example:
+//
+//	next, err := orderStatus.Transition(ctx, order.Status, StatusPaid,
+//	    func(ctx context.Context, from, to OrderStatus) error {
+//	        return orders.UpdateColumns(ctx, order.ID, map[string]interface{}{"status": to})
+//	    })
+func (m *Machine[S]) Transition(ctx context.Context, current, to S, hooks ...Hook[S]) (S, error) {
+	if !m.Can(current, to) {
+		return current, errors.Wrap(ErrIllegalTransition, errors.CodeConflict,
+			fmt.Sprintf("cannot transition from %v to %v", current, to)).
+			WithContext("from", current).
+			WithContext("to", to)
+	}
+
+	for _, group := range [][]Hook[S]{m.onExit[current], hooks, m.onEnter[to]} {
+		for _, hook := range group {
+			if err := hook(ctx, current, to); err != nil {
+				return current, err
+			}
+		}
+	}
+	return to, nil
+}
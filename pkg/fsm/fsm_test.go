@@ -0,0 +1,114 @@
+package fsm
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type accountStatus string
+
+const (
+	statusPending   accountStatus = "pending"
+	statusActive    accountStatus = "active"
+	statusSuspended accountStatus = "suspended"
+	statusClosed    accountStatus = "closed"
+)
+
+func newAccountMachine() *Machine[accountStatus] {
+	return New[accountStatus]().
+		Allow(statusPending, statusActive, statusClosed).
+		Allow(statusActive, statusSuspended, statusClosed).
+		Allow(statusSuspended, statusActive, statusClosed)
+}
+
+func Test_Machine_Allows_Declared_Transitions(t *testing.T) {
+	m := newAccountMachine()
+
+	assert.True(t, m.Can(statusPending, statusActive))
+	assert.True(t, m.Can(statusSuspended, statusActive))
+	assert.False(t, m.Can(statusActive, statusPending))
+	assert.False(t, m.Can(statusClosed, statusActive))
+	assert.False(t, m.Can(statusActive, statusActive))
+
+	assert.Equal(t, []accountStatus{statusSuspended, statusClosed}, m.Targets(statusActive))
+	assert.Empty(t, m.Targets(statusClosed))
+
+	next, err := m.Transition(context.Background(), statusPending, statusActive)
+	require.NoError(t, err)
+	assert.Equal(t, statusActive, next)
+}
+
+func Test_Machine_Rejects_Illegal_Transitions_With_Conflict(t *testing.T) {
+	m := newAccountMachine()
+	called := false
+
+	next, err := m.Transition(context.Background(), statusClosed, statusActive,
+		func(ctx context.Context, from, to accountStatus) error {
+			called = true
+			return nil
+		})
+
+	assert.Equal(t, statusClosed, next)
+	assert.False(t, called)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.CodeConflict))
+	assert.True(t, stdErrors.Is(err, ErrIllegalTransition))
+
+	appErr, ok := errors.As(err)
+	require.True(t, ok)
+	assert.Equal(t, "cannot transition from closed to active", appErr.Message)
+	assert.Equal(t, statusClosed, appErr.Context["from"])
+	assert.Equal(t, statusActive, appErr.Context["to"])
+}
+
+func Test_Machine_Runs_Exit_Transition_And_Enter_Hooks_In_Order(t *testing.T) {
+	var calls []string
+	record := func(name string) Hook[accountStatus] {
+		return func(ctx context.Context, from, to accountStatus) error {
+			calls = append(calls, name+":"+string(from)+"->"+string(to))
+			return nil
+		}
+	}
+
+	m := newAccountMachine().
+		OnEnter(statusActive, record("enter-active")).
+		OnExit(statusActive, record("exit-active")).
+		OnEnter(statusSuspended, record("enter-suspended")).
+		OnEnter(statusSuspended, record("notify-owner"))
+
+	_, err := m.Transition(context.Background(), statusPending, statusActive, record("save"))
+	require.NoError(t, err)
+	_, err = m.Transition(context.Background(), statusActive, statusSuspended, record("save"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"save:pending->active",
+		"enter-active:pending->active",
+		"exit-active:active->suspended",
+		"save:active->suspended",
+		"enter-suspended:active->suspended",
+		"notify-owner:active->suspended",
+	}, calls)
+}
+
+func Test_Machine_Failing_Hook_Aborts_Transition(t *testing.T) {
+	entered := false
+	m := newAccountMachine().OnEnter(statusClosed, func(ctx context.Context, from, to accountStatus) error {
+		entered = true
+		return nil
+	})
+
+	saveErr := errors.New(errors.CodeDatabaseError, "failed to save status")
+	next, err := m.Transition(context.Background(), statusActive, statusClosed,
+		func(ctx context.Context, from, to accountStatus) error { return saveErr })
+
+	assert.Equal(t, statusActive, next)
+	assert.Same(t, saveErr, err)
+	assert.False(t, entered)
+}
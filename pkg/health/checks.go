@@ -0,0 +1,197 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// CheckError is returned by the built-in checks. Its message is safe to expose in
+// health reports, while the underlying error (which may contain internal hosts or
+// IPs) is kept as the cause for logging via errors.Unwrap.
+type CheckError struct {
+	Message string
+	Cause   error
+}
+
+// Error returns the sanitized message
+func (e *CheckError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the underlying error
+func (e *CheckError) Unwrap() error {
+	return e.Cause
+}
+
+// tcpCheck verifies a TCP connection can be opened
+type tcpCheck struct {
+	name    string
+	address string
+	timeout time.Duration
+}
+
+// TCPCheck creates a check that succeeds when a TCP connection to address
+// (host:port) can be established within timeout.
+//
+// Example:
+//
+//	checker.Register(health.TCPCheck("redis", "redis:6379", time.Second))
+func TCPCheck(name, address string, timeout time.Duration) Check {
+	return &tcpCheck{name: name, address: address, timeout: timeout}
+}
+
+func (c *tcpCheck) Name() string {
+	return c.name
+}
+
+func (c *tcpCheck) Check(ctx context.Context) error {
+	ctx, cancel := withCheckTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return checkFailure(ctx, "tcp connection failed", err)
+	}
+	return conn.Close()
+}
+
+// httpCheck verifies an HTTP endpoint answers with the expected status
+type httpCheck struct {
+	name           string
+	url            string
+	expectedStatus int
+	timeout        time.Duration
+	client         *http.Client
+}
+
+// HTTPCheck creates a check that sends a GET request to url and succeeds when the
+// response status equals expectedStatus (http.StatusOK when 0) within timeout.
+//
+// Example:
+//
+//	checker.Register(health.HTTPCheck("payment-gateway", "https://pay.example.com/ping", http.StatusOK, 2*time.Second))
+func HTTPCheck(name, url string, expectedStatus int, timeout time.Duration) Check {
+	if expectedStatus == 0 {
+		expectedStatus = http.StatusOK
+	}
+	return &httpCheck{
+		name:           name,
+		url:            url,
+		expectedStatus: expectedStatus,
+		timeout:        timeout,
+		client:         &http.Client{},
+	}
+}
+
+func (c *httpCheck) Name() string {
+	return c.name
+}
+
+func (c *httpCheck) Check(ctx context.Context) error {
+	ctx, cancel := withCheckTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return &CheckError{Message: "invalid health check request", Cause: err}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return checkFailure(ctx, "http request failed", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != c.expectedStatus {
+		return &CheckError{
+			Message: fmt.Sprintf("unexpected status %d, expected %d", resp.StatusCode, c.expectedStatus),
+		}
+	}
+	return nil
+}
+
+// withCheckTimeout bounds ctx by timeout when one is configured
+func withCheckTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// checkFailure builds a sanitized CheckError, reporting timeouts explicitly
+func checkFailure(ctx context.Context, message string, cause error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		message = message + ": timed out"
+	}
+	return &CheckError{Message: message, Cause: cause}
+}
+
+// slowCheck degrades a check that answers slower than a threshold
+type slowCheck struct {
+	check     Check
+	threshold time.Duration
+}
+
+// DegradeWhenSlow wraps check so that it reports StatusDegraded when it succeeds but
+// takes longer than threshold, giving early warning before the dependency times out.
+//
+// Example:
+//
+//	checker.Register(health.DegradeWhenSlow(health.NewCheck("postgres", pgClient.Health), 200*time.Millisecond))
+func DegradeWhenSlow(check Check, threshold time.Duration) Check {
+	return &slowCheck{check: check, threshold: threshold}
+}
+
+func (c *slowCheck) Name() string {
+	return c.check.Name()
+}
+
+func (c *slowCheck) Unwrap() Check {
+	return c.check
+}
+
+func (c *slowCheck) Check(ctx context.Context) error {
+	start := time.Now()
+	if err := c.check.Check(ctx); err != nil {
+		return err
+	}
+
+	if latency := time.Since(start); latency > c.threshold {
+		return Degrade(&CheckError{
+			Message: fmt.Sprintf("latency %s above %s", latency.Round(time.Millisecond), c.threshold),
+		})
+	}
+	return nil
+}
+
+// PoolSaturationCheck creates a check that reports StatusDegraded when the share of
+// connections in use reaches threshold (0 to 1). stats returns the connections in use
+// and the pool size; a size of 0 means unlimited and is never saturated.
+//
+// Example:
+//
+//	sqlDB, _ := pgClient.DB().DB()
+//	checker.Register(health.PoolSaturationCheck("postgres-pool", func() (int, int) {
+//	    stats := sqlDB.Stats()
+//	    return stats.InUse, stats.MaxOpenConnections
+//	}, 0.9))
+func PoolSaturationCheck(name string, stats func() (inUse, size int), threshold float64) Check {
+	return NewCheck(name, func(context.Context) error {
+		inUse, size := stats()
+		if size <= 0 {
+			return nil
+		}
+
+		if saturation := float64(inUse) / float64(size); saturation >= threshold {
+			return Degrade(&CheckError{
+				Message: fmt.Sprintf("connection pool %d/%d in use", inUse, size),
+			})
+		}
+		return nil
+	})
+}
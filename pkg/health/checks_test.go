@@ -0,0 +1,103 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TCPCheck_Healthy_When_Listener_Accepts(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	result := Run(context.Background(), TCPCheck("tcp", listener.Addr().String(), time.Second), 0)
+
+	assert.Equal(t, "tcp", result.Name)
+	assert.Equal(t, StatusHealthy, result.Status)
+	assert.Empty(t, result.Error)
+	assert.Positive(t, result.Latency)
+}
+
+func Test_TCPCheck_Reports_Sanitized_Error(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	err = TCPCheck("tcp", address, time.Second).Check(context.Background())
+	require.Error(t, err)
+
+	assert.Equal(t, "tcp connection failed", err.Error())
+	assert.NotContains(t, err.Error(), "127.0.0.1")
+
+	var checkErr *CheckError
+	require.True(t, errors.As(err, &checkErr))
+	assert.Contains(t, checkErr.Cause.Error(), address)
+}
+
+func Test_HTTPCheck_Healthy_On_Expected_Status(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	result := Run(context.Background(), HTTPCheck("api", server.URL, http.StatusNoContent, time.Second), 0)
+
+	assert.Equal(t, StatusHealthy, result.Status)
+	assert.Empty(t, result.Error)
+}
+
+func Test_HTTPCheck_Unhealthy_On_Unexpected_Status(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	result := Run(context.Background(), HTTPCheck("api", server.URL, 0, time.Second), 0)
+
+	assert.Equal(t, StatusUnhealthy, result.Status)
+	assert.Equal(t, "unexpected status 503, expected 200", result.Error)
+}
+
+func Test_HTTPCheck_Enforces_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	start := time.Now()
+	result := Run(context.Background(), HTTPCheck("slow", server.URL, http.StatusOK, 50*time.Millisecond), 0)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, StatusUnhealthy, result.Status)
+	assert.Equal(t, "http request failed: timed out", result.Error)
+	assert.NotContains(t, result.Error, server.URL)
+}
+
+func Test_Checker_Aggregates_Results(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Register(
+		NewCheck("ok", func(ctx context.Context) error { return nil }),
+		NewCheck("down", func(ctx context.Context) error { return errors.New("down") }),
+	)
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusUnhealthy, report.Status)
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, StatusHealthy, report.Checks[0].Status)
+	assert.Equal(t, "down", report.Checks[1].Error)
+}
@@ -0,0 +1,252 @@
+// Package health provides health checks for application dependencies
+// (databases, caches, downstream HTTP services, ...).
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Status is the outcome of a health check
+type Status string
+
+const (
+	StatusHealthy   Status = "healthy"
+	StatusDegraded  Status = "degraded" // Working, but close to failing (saturated, slow, ...)
+	StatusUnhealthy Status = "unhealthy"
+)
+
+// DefaultTimeout bounds a check when no timeout is configured
+const DefaultTimeout = 5 * time.Second
+
+// Check is a single named health check
+type Check interface {
+	// Name identifies the check in reports, e.g. "postgres" or "payment-gateway"
+	Name() string
+	// Check returns nil when the dependency is healthy, or an error wrapped with Degrade
+	// when it works but needs attention. It must honor ctx cancellation.
+	Check(ctx context.Context) error
+}
+
+// DegradedError marks a check as degraded rather than unhealthy
+type DegradedError struct {
+	Err error
+}
+
+// Error returns the message of the wrapped error
+func (e *DegradedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *DegradedError) Unwrap() error {
+	return e.Err
+}
+
+// Degrade wraps err so that the check reports StatusDegraded instead of
+// StatusUnhealthy. It returns nil when err is nil.
+//
+// Example:
+//
+//	if stats.InUse > stats.MaxOpenConnections*9/10 {
+//	    return health.Degrade(errors.New("connection pool above 90%"))
+//	}
+func Degrade(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &DegradedError{Err: err}
+}
+
+// IsDegraded reports whether err was wrapped with Degrade
+func IsDegraded(err error) bool {
+	var degraded *DegradedError
+	return errors.As(err, &degraded)
+}
+
+// CheckFunc adapts a function to the Check interface
+type CheckFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewCheck creates a Check from a function.
+//
+// Example:
+//
+//	check := health.NewCheck("postgres", pgClient.Health)
+func NewCheck(name string, fn func(ctx context.Context) error) Check {
+	return &CheckFunc{name: name, fn: fn}
+}
+
+// Name returns the check name
+func (c *CheckFunc) Name() string {
+	return c.name
+}
+
+// Check runs the check function
+func (c *CheckFunc) Check(ctx context.Context) error {
+	return c.fn(ctx)
+}
+
+// optionalCheck marks a check whose failure degrades the report instead of failing it
+type optionalCheck struct {
+	check Check
+}
+
+// Optional marks check as not required: when it is unhealthy the report is degraded
+// rather than unhealthy, so readiness doesn't fail because of a non-critical dependency.
+//
+// Example:
+//
+//	checker.Register(health.Optional(health.HTTPCheck("recommendations", url, 0, time.Second)))
+func Optional(check Check) Check {
+	return &optionalCheck{check: check}
+}
+
+func (c *optionalCheck) Name() string {
+	return c.check.Name()
+}
+
+func (c *optionalCheck) Check(ctx context.Context) error {
+	return c.check.Check(ctx)
+}
+
+func (c *optionalCheck) Unwrap() Check {
+	return c.check
+}
+
+// IsOptional reports whether check was marked with Optional, including beneath other
+// wrappers such as DegradeWhenSlow. Wrappers expose the check they wrap with an
+// Unwrap() Check method.
+func IsOptional(check Check) bool {
+	for check != nil {
+		if _, ok := check.(*optionalCheck); ok {
+			return true
+		}
+		wrapper, ok := check.(interface{ Unwrap() Check })
+		if !ok {
+			return false
+		}
+		check = wrapper.Unwrap()
+	}
+	return false
+}
+
+// Result is the outcome of running a single check
+type Result struct {
+	Name      string        `json:"name"`
+	Status    Status        `json:"status"`
+	Optional  bool          `json:"optional,omitempty"`
+	Latency   time.Duration `json:"latency"`
+	Error     string        `json:"error,omitempty"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// Run executes check with the given timeout, measuring its latency and capturing its
+// error. Errors wrapped with Degrade give StatusDegraded, other errors StatusUnhealthy.
+func Run(ctx context.Context, check Check, timeout time.Duration) Result {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+
+	result := Result{
+		Name:      check.Name(),
+		Status:    StatusHealthy,
+		Optional:  IsOptional(check),
+		Latency:   time.Since(start),
+		CheckedAt: start.UTC(),
+	}
+	if err != nil {
+		result.Status = StatusUnhealthy
+		if IsDegraded(err) {
+			result.Status = StatusDegraded
+		}
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Report aggregates the results of several checks
+type Report struct {
+	Status   Status   `json:"status"`
+	Checks   []Result `json:"checks"`
+	Warnings []string `json:"warnings,omitempty"` // One per degraded or failed optional check
+}
+
+// HTTPStatus returns the status code a health endpoint should answer with:
+// http.StatusServiceUnavailable when unhealthy, http.StatusOK when healthy or degraded.
+func (r Report) HTTPStatus() int {
+	if r.Status == StatusUnhealthy {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// Checker runs a set of registered checks concurrently
+type Checker struct {
+	mu      sync.RWMutex
+	checks  []Check
+	timeout time.Duration
+}
+
+// NewChecker creates a checker that bounds each check by timeout
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Register adds checks to the checker
+func (c *Checker) Register(checks ...Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, checks...)
+}
+
+// Run executes all checks concurrently. The report is unhealthy if any required check
+// is unhealthy, and degraded if any check is degraded or an optional check is unhealthy.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make([]Check, len(c.checks))
+	copy(checks, c.checks)
+	c.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = Run(ctx, check, c.timeout)
+		}(i, check)
+	}
+	wg.Wait()
+
+	return aggregate(results)
+}
+
+// aggregate combines check results into a report
+func aggregate(results []Result) Report {
+	report := Report{Status: StatusHealthy, Checks: results}
+	for _, result := range results {
+		switch {
+		case result.Status == StatusHealthy:
+			continue
+		case result.Status == StatusUnhealthy && !result.Optional:
+			report.Status = StatusUnhealthy
+			continue
+		case report.Status == StatusHealthy:
+			report.Status = StatusDegraded
+		}
+		report.Warnings = append(report.Warnings, result.Name+": "+result.Error)
+	}
+	return report
+}
@@ -0,0 +1,151 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCheck returns whatever error it was last set to
+type fakeCheck struct {
+	name string
+	mu   sync.Mutex
+	err  error
+}
+
+func (c *fakeCheck) Name() string {
+	return c.name
+}
+
+func (c *fakeCheck) Check(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *fakeCheck) set(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+func Test_Checker_State_Transitions(t *testing.T) {
+	db := &fakeCheck{name: "postgres"}
+	checker := NewChecker(time.Second)
+	checker.Register(db, NewCheck("config", func(context.Context) error { return nil }))
+
+	steps := []struct {
+		name     string
+		err      error
+		status   Status
+		code     int
+		warnings []string
+	}{
+		{name: "healthy", status: StatusHealthy, code: http.StatusOK},
+		{name: "degraded", err: Degrade(errors.New("pool saturated")), status: StatusDegraded, code: http.StatusOK, warnings: []string{"postgres: pool saturated"}},
+		{name: "unhealthy", err: errors.New("connection refused"), status: StatusUnhealthy, code: http.StatusServiceUnavailable},
+		{name: "degraded again", err: Degrade(errors.New("slow")), status: StatusDegraded, code: http.StatusOK, warnings: []string{"postgres: slow"}},
+		{name: "recovered", status: StatusHealthy, code: http.StatusOK},
+	}
+
+	for _, step := range steps {
+		db.set(step.err)
+		report := checker.Run(context.Background())
+
+		assert.Equal(t, step.status, report.Status, step.name)
+		assert.Equal(t, step.status, report.Checks[0].Status, step.name)
+		assert.Equal(t, StatusHealthy, report.Checks[1].Status, step.name)
+		assert.Equal(t, step.code, report.HTTPStatus(), step.name)
+		assert.Equal(t, step.warnings, report.Warnings, step.name)
+	}
+}
+
+func Test_Checker_Optional_Check_Degrades_Report(t *testing.T) {
+	cache := &fakeCheck{name: "recommendations", err: errors.New("down")}
+	checker := NewChecker(time.Second)
+	checker.Register(NewCheck("postgres", func(context.Context) error { return nil }), Optional(cache))
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, http.StatusOK, report.HTTPStatus())
+	assert.Equal(t, StatusUnhealthy, report.Checks[1].Status)
+	assert.True(t, report.Checks[1].Optional)
+	assert.Equal(t, []string{"recommendations: down"}, report.Warnings)
+}
+
+func Test_Checker_Optional_Check_Stays_Optional_When_Wrapped(t *testing.T) {
+	cache := &fakeCheck{name: "recommendations", err: errors.New("down")}
+	checker := NewChecker(time.Second)
+	checker.Register(DegradeWhenSlow(Optional(cache), time.Second))
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.True(t, report.Checks[0].Optional)
+	assert.True(t, IsOptional(Optional(DegradeWhenSlow(cache, time.Second))))
+	assert.False(t, IsOptional(DegradeWhenSlow(cache, time.Second)))
+}
+
+func Test_Checker_Unhealthy_Wins_Over_Degraded(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Register(
+		&fakeCheck{name: "redis", err: Degrade(errors.New("slow"))},
+		&fakeCheck{name: "postgres", err: errors.New("down")},
+	)
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusUnhealthy, report.Status)
+	assert.Equal(t, []string{"redis: slow"}, report.Warnings)
+}
+
+func Test_Degrade(t *testing.T) {
+	assert.NoError(t, Degrade(nil))
+
+	cause := errors.New("slow")
+	err := Degrade(cause)
+	assert.True(t, IsDegraded(err))
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "slow", err.Error())
+	assert.False(t, IsDegraded(cause))
+}
+
+func Test_DegradeWhenSlow(t *testing.T) {
+	slow := DegradeWhenSlow(NewCheck("postgres", func(context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}), 5*time.Millisecond)
+
+	result := Run(context.Background(), slow, time.Second)
+	assert.Equal(t, "postgres", result.Name)
+	assert.Equal(t, StatusDegraded, result.Status)
+	assert.Contains(t, result.Error, "above 5ms")
+
+	fast := DegradeWhenSlow(NewCheck("postgres", func(context.Context) error { return nil }), time.Second)
+	assert.Equal(t, StatusHealthy, Run(context.Background(), fast, time.Second).Status)
+
+	failing := DegradeWhenSlow(NewCheck("postgres", func(context.Context) error { return errors.New("down") }), time.Second)
+	assert.Equal(t, StatusUnhealthy, Run(context.Background(), failing, time.Second).Status)
+}
+
+func Test_PoolSaturationCheck(t *testing.T) {
+	inUse, size := 5, 10
+	check := PoolSaturationCheck("pool", func() (int, int) { return inUse, size }, 0.9)
+
+	require.NoError(t, check.Check(context.Background()))
+
+	inUse = 9
+	err := check.Check(context.Background())
+	assert.True(t, IsDegraded(err))
+	assert.Equal(t, "connection pool 9/10 in use", err.Error())
+
+	size = 0
+	assert.NoError(t, check.Check(context.Background()))
+}
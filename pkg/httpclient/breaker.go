@@ -0,0 +1,207 @@
+package httpclient
+
+import (
+	stdErrors "errors"
+	"sync"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// ErrCircuitOpen is the cause of the CodeServiceUnavailable errors returned while a
+// circuit breaker is open. Check for it with the standard library's errors.Is.
+var ErrCircuitOpen = stdErrors.New("circuit breaker is open")
+
+// BreakerState is the state of a CircuitBreaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects every request until the cooldown has elapsed
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe through to test the dependency
+	BreakerHalfOpen
+)
+
+// String returns the state name, e.g. for a metric label
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig configures a CircuitBreaker
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker
+	FailureThreshold int
+
+	// Cooldown is how long the breaker stays open before letting a probe through
+	Cooldown time.Duration
+
+	// IsFailure reports whether a request's error counts as a failure of the
+	// dependency. Defaults to errors.IsRetryable, so 4xx responses do not count.
+	IsFailure func(err error) bool
+
+	// OnStateChange, if set, is called on every transition, e.g. to update a metric.
+	// It must not call back into the breaker.
+	OnStateChange func(name string, from, to BreakerState)
+}
+
+// DefaultBreakerConfig opens after 5 consecutive failures and probes after 30s
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold: 5,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+// CircuitBreaker stops calling a failing dependency. After FailureThreshold
+// consecutive failures it opens and rejects requests with CodeServiceUnavailable;
+// once Cooldown has elapsed it half-opens and lets one probe through, closing again
+// if the probe succeeds and re-opening if it fails. It is safe for concurrent use.
+type CircuitBreaker struct {
+	name   string
+	config BreakerConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker creates a closed breaker. Zero fields of cfg take the defaults of
+// DefaultBreakerConfig; name identifies the breaker in errors and OnStateChange.
+//
+// Example:
+//
+//	breaker := httpclient.NewCircuitBreaker("payments", httpclient.BreakerConfig{FailureThreshold: 3})
+//	if err := breaker.Allow(); err != nil {
+//	    return err
+//	}
+//	err := callPayments(ctx)
+//	breaker.Record(err)
+func NewCircuitBreaker(name string, cfg BreakerConfig) *CircuitBreaker {
+	defaults := DefaultBreakerConfig()
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaults.FailureThreshold
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = defaults.Cooldown
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = errors.IsRetryable
+	}
+
+	return &CircuitBreaker{name: name, config: cfg, now: time.Now}
+}
+
+// State returns the breaker's current state
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Allow returns nil if a request may be sent, and a CodeServiceUnavailable error
+// caused by ErrCircuitOpen otherwise. Every allowed request must be followed by
+// Record.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.config.Cooldown {
+		b.setState(BreakerHalfOpen)
+	}
+
+	switch {
+	case b.state == BreakerClosed:
+		return nil
+	case b.state == BreakerHalfOpen && !b.probing:
+		b.probing = true
+		return nil
+	default:
+		return errors.Wrap(ErrCircuitOpen, errors.CodeServiceUnavailable).
+			WithContext("circuit_breaker", b.name)
+	}
+}
+
+// Record records the outcome of a request let through by Allow
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if err == nil || !b.config.IsFailure(err) {
+		b.failures = 0
+		if b.state != BreakerClosed {
+			b.setState(BreakerClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.openedAt = b.now()
+		if b.state != BreakerOpen {
+			b.setState(BreakerOpen)
+		}
+	}
+}
+
+// release gives up a request let through by Allow without recording an outcome,
+// e.g. when the caller canceled it
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *CircuitBreaker) setState(state BreakerState) {
+	from := b.state
+	b.state = state
+	if b.config.OnStateChange != nil {
+		b.config.OnStateChange(b.name, from, state)
+	}
+}
+
+// breakers holds one CircuitBreaker per host
+type breakers struct {
+	config BreakerConfig
+
+	mu    sync.Mutex
+	hosts map[string]*CircuitBreaker
+}
+
+func (b *breakers) get(host string) *CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breaker, ok := b.hosts[host]
+	if !ok {
+		breaker = NewCircuitBreaker(host, b.config)
+		b.hosts[host] = breaker
+	}
+	return breaker
+}
+
+func (b *breakers) states() map[string]BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	states := make(map[string]BreakerState, len(b.hosts))
+	for host, breaker := range b.hosts {
+		states[host] = breaker.State()
+	}
+	return states
+}
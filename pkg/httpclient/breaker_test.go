@@ -0,0 +1,161 @@
+package httpclient
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestBreaker(cfg BreakerConfig) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	breaker := NewCircuitBreaker("test", cfg)
+	breaker.now = clock.Now
+	return breaker, clock
+}
+
+func Test_CircuitBreaker_Opens_After_Consecutive_Failures(t *testing.T) {
+	breaker, _ := newTestBreaker(BreakerConfig{FailureThreshold: 3, Cooldown: time.Minute})
+	failure := errors.New(errors.CodeServiceUnavailable)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, breaker.Allow())
+		breaker.Record(failure)
+	}
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	require.NoError(t, breaker.Allow())
+	breaker.Record(failure)
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	err := breaker.Allow()
+	assert.True(t, errors.Is(err, errors.CodeServiceUnavailable))
+	assert.True(t, stdErrors.Is(err, ErrCircuitOpen))
+}
+
+func Test_CircuitBreaker_Success_Resets_Failures(t *testing.T) {
+	breaker, _ := newTestBreaker(BreakerConfig{FailureThreshold: 2})
+	failure := errors.New(errors.CodeServiceUnavailable)
+
+	breaker.Record(failure)
+	breaker.Record(nil)
+	breaker.Record(failure)
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	// client errors mean the dependency is up
+	breaker.Record(errors.NotFound("Order"))
+	breaker.Record(errors.NotFound("Order"))
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
+
+func Test_CircuitBreaker_Half_Opens_After_Cooldown(t *testing.T) {
+	var transitions []string
+	breaker, clock := newTestBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		Cooldown:         time.Minute,
+		OnStateChange: func(_ string, from, to BreakerState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+	failure := errors.New(errors.CodeServiceUnavailable)
+
+	require.NoError(t, breaker.Allow())
+	breaker.Record(failure)
+	assert.Error(t, breaker.Allow())
+
+	clock.Advance(time.Minute)
+	require.NoError(t, breaker.Allow(), "probe is let through")
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	assert.Error(t, breaker.Allow(), "only one probe at a time")
+
+	// failed probe re-opens for another cooldown
+	breaker.Record(failure)
+	assert.Equal(t, BreakerOpen, breaker.State())
+	clock.Advance(30 * time.Second)
+	assert.Error(t, breaker.Allow())
+
+	clock.Advance(30 * time.Second)
+	require.NoError(t, breaker.Allow())
+	breaker.Record(nil)
+	assert.Equal(t, BreakerClosed, breaker.State())
+	assert.NoError(t, breaker.Allow())
+
+	assert.Equal(t, []string{
+		"closed->open", "open->half_open", "half_open->open", "open->half_open", "half_open->closed",
+	}, transitions)
+}
+
+func Test_Client_Breaker_Trips_And_Fails_Fast(t *testing.T) {
+	server, calls := newFlakyServer(t, 100, http.StatusServiceUnavailable, nil)
+	client := newTestClient(Config{
+		Retry:   utils.BackoffConfig{MaxAttempts: 5, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond},
+		Breaker: &BreakerConfig{FailureThreshold: 3, Cooldown: time.Hour},
+	})
+
+	_, err := client.Get(context.Background(), server.URL)
+	assert.True(t, stdErrors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, int32(3), calls.Load(), "retries stop once the breaker opens")
+
+	_, err = client.Get(context.Background(), server.URL)
+	assert.True(t, errors.Is(err, errors.CodeServiceUnavailable))
+	assert.Equal(t, int32(3), calls.Load(), "open breaker fails fast")
+
+	host, _ := url.Parse(server.URL)
+	assert.Equal(t, map[string]BreakerState{host.Host: BreakerOpen}, client.BreakerStates())
+}
+
+func Test_Client_Breaker_Half_Opens_After_Cooldown(t *testing.T) {
+	server, calls := newFlakyServer(t, 2, http.StatusServiceUnavailable, nil)
+	client := newTestClient(Config{
+		Retry:   utils.BackoffConfig{MaxAttempts: 1},
+		Breaker: &BreakerConfig{FailureThreshold: 2, Cooldown: 50 * time.Millisecond},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Get(ctx, server.URL)
+		assert.Error(t, err)
+	}
+	_, err := client.Get(ctx, server.URL)
+	assert.True(t, stdErrors.Is(err, ErrCircuitOpen))
+
+	time.Sleep(60 * time.Millisecond)
+
+	resp, err := client.Get(ctx, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", readBody(t, resp))
+	assert.Equal(t, int32(3), calls.Load())
+
+	host, _ := url.Parse(server.URL)
+	assert.Equal(t, BreakerClosed, client.BreakerStates()[host.Host])
+}
+
+func Test_Client_Without_Breaker_Has_No_States(t *testing.T) {
+	assert.Empty(t, New(Config{}).BreakerStates())
+}
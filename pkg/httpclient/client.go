@@ -0,0 +1,296 @@
+// Package httpclient is a resilient net/http client for calling external services.
+// Failed attempts are retried with exponential backoff (see utils.RetryWithBackoff)
+// when errors.IsRetryable allows it, so 5xx, 429 and timeouts retry while other 4xx
+// responses fail immediately. Retry-After headers are honored, every attempt has its
+// own timeout, and error responses are returned as *errors.AppError built with
+// errors.FromHTTPStatus. An optional circuit breaker per host stops calling a
+// dependency that keeps failing.
+//
+// A Client is safe for concurrent use and should be shared.
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	stdErrors "errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/servertiming"
+	"github.com/phatnt199/go-infra/pkg/utils"
+)
+
+// RetryAfterContextKey is the AppError context key holding the time.Duration a
+// response's Retry-After header asked for
+const RetryAfterContextKey = "retry_after"
+
+// Config configures a Client
+type Config struct {
+	// Timeout bounds every attempt, including reading the response headers. The
+	// request's context bounds the call as a whole, retries included.
+	Timeout time.Duration
+
+	// Retry configures the backoff between attempts. ShouldRetry defaults to
+	// errors.IsRetryable and RetryAfter to the response's Retry-After header.
+	Retry utils.BackoffConfig
+
+	// Transport sends the requests, defaulting to http.DefaultTransport
+	Transport http.RoundTripper
+
+	// MaxErrorBodySize limits how many bytes of an error response body are kept in
+	// the AppError details
+	MaxErrorBodySize int64
+
+	// Breaker, if set, enables a circuit breaker per host. Requests to a host whose
+	// breaker is open fail fast with CodeServiceUnavailable and are not retried.
+	Breaker *BreakerConfig
+}
+
+// DefaultConfig returns a 10s per-attempt timeout with utils.DefaultBackoffConfig
+func DefaultConfig() Config {
+	return Config{
+		Timeout:          10 * time.Second,
+		Retry:            utils.DefaultBackoffConfig(),
+		MaxErrorBodySize: 4 << 10,
+	}
+}
+
+// Client sends HTTP requests with retries
+type Client struct {
+	config   Config
+	client   *http.Client
+	breakers *breakers
+}
+
+// New creates a Client. Zero fields of cfg take the defaults of DefaultConfig.
+//
+// Example:
+//
+//	client := httpclient.New(httpclient.Config{
+//	    Timeout: 5 * time.Second,
+//	    Retry:   utils.BackoffConfig{MaxAttempts: 4},
+//	})
+//	resp, err := client.Get(ctx, "https://api.example.com/orders/42")
+//	if err != nil {
+//	    return err // *errors.AppError, e.g. CodeNotFound for a 404
+//	}
+//	defer resp.Body.Close()
+func New(cfg Config) *Client {
+	defaults := DefaultConfig()
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+	if cfg.MaxErrorBodySize <= 0 {
+		cfg.MaxErrorBodySize = defaults.MaxErrorBodySize
+	}
+	if cfg.Retry.RetryAfter == nil {
+		cfg.Retry.RetryAfter = retryAfterFromError
+	}
+	if cfg.Transport == nil {
+		cfg.Transport = http.DefaultTransport
+	}
+
+	c := &Client{
+		config: cfg,
+		client: &http.Client{Transport: cfg.Transport},
+	}
+
+	if cfg.Breaker != nil {
+		c.breakers = &breakers{config: *cfg.Breaker, hosts: map[string]*CircuitBreaker{}}
+
+		shouldRetry := cfg.Retry.ShouldRetry
+		if shouldRetry == nil {
+			shouldRetry = errors.IsRetryable
+		}
+		c.config.Retry.ShouldRetry = func(err error) bool {
+			return !stdErrors.Is(err, ErrCircuitOpen) && shouldRetry(err)
+		}
+	}
+
+	return c
+}
+
+// BreakerStates returns the state of every host's circuit breaker, e.g. to export
+// as a metric. It is empty when Config.Breaker is not set.
+func (c *Client) BreakerStates() map[string]BreakerState {
+	if c.breakers == nil {
+		return map[string]BreakerState{}
+	}
+	return c.breakers.states()
+}
+
+// Do sends req, retrying failed attempts. Responses with status 400 or above are
+// returned as an *errors.AppError (and no response); other responses must have their
+// body closed by the caller.
+//
+// Requests with a body are only retried when req.GetBody is set, which
+// http.NewRequest does for bytes, strings and bytes.Buffer readers. Non-idempotent
+// requests are retried like any other, so only send them through a retrying client
+// when the server deduplicates them (e.g. with an idempotency key).
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	// Retries and backoff are part of the time spent calling the service
+	defer servertiming.Start(req.Context(), "external").Stop()
+
+	retry := c.config.Retry
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		retry.MaxAttempts = 1
+	}
+
+	var resp *http.Response
+	err := utils.RetryWithBackoff(req.Context(), retry, func(ctx context.Context) error {
+		attemptResp, err := c.attempt(ctx, req)
+		if err != nil {
+			return err
+		}
+		resp = attemptResp
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Get sends a GET request to url
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInvalidInput, "invalid request")
+	}
+	return c.Do(req)
+}
+
+// Post sends a POST request with body to url
+func (c *Client) Post(ctx context.Context, url, contentType string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInvalidInput, "invalid request")
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
+
+// attempt sends one attempt of req through the host's circuit breaker, if enabled
+func (c *Client) attempt(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if c.breakers == nil {
+		return c.send(ctx, req)
+	}
+
+	breaker := c.breakers.get(req.URL.Host)
+	if err := breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.send(ctx, req)
+	if err != nil && ctx.Err() != nil {
+		// the caller gave up, which says nothing about the dependency
+		breaker.release()
+	} else {
+		breaker.Record(err)
+	}
+
+	return resp, err
+}
+
+// send sends one attempt of req with its own timeout
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+
+	attemptReq := req.Clone(attemptCtx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, errors.Wrap(err, errors.CodeInternal, "failed to rewind request body")
+		}
+		attemptReq.Body = body
+	}
+
+	resp, err := c.client.Do(attemptReq)
+	if err != nil {
+		cancel()
+		code := errors.CodeExternalService
+		if attemptCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+			code = errors.CodeTimeout
+		}
+		return nil, errors.Wrap(err, code).
+			WithContext("method", req.Method).
+			WithContext("url", req.URL.Redacted())
+	}
+
+	if resp.StatusCode < http.StatusBadRequest {
+		// the attempt's context must outlive Do until the caller has read the body
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		return resp, nil
+	}
+
+	defer cancel()
+	return nil, c.responseError(req, resp)
+}
+
+// responseError converts an error response into an AppError and closes its body
+func (c *Client) responseError(req *http.Request, resp *http.Response) *errors.AppError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, c.config.MaxErrorBodySize))
+	_ = resp.Body.Close()
+
+	appErr := errors.FromHTTPStatus(resp.StatusCode).
+		WithContext("method", req.Method).
+		WithContext("url", req.URL.Redacted()).
+		WithContext("status", resp.StatusCode)
+	if len(body) > 0 {
+		appErr.WithDetails(strings.TrimSpace(string(body)))
+	}
+	if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		appErr.WithContext(RetryAfterContextKey, delay)
+	}
+
+	return appErr
+}
+
+// parseRetryAfter parses a Retry-After header, either delay seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// retryAfterFromError returns the Retry-After delay stored by responseError
+func retryAfterFromError(err error) (time.Duration, bool) {
+	appErr, ok := errors.As(err)
+	if !ok {
+		return 0, false
+	}
+	delay, ok := appErr.Context[RetryAfterContextKey].(time.Duration)
+	return delay, ok
+}
+
+// cancelOnClose releases the attempt's context when the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
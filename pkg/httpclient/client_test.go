@@ -0,0 +1,234 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/servertiming"
+	"github.com/phatnt199/go-infra/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer fails the first failures requests with status, then answers 200 "ok"
+func newFlakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			http.Error(w, "try again", status)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newTestClient(cfg Config) *Client {
+	if cfg.Retry.MaxAttempts == 0 {
+		cfg.Retry = utils.BackoffConfig{MaxAttempts: 4, InitialDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	}
+	return New(cfg)
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func Test_Client_Retries_Server_Errors(t *testing.T) {
+	server, calls := newFlakyServer(t, 2, http.StatusServiceUnavailable, nil)
+
+	resp, err := newTestClient(Config{}).Get(context.Background(), server.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ok", readBody(t, resp))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func Test_Client_Returns_AppError_After_Max_Attempts(t *testing.T) {
+	server, calls := newFlakyServer(t, 10, http.StatusBadGateway, nil)
+
+	resp, err := newTestClient(Config{}).Get(context.Background(), server.URL)
+	assert.Nil(t, resp)
+
+	appErr, ok := errors.As(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeExternalService, appErr.Code)
+	assert.Equal(t, http.StatusBadGateway, appErr.GetHTTPStatus())
+	assert.Equal(t, "try again", appErr.Details)
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func Test_Client_Does_Not_Retry_Client_Errors(t *testing.T) {
+	server, calls := newFlakyServer(t, 10, http.StatusNotFound, nil)
+
+	_, err := newTestClient(Config{}).Get(context.Background(), server.URL)
+
+	assert.True(t, errors.Is(err, errors.CodeNotFound))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func Test_Client_Respects_Retry_After(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+	client := newTestClient(Config{Retry: utils.BackoffConfig{MaxAttempts: 2, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Second}})
+
+	start := time.Now()
+	resp, err := client.Get(context.Background(), server.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ok", readBody(t, resp))
+	assert.Equal(t, int32(2), calls.Load())
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func Test_Client_Per_Attempt_Timeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := newTestClient(Config{Timeout: 50 * time.Millisecond}).Get(context.Background(), server.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ok", readBody(t, resp))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func Test_Client_Timeout_Error_Code(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	client := newTestClient(Config{Timeout: 20 * time.Millisecond, Retry: utils.BackoffConfig{MaxAttempts: 1}})
+	_, err := client.Get(context.Background(), server.URL)
+
+	assert.True(t, errors.Is(err, errors.CodeTimeout))
+}
+
+func Test_Client_Replays_Request_Body(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"id":1}`, string(body))
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := newTestClient(Config{}).Post(context.Background(), server.URL, "application/json", []byte(`{"id":1}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func Test_Client_Does_Not_Retry_Unreplayable_Body(t *testing.T) {
+	server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable, nil)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, io.NopCloser(strings.NewReader("payload")))
+	require.NoError(t, err)
+
+	_, err = newTestClient(Config{}).Do(req)
+
+	assert.True(t, errors.Is(err, errors.CodeServiceUnavailable))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+type countingTransport struct {
+	calls atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func Test_Client_Uses_Custom_Transport(t *testing.T) {
+	server, _ := newFlakyServer(t, 0, 0, nil)
+	transport := &countingTransport{}
+
+	resp, err := newTestClient(Config{Transport: transport}).Get(context.Background(), server.URL)
+	require.NoError(t, err)
+	readBody(t, resp)
+
+	assert.Equal(t, int32(1), transport.calls.Load())
+}
+
+func Test_Client_Stops_When_Context_Canceled(t *testing.T) {
+	server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable, nil)
+	client := newTestClient(Config{Retry: utils.BackoffConfig{MaxAttempts: 10, InitialDelay: time.Hour, MaxDelay: time.Hour}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Get(ctx, server.URL)
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func Test_Client_Records_Calls_In_Server_Timing(t *testing.T) {
+	server, _ := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil)
+	timing := servertiming.New()
+	ctx := servertiming.NewContext(context.Background(), timing)
+
+	resp, err := newTestClient(Config{}).Get(ctx, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", readBody(t, resp))
+
+	// One call, retries included
+	metrics := timing.Metrics()
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "external", metrics[0].Name)
+	assert.Equal(t, 1, metrics[0].Count)
+}
+
+func Test_ParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	delay, ok := parseRetryAfter("3", now)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+
+	delay, ok = parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, delay)
+
+	delay, ok = parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Zero(t, delay)
+
+	for _, value := range []string{"", "-1", "soon"} {
+		_, ok = parseRetryAfter(value, now)
+		assert.False(t, ok, value)
+	}
+}
@@ -0,0 +1,281 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/infra/postgres"
+	"github.com/phatnt199/go-infra/pkg/logger"
+	defaultLogger "github.com/phatnt199/go-infra/pkg/logger/default_logger"
+)
+
+// Client represents a MySQL database client
+type Client struct {
+	db     *gorm.DB
+	logger logger.Logger
+}
+
+// Config holds MySQL client configuration
+type Config struct {
+	DSN             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	LogLevel        gormlogger.LogLevel
+	SlowThreshold   time.Duration
+}
+
+// DatabaseConfig represents database configuration (simplified version matching the existing config structure)
+type DatabaseConfig struct {
+	Driver          string        `mapstructure:"driver" json:"driver"`
+	Host            string        `mapstructure:"host" json:"host"`
+	Port            int           `mapstructure:"port" json:"port"`
+	User            string        `mapstructure:"user" json:"user"`
+	Password        string        `mapstructure:"password" json:"password"`
+	DBName          string        `mapstructure:"dbName" json:"dbName"`
+	MaxOpenConns    int           `mapstructure:"maxOpenConns" json:"maxOpenConns"`
+	MaxIdleConns    int           `mapstructure:"maxIdleConns" json:"maxIdleConns"`
+	ConnMaxLifetime time.Duration `mapstructure:"connMaxLifetime" json:"connMaxLifetime"`
+	ConnMaxIdleTime time.Duration `mapstructure:"connMaxIdleTime" json:"connMaxIdleTime"`
+	SlowThreshold   time.Duration `mapstructure:"slowThreshold" json:"slowThreshold"`
+}
+
+// DSN returns the MySQL DSN connection string. Times are parsed into time.Time in UTC
+// and the connection uses utf8mb4.
+func (c *DatabaseConfig) DSN() string {
+	cfg := mysqldriver.NewConfig()
+	cfg.User = c.User
+	cfg.Passwd = c.Password
+	cfg.Net = "tcp"
+	cfg.Addr = fmt.Sprintf("%s:%d", c.Host, c.Port)
+	cfg.DBName = c.DBName
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	cfg.Params = map[string]string{"charset": "utf8mb4"}
+	return cfg.FormatDSN()
+}
+
+// New creates a new MySQL client with the given configuration
+func New(cfg *Config, log logger.Logger) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.BadRequest("mysql configuration is required")
+	}
+
+	if cfg.DSN == "" {
+		return nil, errors.BadRequest("mysql DSN is required")
+	}
+
+	if log == nil {
+		log = defaultLogger.GetLogger()
+	}
+
+	// Create custom GORM logger that uses our logger
+	gormLog := postgres.NewGormLogger(log, cfg.LogLevel, cfg.SlowThreshold)
+
+	// Configure GORM
+	gormConfig := &gorm.Config{
+		Logger:                 gormLog,
+		SkipDefaultTransaction: true, // Disable default transaction for better performance
+		PrepareStmt:            true, // Prepare statements for better performance
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+
+	// Open database connection
+	db, err := gorm.Open(mysql.Open(cfg.DSN), gormConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDatabaseError, "failed to connect to mysql")
+	}
+
+	client, err := newClient(db, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infow("mysql client initialized successfully", logger.Fields{
+		"max_open_conns": cfg.MaxOpenConns,
+		"max_idle_conns": cfg.MaxIdleConns,
+	})
+
+	return client, nil
+}
+
+// newClient wraps an opened GORM database, applying the connection pool settings
+func newClient(db *gorm.DB, cfg *Config, log logger.Logger) (*Client, error) {
+	// Get underlying sql.DB to configure connection pool
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDatabaseError, "failed to get database instance")
+	}
+
+	// Set connection pool settings
+	if cfg.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if cfg.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+
+	return &Client{
+		db:     db,
+		logger: log,
+	}, nil
+}
+
+// NewFromAppConfig creates a new MySQL client from application config
+func NewFromAppConfig(cfg *DatabaseConfig, log logger.Logger) (*Client, error) {
+	if cfg == nil {
+		return nil, errors.BadRequest("database configuration is required")
+	}
+
+	if cfg.Driver != "mysql" {
+		return nil, errors.BadRequest(fmt.Sprintf("invalid driver: %s, expected mysql", cfg.Driver))
+	}
+
+	// Default to silent; enable Info logging when a logger is provided.
+	logLevel := gormlogger.Silent
+	if log != nil {
+		logLevel = gormlogger.Info
+	}
+
+	mysqlConfig := &Config{
+		DSN:             cfg.DSN(),
+		MaxOpenConns:    cfg.MaxOpenConns,
+		MaxIdleConns:    cfg.MaxIdleConns,
+		ConnMaxLifetime: cfg.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.ConnMaxIdleTime,
+		LogLevel:        logLevel,
+		SlowThreshold:   cfg.SlowThreshold, // NewGormLogger defaults to 200ms when unset
+	}
+
+	return New(mysqlConfig, log)
+}
+
+// DB returns the underlying GORM database instance
+func (c *Client) DB() *gorm.DB {
+	return c.db
+}
+
+// WithContext returns a new GORM DB instance with the given context
+func (c *Client) WithContext(ctx context.Context) *gorm.DB {
+	return c.db.WithContext(ctx)
+}
+
+// Health checks the database connection health
+func (c *Client) Health(ctx context.Context) error {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return errors.Wrap(err, errors.CodeDatabaseError, "failed to get database instance")
+	}
+
+	// Ping with context to check connection
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return errors.Wrap(err, errors.CodeDatabaseError, "database ping failed")
+	}
+
+	return nil
+}
+
+// Stats returns database connection pool statistics
+func (c *Client) Stats() (*postgres.Stats, error) {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeDatabaseError, "failed to get database instance")
+	}
+
+	stats := sqlDB.Stats()
+	return &postgres.Stats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration,
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}, nil
+}
+
+// Close closes the database connection
+func (c *Client) Close() error {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return errors.Wrap(err, errors.CodeDatabaseError, "failed to get database instance")
+	}
+
+	if err := sqlDB.Close(); err != nil {
+		return errors.Wrap(err, errors.CodeDatabaseError, "failed to close database connection")
+	}
+
+	c.logger.Info("mysql client closed successfully")
+	return nil
+}
+
+// Transaction executes a function within a database transaction
+func (c *Client) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return c.TransactionWithOptions(ctx, nil, fn)
+}
+
+// TransactionWithOptions executes a function within a database transaction with custom options
+func (c *Client) TransactionWithOptions(ctx context.Context, opts *postgres.TxOptions, fn func(tx *gorm.DB) error) error {
+	sqlOpts := &sql.TxOptions{}
+	if opts != nil {
+		sqlOpts.ReadOnly = opts.ReadOnly
+	}
+
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx); err != nil {
+			// Check if it's already an AppError
+			if _, ok := errors.As(err); ok {
+				return err
+			}
+			return errors.Wrap(err, errors.CodeDatabaseError, "transaction failed")
+		}
+		return nil
+	}, sqlOpts)
+}
+
+// AutoMigrate runs auto migration for the given models
+func (c *Client) AutoMigrate(models ...interface{}) error {
+	if err := c.db.AutoMigrate(models...); err != nil {
+		return errors.Wrap(err, errors.CodeDatabaseError, "auto migration failed")
+	}
+
+	c.logger.Infow("auto migration completed successfully", logger.Fields{
+		"models_count": len(models),
+	})
+
+	return nil
+}
+
+// Exec executes raw SQL
+func (c *Client) Exec(ctx context.Context, sql string, values ...interface{}) error {
+	if err := c.db.WithContext(ctx).Exec(sql, values...).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDatabaseError, "failed to execute query")
+	}
+	return nil
+}
+
+// Raw executes raw SQL query and scans results
+func (c *Client) Raw(ctx context.Context, dest interface{}, sql string, values ...interface{}) error {
+	if err := c.db.WithContext(ctx).Raw(sql, values...).Scan(dest).Error; err != nil {
+		return errors.Wrap(err, errors.CodeDatabaseError, "failed to execute raw query")
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package mysql
+
+import (
+	"context"
+
+	appConfig "github.com/phatnt199/go-infra/pkg/application/config"
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/logger"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+var (
+	// Module provides a MySQL client (and its *gorm.DB) built from the loaded
+	// application configuration, closed when the application stops
+	Module = fx.Module(
+		"mysqlfx",
+		mysqlProviders,
+		mysqlInvokes,
+	)
+
+	mysqlProviders = fx.Options(
+		fx.Provide(
+			ProvideClient,
+			func(client *Client) *gorm.DB { return client.DB() },
+		),
+	)
+
+	mysqlInvokes = fx.Options(fx.Invoke(registerHooks))
+)
+
+// ProvideClient creates a MySQL client from config.Get().Database
+func ProvideClient(log logger.Logger) (*Client, error) {
+	cfg := appConfig.Get()
+	if cfg == nil {
+		return nil, errors.Internal("application configuration is not loaded")
+	}
+
+	return NewFromAppConfig(&DatabaseConfig{
+		Driver:          cfg.Database.Driver,
+		Host:            cfg.Database.Host,
+		Port:            cfg.Database.Port,
+		User:            cfg.Database.Username,
+		Password:        cfg.Database.Password,
+		DBName:          cfg.Database.Database,
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.Database.ConnMaxIdleTime,
+		SlowThreshold:   cfg.Database.SlowThreshold,
+	}, log)
+}
+
+// registerHooks checks the connection on start and closes it on stop
+func registerHooks(lc fx.Lifecycle, client *Client) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return client.Health(ctx)
+		},
+		OnStop: func(ctx context.Context) error {
+			return client.Close()
+		},
+	})
+}
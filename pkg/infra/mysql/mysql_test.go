@@ -0,0 +1,93 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/infra/postgres"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+)
+
+type testProduct struct {
+	postgres.BaseModel
+	Name string
+}
+
+// newTestClient builds a Client over in-memory SQLite, standing in for a MySQL server
+func newTestClient(t *testing.T, models ...interface{}) *Client {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+
+	// a single connection keeps every query on the same in-memory database
+	client, err := newClient(db, &Config{MaxOpenConns: 1}, empty.EmptyLogger)
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(models...))
+	return client
+}
+
+func Test_Health_Succeeds_On_Open_Connection(t *testing.T) {
+	client := newTestClient(t)
+
+	require.NoError(t, client.Health(context.Background()))
+
+	stats, err := client.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.MaxOpenConnections)
+}
+
+func Test_Health_Fails_After_Close(t *testing.T) {
+	client := newTestClient(t)
+	require.NoError(t, client.Close())
+
+	err := client.Health(context.Background())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.CodeDatabaseError))
+}
+
+func Test_Generic_Repository_Works_Against_Client(t *testing.T) {
+	client := newTestClient(t, &testProduct{})
+	t.Cleanup(func() { _ = client.Close() })
+	repo := postgres.NewRepository[testProduct, uint](client.DB())
+	ctx := context.Background()
+
+	err := client.Transaction(ctx, func(tx *gorm.DB) error {
+		return repo.WithDB(tx).Create(ctx, &testProduct{Name: "keyboard"})
+	})
+	require.NoError(t, err)
+
+	found, err := repo.FindOne(ctx, map[string]interface{}{"name": "keyboard"})
+	require.NoError(t, err)
+	assert.Equal(t, "keyboard", found.Name)
+}
+
+func Test_DatabaseConfig_DSN(t *testing.T) {
+	cfg := &DatabaseConfig{
+		Host:     "localhost",
+		Port:     3306,
+		User:     "app",
+		Password: "secret",
+		DBName:   "shop",
+	}
+
+	dsn := cfg.DSN()
+	assert.True(t, strings.HasPrefix(dsn, "app:secret@tcp(localhost:3306)/shop?"), dsn)
+	assert.Contains(t, dsn, "parseTime=true")
+	assert.Contains(t, dsn, "charset=utf8mb4")
+}
+
+func Test_NewFromAppConfig_Rejects_Other_Drivers(t *testing.T) {
+	_, err := NewFromAppConfig(&DatabaseConfig{Driver: "postgres"}, nil)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
@@ -11,9 +11,9 @@ type RepositoryAdapter[T any, ID comparable] struct {
 }
 
 // NewRepositoryAdapter creates a new repository adapter
-func NewRepositoryAdapter[T any, ID comparable](db *gorm.DB) *RepositoryAdapter[T, ID] {
+func NewRepositoryAdapter[T any, ID comparable](db *gorm.DB, opts ...RepositoryOption) *RepositoryAdapter[T, ID] {
 	return &RepositoryAdapter[T, ID]{
-		Repository: NewRepository[T, ID](db),
+		Repository: NewRepository[T, ID](db, opts...),
 	}
 }
 
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+const (
+	// DefaultBatchSize is the number of buffered items that triggers a flush
+	DefaultBatchSize = 100
+
+	// DefaultBatchInterval is the longest an item stays buffered before a flush
+	DefaultBatchInterval = time.Second
+)
+
+// batchWriterConfig holds the options of a BatchWriter
+type batchWriterConfig struct {
+	size     int
+	interval time.Duration
+	onError  func(err error)
+}
+
+// BatchWriterOption configures a BatchWriter
+type BatchWriterOption func(*batchWriterConfig)
+
+// WithBatchSize flushes once size items are buffered (DefaultBatchSize by default)
+func WithBatchSize(size int) BatchWriterOption {
+	return func(cfg *batchWriterConfig) {
+		if size > 0 {
+			cfg.size = size
+		}
+	}
+}
+
+// WithBatchInterval flushes buffered items at least every interval
+// (DefaultBatchInterval by default)
+func WithBatchInterval(interval time.Duration) BatchWriterOption {
+	return func(cfg *batchWriterConfig) {
+		if interval > 0 {
+			cfg.interval = interval
+		}
+	}
+}
+
+// WithBatchErrorHandler receives the errors of automatic flushes, triggered by Add or
+// by the interval. Items of a failed batch are dropped.
+func WithBatchErrorHandler(fn func(err error)) BatchWriterOption {
+	return func(cfg *batchWriterConfig) {
+		cfg.onError = fn
+	}
+}
+
+// BatchWriter buffers inserts and writes them with CreateInBatches once enough items
+// are buffered or the flush interval elapses, for high-volume writes such as telemetry
+// where single-row inserts are too slow. It is safe for concurrent use; Close drains
+// the remaining items.
+type BatchWriter[T any] struct {
+	db  *gorm.DB
+	cfg batchWriterConfig
+
+	mu     sync.Mutex
+	items  []T
+	closed bool
+
+	// flushMu serializes flushes so batches are written in the order they were taken
+	flushMu sync.Mutex
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewBatchWriter creates a batch writer and starts its interval flush. Close it on
+// shutdown, e.g. with lc.Append(writer.Hook()).
+//
+// Example:
+//
+//	writer := postgres.NewBatchWriter[PageView](db,
+//	    postgres.WithBatchSize(500),
+//	    postgres.WithBatchInterval(2*time.Second),
+//	    postgres.WithBatchErrorHandler(func(err error) {
+//	        log.Errorw("failed to write page views", logger.Fields{"error": err})
+//	    }),
+//	)
+//	lc.Append(writer.Hook())
+//
+//	_ = writer.Add(PageView{Path: "/pricing"})
+func NewBatchWriter[T any](db *gorm.DB, opts ...BatchWriterOption) *BatchWriter[T] {
+	cfg := batchWriterConfig{
+		size:     DefaultBatchSize,
+		interval: DefaultBatchInterval,
+		onError:  func(error) {},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	w := &BatchWriter[T]{
+		db:   db,
+		cfg:  cfg,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Add buffers item, flushing synchronously when the batch is full. It returns an
+// error once the writer is closed.
+func (w *BatchWriter[T]) Add(item T) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return errors.Internal("batch writer is closed")
+	}
+	w.items = append(w.items, item)
+	full := len(w.items) >= w.cfg.size
+	w.mu.Unlock()
+
+	if full {
+		w.report(w.Flush(context.Background()))
+	}
+	return nil
+}
+
+// Len returns the number of buffered items
+func (w *BatchWriter[T]) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.items)
+}
+
+// Flush writes the buffered items now
+func (w *BatchWriter[T]) Flush(ctx context.Context) error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	batch := w.items
+	w.items = nil
+	w.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := w.db.WithContext(ctx).CreateInBatches(&batch, w.cfg.size).Error; err != nil {
+		return dbError(err, "failed to write batch").WithContext("count", len(batch))
+	}
+	return nil
+}
+
+// Close stops the interval flush, rejects further items and writes the remaining ones
+func (w *BatchWriter[T]) Close(ctx context.Context) error {
+	w.closeOnce.Do(func() {
+		w.mu.Lock()
+		w.closed = true
+		w.mu.Unlock()
+
+		close(w.stop)
+		<-w.done
+	})
+	return w.Flush(ctx)
+}
+
+// Hook returns an fx lifecycle hook closing the writer when the application stops
+func (w *BatchWriter[T]) Hook() fx.Hook {
+	return fx.Hook{OnStop: w.Close}
+}
+
+// run flushes the buffered items every interval until the writer is closed
+func (w *BatchWriter[T]) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.report(w.Flush(context.Background()))
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// report passes the error of an automatic flush to the error handler
+func (w *BatchWriter[T]) report(err error) {
+	if err != nil {
+		w.cfg.onError(err)
+	}
+}
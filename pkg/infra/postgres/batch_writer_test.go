@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"gorm.io/gorm"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+type testEvent struct {
+	ID   uint `gorm:"primaryKey"`
+	Name string
+}
+
+func countEvents(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var count int64
+	require.NoError(t, db.Model(&testEvent{}).Count(&count).Error)
+	return count
+}
+
+func Test_BatchWriter_Flushes_By_Size(t *testing.T) {
+	db := newTestDB(t, &testEvent{})
+	writer := NewBatchWriter[testEvent](db, WithBatchSize(3), WithBatchInterval(time.Hour))
+
+	for _, name := range []string{"a", "b"} {
+		require.NoError(t, writer.Add(testEvent{Name: name}))
+	}
+	assert.Zero(t, countEvents(t, db))
+	assert.Equal(t, 2, writer.Len())
+
+	require.NoError(t, writer.Add(testEvent{Name: "c"}))
+	assert.Equal(t, int64(3), countEvents(t, db))
+	assert.Zero(t, writer.Len())
+
+	require.NoError(t, writer.Add(testEvent{Name: "d"}))
+	assert.Equal(t, int64(3), countEvents(t, db))
+
+	require.NoError(t, writer.Close(context.Background()))
+	assert.Equal(t, int64(4), countEvents(t, db))
+	assert.Error(t, writer.Add(testEvent{Name: "e"}))
+}
+
+func Test_BatchWriter_Flushes_By_Time(t *testing.T) {
+	db := newTestDB(t, &testEvent{})
+	writer := NewBatchWriter[testEvent](db, WithBatchSize(100), WithBatchInterval(20*time.Millisecond))
+	defer writer.Close(context.Background())
+
+	require.NoError(t, writer.Add(testEvent{Name: "a"}))
+	require.NoError(t, writer.Add(testEvent{Name: "b"}))
+
+	assert.Eventually(t, func() bool {
+		return writer.Len() == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int64(2), countEvents(t, db))
+}
+
+func Test_BatchWriter_Concurrent_Adds(t *testing.T) {
+	db := newTestDB(t, &testEvent{})
+	writer := NewBatchWriter[testEvent](db, WithBatchSize(7), WithBatchInterval(5*time.Millisecond))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				assert.NoError(t, writer.Add(testEvent{Name: "event"}))
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, writer.Close(context.Background()))
+	assert.Equal(t, int64(100), countEvents(t, db))
+}
+
+func Test_BatchWriter_Reports_Flush_Errors(t *testing.T) {
+	db := newTestDB(t)
+	errs := make(chan error, 1)
+	writer := NewBatchWriter[testEvent](db, WithBatchSize(1), WithBatchErrorHandler(func(err error) {
+		errs <- err
+	}))
+	defer writer.Close(context.Background())
+
+	// testEvent isn't migrated, so the insert fails
+	require.NoError(t, writer.Add(testEvent{Name: "a"}))
+
+	select {
+	case err := <-errs:
+		appErr, ok := errors.As(err)
+		require.True(t, ok)
+		assert.Equal(t, 1, appErr.Context["count"])
+	case <-time.After(time.Second):
+		t.Fatal("flush error was not reported")
+	}
+}
+
+func Test_BatchWriter_Hook_Drains_On_Stop(t *testing.T) {
+	db := newTestDB(t, &testEvent{})
+	writer := NewBatchWriter[testEvent](db, WithBatchInterval(time.Hour))
+
+	app := fxtest.New(t, fx.Invoke(func(lc fx.Lifecycle) { lc.Append(writer.Hook()) }))
+	app.RequireStart()
+	require.NoError(t, writer.Add(testEvent{Name: "a"}))
+	app.RequireStop()
+
+	assert.Equal(t, int64(1), countEvents(t, db))
+}
@@ -2,11 +2,15 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
 
 	"github.com/phatnt199/go-infra/pkg/logger"
+	"github.com/phatnt199/go-infra/pkg/servertiming"
 )
 
 // gormLogger implements GORM's logger interface using our custom logger
@@ -16,8 +20,9 @@ type gormLogger struct {
 	slowThreshold time.Duration
 }
 
-// newGormLogger creates a new GORM logger
-func newGormLogger(log logger.Logger, level gormlogger.LogLevel, slowThreshold time.Duration) gormlogger.Interface {
+// NewGormLogger creates a GORM logger writing to log. It is shared by the SQL clients
+// (postgres, mysql) so every driver logs queries, slow queries and errors the same way.
+func NewGormLogger(log logger.Logger, level gormlogger.LogLevel, slowThreshold time.Duration) gormlogger.Interface {
 	if slowThreshold == 0 {
 		slowThreshold = 200 * time.Millisecond
 	}
@@ -57,31 +62,47 @@ func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{})
 	}
 }
 
-// Trace logs SQL queries
+// Trace logs SQL queries and records them as the "db" phase of the request's
+// servertiming.Timing
 func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	servertiming.FromContext(ctx).Record("db", elapsed)
+
 	if l.logLevel <= gormlogger.Silent {
 		return
 	}
 
-	elapsed := time.Since(begin)
 	sql, rows := fc()
 
-	fields := logger.Fields{
-		"elapsed": elapsed,
-		"rows":    rows,
-		"sql":     sql,
-	}
+	fields := logger.ContextFields(ctx)
+	fields["elapsed"] = elapsed
+	fields["rows"] = rows
+	fields["sql"] = sql
 
 	switch {
+	case err != nil && isBenignQueryError(err):
+		// Not-found lookups and canceled requests are expected, keep them out of error logs
+		if l.logLevel >= gormlogger.Info {
+			fields["error"] = err.Error()
+			l.logger.Debugw("database query executed", fields)
+		}
+
 	case err != nil && l.logLevel >= gormlogger.Error:
 		fields["error"] = err.Error()
 		l.logger.Errorw("database query error", fields)
 
 	case elapsed > l.slowThreshold && l.slowThreshold != 0 && l.logLevel >= gormlogger.Warn:
+		// A slow query isn't an error, log it as a warning with the offending caller
 		fields["threshold"] = l.slowThreshold
-		l.logger.Errorw("slow query detected", fields)
+		fields["caller"] = utils.FileWithLineNum()
+		l.logger.Warnw("slow query detected", fields)
 
 	case l.logLevel >= gormlogger.Info:
 		l.logger.Debugw("database query executed", fields)
 	}
 }
+
+// isBenignQueryError reports whether err is an expected query outcome rather than a failure
+func isBenignQueryError(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/phatnt199/go-infra/pkg/logger"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+	"github.com/phatnt199/go-infra/pkg/servertiming"
+)
+
+type capturedLog struct {
+	level  string
+	msg    string
+	fields logger.Fields
+}
+
+// captureLogger records structured log calls, other methods are no-ops
+type captureLogger struct {
+	logger.Logger
+	mu      sync.Mutex
+	entries []capturedLog
+}
+
+func newCaptureLogger() *captureLogger {
+	return &captureLogger{Logger: empty.EmptyLogger}
+}
+
+func (l *captureLogger) record(level, msg string, fields logger.Fields) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, capturedLog{level: level, msg: msg, fields: fields})
+}
+
+func (l *captureLogger) Debugw(msg string, fields logger.Fields) { l.record("debug", msg, fields) }
+func (l *captureLogger) Infow(msg string, fields logger.Fields)  { l.record("info", msg, fields) }
+func (l *captureLogger) Warnw(msg string, fields logger.Fields)  { l.record("warn", msg, fields) }
+func (l *captureLogger) Errorw(msg string, fields logger.Fields) { l.record("error", msg, fields) }
+
+func (l *captureLogger) levels() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	levels := make([]string, len(l.entries))
+	for i, entry := range l.entries {
+		levels[i] = entry.level
+	}
+	return levels
+}
+
+func traceQuery(gormLog gormlogger.Interface, elapsed time.Duration, err error) {
+	gormLog.Trace(context.Background(), time.Now().Add(-elapsed), func() (string, int64) {
+		return "SELECT * FROM users", 1
+	}, err)
+}
+
+func Test_Gorm_Logger_Logs_Slow_Queries_As_Warnings(t *testing.T) {
+	log := newCaptureLogger()
+	gormLog := NewGormLogger(log, gormlogger.Info, 50*time.Millisecond)
+
+	traceQuery(gormLog, time.Second, nil)
+	traceQuery(gormLog, time.Millisecond, nil)
+
+	require.Equal(t, []string{"warn", "debug"}, log.levels())
+	slow := log.entries[0]
+	assert.Equal(t, "slow query detected", slow.msg)
+	assert.Equal(t, 50*time.Millisecond, slow.fields["threshold"])
+	assert.NotEmpty(t, slow.fields["caller"])
+}
+
+func Test_Gorm_Logger_Uses_Configured_Slow_Threshold(t *testing.T) {
+	log := newCaptureLogger()
+	gormLog := NewGormLogger(log, gormlogger.Warn, 2*time.Second)
+
+	traceQuery(gormLog, time.Second, nil)
+	assert.Empty(t, log.levels())
+
+	// unset thresholds fall back to the 200ms default
+	gormLog = NewGormLogger(log, gormlogger.Warn, 0)
+	traceQuery(gormLog, time.Second, nil)
+	assert.Equal(t, []string{"warn"}, log.levels())
+}
+
+func Test_Gorm_Logger_Does_Not_Log_Benign_Errors_As_Errors(t *testing.T) {
+	log := newCaptureLogger()
+	gormLog := NewGormLogger(log, gormlogger.Info, time.Second)
+
+	traceQuery(gormLog, time.Millisecond, gorm.ErrRecordNotFound)
+	traceQuery(gormLog, time.Millisecond, fmt.Errorf("query aborted: %w", context.Canceled))
+	assert.Equal(t, []string{"debug", "debug"}, log.levels())
+
+	traceQuery(gormLog, time.Millisecond, stdErrors.New("connection refused"))
+	assert.Equal(t, []string{"debug", "debug", "error"}, log.levels())
+
+	// below Info level benign errors are not logged at all
+	log = newCaptureLogger()
+	gormLog = NewGormLogger(log, gormlogger.Error, time.Second)
+	traceQuery(gormLog, time.Millisecond, gorm.ErrRecordNotFound)
+	assert.Empty(t, log.levels())
+}
+
+func Test_Gorm_Logger_Records_Queries_In_Server_Timing(t *testing.T) {
+	// Queries are timed even when logging is silent
+	gormLog := NewGormLogger(newCaptureLogger(), gormlogger.Silent, time.Second)
+	timing := servertiming.New()
+	ctx := servertiming.NewContext(context.Background(), timing)
+
+	for _, elapsed := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond} {
+		gormLog.Trace(ctx, time.Now().Add(-elapsed), func() (string, int64) { return "SELECT 1", 1 }, nil)
+	}
+
+	metrics := timing.Metrics()
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "db", metrics[0].Name)
+	assert.Equal(t, 2, metrics[0].Count)
+	assert.GreaterOrEqual(t, metrics[0].Duration, 30*time.Millisecond)
+}
@@ -0,0 +1,82 @@
+// Package migrations holds the global registry the files generated by
+// postgres.Migrator.CreateMigrationFile register themselves in. Import the package
+// containing the generated files for its side effects, then pass All to the
+// Migrator:
+//
+//	import _ "example.com/app/db/migrations"
+//
+//	err := migrator.Up(ctx, migrations.All())
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/phatnt199/go-infra/pkg/infra/postgres"
+)
+
+// Migration is the migration type consumed by postgres.Migrator
+type Migration = postgres.Migration
+
+// Registry collects migrations by version. It is safe for concurrent use.
+type Registry struct {
+	mu         sync.Mutex
+	migrations map[string]Migration
+}
+
+// NewRegistry creates an empty registry. Most applications use the package-level
+// Register and All instead.
+func NewRegistry() *Registry {
+	return &Registry{migrations: make(map[string]Migration)}
+}
+
+// Register adds m to the registry. It panics when m has no version or no Up
+// function, or when a migration with the same version is already registered, so
+// that conflicting migration files fail at startup rather than being skipped.
+func (r *Registry) Register(m Migration) {
+	if m.Version == "" {
+		panic(fmt.Sprintf("migrations: migration %q has no version", m.Name))
+	}
+	if m.Up == nil {
+		panic(fmt.Sprintf("migrations: migration %s has no up function", m.Version))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.migrations[m.Version]; ok {
+		panic(fmt.Sprintf("migrations: duplicate version %s (%q and %q)", m.Version, existing.Name, m.Name))
+	}
+	r.migrations[m.Version] = m
+}
+
+// All returns the registered migrations sorted by version, regardless of the order
+// they were registered in
+func (r *Registry) All() []Migration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	all := make([]Migration, 0, len(r.migrations))
+	for _, m := range r.migrations {
+		all = append(all, m)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Version < all[j].Version
+	})
+	return all
+}
+
+// defaultRegistry is the registry used by the generated migration files
+var defaultRegistry = NewRegistry()
+
+// Register adds m to the global registry. Generated migration files call it from
+// their init function. See Registry.Register.
+func Register(m Migration) {
+	defaultRegistry.Register(m)
+}
+
+// All returns the migrations of the global registry sorted by version
+func All() []Migration {
+	return defaultRegistry.All()
+}
@@ -0,0 +1,73 @@
+package migrations
+
+import (
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/phatnt199/go-infra/pkg/infra/postgres"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+)
+
+func noop(*gorm.DB) error { return nil }
+
+func Test_All_Sorts_By_Version_Regardless_Of_Registration_Order(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Migration{Version: "20240301000000", Name: "third", Up: noop})
+	r.Register(Migration{Version: "20240101000000", Name: "first", Up: noop})
+	r.Register(Migration{Version: "20240201000000", Name: "second", Up: noop})
+
+	var names []string
+	for _, m := range r.All() {
+		names = append(names, m.Name)
+	}
+	assert.Equal(t, []string{"first", "second", "third"}, names)
+}
+
+func Test_Register_Panics_On_Duplicate_Version(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Migration{Version: "20240101000000", Name: "create_users", Up: noop})
+
+	assert.PanicsWithValue(t,
+		`migrations: duplicate version 20240101000000 ("create_users" and "create_posts")`,
+		func() { r.Register(Migration{Version: "20240101000000", Name: "create_posts", Up: noop}) },
+	)
+	assert.Len(t, r.All(), 1)
+}
+
+func Test_Register_Panics_On_Incomplete_Migration(t *testing.T) {
+	r := NewRegistry()
+
+	assert.Panics(t, func() { r.Register(Migration{Name: "no_version", Up: noop}) })
+	assert.Panics(t, func() { r.Register(Migration{Version: "20240101000000", Name: "no_up"}) })
+	assert.Empty(t, r.All())
+}
+
+func Test_Global_Registry(t *testing.T) {
+	Register(Migration{Version: "20990101000000", Name: "global", Up: noop})
+
+	all := All()
+	assert.Equal(t, "global", all[len(all)-1].Name)
+}
+
+func Test_Generated_Migration_File_Registers_Through_Package(t *testing.T) {
+	migrator := postgres.NewMigratorWithPath(nil, t.TempDir(), empty.EmptyLogger)
+	path, err := migrator.CreateMigrationFile("create users")
+	require.NoError(t, err)
+
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+	require.NoError(t, err)
+
+	var imports []string
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		require.NoError(t, err)
+		imports = append(imports, importPath)
+	}
+	assert.Contains(t, imports, "github.com/phatnt199/go-infra/pkg/infra/postgres/migrations")
+}
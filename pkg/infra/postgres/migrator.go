@@ -2,14 +2,17 @@ package postgres
 
 import (
 	"context"
+	stdErrors "errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/phatnt199/go-infra/pkg/errors"
 	"github.com/phatnt199/go-infra/pkg/logger"
@@ -229,7 +232,9 @@ func (m *Migrator) AutoMigrate(models ...interface{}) error {
 	return nil
 }
 
-// CreateMigrationFile creates a new migration file
+// CreateMigrationFile creates a new migration file in the migrations directory. The
+// file registers its migration in the migrations package registry from init; run
+// the registered migrations with m.Up(ctx, migrations.All()).
 func (m *Migrator) CreateMigrationFile(name string) (string, error) {
 	if m.migrationsDir == "" {
 		return "", errors.BadRequest("migrations directory not configured")
@@ -256,10 +261,12 @@ func (m *Migrator) CreateMigrationFile(name string) (string, error) {
 
 import (
 	"gorm.io/gorm"
+
+	"github.com/phatnt199/go-infra/pkg/infra/postgres/migrations"
 )
 
 func init() {
-	Register(Migration{
+	migrations.Register(migrations.Migration{
 		Version: "%s",
 		Name:    "%s",
 		Up: func(tx *gorm.DB) error {
@@ -378,3 +385,149 @@ func (m *Migrator) buildMigrationInfoList(migrations []Migration, applied []Migr
 
 	return list
 }
+
+// createIndexPattern matches the start of a CREATE [UNIQUE] INDEX [CONCURRENTLY] statement
+var createIndexPattern = regexp.MustCompile(`(?i)^\s*CREATE\s+(UNIQUE\s+)?INDEX\s+(CONCURRENTLY\s+)?`)
+
+// createIndexNamePattern matches the index name following createIndexPattern
+var createIndexNamePattern = regexp.MustCompile(`(?i)^(IF\s+NOT\s+EXISTS\s+)?("[^"]+"|[A-Za-z_][A-Za-z0-9_$]*)\s+ON\s`)
+
+// ensureIndexSavepoint is the savepoint EnsureIndex rolls back to when creating the
+// index fails within a transaction, which PostgreSQL would otherwise leave aborted
+const ensureIndexSavepoint = "ensure_index"
+
+// sqlStateDuplicateTable is the SQLSTATE of creating a relation, such as an index,
+// that already exists
+const sqlStateDuplicateTable = "42P07"
+
+// EnsureIndex creates the index indexName on tableName with createSQL unless it
+// already exists, for the partial and expression indexes AutoMigrate can't declare.
+// createSQL is a CREATE [UNIQUE] INDEX statement. On PostgreSQL it runs as CREATE INDEX
+// CONCURRENTLY, which doesn't block writes, unless the migrator's db is a transaction,
+// where CONCURRENTLY isn't allowed; an invalid index left by a failed concurrent build
+// is dropped and built again. An index created meanwhile by another instance counts as
+// success, so migrations can call EnsureIndex on every run, and within a transaction
+// the failed CREATE INDEX is rolled back to a savepoint so the transaction goes on.
+//
+// Example:
+//
+//	err := migrator.EnsureIndex(ctx, "orders", "idx_orders_pending_created_at",
+//	    `CREATE INDEX idx_orders_pending_created_at ON orders (created_at) WHERE status = 'pending'`)
+func (m *Migrator) EnsureIndex(ctx context.Context, tableName, indexName, createSQL string) error {
+	if !createIndexPattern.MatchString(createSQL) {
+		return errors.New(errors.CodeInvalidInput, "createSQL must be a CREATE INDEX statement").
+			WithContext("index", indexName)
+	}
+	if name, ok := createIndexName(createSQL); !ok || name != indexName {
+		return errors.New(errors.CodeInvalidInput, fmt.Sprintf("createSQL must create the index %s", indexName)).
+			WithContext("index", indexName)
+	}
+
+	db := m.db.WithContext(ctx)
+	_, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter)
+	concurrently := db.Dialector.Name() == "postgres" && !inTransaction
+
+	exists, valid, err := m.indexState(db, tableName, indexName)
+	if err != nil {
+		return errors.Wrap(err, errors.CodeDatabaseError, fmt.Sprintf("failed to check index %s", indexName))
+	}
+	if exists && valid {
+		return nil
+	}
+
+	if exists {
+		m.logger.Warnw("rebuilding invalid index", logger.Fields{"table": tableName, "index": indexName})
+		drop := "DROP INDEX IF EXISTS ?"
+		if concurrently {
+			drop = "DROP INDEX CONCURRENTLY IF EXISTS ?"
+		}
+		if err := db.Exec(drop, clause.Table{Name: indexName}).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDatabaseError, fmt.Sprintf("failed to drop invalid index %s", indexName))
+		}
+	}
+
+	prefix := "CREATE ${1}INDEX "
+	if concurrently {
+		prefix = "CREATE ${1}INDEX CONCURRENTLY "
+	}
+	statement := createIndexPattern.ReplaceAllString(createSQL, prefix)
+
+	if err := m.createIndex(db, inTransaction, statement); err != nil {
+		if !isAlreadyExists(err) {
+			return errors.Wrap(err, errors.CodeDatabaseError, fmt.Sprintf("failed to create index %s", indexName))
+		}
+
+		// Another instance created it meanwhile, unless the name is taken by another
+		// relation, such as a table or an index of another table
+		exists, _, stateErr := m.indexState(db, tableName, indexName)
+		if stateErr != nil {
+			return errors.Wrap(stateErr, errors.CodeDatabaseError, fmt.Sprintf("failed to check index %s", indexName))
+		}
+		if !exists {
+			return errors.Wrap(err, errors.CodeConflict, fmt.Sprintf("relation %s exists but is not an index of %s", indexName, tableName))
+		}
+		return nil
+	}
+
+	m.logger.Infow("index created", logger.Fields{"table": tableName, "index": indexName})
+	return nil
+}
+
+// createIndexName returns the unquoted name of the index createSQL creates
+func createIndexName(createSQL string) (string, bool) {
+	rest := createSQL[len(createIndexPattern.FindString(createSQL)):]
+	match := createIndexNamePattern.FindStringSubmatch(rest)
+	if match == nil {
+		return "", false
+	}
+	return strings.Trim(match[2], `"`), true
+}
+
+// createIndex runs statement, within a savepoint in a transaction so that a failure
+// doesn't abort it
+func (m *Migrator) createIndex(db *gorm.DB, inTransaction bool, statement string) error {
+	if !inTransaction {
+		return db.Exec(statement).Error
+	}
+
+	if err := db.SavePoint(ensureIndexSavepoint).Error; err != nil {
+		return err
+	}
+	if err := db.Exec(statement).Error; err != nil {
+		if rollbackErr := db.RollbackTo(ensureIndexSavepoint).Error; rollbackErr != nil {
+			return stdErrors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// indexState reports whether the index exists and, on PostgreSQL, whether it is valid:
+// a CREATE INDEX CONCURRENTLY that failed leaves an invalid index behind
+func (m *Migrator) indexState(db *gorm.DB, tableName, indexName string) (exists, valid bool, err error) {
+	if db.Dialector.Name() != "postgres" {
+		exists = db.Migrator().HasIndex(tableName, indexName)
+		return exists, exists, nil
+	}
+
+	var states []bool
+	err = db.Raw(`SELECT ix.indisvalid FROM pg_indexes pi
+		JOIN pg_namespace n ON n.nspname = pi.schemaname
+		JOIN pg_class c ON c.relname = pi.indexname AND c.relnamespace = n.oid
+		JOIN pg_index ix ON ix.indexrelid = c.oid
+		WHERE pi.schemaname = CURRENT_SCHEMA() AND pi.tablename = ? AND pi.indexname = ?`,
+		tableName, indexName).Scan(&states).Error
+	if err != nil || len(states) == 0 {
+		return false, false, err
+	}
+	return true, states[0], nil
+}
+
+// isAlreadyExists reports whether err is the failure to create a relation that exists
+func isAlreadyExists(err error) bool {
+	var stateErr interface{ SQLState() string }
+	if stdErrors.As(err, &stateErr) {
+		return stateErr.SQLState() == sqlStateDuplicateTable
+	}
+	return strings.Contains(err.Error(), "already exists")
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+)
+
+const createPendingIndexSQL = `CREATE INDEX idx_accounts_free_name ON test_accounts (name) WHERE plan = 'free'`
+
+func Test_EnsureIndex_Is_Idempotent(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	migrator := NewMigrator(db, empty.EmptyLogger)
+	ctx := context.Background()
+
+	require.NoError(t, migrator.EnsureIndex(ctx, "test_accounts", "idx_accounts_free_name", createPendingIndexSQL))
+	assert.True(t, db.Migrator().HasIndex("test_accounts", "idx_accounts_free_name"))
+
+	// Re-running skips the existing index rather than failing on the plain CREATE INDEX
+	require.NoError(t, migrator.EnsureIndex(ctx, "test_accounts", "idx_accounts_free_name", createPendingIndexSQL))
+}
+
+func Test_EnsureIndex_Rejects_Other_Statements(t *testing.T) {
+	migrator := NewMigrator(newTestDB(t, &testAccount{}), empty.EmptyLogger)
+
+	err := migrator.EnsureIndex(context.Background(), "test_accounts", "idx_accounts_name", "DROP TABLE test_accounts")
+	assert.True(t, errors.Is(err, errors.CodeInvalidInput), err)
+
+	// The statement must create the index that is checked
+	err = migrator.EnsureIndex(context.Background(), "test_accounts", "idx_other_name", createPendingIndexSQL)
+	assert.True(t, errors.Is(err, errors.CodeInvalidInput), err)
+	assert.NoError(t, migrator.EnsureIndex(context.Background(), "test_accounts", "idx_accounts_free_name",
+		`CREATE INDEX IF NOT EXISTS "idx_accounts_free_name" ON test_accounts (name) WHERE plan = 'free'`))
+}
+
+// sqlStateError is a driver error carrying a SQLSTATE, like pgconn.PgError
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "relation already exists" }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func Test_EnsureIndex_Keeps_The_Transaction_Usable_When_Losing_The_Race(t *testing.T) {
+	client, rec := newRecordingClient(t)
+	ctx := context.Background()
+
+	// Another instance creates the index between the check and the CREATE INDEX
+	var checks int
+	rec.execErr = func(query string) error {
+		if strings.HasPrefix(query, "CREATE INDEX") {
+			return sqlStateError(sqlStateDuplicateTable)
+		}
+		return nil
+	}
+	rec.rows = func(query string) []driver.Value {
+		if strings.Contains(query, "pg_indexes") {
+			checks++
+			if checks > 1 {
+				return []driver.Value{false}
+			}
+		}
+		return nil
+	}
+
+	err := client.db.Transaction(func(tx *gorm.DB) error {
+		if err := NewMigrator(tx, empty.EmptyLogger).EnsureIndex(ctx, "test_accounts", "idx_accounts_free_name", createPendingIndexSQL); err != nil {
+			return err
+		}
+		return tx.Exec("SELECT 1").Error
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"BEGIN",
+		"SELECT ix.indisvalid FROM pg_indexes pi",
+		"SAVEPOINT ensure_index",
+		"CREATE INDEX idx_accounts_free_name ON test_accounts (name)",
+		"ROLLBACK TO SAVEPOINT ensure_index",
+		"SELECT ix.indisvalid FROM pg_indexes pi",
+		"SELECT 1",
+		"COMMIT",
+	}, firstLines(rec))
+}
+
+func Test_EnsureIndex_Fails_When_The_Name_Belongs_To_Another_Relation(t *testing.T) {
+	client, rec := newRecordingClient(t)
+	rec.execErr = func(query string) error {
+		if strings.HasPrefix(query, "CREATE INDEX") {
+			return sqlStateError(sqlStateDuplicateTable)
+		}
+		return nil
+	}
+
+	err := NewMigrator(client.db, empty.EmptyLogger).EnsureIndex(context.Background(), "test_accounts", "idx_accounts_free_name", createPendingIndexSQL)
+	assert.True(t, errors.Is(err, errors.CodeConflict), err)
+}
+
+// firstLines returns the recorded statements up to their first line break or WHERE
+func firstLines(rec *recordingDriver) []string {
+	var lines []string
+	for _, statement := range statements(rec) {
+		lines = append(lines, strings.TrimSpace(strings.SplitN(statement, "\n", 2)[0]))
+	}
+	return lines
+}
+
+func Test_EnsureIndex_Creates_Concurrently_Outside_Transactions(t *testing.T) {
+	client, rec := newRecordingClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, NewMigrator(client.db, empty.EmptyLogger).EnsureIndex(ctx, "test_accounts", "idx_accounts_free_name", createPendingIndexSQL))
+
+	require.Len(t, queriesMatching(rec, "pg_indexes"), 1)
+	assert.Equal(t,
+		[]recordedQuery{{conn: 1, query: `CREATE INDEX CONCURRENTLY idx_accounts_free_name ON test_accounts (name) WHERE plan = 'free'`}},
+		queriesMatching(rec, "CREATE INDEX"))
+
+	// CONCURRENTLY is not allowed in transactions
+	err := client.db.Transaction(func(tx *gorm.DB) error {
+		return NewMigrator(tx, empty.EmptyLogger).EnsureIndex(ctx, "test_accounts", "idx_accounts_email_lower",
+			`CREATE UNIQUE INDEX CONCURRENTLY idx_accounts_email_lower ON test_accounts (lower(email))`)
+	})
+	require.NoError(t, err)
+	assert.Len(t, queriesMatching(rec, "CREATE UNIQUE INDEX idx_accounts_email_lower ON test_accounts (lower(email))"), 1)
+}
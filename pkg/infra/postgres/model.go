@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BaseModel provides the common primary key and audit timestamp columns.
+// Embed it in your models to get created_at/updated_at tracking and soft deletes:
+//
+//	type User struct {
+//	    postgres.BaseModel
+//	    Email string `gorm:"uniqueIndex;not null"`
+//	}
+//
+// Repository.List orders by created_at DESC by default when the column exists.
+type BaseModel struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/phatnt199/go-infra/pkg/errors"
 	"github.com/phatnt199/go-infra/pkg/logger"
 	defaultLogger "github.com/phatnt199/go-infra/pkg/logger/default_logger"
+	"github.com/phatnt199/go-infra/pkg/utils"
 )
 
 // Client represents a PostgreSQL database client
@@ -45,6 +46,7 @@ type DatabaseConfig struct {
 	MaxIdleConns    int           `mapstructure:"maxIdleConns" json:"maxIdleConns"`
 	ConnMaxLifetime time.Duration `mapstructure:"connMaxLifetime" json:"connMaxLifetime"`
 	ConnMaxIdleTime time.Duration `mapstructure:"connMaxIdleTime" json:"connMaxIdleTime"`
+	SlowThreshold   time.Duration `mapstructure:"slowThreshold" json:"slowThreshold"`
 }
 
 // DSN returns the PostgreSQL DSN connection string
@@ -68,7 +70,7 @@ func New(cfg *Config, log logger.Logger) (*Client, error) {
 	}
 
 	// Create custom GORM logger that uses our logger
-	gormLog := newGormLogger(log, cfg.LogLevel, cfg.SlowThreshold)
+	gormLog := NewGormLogger(log, cfg.LogLevel, cfg.SlowThreshold)
 
 	// Configure GORM
 	gormConfig := &gorm.Config{
@@ -144,7 +146,7 @@ func NewFromAppConfig(cfg *DatabaseConfig, log logger.Logger) (*Client, error) {
 		ConnMaxLifetime: cfg.ConnMaxLifetime,
 		ConnMaxIdleTime: cfg.ConnMaxIdleTime,
 		LogLevel:        logLevel,
-		SlowThreshold:   200 * time.Millisecond,
+		SlowThreshold:   cfg.SlowThreshold, // NewGormLogger defaults to 200ms when unset
 	}
 
 	client, err := New(pgConfig, log)
@@ -246,10 +248,11 @@ func (c *Client) TransactionWithOptions(ctx context.Context, opts *TxOptions, fn
 	txOpts := &TxOptions{}
 	if opts != nil {
 		txOpts.ReadOnly = opts.ReadOnly
+		txOpts.Isolation = opts.Isolation
 	}
 
 	// Pass SQL transaction options to GORM's Transaction helper so ReadOnly is enforced when supported.
-	sqlOpts := &sql.TxOptions{ReadOnly: txOpts.ReadOnly}
+	sqlOpts := &sql.TxOptions{ReadOnly: txOpts.ReadOnly, Isolation: txOpts.Isolation}
 	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := fn(tx); err != nil {
 			// Check if it's already an AppError
@@ -262,9 +265,49 @@ func (c *Client) TransactionWithOptions(ctx context.Context, opts *TxOptions, fn
 	}, sqlOpts)
 }
 
+// TransactionWithRetry runs fn in a transaction like TransactionWithOptions, retrying
+// the whole transaction with exponential backoff when it fails with a serialization
+// failure or deadlock (see errors.IsSerializationFailure), for up to maxAttempts
+// attempts in total. Other errors are returned immediately. fn must be safe to run
+// more than once, e.g. without side effects outside the transaction.
+//
+// Example:
+//
+//	err := client.TransactionWithRetry(ctx, &postgres.TxOptions{Isolation: sql.LevelSerializable}, 5,
+//	    func(tx *gorm.DB) error {
+//	        return transfer(tx, from, to, amount)
+//	    })
+func (c *Client) TransactionWithRetry(ctx context.Context, opts *TxOptions, maxAttempts int, fn func(tx *gorm.DB) error) error {
+	cfg := txRetryBackoff
+	cfg.MaxAttempts = maxAttempts
+	cfg.ShouldRetry = errors.IsSerializationFailure
+
+	attempt := 0
+	return utils.RetryWithBackoff(ctx, cfg, func(ctx context.Context) error {
+		attempt++
+		if attempt > 1 {
+			c.logger.WarnFields("retrying transaction after serialization failure",
+				logger.Int("attempt", attempt),
+				logger.Int("max_attempts", cfg.MaxAttempts),
+			)
+		}
+		return c.TransactionWithOptions(ctx, opts, fn)
+	})
+}
+
+// txRetryBackoff spaces out TransactionWithRetry attempts. Conflicting transactions
+// usually finish within milliseconds, so it starts lower than the default backoff.
+var txRetryBackoff = utils.BackoffConfig{
+	InitialDelay: 20 * time.Millisecond,
+	MaxDelay:     time.Second,
+	Multiplier:   2,
+	Jitter:       0.2,
+}
+
 // TxOptions represents transaction options
 type TxOptions struct {
-	ReadOnly bool
+	ReadOnly  bool
+	Isolation sql.IsolationLevel // e.g. sql.LevelSerializable; the default level when zero
 }
 
 // AutoMigrate runs auto migration for the given models
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+)
+
+// serializationError mimics a driver error with SQLSTATE 40001
+type serializationError struct{}
+
+func (serializationError) Error() string {
+	return "could not serialize access due to concurrent update"
+}
+func (serializationError) SQLState() string { return errors.SQLStateSerializationFailure }
+
+func newTestClient(t *testing.T, models ...interface{}) *Client {
+	t.Helper()
+	return &Client{db: newTestDB(t, models...), logger: empty.EmptyLogger}
+}
+
+func Test_TransactionWithRetry_Retries_Serialization_Failures(t *testing.T) {
+	client := newTestClient(t, &testAccount{})
+	ctx := context.Background()
+
+	attempts := 0
+	err := client.TransactionWithRetry(ctx, nil, 5, func(tx *gorm.DB) error {
+		attempts++
+		if err := tx.Create(&testAccount{Email: "ann@example.com"}).Error; err != nil {
+			return err
+		}
+		if attempts <= 2 {
+			return serializationError{}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	// the failed attempts were rolled back
+	var count int64
+	require.NoError(t, client.DB().Model(&testAccount{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func Test_TransactionWithRetry_Fails_Fast_On_Other_Errors(t *testing.T) {
+	client := newTestClient(t)
+
+	attempts := 0
+	err := client.TransactionWithRetry(context.Background(), nil, 5, func(tx *gorm.DB) error {
+		attempts++
+		return errors.Conflict("version mismatch")
+	})
+	assert.True(t, errors.Is(err, errors.CodeConflict), err)
+	assert.Equal(t, 1, attempts)
+}
+
+func Test_TransactionWithRetry_Gives_Up_After_Max_Attempts(t *testing.T) {
+	client := newTestClient(t)
+
+	attempts := 0
+	err := client.TransactionWithRetry(context.Background(), nil, 2, func(tx *gorm.DB) error {
+		attempts++
+		return serializationError{}
+	})
+	assert.True(t, errors.IsSerializationFailure(err), err)
+	assert.Equal(t, 2, attempts)
+}
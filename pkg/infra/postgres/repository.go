@@ -2,38 +2,143 @@ package postgres
 
 import (
 	"context"
+	stdErrors "errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 
 	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/utils"
 )
 
+// defaultBatchSize is the number of rows fetched per query by Each
+const defaultBatchSize = 100
+
+// columnNamePattern matches the column names accepted as condition keys, optionally
+// qualified with a table name ("users.email")
+var columnNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
 // Repository is a generic GORM repository implementation
 // T is the entity type, ID is the primary key type
 type Repository[T any, ID comparable] struct {
-	db *gorm.DB
+	db    *gorm.DB
+	cfg   repositoryConfig
+	hooks *repositoryHooks[T]
+}
+
+// repositoryHooks are the lifecycle hooks of a Repository, shared with the copies
+// returned by WithDB
+type repositoryHooks[T any] struct {
+	beforeCreate utils.ErrorHooks[*T]
+	afterCreate  utils.ErrorHooks[*T]
+}
+
+// defaultPrimaryKey is the primary key column used when none is configured or detected
+const defaultPrimaryKey = "id"
+
+// repositoryConfig holds the settings applied by RepositoryOption
+type repositoryConfig struct {
+	allowedColumns map[string]struct{}
+	primaryKey     string
+	compositeKey   bool // the model has a composite primary key and no WithPrimaryKey
+}
+
+// RepositoryOption configures a Repository
+type RepositoryOption func(*repositoryConfig)
+
+// WithAllowedColumns restricts the condition keys accepted by FindOne, FindAll,
+// Count, DeleteWhere, List and Each to columns. Without it, keys only have to be
+// plain (optionally table-qualified) identifiers.
+//
+// Example:
+//
+//	repo := postgres.NewRepository[User, uuid.UUID](db,
+//	    postgres.WithAllowedColumns("email", "status", "plan"))
+func WithAllowedColumns(columns ...string) RepositoryOption {
+	return func(cfg *repositoryConfig) {
+		cfg.allowedColumns = make(map[string]struct{}, len(columns))
+		for _, column := range columns {
+			cfg.allowedColumns[column] = struct{}{}
+		}
+	}
+}
+
+// WithPrimaryKey sets the column matched by the ID-based methods (FindByID,
+// UpdateColumns, Delete, SoftDelete, SoftDeleteCascade, Restore, Exists). By default
+// the model's single primary key field is used, falling back to "id". On models with
+// composite keys, where the ID-based methods fail with CodeInvalidInput, use FindOne
+// and DeleteWhere with conditions instead.
+//
+// Example:
+//
+//	repo := postgres.NewRepository[Country, string](db, postgres.WithPrimaryKey("code"))
+func WithPrimaryKey(column string) RepositoryOption {
+	return func(cfg *repositoryConfig) {
+		cfg.primaryKey = column
+	}
 }
 
 // NewRepository creates a new generic repository
-func NewRepository[T any, ID comparable](db *gorm.DB) *Repository[T, ID] {
-	return &Repository[T, ID]{
-		db: db,
+func NewRepository[T any, ID comparable](db *gorm.DB, opts ...RepositoryOption) *Repository[T, ID] {
+	var cfg repositoryConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	r := &Repository[T, ID]{
+		db:    db,
+		cfg:   cfg,
+		hooks: &repositoryHooks[T]{},
 	}
+	if r.cfg.primaryKey == "" {
+		r.cfg.primaryKey, r.cfg.compositeKey = r.detectPrimaryKey()
+	}
+	return r
 }
 
-// Create creates a new entity
+// Create creates a new entity, running the BeforeCreate hooks first (any error aborts
+// the insert) and the AfterCreate hooks once it is stored
 func (r *Repository[T, ID]) Create(ctx context.Context, entity *T) error {
-	if err := r.db.WithContext(ctx).Create(entity).Error; err != nil {
+	if err := r.hooks.beforeCreate.Fire(ctx, entity); err != nil {
+		return err
+	}
+
+	err := r.run(ctx, func(db *gorm.DB) error {
+		return db.Create(entity).Error
+	})
+	if err != nil {
 		// Check for unique constraint violation
 		if errors.IsUniqueViolation(err) {
 			return errors.AlreadyExists(r.getEntityName())
 		}
-		return errors.Wrap(err, errors.CodeDatabaseError, "failed to create entity")
+		return dbError(err, "failed to create entity")
 	}
-	return nil
+
+	return r.hooks.afterCreate.Fire(ctx, entity)
+}
+
+// BeforeCreate returns the hooks Create runs before inserting an entity, e.g. to
+// validate or default fields.
+//
+// Example:
+//
+//	repo.BeforeCreate().Add(func(ctx context.Context, user *User) error {
+//	    user.Email = strings.ToLower(user.Email)
+//	    return nil
+//	})
+func (r *Repository[T, ID]) BeforeCreate() *utils.ErrorHooks[*T] {
+	return &r.hooks.beforeCreate
+}
+
+// AfterCreate returns the hooks Create runs once an entity is stored. Their errors are
+// returned by Create, but the entity stays created unless Create ran in a transaction.
+func (r *Repository[T, ID]) AfterCreate() *utils.ErrorHooks[*T] {
+	return &r.hooks.afterCreate
 }
 
 // CreateInBatches creates multiple entities in batches
@@ -46,22 +151,32 @@ func (r *Repository[T, ID]) CreateInBatches(ctx context.Context, entities []T, b
 		batchSize = 100
 	}
 
-	if err := r.db.WithContext(ctx).CreateInBatches(entities, batchSize).Error; err != nil {
-		return errors.Wrap(err, errors.CodeDatabaseError, "failed to create entities in batches")
+	err := r.run(ctx, func(db *gorm.DB) error {
+		return db.CreateInBatches(entities, batchSize).Error
+	})
+	if err != nil {
+		return dbError(err, "failed to create entities in batches")
 	}
 	return nil
 }
 
 // FindByID finds an entity by its ID
 func (r *Repository[T, ID]) FindByID(ctx context.Context, id ID) (*T, error) {
+	if err := r.checkPrimaryKey(); err != nil {
+		return nil, err
+	}
+
 	var entity T
 	// Use explicit WHERE clause for clarity and to avoid ambiguity with GORM's primary key detection
 	// This is more explicit than First(&entity, id) and works consistently with all ID types
-	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&entity).Error; err != nil {
+	err := r.run(ctx, func(db *gorm.DB) error {
+		return db.Where(r.primaryKeyClause(), id).First(&entity).Error
+	})
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NotFound(r.getEntityName())
 		}
-		return nil, errors.Wrap(err, errors.CodeDatabaseError, "failed to find entity by id")
+		return nil, dbError(err, "failed to find entity by id")
 	}
 	return &entity, nil
 }
@@ -69,21 +184,23 @@ func (r *Repository[T, ID]) FindByID(ctx context.Context, id ID) (*T, error) {
 // FindOne finds a single entity matching the conditions
 func (r *Repository[T, ID]) FindOne(ctx context.Context, conditions map[string]interface{}) (*T, error) {
 	var entity T
-	query := r.db.WithContext(ctx)
 
 	if len(conditions) == 0 {
 		return nil, errors.BadRequest("at least one condition is required for FindOne")
 	}
 
-	for key, value := range conditions {
-		query = query.Where(fmt.Sprintf("%s = ?", key), value)
-	}
-
-	if err := query.First(&entity).Error; err != nil {
+	err := r.run(ctx, func(db *gorm.DB) error {
+		query, err := r.applyConditions(db, conditions)
+		if err != nil {
+			return err
+		}
+		return query.First(&entity).Error
+	})
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NotFound(r.getEntityName())
 		}
-		return nil, errors.Wrap(err, errors.CodeDatabaseError, "failed to find entity")
+		return nil, dbError(err, "failed to find entity")
 	}
 	return &entity, nil
 }
@@ -91,14 +208,15 @@ func (r *Repository[T, ID]) FindOne(ctx context.Context, conditions map[string]i
 // FindAll finds all entities matching the conditions
 func (r *Repository[T, ID]) FindAll(ctx context.Context, conditions map[string]interface{}) ([]T, error) {
 	var entities []T
-	query := r.db.WithContext(ctx)
-
-	for key, value := range conditions {
-		query = query.Where(fmt.Sprintf("%s = ?", key), value)
-	}
-
-	if err := query.Find(&entities).Error; err != nil {
-		return nil, errors.Wrap(err, errors.CodeDatabaseError, "failed to find entities")
+	err := r.run(ctx, func(db *gorm.DB) error {
+		query, err := r.applyConditions(db, conditions)
+		if err != nil {
+			return err
+		}
+		return query.Find(&entities).Error
+	})
+	if err != nil {
+		return nil, dbError(err, "failed to find entities")
 	}
 	return entities, nil
 }
@@ -123,48 +241,59 @@ func (r *Repository[T, ID]) List(ctx context.Context, opts *ListOptions) (*ListR
 		opts.PageSize = 100
 	}
 
-	var entities []T
-	query := r.db.WithContext(ctx)
-
-	// Apply conditions
-	for key, value := range opts.Conditions {
-		query = query.Where(fmt.Sprintf("%s = ?", key), value)
+	order, err := r.listOrder(opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// Apply custom where clause
-	if opts.Where != "" {
-		query = query.Where(opts.Where, opts.WhereArgs...)
-	}
+	var entities []T
+	var total int64
+	err = r.run(ctx, func(db *gorm.DB) error {
+		query, err := r.applyListFilters(db, opts)
+		if err != nil {
+			return err
+		}
 
-	// Apply preloads
-	for _, preload := range opts.Preloads {
-		if preload != "" {
-			query = query.Preload(preload)
+		// Count total before pagination
+		if !opts.SkipTotal {
+			countQuery := query.Session(&gorm.Session{}) // Clone query for count
+			if err := countQuery.Model(new(T)).Count(&total).Error; err != nil {
+				return dbError(err, "failed to count entities")
+			}
 		}
-	}
 
-	// Count total before pagination
-	var total int64
-	countQuery := query.Session(&gorm.Session{}) // Clone query for count
-	if err := countQuery.Model(new(T)).Count(&total).Error; err != nil {
-		return nil, errors.Wrap(err, errors.CodeDatabaseError, "failed to count entities")
-	}
+		// Apply sorting
+		if len(order.Columns) > 0 {
+			query = query.Order(order)
+		}
 
-	// Apply sorting
-	if opts.OrderBy != "" {
-		query = query.Order(opts.OrderBy)
-	} else {
-		// Default sort by created_at descending if the field exists
-		query = query.Order("created_at DESC")
-	}
+		// Apply pagination
+		offset := (opts.Page - 1) * opts.PageSize
+		limit := opts.PageSize
+		if opts.SkipTotal {
+			// Fetch one extra row to know whether a next page exists
+			limit++
+		}
+		query = query.Limit(limit).Offset(offset)
 
-	// Apply pagination
-	offset := (opts.Page - 1) * opts.PageSize
-	query = query.Limit(opts.PageSize).Offset(offset)
+		// Fetch data
+		return query.Find(&entities).Error
+	})
+	if err != nil {
+		return nil, dbError(err, "failed to list entities")
+	}
 
-	// Fetch data
-	if err := query.Find(&entities).Error; err != nil {
-		return nil, errors.Wrap(err, errors.CodeDatabaseError, "failed to list entities")
+	if opts.SkipTotal {
+		hasMore := len(entities) > opts.PageSize
+		if hasMore {
+			entities = entities[:opts.PageSize]
+		}
+		return &ListResult[T]{
+			Items:    entities,
+			Page:     opts.Page,
+			PageSize: opts.PageSize,
+			HasMore:  hasMore,
+		}, nil
 	}
 
 	totalPages := (total + int64(opts.PageSize) - 1) / int64(opts.PageSize)
@@ -178,27 +307,177 @@ func (r *Repository[T, ID]) List(ctx context.Context, opts *ListOptions) (*ListR
 		Page:       opts.Page,
 		PageSize:   opts.PageSize,
 		TotalPages: int(totalPages),
+		HasMore:    opts.Page < int(totalPages),
 	}, nil
 }
 
+// Each streams the entities matching opts to fn in batches of opts.BatchSize, so large
+// result sets (exports, backfills) are never loaded into memory at once. It honors the
+// conditions, where clause, preloads and ordering of List; Page and PageSize are ignored.
+// Iteration stops at the first error returned by fn, which is returned as-is.
+//
+// Batches use keyset pagination: each one starts after the ordering values of the last
+// row of the previous one, with the primary key as a tie-breaker, so every batch costs
+// the same on large tables and concurrent inserts or deletes never make rows repeat or
+// go missing. Ordering columns must therefore be columns of the model that are never
+// NULL.
+func (r *Repository[T, ID]) Each(ctx context.Context, opts *ListOptions, fn func(*T) error) error {
+	if opts == nil {
+		opts = &ListOptions{}
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize < 1 {
+		batchSize = defaultBatchSize
+	}
+
+	order, err := r.listOrder(opts)
+	if err != nil {
+		return err
+	}
+	keys, err := r.keysetColumns(order)
+	if err != nil {
+		return err
+	}
+
+	keyOrder := clause.OrderBy{}
+	for _, key := range keys {
+		keyOrder.Columns = append(keyOrder.Columns, clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: key.field.DBName},
+			Desc:   key.desc,
+		})
+	}
+
+	var after []interface{}
+	for {
+		// Each batch is a query of its own, so a schema-scoped iteration only holds a
+		// connection while a batch is fetched, not while fn runs
+		var batch []T
+		err := r.run(ctx, func(db *gorm.DB) error {
+			query, err := r.applyListFilters(db, opts)
+			if err != nil {
+				return err
+			}
+			query = query.Order(keyOrder)
+			if after != nil {
+				query = query.Where(keysetAfter(keys, after))
+			}
+			return query.Limit(batchSize).Find(&batch).Error
+		})
+		if err != nil {
+			return dbError(err, "failed to iterate entities")
+		}
+
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) < batchSize {
+			return nil
+		}
+
+		last := reflect.ValueOf(&batch[len(batch)-1]).Elem()
+		after = make([]interface{}, len(keys))
+		for i, key := range keys {
+			after[i], _ = key.field.ValueOf(ctx, last)
+		}
+	}
+}
+
+// keysetColumn is a column of the ordering of Each
+type keysetColumn struct {
+	field *schema.Field
+	desc  bool
+}
+
+// keysetColumns returns the columns of order followed by the primary key columns it
+// lacks, which make the ordering of Each total
+func (r *Repository[T, ID]) keysetColumns(order clause.OrderBy) ([]keysetColumn, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil || stmt.Schema == nil {
+		return nil, errors.Internal(fmt.Sprintf("failed to parse the model of %s", r.getEntityName()))
+	}
+	if len(stmt.Schema.PrimaryFields) == 0 {
+		return nil, errors.New(errors.CodeInvalidInput, fmt.Sprintf("%s has no primary key to iterate on", r.getEntityName()))
+	}
+
+	var keys []keysetColumn
+	seen := map[string]bool{}
+	for _, column := range order.Columns {
+		field := stmt.Schema.LookUpField(column.Column.Name)
+		if field == nil || field.DBName == "" {
+			return nil, errors.New(errors.CodeInvalidInput, fmt.Sprintf("cannot iterate ordered by %q, which is not a column of %s", column.Column.Name, r.getEntityName()))
+		}
+		if !seen[field.DBName] {
+			keys = append(keys, keysetColumn{field: field, desc: column.Desc})
+			seen[field.DBName] = true
+		}
+	}
+	for _, field := range stmt.Schema.PrimaryFields {
+		if !seen[field.DBName] {
+			keys = append(keys, keysetColumn{field: field})
+			seen[field.DBName] = true
+		}
+	}
+	return keys, nil
+}
+
+// keysetAfter returns the condition selecting the rows after values in the ordering of
+// keys: (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ..., with < for descending columns
+func keysetAfter(keys []keysetColumn, values []interface{}) clause.Expression {
+	alternatives := make([]clause.Expression, len(keys))
+	for i, key := range keys {
+		conditions := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, clause.Eq{Column: keysetColumnOf(keys[j]), Value: values[j]})
+		}
+		if key.desc {
+			conditions = append(conditions, clause.Lt{Column: keysetColumnOf(key), Value: values[i]})
+		} else {
+			conditions = append(conditions, clause.Gt{Column: keysetColumnOf(key), Value: values[i]})
+		}
+		alternatives[i] = clause.And(conditions...)
+	}
+	return clause.Or(alternatives...)
+}
+
+// keysetColumnOf returns the column of key in the current table
+func keysetColumnOf(key keysetColumn) clause.Column {
+	return clause.Column{Table: clause.CurrentTable, Name: key.field.DBName}
+}
+
 // Update updates an entity
 func (r *Repository[T, ID]) Update(ctx context.Context, entity *T) error {
-	if err := r.db.WithContext(ctx).Save(entity).Error; err != nil {
-		return errors.Wrap(err, errors.CodeDatabaseError, "failed to update entity")
+	err := r.run(ctx, func(db *gorm.DB) error {
+		return db.Save(entity).Error
+	})
+	if err != nil {
+		return dbError(err, "failed to update entity")
 	}
 	return nil
 }
 
 // UpdateColumns updates specific columns of an entity
 func (r *Repository[T, ID]) UpdateColumns(ctx context.Context, id ID, columns map[string]interface{}) error {
+	if err := r.checkPrimaryKey(); err != nil {
+		return err
+	}
+
 	var entity T
-	result := r.db.WithContext(ctx).Model(&entity).Where("id = ?", id).Updates(columns)
+	var rowsAffected int64
+	err := r.run(ctx, func(db *gorm.DB) error {
+		result := db.Model(&entity).Where(r.primaryKeyClause(), id).Updates(columns)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
 
-	if result.Error != nil {
-		return errors.Wrap(result.Error, errors.CodeDatabaseError, "failed to update columns")
+	if err != nil {
+		return dbError(err, "failed to update columns")
 	}
 
-	if result.RowsAffected == 0 {
+	if rowsAffected == 0 {
 		return errors.NotFound(r.getEntityName())
 	}
 
@@ -207,15 +486,24 @@ func (r *Repository[T, ID]) UpdateColumns(ctx context.Context, id ID, columns ma
 
 // Delete deletes an entity by ID
 func (r *Repository[T, ID]) Delete(ctx context.Context, id ID) error {
+	if err := r.checkPrimaryKey(); err != nil {
+		return err
+	}
+
 	var entity T
 	// Use explicit WHERE clause to avoid SQL parsing issues with UUID types
-	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&entity)
+	var rowsAffected int64
+	err := r.run(ctx, func(db *gorm.DB) error {
+		result := db.Where(r.primaryKeyClause(), id).Delete(&entity)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
 
-	if result.Error != nil {
-		return errors.Wrap(result.Error, errors.CodeDatabaseError, "failed to delete entity")
+	if err != nil {
+		return dbError(err, "failed to delete entity")
 	}
 
-	if result.RowsAffected == 0 {
+	if rowsAffected == 0 {
 		return errors.NotFound(r.getEntityName())
 	}
 
@@ -225,47 +513,122 @@ func (r *Repository[T, ID]) Delete(ctx context.Context, id ID) error {
 // DeleteWhere deletes entities matching conditions
 func (r *Repository[T, ID]) DeleteWhere(ctx context.Context, conditions map[string]interface{}) (int64, error) {
 	var entity T
-	query := r.db.WithContext(ctx).Model(&entity)
-
-	for key, value := range conditions {
-		query = query.Where(fmt.Sprintf("%s = ?", key), value)
-	}
-
-	result := query.Delete(&entity)
-	if result.Error != nil {
-		return 0, errors.Wrap(result.Error, errors.CodeDatabaseError, "failed to delete entities")
+	var rowsAffected int64
+	err := r.run(ctx, func(db *gorm.DB) error {
+		query, err := r.applyConditions(db.Model(&entity), conditions)
+		if err != nil {
+			return err
+		}
+		result := query.Delete(&entity)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, dbError(err, "failed to delete entities")
 	}
 
-	return result.RowsAffected, nil
+	return rowsAffected, nil
 }
 
 // SoftDelete soft deletes an entity by ID (requires deleted_at column)
 func (r *Repository[T, ID]) SoftDelete(ctx context.Context, id ID) error {
+	if err := r.checkPrimaryKey(); err != nil {
+		return err
+	}
+
 	var entity T
 	// Use explicit WHERE clause to avoid SQL parsing issues with UUID types
-	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&entity)
+	var rowsAffected int64
+	err := r.run(ctx, func(db *gorm.DB) error {
+		result := db.Where(r.primaryKeyClause(), id).Delete(&entity)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
 
-	if result.Error != nil {
-		return errors.Wrap(result.Error, errors.CodeDatabaseError, "failed to soft delete entity")
+	if err != nil {
+		return dbError(err, "failed to soft delete entity")
 	}
 
-	if result.RowsAffected == 0 {
+	if rowsAffected == 0 {
 		return errors.NotFound(r.getEntityName())
 	}
 
 	return nil
 }
 
+// SoftDeleteCascade soft deletes an entity by ID together with the rows of its named
+// has-one/has-many relations, in a single transaction. Related models need a deleted_at
+// column to be soft deleted; otherwise their rows are deleted permanently.
+//
+// Example:
+//
+//	// type Order struct { postgres.BaseModel; Items []OrderItem }
+//	err := orderRepo.SoftDeleteCascade(ctx, orderID, []string{"Items"})
+func (r *Repository[T, ID]) SoftDeleteCascade(ctx context.Context, id ID, relations []string) error {
+	if err := r.checkPrimaryKey(); err != nil {
+		return err
+	}
+
+	if err := r.validateCascadeRelations(relations); err != nil {
+		return err
+	}
+
+	return r.Transaction(ctx, func(tx *gorm.DB) error {
+		var entity T
+		if err := tx.Where(r.primaryKeyClause(), id).First(&entity).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NotFound(r.getEntityName())
+			}
+			return dbError(err, "failed to find entity by id")
+		}
+
+		// Selecting associations makes GORM delete their rows before the entity
+		if len(relations) > 0 {
+			tx = tx.Select(relations)
+		}
+		if err := tx.Delete(&entity).Error; err != nil {
+			return dbError(err, "failed to soft delete entity")
+		}
+		return nil
+	})
+}
+
+// validateCascadeRelations rejects relations that are not has-one/has-many
+// relationships of the model
+func (r *Repository[T, ID]) validateCascadeRelations(relations []string) error {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil || stmt.Schema == nil {
+		return errors.Wrap(err, errors.CodeInternal, fmt.Sprintf("failed to parse %s schema", r.getEntityName()))
+	}
+
+	for _, name := range relations {
+		rel, ok := stmt.Schema.Relationships.Relations[name]
+		if !ok || (rel.Type != schema.HasMany && rel.Type != schema.HasOne) {
+			return errors.BadRequest(fmt.Sprintf("%q is not a has-one or has-many relation of %s", name, r.getEntityName()))
+		}
+	}
+	return nil
+}
+
 // Restore restores a soft deleted entity
 func (r *Repository[T, ID]) Restore(ctx context.Context, id ID) error {
+	if err := r.checkPrimaryKey(); err != nil {
+		return err
+	}
+
 	var entity T
-	result := r.db.WithContext(ctx).Model(&entity).Unscoped().Where("id = ?", id).Update("deleted_at", nil)
+	var rowsAffected int64
+	err := r.run(ctx, func(db *gorm.DB) error {
+		result := db.Model(&entity).Unscoped().Where(r.primaryKeyClause(), id).Update("deleted_at", nil)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
 
-	if result.Error != nil {
-		return errors.Wrap(result.Error, errors.CodeDatabaseError, "failed to restore entity")
+	if err != nil {
+		return dbError(err, "failed to restore entity")
 	}
 
-	if result.RowsAffected == 0 {
+	if rowsAffected == 0 {
 		return errors.NotFound(r.getEntityName())
 	}
 
@@ -274,11 +637,18 @@ func (r *Repository[T, ID]) Restore(ctx context.Context, id ID) error {
 
 // Exists checks if an entity exists by ID
 func (r *Repository[T, ID]) Exists(ctx context.Context, id ID) (bool, error) {
+	if err := r.checkPrimaryKey(); err != nil {
+		return false, err
+	}
+
 	var count int64
 	var entity T
 
-	if err := r.db.WithContext(ctx).Model(&entity).Where("id = ?", id).Count(&count).Error; err != nil {
-		return false, errors.Wrap(err, errors.CodeDatabaseError, "failed to check entity existence")
+	err := r.run(ctx, func(db *gorm.DB) error {
+		return db.Model(&entity).Where(r.primaryKeyClause(), id).Count(&count).Error
+	})
+	if err != nil {
+		return false, dbError(err, "failed to check entity existence")
 	}
 
 	return count > 0, nil
@@ -288,14 +658,15 @@ func (r *Repository[T, ID]) Exists(ctx context.Context, id ID) (bool, error) {
 func (r *Repository[T, ID]) Count(ctx context.Context, conditions map[string]interface{}) (int64, error) {
 	var count int64
 	var entity T
-	query := r.db.WithContext(ctx).Model(&entity)
-
-	for key, value := range conditions {
-		query = query.Where(fmt.Sprintf("%s = ?", key), value)
-	}
-
-	if err := query.Count(&count).Error; err != nil {
-		return 0, errors.Wrap(err, errors.CodeDatabaseError, "failed to count entities")
+	err := r.run(ctx, func(db *gorm.DB) error {
+		query, err := r.applyConditions(db.Model(&entity), conditions)
+		if err != nil {
+			return err
+		}
+		return query.Count(&count).Error
+	})
+	if err != nil {
+		return 0, dbError(err, "failed to count entities")
 	}
 
 	return count, nil
@@ -303,48 +674,505 @@ func (r *Repository[T, ID]) Count(ctx context.Context, conditions map[string]int
 
 // Upsert creates or updates an entity (requires unique constraints)
 func (r *Repository[T, ID]) Upsert(ctx context.Context, entity *T, conflictColumns []string) error {
+	onConflict, err := upsertClause(conflictColumns, nil)
+	if err != nil {
+		return err
+	}
+
+	err = r.run(ctx, func(db *gorm.DB) error {
+		return db.Clauses(onConflict).Create(entity).Error
+	})
+	if err != nil {
+		return dbError(err, "failed to upsert entity")
+	}
+
+	return nil
+}
+
+// UpsertMany creates or updates entities with a single INSERT ... ON CONFLICT statement
+// (requires a unique constraint on conflictColumns). On conflict only updateColumns are
+// overwritten; when updateColumns is empty every column is. Include "updated_at" in
+// updateColumns to keep it current.
+//
+// Example:
+//
+//	err := productRepo.UpsertMany(ctx, products, []string{"sku"}, []string{"name", "price", "updated_at"})
+func (r *Repository[T, ID]) UpsertMany(ctx context.Context, entities []T, conflictColumns []string, updateColumns []string) error {
+	return r.UpsertManyInBatches(ctx, entities, conflictColumns, updateColumns, len(entities))
+}
+
+// UpsertManyInBatches is UpsertMany issuing one statement per batchSize entities, for
+// sets too large for a single statement. The batches run in one transaction.
+func (r *Repository[T, ID]) UpsertManyInBatches(ctx context.Context, entities []T, conflictColumns []string, updateColumns []string, batchSize int) error {
+	onConflict, err := upsertClause(conflictColumns, updateColumns)
+	if err != nil {
+		return err
+	}
+
+	if len(entities) == 0 {
+		return nil
+	}
+
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	// The clients skip GORM's default transaction, so open one for the batches. Within
+	// the transaction of ctx this is a savepoint.
+	err = r.run(ctx, func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			return tx.Clauses(onConflict).CreateInBatches(entities, batchSize).Error
+		})
+	})
+	if err != nil {
+		return dbError(err, "failed to upsert entities")
+	}
+
+	return nil
+}
+
+// upsertClause builds the ON CONFLICT clause of the upsert methods
+func upsertClause(conflictColumns []string, updateColumns []string) (clause.OnConflict, error) {
 	if len(conflictColumns) == 0 {
-		return errors.BadRequest("conflict columns must be specified for upsert")
+		return clause.OnConflict{}, errors.BadRequest("conflict columns must be specified for upsert")
 	}
 
 	columns := make([]clause.Column, len(conflictColumns))
 	for i, col := range conflictColumns {
+		if col == "" {
+			return clause.OnConflict{}, errors.BadRequest("conflict columns must not be empty")
+		}
 		columns[i] = clause.Column{Name: col}
 	}
 
-	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns:   columns,
-		UpdateAll: true,
-	}).Create(entity).Error; err != nil {
-		return errors.Wrap(err, errors.CodeDatabaseError, "failed to upsert entity")
+	onConflict := clause.OnConflict{Columns: columns}
+	if len(updateColumns) == 0 {
+		onConflict.UpdateAll = true
+	} else {
+		onConflict.DoUpdates = clause.AssignmentColumns(updateColumns)
 	}
 
-	return nil
+	return onConflict, nil
 }
 
-// Transaction executes a function within a transaction
-func (r *Repository[T, ID]) Transaction(ctx context.Context, fn func(*gorm.DB) error) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := fn(tx); err != nil {
-			if _, ok := errors.As(err); ok {
+// FindOneOrCreate returns the entity matching the conditions, creating it from defaults
+// when none exists. The condition values are assigned onto defaults before inserting,
+// and the returned bool reports whether a new row was created.
+//
+// The insert uses ON CONFLICT DO NOTHING inside a transaction, so a concurrent writer
+// creating the same row (requires a unique constraint on the condition columns) never
+// produces a duplicate: the losing caller re-reads and returns the winner's row.
+func (r *Repository[T, ID]) FindOneOrCreate(ctx context.Context, conditions map[string]interface{}, defaults *T) (*T, bool, error) {
+	if len(conditions) == 0 {
+		return nil, false, errors.BadRequest("at least one condition is required for FindOneOrCreate")
+	}
+	if defaults == nil {
+		defaults = new(T)
+	}
+	if err := r.assignColumns(ctx, defaults, conditions); err != nil {
+		return nil, false, err
+	}
+
+	var entity *T
+	created := false
+	err := r.Transaction(ctx, func(tx *gorm.DB) error {
+		ctx := ContextWithTx(ctx, tx)
+		txRepo := r.WithDB(tx)
+
+		found, err := txRepo.FindOne(ctx, conditions)
+		if err == nil {
+			entity = found
+			return nil
+		}
+		if !errors.Is(err, errors.CodeNotFound) {
+			return err
+		}
+
+		result := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(defaults)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Lost the race against a concurrent insert, read the winner's row
+			entity, err = txRepo.FindOne(ctx, conditions)
+			return err
+		}
+
+		entity = defaults
+		created = true
+		return nil
+	})
+
+	if err != nil && errors.IsUniqueViolation(err) {
+		// Drivers without ON CONFLICT support surface the race as a unique violation
+		entity, err = r.FindOne(ctx, conditions)
+		return entity, false, err
+	}
+	if err != nil {
+		if _, ok := errors.As(err); ok {
+			return nil, false, err
+		}
+		return nil, false, dbError(err, "failed to find or create entity")
+	}
+
+	return entity, created, nil
+}
+
+// UpdateOrCreate updates the entity matching the conditions with values, or creates a
+// new one from the conditions and values when none exists. The returned bool reports
+// whether a new row was created. Races are handled the same way as FindOneOrCreate.
+func (r *Repository[T, ID]) UpdateOrCreate(ctx context.Context, conditions map[string]interface{}, values map[string]interface{}) (*T, bool, error) {
+	if len(conditions) == 0 {
+		return nil, false, errors.BadRequest("at least one condition is required for UpdateOrCreate")
+	}
+
+	entity := new(T)
+	if err := r.assignColumns(ctx, entity, conditions); err != nil {
+		return nil, false, err
+	}
+	if err := r.assignColumns(ctx, entity, values); err != nil {
+		return nil, false, err
+	}
+
+	update := func(txRepo *Repository[T, ID], found *T) (*T, error) {
+		if len(values) == 0 {
+			return found, nil
+		}
+		err := txRepo.run(ctx, func(db *gorm.DB) error {
+			return db.Model(found).Updates(values).Error
+		})
+		if err != nil {
+			return nil, dbError(err, "failed to update entity")
+		}
+		return txRepo.FindOne(ctx, conditions)
+	}
+
+	var result *T
+	created := false
+	err := r.Transaction(ctx, func(tx *gorm.DB) error {
+		ctx := ContextWithTx(ctx, tx)
+		txRepo := r.WithDB(tx)
+
+		found, err := txRepo.FindOne(ctx, conditions)
+		if err == nil {
+			result, err = update(txRepo, found)
+			return err
+		}
+		if !errors.Is(err, errors.CodeNotFound) {
+			return err
+		}
+
+		insert := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(entity)
+		if insert.Error != nil {
+			return insert.Error
+		}
+		if insert.RowsAffected == 0 {
+			// Lost the race against a concurrent insert, update the winner's row
+			if found, err = txRepo.FindOne(ctx, conditions); err != nil {
 				return err
 			}
-			return errors.Wrap(err, errors.CodeDatabaseError, "transaction failed")
+			result, err = update(txRepo, found)
+			return err
 		}
+
+		result = entity
+		created = true
 		return nil
 	})
+
+	if err != nil && errors.IsUniqueViolation(err) {
+		found, err := r.FindOne(ctx, conditions)
+		if err != nil {
+			return nil, false, err
+		}
+		result, err = update(r, found)
+		return result, false, err
+	}
+	if err != nil {
+		if _, ok := errors.As(err); ok {
+			return nil, false, err
+		}
+		return nil, false, dbError(err, "failed to update or create entity")
+	}
+
+	return result, created, nil
 }
 
-// Query returns the underlying GORM DB for custom queries
+// assignColumns sets the given column values onto entity using the GORM schema
+func (r *Repository[T, ID]) assignColumns(ctx context.Context, entity *T, columns map[string]interface{}) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(entity); err != nil {
+		return errors.Wrap(err, errors.CodeDatabaseError, "failed to parse entity schema")
+	}
+
+	value := reflect.ValueOf(entity).Elem()
+	for column, columnValue := range columns {
+		field := stmt.Schema.LookUpField(column)
+		if field == nil {
+			return errors.BadRequest(fmt.Sprintf("unknown column %q for %s", column, r.getEntityName()))
+		}
+		if err := field.Set(ctx, value, columnValue); err != nil {
+			return errors.Wrap(err, errors.CodeInvalidInput, fmt.Sprintf("invalid value for column %q", column))
+		}
+	}
+	return nil
+}
+
+// Transaction executes a function within a transaction
+func (r *Repository[T, ID]) Transaction(ctx context.Context, fn func(*gorm.DB) error) error {
+	return r.run(ctx, func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := fn(tx); err != nil {
+				if _, ok := errors.As(err); ok {
+					return err
+				}
+				return dbError(err, "transaction failed")
+			}
+			return nil
+		})
+	})
+}
+
+// Query returns the underlying GORM DB for custom queries, or the transaction carried
+// by ctx (see ContextWithTx). With a schema in ctx (see ContextWithSchema) it requires
+// that transaction, since the search_path only holds within one: use Client.WithSchema
+// for schema-scoped queries outside of it.
 func (r *Repository[T, ID]) Query(ctx context.Context) *gorm.DB {
+	return r.conn(ctx)
+}
+
+// conn returns the transaction carried by ctx, falling back to the repository's DB,
+// scoped to the schema of ctx, if any
+func (r *Repository[T, ID]) conn(ctx context.Context) *gorm.DB {
+	if scope, ok := ctx.Value(schemaContextKey{}).(*schemaScope); ok {
+		return scope.session(ctx, r.db)
+	}
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.WithContext(ctx)
+	}
 	return r.db.WithContext(ctx)
 }
 
+// run runs fn with the transaction carried by ctx, falling back to the repository's
+// DB, so that every method joins the caller's transaction. With a schema in ctx (see
+// ContextWithSchema), fn runs scoped to it.
+func (r *Repository[T, ID]) run(ctx context.Context, fn func(db *gorm.DB) error) error {
+	if scope, ok := ctx.Value(schemaContextKey{}).(*schemaScope); ok {
+		return scope.run(ctx, r.db, fn)
+	}
+	return fn(r.conn(ctx))
+}
+
 // WithDB returns a new repository instance with a different DB (useful for transactions)
 func (r *Repository[T, ID]) WithDB(db *gorm.DB) *Repository[T, ID] {
 	return &Repository[T, ID]{
-		db: db,
+		db:    db,
+		cfg:   r.cfg,
+		hooks: r.hooks,
+	}
+}
+
+// dbError wraps a database error with the code classified by errors.ClassifyDBError,
+// so constraint violations surface as e.g. CodeDuplicateKey instead of CodeDatabaseError.
+// Queries interrupted by their context become CodeTimeout or CodeCanceled.
+func dbError(err error, message string) *errors.AppError {
+	if appErr, ok := errors.As(err); ok {
+		// Already classified, e.g. a validation error returned within a query
+		return appErr
+	}
+	if code, ok := isContextError(err); ok {
+		return errors.Wrap(err, code, message)
+	}
+	return errors.Wrap(err, errors.ClassifyDBError(err), message)
+}
+
+// isContextError returns the error code for a query stopped by its context: a
+// deadline is a timeout, a cancellation means the client went away
+func isContextError(err error) (errors.ErrorCode, bool) {
+	switch {
+	case stdErrors.Is(err, context.DeadlineExceeded):
+		return errors.CodeTimeout, true
+	case stdErrors.Is(err, context.Canceled):
+		return errors.CodeCanceled, true
 	}
+	return "", false
+}
+
+// primaryKeyClause returns the WHERE clause matching a single entity by ID
+func (r *Repository[T, ID]) primaryKeyClause() string {
+	return fmt.Sprintf("%s = ?", r.cfg.primaryKey)
+}
+
+// checkPrimaryKey returns a CodeInvalidInput error when the model has a composite
+// primary key, which the single ID of the ID-based methods can't identify
+func (r *Repository[T, ID]) checkPrimaryKey() error {
+	if r.cfg.compositeKey {
+		return errors.New(errors.CodeInvalidInput,
+			fmt.Sprintf("%s has a composite primary key, use FindOne or WithPrimaryKey", r.getEntityName()))
+	}
+	return nil
+}
+
+// detectPrimaryKey returns the column of the model's single primary key field, or
+// defaultPrimaryKey when the model has none or can't be parsed. composite reports that
+// the model has several primary key fields.
+func (r *Repository[T, ID]) detectPrimaryKey() (column string, composite bool) {
+	if r.db == nil {
+		return defaultPrimaryKey, false
+	}
+
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil || stmt.Schema == nil {
+		return defaultPrimaryKey, false
+	}
+	if len(stmt.Schema.PrimaryFields) > 1 {
+		return defaultPrimaryKey, true
+	}
+	if len(stmt.Schema.PrimaryFields) == 1 && stmt.Schema.PrimaryFields[0].DBName != "" {
+		return stmt.Schema.PrimaryFields[0].DBName, false
+	}
+	return defaultPrimaryKey, false
+}
+
+// applyConditions adds an equality clause per condition. Keys are interpolated into
+// the SQL, so keys that are not valid column names, or not allowed by
+// WithAllowedColumns, are rejected with CodeBadRequest.
+func (r *Repository[T, ID]) applyConditions(query *gorm.DB, conditions map[string]interface{}) (*gorm.DB, error) {
+	for key, value := range conditions {
+		if err := r.validateColumn(key); err != nil {
+			return nil, err
+		}
+		query = query.Where(fmt.Sprintf("%s = ?", key), value)
+	}
+	return query, nil
+}
+
+// validateColumn reports whether column may be used as a condition key
+func (r *Repository[T, ID]) validateColumn(column string) error {
+	if r.cfg.allowedColumns != nil {
+		if _, ok := r.cfg.allowedColumns[column]; !ok {
+			return errors.BadRequest(fmt.Sprintf("column %q is not allowed for %s", column, r.getEntityName()))
+		}
+		return nil
+	}
+	if !columnNamePattern.MatchString(column) {
+		return errors.BadRequest(fmt.Sprintf("invalid column name %q", column))
+	}
+	return nil
+}
+
+// applyListFilters applies the conditions, custom where clause and preloads of opts
+func (r *Repository[T, ID]) applyListFilters(query *gorm.DB, opts *ListOptions) (*gorm.DB, error) {
+	// Apply conditions
+	query, err := r.applyConditions(query, opts.Conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply custom where clause
+	if opts.Where != "" {
+		query = query.Where(opts.Where, opts.WhereArgs...)
+	}
+
+	// Apply preloads
+	for _, preload := range opts.Preloads {
+		if preload != "" {
+			query = query.Preload(preload)
+		}
+	}
+
+	return query, nil
+}
+
+// listOrder returns the ordering of opts.OrderBy, or the default ordering when it is
+// empty. OrderBy often comes from the query string, so it is parsed rather than
+// passed to SQL: comma-separated "column [asc|desc]" terms naming columns of the
+// model (or the allowed columns) give a CodeInvalidInput error otherwise.
+func (r *Repository[T, ID]) listOrder(opts *ListOptions) (clause.OrderBy, error) {
+	if opts.OrderBy == "" {
+		if order, ok := r.defaultOrder(); ok {
+			// Default sort by created_at descending, falling back to the primary key
+			return clause.OrderBy{Columns: []clause.OrderByColumn{order}}, nil
+		}
+		return clause.OrderBy{}, nil
+	}
+
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		stmt.Schema = nil
+	}
+
+	var order clause.OrderBy
+	for _, term := range strings.Split(opts.OrderBy, ",") {
+		invalid := errors.New(errors.CodeInvalidInput, fmt.Sprintf("invalid order by %q", strings.TrimSpace(term)))
+
+		parts := strings.Fields(term)
+		if len(parts) == 0 || len(parts) > 2 || r.validateColumn(parts[0]) != nil {
+			return clause.OrderBy{}, invalid
+		}
+
+		column := parts[0]
+		if r.cfg.allowedColumns == nil && stmt.Schema != nil && !strings.Contains(column, ".") {
+			field := stmt.Schema.LookUpField(column)
+			if field == nil || field.DBName == "" {
+				return clause.OrderBy{}, invalid
+			}
+			column = field.DBName
+		}
+
+		desc := false
+		if len(parts) == 2 {
+			switch strings.ToLower(parts[1]) {
+			case "asc":
+			case "desc":
+				desc = true
+			default:
+				return clause.OrderBy{}, invalid
+			}
+		}
+
+		order.Columns = append(order.Columns, clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc})
+	}
+	return order, nil
+}
+
+// defaultOrder returns the default List ordering. It uses created_at DESC when the
+// model has that column and falls back to the primary key otherwise, so tables
+// without audit timestamps can still be listed.
+func (r *Repository[T, ID]) defaultOrder() (clause.OrderByColumn, bool) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil || stmt.Schema == nil {
+		return clause.OrderByColumn{}, false
+	}
+
+	if field := stmt.Schema.LookUpField("created_at"); field != nil && field.DBName != "" {
+		return clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName},
+			Desc:   true,
+		}, true
+	}
+
+	return r.primaryKeyOrder()
+}
+
+// primaryKeyOrder returns an ascending ordering on the model's primary key
+func (r *Repository[T, ID]) primaryKeyOrder() (clause.OrderByColumn, bool) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil || stmt.Schema == nil {
+		return clause.OrderByColumn{}, false
+	}
+
+	if field := stmt.Schema.PrioritizedPrimaryField; field != nil {
+		return clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName},
+		}, true
+	}
+
+	return clause.OrderByColumn{}, false
 }
 
 // getEntityName returns the name of the entity type
@@ -361,11 +1189,17 @@ func (r *Repository[T, ID]) getEntityName() string {
 type ListOptions struct {
 	Page       int                    // Current page (1-based)
 	PageSize   int                    // Items per page
-	OrderBy    string                 // Order by clause (e.g., "created_at DESC")
+	OrderBy    string                 // Comma-separated "column [asc|desc]" terms (e.g., "created_at DESC")
 	Conditions map[string]interface{} // Simple equality conditions
 	Where      string                 // Custom where clause
 	WhereArgs  []interface{}          // Arguments for custom where clause
 	Preloads   []string               // Relations to preload
+	BatchSize  int                    // Rows fetched per query by Each (default 100)
+
+	// SkipTotal skips the COUNT query, e.g. for infinite scroll. List then fetches
+	// PageSize+1 rows to set HasMore and leaves Total and TotalPages zeroed.
+	// Cheaper on large tables, but clients can't show page numbers or a total.
+	SkipTotal bool
 }
 
 // ListResult represents the result of a list operation
@@ -375,11 +1209,12 @@ type ListResult[T any] struct {
 	Page       int   // Current page
 	PageSize   int   // Items per page
 	TotalPages int   // Total number of pages
+	HasMore    bool  // Whether a next page exists (the only indicator with SkipTotal)
 }
 
 // HasNextPage returns true if there are more pages
 func (r *ListResult[T]) HasNextPage() bool {
-	return r.Page < r.TotalPages
+	return r.HasMore || r.Page < r.TotalPages
 }
 
 // HasPrevPage returns true if there is a previous page
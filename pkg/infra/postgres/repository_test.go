@@ -0,0 +1,710 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+type testAuditedEntity struct {
+	BaseModel
+	Name string
+}
+
+type testAccount struct {
+	BaseModel
+	Email string `gorm:"uniqueIndex;not null"`
+	Name  string
+	Plan  string
+}
+
+type testPlainEntity struct {
+	Code string `gorm:"primaryKey"`
+	Name string
+}
+
+// newTestDB opens an in-memory SQLite database migrated with the given models.
+// The repository only relies on portable GORM features, so SQLite is enough for
+// exercising it without a PostgreSQL server.
+func newTestDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// a single connection keeps every query on the same in-memory database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	require.NoError(t, db.AutoMigrate(models...))
+	return db
+}
+
+func Test_List_Orders_By_Created_At_When_Present(t *testing.T) {
+	db := newTestDB(t, &testAuditedEntity{})
+	repo := NewRepository[testAuditedEntity, uint](db)
+	ctx := context.Background()
+
+	for _, name := range []string{"first", "second", "third"} {
+		require.NoError(t, repo.Create(ctx, &testAuditedEntity{Name: name}))
+	}
+
+	stmt := db.Session(&gorm.Session{DryRun: true}).Order(mustDefaultOrder(t, repo)).Find(&[]testAuditedEntity{}).Statement
+	assert.Contains(t, stmt.SQL.String(), "ORDER BY `test_audited_entities`.`created_at` DESC")
+
+	result, err := repo.List(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.Total)
+}
+
+func Test_List_Falls_Back_To_Primary_Key_Without_Created_At(t *testing.T) {
+	db := newTestDB(t, &testPlainEntity{})
+	repo := NewRepository[testPlainEntity, string](db)
+	ctx := context.Background()
+
+	for _, code := range []string{"c", "a", "b"} {
+		require.NoError(t, repo.Create(ctx, &testPlainEntity{Code: code, Name: code}))
+	}
+
+	result, err := repo.List(ctx, &ListOptions{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 3)
+	assert.Equal(t, []string{"a", "b", "c"},
+		[]string{result.Items[0].Code, result.Items[1].Code, result.Items[2].Code})
+}
+
+func mustDefaultOrder[T any, ID comparable](t *testing.T, repo *Repository[T, ID]) interface{} {
+	t.Helper()
+	order, ok := repo.defaultOrder()
+	require.True(t, ok)
+	return order
+}
+
+func Test_FindOneOrCreate(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+	conditions := map[string]interface{}{"email": "john@example.com"}
+
+	account, created, err := repo.FindOneOrCreate(ctx, conditions, &testAccount{Name: "John"})
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "john@example.com", account.Email)
+	assert.Equal(t, "John", account.Name)
+
+	again, created, err := repo.FindOneOrCreate(ctx, conditions, &testAccount{Name: "Other"})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, account.ID, again.ID)
+	assert.Equal(t, "John", again.Name)
+
+	_, _, err = repo.FindOneOrCreate(ctx, nil, nil)
+	assert.Error(t, err)
+}
+
+func Test_FindOneOrCreate_Concurrent_Callers_Create_One_Row(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+
+	const workers = 10
+	var createdCount int32
+	ids := make([]uint, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			account, created, err := repo.FindOneOrCreate(ctx, map[string]interface{}{"email": "race@example.com"}, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if created {
+				atomic.AddInt32(&createdCount, 1)
+			}
+			ids[i] = account.ID
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), createdCount)
+	for _, id := range ids {
+		assert.Equal(t, ids[0], id)
+	}
+
+	count, err := repo.Count(ctx, map[string]interface{}{"email": "race@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func Test_FindOneOrCreate_Recovers_From_Lost_Insert_Race(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+	simulateConcurrentInsert(t, db, "INSERT INTO test_accounts (email, name) VALUES ('lost@example.com', 'Winner')")
+
+	account, created, err := repo.FindOneOrCreate(ctx, map[string]interface{}{"email": "lost@example.com"}, &testAccount{Name: "Loser"})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "Winner", account.Name)
+}
+
+func Test_UpdateOrCreate(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+	conditions := map[string]interface{}{"email": "jane@example.com"}
+
+	account, created, err := repo.UpdateOrCreate(ctx, conditions, map[string]interface{}{"plan": "free"})
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "free", account.Plan)
+
+	updated, created, err := repo.UpdateOrCreate(ctx, conditions, map[string]interface{}{"plan": "pro"})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, account.ID, updated.ID)
+	assert.Equal(t, "pro", updated.Plan)
+
+	// the losing side of an insert race updates the winner's row
+	simulateConcurrentInsert(t, db, "INSERT INTO test_accounts (email, plan) VALUES ('race@example.com', 'free')")
+	raced, created, err := repo.UpdateOrCreate(ctx, map[string]interface{}{"email": "race@example.com"}, map[string]interface{}{"plan": "team"})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "team", raced.Plan)
+
+	_, _, err = repo.UpdateOrCreate(ctx, map[string]interface{}{"unknown": 1}, nil)
+	assert.Error(t, err)
+}
+
+// simulateConcurrentInsert runs the given insert right before the next GORM create,
+// on the same connection, as if another writer had won the race
+func simulateConcurrentInsert(t *testing.T, db *gorm.DB, insertSQL string) {
+	t.Helper()
+	var fired int32
+	require.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:concurrent_insert", func(tx *gorm.DB) {
+		if atomic.CompareAndSwapInt32(&fired, 0, 1) {
+			require.NoError(t, tx.Session(&gorm.Session{NewDB: true}).Exec(insertSQL).Error)
+		}
+	}))
+	t.Cleanup(func() { _ = db.Callback().Create().Remove("test:concurrent_insert") })
+}
+
+func Test_Each_Streams_Rows_In_Batches(t *testing.T) {
+	db := newTestDB(t, &testAuditedEntity{})
+	repo := NewRepository[testAuditedEntity, uint](db)
+	ctx := context.Background()
+
+	entities := make([]testAuditedEntity, 250)
+	for i := range entities {
+		entities[i].Name = fmt.Sprintf("entity-%03d", i)
+	}
+	require.NoError(t, repo.CreateInBatches(ctx, entities, 100))
+
+	var queries int
+	require.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:count_queries", func(*gorm.DB) {
+		queries++
+	}))
+
+	var processed int
+	var previous string
+	err := repo.Each(ctx, &ListOptions{OrderBy: "name ASC", BatchSize: 100}, func(entity *testAuditedEntity) error {
+		assert.Greater(t, entity.Name, previous)
+		previous = entity.Name
+		processed++
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 250, processed)
+	assert.Equal(t, 3, queries)
+}
+
+func Test_Each_Honors_Conditions_And_Stops_On_Error(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		plan := "free"
+		if i%2 == 0 {
+			plan = "pro"
+		}
+		require.NoError(t, repo.Create(ctx, &testAccount{Email: fmt.Sprintf("user%d@example.com", i), Plan: plan}))
+	}
+
+	var pro int
+	err := repo.Each(ctx, &ListOptions{Conditions: map[string]interface{}{"plan": "pro"}, BatchSize: 2}, func(account *testAccount) error {
+		assert.Equal(t, "pro", account.Plan)
+		pro++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, pro)
+
+	stop := errors.Conflict("stop")
+	var seen int
+	err = repo.Each(ctx, &ListOptions{BatchSize: 3}, func(*testAccount) error {
+		seen++
+		if seen == 4 {
+			return stop
+		}
+		return nil
+	})
+	assert.Same(t, stop, err)
+	assert.Equal(t, 4, seen)
+}
+
+func Test_Each_Does_Not_Skip_Rows_When_Rows_Are_Deleted(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		plan := []string{"free", "pro", "team"}[i%3]
+		require.NoError(t, repo.Create(ctx, &testAccount{Email: fmt.Sprintf("user%d@example.com", i), Plan: plan}))
+	}
+
+	// Deleting each row once processed, as a cleanup job would, shifts the offsets
+	seen := map[string]int{}
+	var plans []string
+	err := repo.Each(ctx, &ListOptions{OrderBy: "plan desc", BatchSize: 3}, func(account *testAccount) error {
+		seen[account.Email]++
+		plans = append(plans, account.Plan)
+		return db.Unscoped().Delete(&testAccount{}, account.ID).Error
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, seen, 10)
+	for email, count := range seen {
+		assert.Equal(t, 1, count, email)
+	}
+	assert.IsNonIncreasing(t, plans)
+}
+
+func Test_List_SkipTotal_Does_Not_Count(t *testing.T) {
+	db := newTestDB(t, &testAuditedEntity{})
+	repo := NewRepository[testAuditedEntity, uint](db)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, repo.Create(ctx, &testAuditedEntity{Name: fmt.Sprintf("entity-%d", i)}))
+	}
+
+	var statements []string
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:record_queries", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+
+	result, err := repo.List(ctx, &ListOptions{Page: 1, PageSize: 2, SkipTotal: true})
+	require.NoError(t, err)
+
+	require.Len(t, statements, 1)
+	assert.NotContains(t, strings.ToLower(statements[0]), "count(")
+	assert.Len(t, result.Items, 2)
+	assert.True(t, result.HasNextPage())
+	assert.Zero(t, result.Total)
+	assert.Zero(t, result.TotalPages)
+
+	result, err = repo.List(ctx, &ListOptions{Page: 3, PageSize: 2, SkipTotal: true})
+	require.NoError(t, err)
+	assert.Len(t, result.Items, 1)
+	assert.False(t, result.HasNextPage())
+
+	statements = nil
+	result, err = repo.List(ctx, &ListOptions{Page: 1, PageSize: 2})
+	require.NoError(t, err)
+	assert.Len(t, statements, 2)
+	assert.Equal(t, int64(5), result.Total)
+	assert.True(t, result.HasNextPage())
+}
+
+func Test_UpsertMany_Twice_Does_Not_Duplicate(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+
+	batch := func(plan string) []testAccount {
+		return []testAccount{
+			{Email: "a@example.com", Name: "A", Plan: plan},
+			{Email: "b@example.com", Name: "B", Plan: plan},
+			{Email: "c@example.com", Name: "C", Plan: plan},
+		}
+	}
+
+	require.NoError(t, repo.UpsertMany(ctx, batch("free"), []string{"email"}, []string{"plan"}))
+	require.NoError(t, repo.UpsertMany(ctx, batch("pro"), []string{"email"}, []string{"plan"}))
+
+	count, err := repo.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	accounts, err := repo.FindAll(ctx, nil)
+	require.NoError(t, err)
+	for _, account := range accounts {
+		assert.Equal(t, "pro", account.Plan)
+	}
+}
+
+func Test_UpsertMany_Updates_Only_Listed_Columns(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.UpsertMany(ctx, []testAccount{{Email: "a@example.com", Name: "Old", Plan: "free"}}, []string{"email"}, nil))
+	require.NoError(t, repo.UpsertMany(ctx, []testAccount{{Email: "a@example.com", Name: "New", Plan: "pro"}}, []string{"email"}, []string{"plan"}))
+
+	account, err := repo.FindOne(ctx, map[string]interface{}{"email": "a@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "Old", account.Name)
+	assert.Equal(t, "pro", account.Plan)
+}
+
+func Test_UpsertManyInBatches(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+
+	accounts := make([]testAccount, 25)
+	for i := range accounts {
+		accounts[i] = testAccount{Email: fmt.Sprintf("user-%d@example.com", i), Plan: "free"}
+	}
+
+	require.NoError(t, repo.UpsertManyInBatches(ctx, accounts, []string{"email"}, nil, 10))
+	require.NoError(t, repo.UpsertManyInBatches(ctx, accounts, []string{"email"}, nil, 10))
+
+	count, err := repo.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(25), count)
+}
+
+func Test_UpsertManyInBatches_Rolls_Back_When_A_Batch_Fails(t *testing.T) {
+	// The clients skip GORM's default transaction
+	db := newTestDB(t, &testAccount{}).Session(&gorm.Session{SkipDefaultTransaction: true})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+
+	var batches int
+	require.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:fail_third_batch", func(tx *gorm.DB) {
+		if batches++; batches == 3 {
+			_ = tx.AddError(fmt.Errorf("batch failed"))
+		}
+	}))
+
+	accounts := make([]testAccount, 25)
+	for i := range accounts {
+		accounts[i] = testAccount{Email: fmt.Sprintf("user-%d@example.com", i), Plan: "free"}
+	}
+
+	err := repo.UpsertManyInBatches(ctx, accounts, []string{"email"}, nil, 10)
+	require.Error(t, err)
+	assert.Equal(t, 3, batches)
+
+	count, err := repo.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func Test_UpsertMany_Requires_Conflict_Columns(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+	accounts := []testAccount{{Email: "a@example.com"}}
+
+	err := repo.UpsertMany(ctx, accounts, nil, nil)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	err = repo.UpsertMany(ctx, accounts, []string{""}, nil)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
+
+func Test_List_Order_By_Is_Parsed(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+	for _, email := range []string{"b@example.com", "a@example.com", "c@example.com"} {
+		require.NoError(t, repo.Create(ctx, &testAccount{Email: email, Plan: "free"}))
+	}
+
+	result, err := repo.List(ctx, &ListOptions{OrderBy: "plan, Email DESC"})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 3)
+	assert.Equal(t, "c@example.com", result.Items[0].Email)
+	assert.Equal(t, "a@example.com", result.Items[2].Email)
+
+	for _, orderBy := range []string{
+		"email; DROP TABLE test_accounts",
+		"(SELECT 1)",
+		"email desc nulls first",
+		"email sideways",
+		"password",
+		"email,",
+	} {
+		_, err := repo.List(ctx, &ListOptions{OrderBy: orderBy})
+		assert.True(t, errors.Is(err, errors.CodeInvalidInput), orderBy)
+		err = repo.Each(ctx, &ListOptions{OrderBy: orderBy}, func(*testAccount) error { return nil })
+		assert.True(t, errors.Is(err, errors.CodeInvalidInput), orderBy)
+	}
+
+	count, err := repo.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func Test_Conditions_Reject_Malicious_Column_Names(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+	require.NoError(t, repo.Create(ctx, &testAccount{Email: "a@example.com"}))
+
+	malicious := map[string]interface{}{"id; DROP TABLE test_accounts; --": 1}
+
+	_, err := repo.FindOne(ctx, malicious)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	_, err = repo.FindAll(ctx, malicious)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	_, err = repo.Count(ctx, malicious)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	_, err = repo.DeleteWhere(ctx, malicious)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	_, err = repo.List(ctx, &ListOptions{Conditions: malicious})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	err = repo.Each(ctx, &ListOptions{Conditions: malicious}, func(*testAccount) error { return nil })
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	count, err := repo.Count(ctx, map[string]interface{}{"test_accounts.email": "a@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func Test_WithAllowedColumns_Restricts_Condition_Keys(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db, WithAllowedColumns("email"))
+	ctx := context.Background()
+	require.NoError(t, repo.Create(ctx, &testAccount{Email: "a@example.com", Plan: "pro"}))
+
+	found, err := repo.FindOne(ctx, map[string]interface{}{"email": "a@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "pro", found.Plan)
+
+	_, err = repo.FindAll(ctx, map[string]interface{}{"plan": "pro"})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	_, err = repo.WithDB(db).Count(ctx, map[string]interface{}{"plan": "pro"})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
+
+// captureQueries records the SQL of every query run through db
+func captureQueries(t *testing.T, db *gorm.DB) *[]string {
+	t.Helper()
+	var queries []string
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		queries = append(queries, tx.Statement.SQL.String())
+	}))
+	return &queries
+}
+
+func Test_FindByID_Uses_Configured_Primary_Key(t *testing.T) {
+	db := newTestDB(t, &testPlainEntity{})
+	repo := NewRepository[testPlainEntity, string](db, WithPrimaryKey("code"))
+	ctx := context.Background()
+	require.NoError(t, repo.Create(ctx, &testPlainEntity{Code: "vn", Name: "Vietnam"}))
+	queries := captureQueries(t, db)
+
+	found, err := repo.FindByID(ctx, "vn")
+	require.NoError(t, err)
+	assert.Equal(t, "Vietnam", found.Name)
+	require.Len(t, *queries, 1)
+	assert.Contains(t, (*queries)[0], "WHERE code = ?")
+
+	exists, err := repo.Exists(ctx, "vn")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, repo.UpdateColumns(ctx, "vn", map[string]interface{}{"name": "Viet Nam"}))
+	require.NoError(t, repo.WithDB(db).Delete(ctx, "vn"))
+	_, err = repo.FindByID(ctx, "vn")
+	assert.True(t, errors.Is(err, errors.CodeNotFound))
+}
+
+func Test_Primary_Key_Is_Detected_From_Schema(t *testing.T) {
+	db := newTestDB(t, &testPlainEntity{}, &testAccount{})
+
+	assert.Equal(t, "code", NewRepository[testPlainEntity, string](db).cfg.primaryKey)
+	assert.Equal(t, "id", NewRepository[testAccount, uint](db).cfg.primaryKey)
+}
+
+type testMembership struct {
+	OrgID     uint `gorm:"primaryKey"`
+	UserID    uint `gorm:"primaryKey"`
+	Role      string
+	DeletedAt gorm.DeletedAt
+}
+
+func Test_ID_Methods_Reject_Composite_Primary_Keys(t *testing.T) {
+	db := newTestDB(t, &testMembership{})
+	repo := NewRepository[testMembership, uint](db)
+	ctx := context.Background()
+	require.NoError(t, repo.Create(ctx, &testMembership{OrgID: 1, UserID: 2, Role: "admin"}))
+	queries := captureQueries(t, db)
+
+	_, err := repo.FindByID(ctx, 1)
+	assert.True(t, errors.Is(err, errors.CodeInvalidInput), err)
+	_, err = repo.Exists(ctx, 1)
+	assert.True(t, errors.Is(err, errors.CodeInvalidInput), err)
+	for name, call := range map[string]func() error{
+		"UpdateColumns": func() error { return repo.UpdateColumns(ctx, 1, map[string]interface{}{"role": "member"}) },
+		"Delete":        func() error { return repo.Delete(ctx, 1) },
+		"SoftDelete":    func() error { return repo.SoftDelete(ctx, 1) },
+		"Restore":       func() error { return repo.Restore(ctx, 1) },
+		"SoftDeleteCascade": func() error {
+			return repo.SoftDeleteCascade(ctx, 1, nil)
+		},
+	} {
+		assert.True(t, errors.Is(call(), errors.CodeInvalidInput), name)
+	}
+	assert.Empty(t, *queries)
+
+	// Conditions still identify the rows
+	_, err = repo.FindOne(ctx, map[string]interface{}{"org_id": 1, "user_id": 2})
+	require.NoError(t, err)
+}
+
+type testOrder struct {
+	BaseModel
+	Number string
+	Items  []testOrderItem `gorm:"foreignKey:OrderID"`
+}
+
+type testOrderItem struct {
+	BaseModel
+	OrderID uint
+	SKU     string
+}
+
+func Test_SoftDeleteCascade_Soft_Deletes_Children(t *testing.T) {
+	db := newTestDB(t, &testOrder{}, &testOrderItem{})
+	repo := NewRepository[testOrder, uint](db)
+	ctx := context.Background()
+
+	order := &testOrder{Number: "o-1", Items: []testOrderItem{{SKU: "a"}, {SKU: "b"}}}
+	other := &testOrder{Number: "o-2", Items: []testOrderItem{{SKU: "c"}}}
+	require.NoError(t, repo.Create(ctx, order))
+	require.NoError(t, repo.Create(ctx, other))
+
+	require.NoError(t, repo.SoftDeleteCascade(ctx, order.ID, []string{"Items"}))
+
+	var deletedOrder testOrder
+	require.NoError(t, db.Unscoped().First(&deletedOrder, order.ID).Error)
+	assert.True(t, deletedOrder.DeletedAt.Valid)
+
+	var items []testOrderItem
+	require.NoError(t, db.Unscoped().Where("order_id = ?", order.ID).Find(&items).Error)
+	require.Len(t, items, 2)
+	for _, item := range items {
+		assert.True(t, item.DeletedAt.Valid, item.SKU)
+	}
+
+	var remaining int64
+	require.NoError(t, db.Model(&testOrderItem{}).Count(&remaining).Error)
+	assert.Equal(t, int64(1), remaining)
+}
+
+func Test_SoftDeleteCascade_Validates_Relations_And_ID(t *testing.T) {
+	db := newTestDB(t, &testOrder{}, &testOrderItem{})
+	repo := NewRepository[testOrder, uint](db)
+	ctx := context.Background()
+
+	err := repo.SoftDeleteCascade(ctx, 1, []string{"Number"})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	err = repo.SoftDeleteCascade(ctx, 42, []string{"Items"})
+	assert.True(t, errors.Is(err, errors.CodeNotFound))
+}
+
+func Test_Repository_Classifies_Database_Errors(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+
+	err := repo.CreateInBatches(ctx, []testAccount{{Email: "a@example.com"}, {Email: "a@example.com"}}, 10)
+	assert.True(t, errors.Is(err, errors.CodeDuplicateKey))
+}
+
+func Test_Repository_Maps_Context_Errors(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	require.NoError(t, repo.Create(context.Background(), &testAccount{Email: "a@example.com"}))
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.FindByID(canceled, 1)
+	assert.True(t, errors.Is(err, errors.CodeCanceled), "FindByID: %v", err)
+	_, err = repo.List(canceled, nil)
+	assert.True(t, errors.Is(err, errors.CodeCanceled), "List: %v", err)
+	err = repo.Create(canceled, &testAccount{Email: "b@example.com"})
+	assert.True(t, errors.Is(err, errors.CodeCanceled), "Create: %v", err)
+
+	expired, cancelExpired := context.WithTimeout(context.Background(), -time.Second)
+	defer cancelExpired()
+
+	_, err = repo.Count(expired, nil)
+	assert.True(t, errors.Is(err, errors.CodeTimeout), "Count: %v", err)
+}
+
+func Test_Create_Runs_Hooks(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+
+	var calls []string
+	repo.BeforeCreate().Add(func(ctx context.Context, account *testAccount) error {
+		calls = append(calls, "before")
+		account.Email = strings.ToLower(account.Email)
+		return nil
+	})
+	repo.AfterCreate().Add(func(ctx context.Context, account *testAccount) error {
+		calls = append(calls, fmt.Sprintf("after:%d", account.ID))
+		return nil
+	})
+
+	account := &testAccount{Email: "A@Example.com"}
+	require.NoError(t, repo.Create(ctx, account))
+	assert.Equal(t, []string{"before", fmt.Sprintf("after:%d", account.ID)}, calls)
+	assert.Equal(t, "a@example.com", account.Email)
+
+	// hooks are shared with repositories bound to another connection
+	assert.Equal(t, 1, repo.WithDB(db).BeforeCreate().Len())
+}
+
+func Test_Create_Aborts_When_Before_Hook_Fails(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	repo := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+
+	rejected := errors.Validation("email domain is not allowed")
+	repo.BeforeCreate().Add(func(context.Context, *testAccount) error { return rejected })
+
+	err := repo.Create(ctx, &testAccount{Email: "a@example.com"})
+	assert.ErrorIs(t, err, rejected)
+
+	count, err := repo.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// 🎓 LEARNING: search_path and connection pools
+// search_path is a setting of the PostgreSQL connection, not of the statement. A plain
+// `SET search_path` on a pooled *gorm.DB changes whichever connection ran it, and the
+// next query may run on another one (or a later request may inherit the tenant).
+// Pinning a connection for the whole request instead starves the pool under load. So
+// schema-scoped queries run in a transaction using `SET LOCAL`, which ends with it:
+// the caller's transaction if any, otherwise a short one around each operation.
+
+// schemaNamePattern matches the unquoted identifiers accepted as schema names, at most
+// 63 bytes like every PostgreSQL identifier
+var schemaNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,62}$`)
+
+// ValidateSchemaName returns a CodeInvalidInput error unless schema is a plain
+// identifier (letters, digits and underscores, not starting with a digit), so that it
+// can't inject SQL into the search_path.
+func ValidateSchemaName(schema string) error {
+	if !schemaNamePattern.MatchString(schema) {
+		return errors.New(errors.CodeInvalidInput, fmt.Sprintf("invalid schema name %q", schema))
+	}
+	return nil
+}
+
+// WithSchema runs fn with a session whose queries use schema as the search_path, for
+// tenant-per-schema databases. fn runs in a transaction of its own, so the connection
+// returns to the pool when fn does, or within the transaction of ctx (see
+// ContextWithTx), for the rest of which the search_path is then set.
+//
+// Example:
+//
+//	var invoices []Invoice
+//	err := client.WithSchema(c.UserContext(), "tenant_acme", func(db *gorm.DB) error {
+//	    return db.Where("status = ?", "open").Find(&invoices).Error // tenant_acme.invoices
+//	})
+func (c *Client) WithSchema(ctx context.Context, schema string, fn func(db *gorm.DB) error) error {
+	scope := &schemaScope{schema: schema, sessions: make(map[gorm.ConnPool]*gorm.DB)}
+	return scope.run(ctx, c.db, fn)
+}
+
+type schemaContextKey struct{}
+
+// schemaScope is the schema of a context, with the sessions opened for it
+type schemaScope struct {
+	schema string
+
+	mu       sync.Mutex
+	sessions map[gorm.ConnPool]*gorm.DB
+}
+
+// ContextWithSchema returns a copy of ctx scoped to schema: repository methods called
+// with it run their queries with schema as the search_path, each in a transaction of
+// its own unless ctx carries one (see Client.WithSchema). Invalid schema names fail
+// the queries with a CodeInvalidInput error. Typically set by a middleware resolving the tenant.
+//
+// Example:
+//
+//	app.Use(func(c *fiber.Ctx) error {
+//	    ctx := postgres.ContextWithSchema(c.UserContext(), "tenant_"+tenantID(c))
+//	    c.SetUserContext(ctx)
+//	    return c.Next()
+//	})
+//
+//	invoices, err := invoiceRepo.FindAll(c.UserContext()) // tenant's invoices only
+func ContextWithSchema(ctx context.Context, schema string) context.Context {
+	return context.WithValue(ctx, schemaContextKey{}, &schemaScope{
+		schema:   schema,
+		sessions: make(map[gorm.ConnPool]*gorm.DB),
+	})
+}
+
+// SchemaFromContext returns the schema set by ContextWithSchema, if any
+func SchemaFromContext(ctx context.Context) (string, bool) {
+	scope, ok := ctx.Value(schemaContextKey{}).(*schemaScope)
+	if !ok {
+		return "", false
+	}
+	return scope.schema, true
+}
+
+// run runs fn scoped to the schema, within the transaction of ctx or db if any, or
+// else in a transaction of its own
+func (s *schemaScope) run(ctx context.Context, db *gorm.DB, fn func(db *gorm.DB) error) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		db = tx
+	}
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok {
+		session := s.session(ctx, db)
+		if session.Error != nil {
+			return session.Error
+		}
+		return fn(session)
+	}
+
+	if err := ValidateSchemaName(s.schema); err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setLocalSearchPath(tx, s.schema); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// session returns the schema-scoped session of the transaction of ctx, or db, setting
+// its search_path on first use. Outside a transaction the session fails, since the
+// search_path wouldn't hold from one query to the next.
+func (s *schemaScope) session(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := TxFromContext(ctx); ok {
+		db = tx
+	}
+	failed := func(err error) *gorm.DB {
+		session := db.WithContext(ctx)
+		_ = session.AddError(err)
+		return session
+	}
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); !ok {
+		return failed(errors.New(errors.CodeInternal, "schema-scoped queries outside a transaction must use Client.WithSchema"))
+	}
+	if err := ValidateSchemaName(s.schema); err != nil {
+		return failed(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session, ok := s.sessions[db.Statement.ConnPool]; ok {
+		return session.WithContext(ctx)
+	}
+
+	session := db.WithContext(ctx)
+	if err := setLocalSearchPath(session, s.schema); err != nil {
+		return failed(err)
+	}
+	s.sessions[db.Statement.ConnPool] = session
+	return session
+}
+
+// setLocalSearchPath sets the search_path of the transaction tx to schema, which must
+// be valid (see ValidateSchemaName)
+func setLocalSearchPath(tx *gorm.DB, schema string) error {
+	if err := tx.Exec(fmt.Sprintf(`SET LOCAL search_path TO "%s"`, schema)).Error; err != nil {
+		return dbError(err, "failed to set search_path")
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gormPostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+)
+
+// recordedQuery is a statement run by the recording driver, with its connection
+type recordedQuery struct {
+	conn  int
+	query string
+}
+
+// recordingDriver is a database/sql driver recording the statements it receives, so
+// that the search_path handling can be tested without a PostgreSQL server. Queries
+// return no rows unless rows is set.
+type recordingDriver struct {
+	mu      sync.Mutex
+	conns   int
+	queries []recordedQuery
+
+	// execErr, if set, returns the error of each statement run with Exec
+	execErr func(query string) error
+	// rows, if set, returns the single-column rows of each query
+	rows func(query string) []driver.Value
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.conns++
+	return &recordingConn{driver: d, id: d.conns}, nil
+}
+
+func (d *recordingDriver) record(conn int, query string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, recordedQuery{conn: conn, query: query})
+}
+
+func (d *recordingDriver) recorded() []recordedQuery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]recordedQuery(nil), d.queries...)
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+	id     int
+}
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *recordingConn) Close() error                        { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *recordingConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.driver.record(c.id, "BEGIN")
+	return recordingTx{c}, nil
+}
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.driver.record(c.id, query)
+	if c.driver.execErr != nil {
+		if err := c.driver.execErr(query); err != nil {
+			return nil, err
+		}
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *recordingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.driver.record(c.id, query)
+	if c.driver.rows != nil {
+		return &valueRows{values: c.driver.rows(query)}, nil
+	}
+	return emptyRows{}, nil
+}
+
+type recordingTx struct{ conn *recordingConn }
+
+func (tx recordingTx) Commit() error   { tx.conn.driver.record(tx.conn.id, "COMMIT"); return nil }
+func (tx recordingTx) Rollback() error { tx.conn.driver.record(tx.conn.id, "ROLLBACK"); return nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return []string{"id"} }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+// valueRows are rows of a single column
+type valueRows struct{ values []driver.Value }
+
+func (*valueRows) Columns() []string { return []string{"value"} }
+func (*valueRows) Close() error      { return nil }
+func (r *valueRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+var registerRecordingDriver sync.Once
+
+// newRecordingClient returns a client using the PostgreSQL dialect over a recording
+// driver, with a pool of several connections
+func newRecordingClient(t *testing.T) (*Client, *recordingDriver) {
+	t.Helper()
+	rec := &recordingDriver{}
+	registerRecordingDriver.Do(func() { sql.Register("postgres-recording", &routingDriver{}) })
+	routing.Store(t.Name(), rec)
+	t.Cleanup(func() { routing.Delete(t.Name()) })
+
+	sqlDB, err := sql.Open("postgres-recording", t.Name())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(gormPostgres.New(gormPostgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger:                 gormlogger.Default.LogMode(gormlogger.Silent),
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return &Client{db: db, logger: empty.EmptyLogger}, rec
+}
+
+// routing dispatches connections to the recording driver of each test, by DSN
+var routing sync.Map
+
+type routingDriver struct{}
+
+func (routingDriver) Open(name string) (driver.Conn, error) {
+	rec, _ := routing.Load(name)
+	return rec.(*recordingDriver).Open(name)
+}
+
+// queriesMatching returns the recorded statements containing substr
+func queriesMatching(rec *recordingDriver, substr string) []recordedQuery {
+	var matching []recordedQuery
+	for _, q := range rec.recorded() {
+		if strings.Contains(q.query, substr) {
+			matching = append(matching, q)
+		}
+	}
+	return matching
+}
+
+// statements returns the recorded statements, without their WHERE clause
+func statements(rec *recordingDriver) []string {
+	var statements []string
+	for _, q := range rec.recorded() {
+		statements = append(statements, strings.SplitN(q.query, " WHERE", 2)[0])
+	}
+	return statements
+}
+
+func Test_WithSchema_Rejects_Invalid_Schema_Names(t *testing.T) {
+	client, rec := newRecordingClient(t)
+
+	for _, schema := range []string{"", "tenant-a", `tenant"; DROP TABLE users; --`, "1tenant", strings.Repeat("a", 64)} {
+		err := client.WithSchema(context.Background(), schema, func(db *gorm.DB) error {
+			t.Errorf("fn called for schema %q", schema)
+			return nil
+		})
+		assert.True(t, errors.Is(err, errors.CodeInvalidInput), schema)
+	}
+	assert.Empty(t, rec.recorded())
+}
+
+func Test_WithSchema_Runs_Queries_In_A_Transaction_Using_The_Schema(t *testing.T) {
+	client, rec := newRecordingClient(t)
+
+	err := client.WithSchema(context.Background(), "tenant_a", func(db *gorm.DB) error {
+		var accounts []testAccount
+		if err := db.Find(&accounts).Error; err != nil {
+			return err
+		}
+		return db.Where("plan = ?", "pro").Find(&accounts).Error
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"BEGIN",
+		`SET LOCAL search_path TO "tenant_a"`,
+		`SELECT * FROM "test_accounts"`,
+		`SELECT * FROM "test_accounts"`,
+		"COMMIT",
+	}, statements(rec))
+
+	// The connection is back in the pool once fn returns
+	sqlDB, err := client.DB().DB()
+	require.NoError(t, err)
+	assert.Zero(t, sqlDB.Stats().InUse)
+}
+
+func Test_ContextWithSchema_Scopes_Repository_Queries(t *testing.T) {
+	client, rec := newRecordingClient(t)
+	repo := NewRepository[testAccount, uint](client.DB())
+	ctx := context.Background()
+
+	tenantCtx := ContextWithSchema(ctx, "tenant_b")
+	schema, ok := SchemaFromContext(tenantCtx)
+	require.True(t, ok)
+	assert.Equal(t, "tenant_b", schema)
+
+	_, err := repo.FindAll(tenantCtx, nil)
+	require.NoError(t, err)
+	_, err = repo.Count(tenantCtx, nil)
+	require.NoError(t, err)
+
+	// Each call sets the schema in a transaction of its own
+	assert.Equal(t, []string{
+		"BEGIN",
+		`SET LOCAL search_path TO "tenant_b"`,
+		`SELECT * FROM "test_accounts"`,
+		"COMMIT",
+		"BEGIN",
+		`SET LOCAL search_path TO "tenant_b"`,
+		`SELECT count(*) FROM "test_accounts"`,
+		"COMMIT",
+	}, statements(rec))
+
+	// Without the schema, queries use the pool as usual
+	_, err = repo.FindAll(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, queriesMatching(rec, "search_path"), 2)
+
+	_, err = repo.FindAll(ContextWithSchema(ctx, "tenant b"), nil)
+	assert.True(t, errors.Is(err, errors.CodeInvalidInput), err)
+
+	// A raw query can't hold the search_path outside a transaction
+	var accounts []testAccount
+	assert.Error(t, repo.Query(tenantCtx).Find(&accounts).Error)
+}
+
+func Test_ContextWithSchema_Does_Not_Hold_Connections_Between_Calls(t *testing.T) {
+	client, _ := newRecordingClient(t)
+	sqlDB, err := client.DB().DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	repo := NewRepository[testAccount, uint](client.DB())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	tenantCtx := ContextWithSchema(ctx, "tenant_d")
+
+	_, err = repo.FindAll(tenantCtx, nil)
+	require.NoError(t, err)
+
+	// With a single connection, an unscoped transaction of the same request still
+	// gets one, as the scoped query released it
+	err = NewTxManager(client.DB()).Do(ctx, func(ctx context.Context, uow *UnitOfWork) error {
+		_, err := repo.Count(ctx, nil)
+		return err
+	})
+	require.NoError(t, err)
+
+	_, err = repo.Count(tenantCtx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, sqlDB.Stats().InUse)
+}
+
+func Test_ContextWithSchema_Sets_Local_Search_Path_In_Transactions(t *testing.T) {
+	client, rec := newRecordingClient(t)
+	repo := NewRepository[testAccount, uint](client.DB())
+	ctx := ContextWithSchema(context.Background(), "tenant_c")
+
+	err := NewTxManager(client.DB()).Do(ctx, func(ctx context.Context, uow *UnitOfWork) error {
+		if _, err := repo.FindAll(ctx, nil); err != nil {
+			return err
+		}
+		_, err := repo.Count(ctx, nil)
+		return err
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"BEGIN",
+		`SET LOCAL search_path TO "tenant_c"`,
+		`SELECT * FROM "test_accounts"`,
+		`SELECT count(*) FROM "test_accounts"`,
+		"COMMIT",
+	}, statements(rec))
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/utils"
+)
+
+// Comparison operators accepted in utils.FilterModel.Comparison by ScopeFromListQuery
+const (
+	ComparisonEquals      = "eq"
+	ComparisonNotEquals   = "ne"
+	ComparisonGreater     = "gt"
+	ComparisonGreaterOrEq = "gte"
+	ComparisonLess        = "lt"
+	ComparisonLessOrEq    = "lte"
+	ComparisonContains    = "contains" // LIKE %value%, with % and _ in value matched literally
+	ComparisonIn          = "in"       // comma-separated values
+)
+
+// ScopeFromListQuery returns a GORM scope applying the filters, ordering and
+// pagination of q, so the filter DSL of list endpoints can be reused on custom
+// queries. allowed maps the API field names clients may filter and sort on to
+// column names; any other field, an unknown comparison operator or a sort direction
+// other than asc/desc adds a CodeBadRequest error to the query instead.
+//
+// An empty comparison means ComparisonEquals. OrderBy holds comma-separated
+// "field [asc|desc]" terms. Pagination is applied only when q.Size is positive.
+//
+// Example:
+//
+//	var orders []Order
+//	err := db.WithContext(ctx).
+//		Scopes(postgres.ScopeFromListQuery(q, map[string]string{
+//			"status":    "status",
+//			"createdAt": "created_at",
+//		})).
+//		Find(&orders).Error
+func ScopeFromListQuery(q *utils.ListQuery, allowed map[string]string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if q == nil {
+			return db
+		}
+
+		for _, filter := range q.Filters {
+			if filter == nil {
+				continue
+			}
+			column, ok := allowed[filter.Field]
+			if !ok {
+				_ = db.AddError(errors.BadRequest(fmt.Sprintf("filtering on %q is not allowed", filter.Field)))
+				return db
+			}
+			expr, err := filterExpression(column, filter)
+			if err != nil {
+				_ = db.AddError(err)
+				return db
+			}
+			db = db.Where(expr)
+		}
+
+		if q.OrderBy != "" {
+			orderBy, err := orderByColumns(q.OrderBy, allowed)
+			if err != nil {
+				_ = db.AddError(err)
+				return db
+			}
+			db = db.Order(orderBy)
+		}
+
+		if q.Size > 0 {
+			db = db.Limit(q.GetLimit()).Offset(q.GetOffset())
+		}
+
+		return db
+	}
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, so that "contains" filters
+// match their value literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// filterExpression builds the condition of filter on column
+func filterExpression(column string, filter *utils.FilterModel) (clause.Expression, error) {
+	col := clause.Column{Name: column}
+
+	switch strings.ToLower(filter.Comparison) {
+	case "", ComparisonEquals:
+		return clause.Eq{Column: col, Value: filter.Value}, nil
+	case ComparisonNotEquals:
+		return clause.Neq{Column: col, Value: filter.Value}, nil
+	case ComparisonGreater:
+		return clause.Gt{Column: col, Value: filter.Value}, nil
+	case ComparisonGreaterOrEq:
+		return clause.Gte{Column: col, Value: filter.Value}, nil
+	case ComparisonLess:
+		return clause.Lt{Column: col, Value: filter.Value}, nil
+	case ComparisonLessOrEq:
+		return clause.Lte{Column: col, Value: filter.Value}, nil
+	case ComparisonContains:
+		return clause.Expr{
+			SQL:  `? LIKE ? ESCAPE '\'`,
+			Vars: []interface{}{col, "%" + likeEscaper.Replace(filter.Value) + "%"},
+		}, nil
+	case ComparisonIn:
+		parts := strings.Split(filter.Value, ",")
+		values := make([]interface{}, len(parts))
+		for i, part := range parts {
+			values[i] = strings.TrimSpace(part)
+		}
+		return clause.IN{Column: col, Values: values}, nil
+	default:
+		return nil, errors.BadRequest(fmt.Sprintf("unsupported comparison %q for %q", filter.Comparison, filter.Field))
+	}
+}
+
+// orderByColumns parses comma-separated "field [asc|desc]" terms into an ORDER BY clause
+func orderByColumns(orderBy string, allowed map[string]string) (clause.OrderBy, error) {
+	var columns []clause.OrderByColumn
+	for _, term := range strings.Split(orderBy, ",") {
+		parts := strings.Fields(term)
+		if len(parts) == 0 {
+			continue
+		}
+		if len(parts) > 2 {
+			return clause.OrderBy{}, errors.BadRequest(fmt.Sprintf("invalid order by %q", strings.TrimSpace(term)))
+		}
+
+		column, ok := allowed[parts[0]]
+		if !ok {
+			return clause.OrderBy{}, errors.BadRequest(fmt.Sprintf("sorting on %q is not allowed", parts[0]))
+		}
+
+		desc := false
+		if len(parts) == 2 {
+			switch strings.ToLower(parts[1]) {
+			case "asc":
+			case "desc":
+				desc = true
+			default:
+				return clause.OrderBy{}, errors.BadRequest(fmt.Sprintf("invalid sort direction %q", parts[1]))
+			}
+		}
+
+		columns = append(columns, clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc})
+	}
+	return clause.OrderBy{Columns: columns}, nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/utils"
+)
+
+var testAccountFields = map[string]string{
+	"email":     "email",
+	"plan":      "plan",
+	"createdAt": "created_at",
+}
+
+// dryRunListQuery returns the statement finding testAccounts through the scope of q
+func dryRunListQuery(t *testing.T, q *utils.ListQuery) *gorm.Statement {
+	t.Helper()
+	db := newTestDB(t, &testAccount{})
+	return db.Session(&gorm.Session{DryRun: true}).
+		Scopes(ScopeFromListQuery(q, testAccountFields)).
+		Find(&[]testAccount{}).Statement
+}
+
+func Test_ScopeFromListQuery_Builds_Where_Order_And_Pagination(t *testing.T) {
+	q := &utils.ListQuery{
+		Size:    10,
+		Page:    3,
+		OrderBy: "createdAt desc, email",
+		Filters: []*utils.FilterModel{
+			{Field: "plan", Value: "pro"},
+			{Field: "email", Value: "example.com", Comparison: "contains"},
+			{Field: "createdAt", Value: "2025-01-01", Comparison: "GTE"},
+		},
+	}
+
+	stmt := dryRunListQuery(t, q)
+	require.NoError(t, stmt.Error)
+
+	sql := stmt.SQL.String()
+	assert.Contains(t, sql, "WHERE `plan` = ? AND `email` LIKE ? ESCAPE '\\' AND `created_at` >= ?")
+	assert.Contains(t, sql, "ORDER BY `created_at` DESC,`email`")
+	assert.Contains(t, sql, "LIMIT 10 OFFSET 20")
+	assert.Equal(t, []interface{}{"pro", "%example.com%", "2025-01-01"}, stmt.Vars[:3])
+}
+
+func Test_ScopeFromListQuery_Contains_Matches_Wildcards_Literally(t *testing.T) {
+	stmt := dryRunListQuery(t, &utils.ListQuery{
+		Filters: []*utils.FilterModel{{Field: "email", Value: `100%_off\`, Comparison: "contains"}},
+	})
+	require.NoError(t, stmt.Error)
+	assert.Equal(t, []interface{}{`%100\%\_off\\%`}, stmt.Vars)
+
+	db := newTestDB(t, &testAccount{})
+	require.NoError(t, db.Create(&[]testAccount{
+		{Email: "100%_off@example.com"},
+		{Email: "100x_off@example.com"},
+		{Email: "100%aoff@example.com"},
+	}).Error)
+
+	var accounts []testAccount
+	require.NoError(t, db.Scopes(ScopeFromListQuery(&utils.ListQuery{
+		Filters: []*utils.FilterModel{{Field: "email", Value: "100%_off", Comparison: "contains"}},
+	}, testAccountFields)).Find(&accounts).Error)
+	require.Len(t, accounts, 1)
+	assert.Equal(t, "100%_off@example.com", accounts[0].Email)
+}
+
+func Test_ScopeFromListQuery_In_Splits_Values(t *testing.T) {
+	stmt := dryRunListQuery(t, &utils.ListQuery{
+		Filters: []*utils.FilterModel{{Field: "plan", Value: "free, pro", Comparison: "in"}},
+	})
+	require.NoError(t, stmt.Error)
+
+	assert.Contains(t, stmt.SQL.String(), "WHERE `plan` IN (?,?)")
+	assert.NotContains(t, stmt.SQL.String(), "LIMIT")
+	assert.Equal(t, []interface{}{"free", "pro"}, stmt.Vars)
+}
+
+func Test_ScopeFromListQuery_Rejects_Invalid_Queries(t *testing.T) {
+	tests := map[string]*utils.ListQuery{
+		"field not allowed": {Filters: []*utils.FilterModel{{Field: "password", Value: "x"}}},
+		"unknown operator":  {Filters: []*utils.FilterModel{{Field: "plan", Value: "x", Comparison: "regex"}}},
+		"sort not allowed":  {OrderBy: "password"},
+		"invalid direction": {OrderBy: "email sideways"},
+		"injected order":    {OrderBy: "email; DROP TABLE test_accounts"},
+	}
+
+	for name, q := range tests {
+		t.Run(name, func(t *testing.T) {
+			stmt := dryRunListQuery(t, q)
+			require.Error(t, stmt.Error)
+			assert.True(t, errors.Is(stmt.Error, errors.CodeBadRequest), stmt.Error)
+		})
+	}
+}
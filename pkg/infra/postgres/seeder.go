@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/logger"
+	defaultLogger "github.com/phatnt199/go-infra/pkg/logger/default_logger"
+)
+
+// Seeder runs idempotent data seeds (default roles, lookup tables, ...).
+// Applied seeds are tracked in the schema_seeds table, the same way Migrator
+// tracks schema migrations in schema_migrations.
+type Seeder struct {
+	db     *gorm.DB
+	logger logger.Logger
+	seeds  []Seed
+	names  map[string]bool
+}
+
+// Seed represents a named data seed
+type Seed struct {
+	Name string
+	// AlwaysRun re-runs the seed on every Run, e.g. for upserting lookup tables.
+	// The seed function must then be safe to run repeatedly.
+	AlwaysRun bool
+	Run       func(tx *gorm.DB) error
+}
+
+// SeedRecord represents an applied seed in the database
+type SeedRecord struct {
+	ID        uint      `gorm:"primaryKey"`
+	Name      string    `gorm:"uniqueIndex;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// TableName specifies the table name for SeedRecord
+func (SeedRecord) TableName() string {
+	return "schema_seeds"
+}
+
+// NewSeeder creates a new seeder instance
+func NewSeeder(db *gorm.DB, log logger.Logger) *Seeder {
+	if log == nil {
+		log = defaultLogger.GetLogger()
+	}
+
+	return &Seeder{
+		db:     db,
+		logger: log,
+		names:  make(map[string]bool),
+	}
+}
+
+// Register adds seeds to the seeder. Seeds run in registration order.
+func (s *Seeder) Register(seeds ...Seed) error {
+	for _, seed := range seeds {
+		if seed.Name == "" {
+			return errors.BadRequest("seed name is required")
+		}
+		if seed.Run == nil {
+			return errors.BadRequest(fmt.Sprintf("seed %s has no run function", seed.Name))
+		}
+		if s.names[seed.Name] {
+			return errors.Conflict(fmt.Sprintf("seed %s is already registered", seed.Name))
+		}
+
+		s.names[seed.Name] = true
+		s.seeds = append(s.seeds, seed)
+	}
+	return nil
+}
+
+// Init initializes the seeds table
+func (s *Seeder) Init(ctx context.Context) error {
+	if err := s.db.WithContext(ctx).AutoMigrate(&SeedRecord{}); err != nil {
+		return errors.Wrap(err, errors.CodeDatabaseError, "failed to initialize seeds table")
+	}
+	return nil
+}
+
+// Run applies all pending seeds and re-runs the ones flagged AlwaysRun
+func (s *Seeder) Run(ctx context.Context) error {
+	if err := s.Init(ctx); err != nil {
+		return err
+	}
+
+	applied, err := s.getAppliedSeeds(ctx)
+	if err != nil {
+		return err
+	}
+
+	var ran int
+	for _, seed := range s.seeds {
+		if _, ok := applied[seed.Name]; ok && !seed.AlwaysRun {
+			continue
+		}
+
+		if err := s.applySeed(ctx, seed, applied[seed.Name]); err != nil {
+			return err
+		}
+		ran++
+	}
+
+	if ran == 0 {
+		s.logger.Info("no pending seeds to apply")
+		return nil
+	}
+
+	s.logger.Infow("seeds applied successfully", logger.Fields{
+		"count": ran,
+	})
+	return nil
+}
+
+// applySeed runs a single seed and records it in the same transaction
+func (s *Seeder) applySeed(ctx context.Context, seed Seed, record *SeedRecord) error {
+	s.logger.Infow("applying seed", logger.Fields{
+		"name":       seed.Name,
+		"always_run": seed.AlwaysRun,
+	})
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := seed.Run(tx); err != nil {
+			return errors.Wrap(err, errors.CodeDatabaseError,
+				fmt.Sprintf("failed to apply seed %s", seed.Name))
+		}
+
+		now := time.Now().UTC()
+		if record != nil {
+			// AlwaysRun seed, refresh the last applied time
+			if err := tx.Model(record).Update("applied_at", now).Error; err != nil {
+				return errors.Wrap(err, errors.CodeDatabaseError, "failed to update seed record")
+			}
+			return nil
+		}
+
+		if err := tx.Create(&SeedRecord{Name: seed.Name, AppliedAt: now}).Error; err != nil {
+			return errors.Wrap(err, errors.CodeDatabaseError, "failed to record seed")
+		}
+		return nil
+	})
+}
+
+// getAppliedSeeds returns applied seeds keyed by name
+func (s *Seeder) getAppliedSeeds(ctx context.Context) (map[string]*SeedRecord, error) {
+	var records []SeedRecord
+	if err := s.db.WithContext(ctx).Find(&records).Error; err != nil {
+		return nil, errors.Wrap(err, errors.CodeDatabaseError, "failed to get applied seeds")
+	}
+
+	applied := make(map[string]*SeedRecord, len(records))
+	for i := range records {
+		applied[records[i].Name] = &records[i]
+	}
+	return applied, nil
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+)
+
+type testRole struct {
+	ID   uint   `gorm:"primaryKey"`
+	Name string `gorm:"uniqueIndex;not null"`
+}
+
+func Test_Seeder_Is_Idempotent(t *testing.T) {
+	db := newTestDB(t, &testRole{})
+	ctx := context.Background()
+
+	var rolesRuns, lookupRuns int
+	seeder := NewSeeder(db, empty.EmptyLogger)
+	require.NoError(t, seeder.Register(
+		Seed{
+			Name: "default_roles",
+			Run: func(tx *gorm.DB) error {
+				rolesRuns++
+				return tx.Create(&[]testRole{{Name: "admin"}, {Name: "user"}}).Error
+			},
+		},
+		Seed{
+			Name:      "lookup_roles",
+			AlwaysRun: true,
+			Run: func(tx *gorm.DB) error {
+				lookupRuns++
+				return tx.Where(testRole{Name: "guest"}).FirstOrCreate(&testRole{}).Error
+			},
+		},
+	))
+
+	require.NoError(t, seeder.Run(ctx))
+	require.NoError(t, seeder.Run(ctx))
+
+	assert.Equal(t, 1, rolesRuns)
+	assert.Equal(t, 2, lookupRuns)
+
+	var roles int64
+	require.NoError(t, db.Model(&testRole{}).Count(&roles).Error)
+	assert.Equal(t, int64(3), roles)
+
+	var records []SeedRecord
+	require.NoError(t, db.Order("name").Find(&records).Error)
+	require.Len(t, records, 2)
+	assert.Equal(t, "default_roles", records[0].Name)
+	assert.Equal(t, "lookup_roles", records[1].Name)
+}
+
+func Test_Seeder_Rolls_Back_Failed_Seed(t *testing.T) {
+	db := newTestDB(t, &testRole{})
+	ctx := context.Background()
+
+	seeder := NewSeeder(db, empty.EmptyLogger)
+	require.NoError(t, seeder.Register(Seed{
+		Name: "broken",
+		Run: func(tx *gorm.DB) error {
+			if err := tx.Create(&testRole{Name: "admin"}).Error; err != nil {
+				return err
+			}
+			return stdErrors.New("boom")
+		},
+	}))
+	assert.Error(t, seeder.Register(Seed{Name: "broken", Run: func(*gorm.DB) error { return nil }}))
+
+	require.Error(t, seeder.Run(ctx))
+
+	var roles, records int64
+	require.NoError(t, db.Model(&testRole{}).Count(&roles).Error)
+	require.NoError(t, db.Model(&SeedRecord{}).Count(&records).Error)
+	assert.Zero(t, roles)
+	assert.Zero(t, records)
+}
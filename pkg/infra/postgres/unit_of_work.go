@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// TxBindable is implemented by repositories that can be bound to a transaction,
+// such as *Repository[T, ID] or custom repositories exposing the same WithDB method
+type TxBindable[R any] interface {
+	WithDB(db *gorm.DB) R
+}
+
+// TxManager runs units of work: functions whose repository calls all share one
+// transaction, committed when the function returns nil and rolled back otherwise.
+type TxManager struct {
+	db *gorm.DB
+}
+
+// NewTxManager creates a transaction manager for db
+func NewTxManager(db *gorm.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// UnitOfWork is a transaction in progress. Use Bind to get repositories bound to it.
+type UnitOfWork struct {
+	tx *gorm.DB
+}
+
+// Tx returns the transaction for custom queries
+func (u *UnitOfWork) Tx() *gorm.DB {
+	return u.tx
+}
+
+type unitOfWorkContextKey struct{}
+
+type txContextKey struct{}
+
+// ContextWithTx returns a copy of ctx carrying tx. Repository methods called with the
+// returned context run in tx instead of the repository's DB, which lets handlers open
+// a transaction without threading it through every service method.
+//
+// Example:
+//
+//	err := client.Transaction(ctx, func(tx *gorm.DB) error {
+//	    ctx := postgres.ContextWithTx(ctx, tx)
+//	    if err := orders.Create(ctx, order); err != nil {
+//	        return err
+//	    }
+//	    return stock.Reserve(ctx, order.Items) // uses the same transaction
+//	})
+func ContextWithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext returns the transaction stored by ContextWithTx or TxManager.Do, if any
+func TxFromContext(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(*gorm.DB)
+	return tx, ok && tx != nil
+}
+
+// Do runs fn within a transaction. The context passed to fn carries the unit of work
+// and its transaction (see ContextWithTx), so repository calls made with it and nested
+// Do calls (e.g. from another service) join the same transaction instead of opening a
+// new one.
+//
+// Example:
+//
+//	err := txManager.Do(ctx, func(ctx context.Context, uow *postgres.UnitOfWork) error {
+//	    if err := postgres.Bind(uow, orderRepo).Create(ctx, order); err != nil {
+//	        return err
+//	    }
+//	    return postgres.Bind(uow, stockRepo).UpdateColumns(ctx, item.ID, map[string]interface{}{
+//	        "quantity": gorm.Expr("quantity - ?", order.Quantity),
+//	    })
+//	})
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context, uow *UnitOfWork) error) error {
+	if uow, ok := UnitOfWorkFromContext(ctx); ok {
+		return fn(ctx, uow)
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		uow := &UnitOfWork{tx: tx}
+		txCtx := ContextWithTx(context.WithValue(ctx, unitOfWorkContextKey{}, uow), tx)
+		if err := fn(txCtx, uow); err != nil {
+			if _, ok := errors.As(err); ok {
+				return err
+			}
+			return dbError(err, "transaction failed")
+		}
+		return nil
+	})
+}
+
+// UnitOfWorkFromContext returns the unit of work started by TxManager.Do, if any
+func UnitOfWorkFromContext(ctx context.Context) (*UnitOfWork, bool) {
+	uow, ok := ctx.Value(unitOfWorkContextKey{}).(*UnitOfWork)
+	return uow, ok
+}
+
+// Bind returns repo bound to the unit of work's transaction
+func Bind[R any](uow *UnitOfWork, repo TxBindable[R]) R {
+	return repo.WithDB(uow.tx)
+}
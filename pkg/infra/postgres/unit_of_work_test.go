@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+func Test_TxManager_Commits_Across_Repositories(t *testing.T) {
+	db := newTestDB(t, &testAccount{}, &testAuditedEntity{})
+	accounts := NewRepository[testAccount, uint](db)
+	entities := NewRepository[testAuditedEntity, uint](db)
+	ctx := context.Background()
+
+	err := NewTxManager(db).Do(ctx, func(ctx context.Context, uow *UnitOfWork) error {
+		if err := Bind(uow, accounts).Create(ctx, &testAccount{Email: "a@example.com"}); err != nil {
+			return err
+		}
+		return Bind(uow, entities).Create(ctx, &testAuditedEntity{Name: "audit"})
+	})
+	require.NoError(t, err)
+
+	accountCount, err := accounts.Count(ctx, nil)
+	require.NoError(t, err)
+	entityCount, err := entities.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), accountCount)
+	assert.Equal(t, int64(1), entityCount)
+}
+
+func Test_TxManager_Rolls_Back_Every_Repository_On_Error(t *testing.T) {
+	db := newTestDB(t, &testAccount{}, &testAuditedEntity{})
+	accounts := NewRepository[testAccount, uint](db)
+	entities := NewRepository[testAuditedEntity, uint](db)
+	ctx := context.Background()
+
+	failure := errors.Conflict("insufficient stock")
+	err := NewTxManager(db).Do(ctx, func(ctx context.Context, uow *UnitOfWork) error {
+		require.NoError(t, Bind(uow, accounts).Create(ctx, &testAccount{Email: "a@example.com"}))
+		require.NoError(t, Bind(uow, entities).Create(ctx, &testAuditedEntity{Name: "audit"}))
+		return failure
+	})
+	assert.Same(t, failure, err)
+
+	accountCount, err := accounts.Count(ctx, nil)
+	require.NoError(t, err)
+	entityCount, err := entities.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, accountCount)
+	assert.Zero(t, entityCount)
+}
+
+func Test_TxManager_Nested_Do_Joins_Outer_Transaction(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	accounts := NewRepository[testAccount, uint](db)
+	txManager := NewTxManager(db)
+	ctx := context.Background()
+
+	err := txManager.Do(ctx, func(ctx context.Context, outer *UnitOfWork) error {
+		require.NoError(t, Bind(outer, accounts).Create(ctx, &testAccount{Email: "a@example.com"}))
+
+		return txManager.Do(ctx, func(ctx context.Context, inner *UnitOfWork) error {
+			assert.Same(t, outer, inner)
+			require.NoError(t, Bind(inner, accounts).Create(ctx, &testAccount{Email: "b@example.com"}))
+			return errors.Internal("boom")
+		})
+	})
+	assert.True(t, errors.Is(err, errors.CodeInternal))
+
+	count, err := accounts.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func Test_Repository_Joins_Context_Transaction(t *testing.T) {
+	db := newTestDB(t, &testAccount{}, &testAuditedEntity{})
+	accounts := NewRepository[testAccount, uint](db)
+	entities := NewRepository[testAuditedEntity, uint](db)
+	ctx := context.Background()
+
+	failure := errors.Conflict("insufficient stock")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		ctx := ContextWithTx(ctx, tx)
+		require.NoError(t, accounts.Create(ctx, &testAccount{Email: "a@example.com"}))
+		require.NoError(t, entities.Create(ctx, &testAuditedEntity{Name: "audit"}))
+
+		// Reads with the context see the uncommitted rows
+		count, err := accounts.Count(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		return failure
+	})
+	assert.Same(t, failure, err)
+
+	accountCount, err := accounts.Count(ctx, nil)
+	require.NoError(t, err)
+	entityCount, err := entities.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, accountCount)
+	assert.Zero(t, entityCount)
+}
+
+func Test_TxManager_Do_Stores_Transaction_In_Context(t *testing.T) {
+	db := newTestDB(t, &testAccount{})
+	accounts := NewRepository[testAccount, uint](db)
+	ctx := context.Background()
+
+	_, ok := TxFromContext(ctx)
+	assert.False(t, ok)
+
+	err := NewTxManager(db).Do(ctx, func(ctx context.Context, uow *UnitOfWork) error {
+		tx, ok := TxFromContext(ctx)
+		require.True(t, ok)
+		assert.Same(t, uow.Tx(), tx)
+
+		// No Bind needed: the context carries the transaction
+		require.NoError(t, accounts.Create(ctx, &testAccount{Email: "a@example.com"}))
+		return errors.Internal("boom")
+	})
+	assert.True(t, errors.Is(err, errors.CodeInternal))
+
+	count, err := accounts.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,159 @@
+// Package json is the JSON codec shared by the go-infra packages. It standardizes on
+// github.com/goccy/go-json (a drop-in, faster replacement for encoding/json) behind an
+// API whose signatures match encoding/json, so the implementation can be swapped in one
+// place. The package is import-free apart from the codec itself, so any package
+// (including pkg/errors) can use it.
+//
+// Example:
+//
+//	data, err := json.Marshal(user)
+//	err = json.NewDecoder(r.Body).Decode(&req)
+package json
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"fmt"
+	"io"
+
+	gojson "github.com/goccy/go-json"
+)
+
+type (
+	// Encoder writes JSON values to an output stream
+	Encoder = gojson.Encoder
+	// Decoder reads JSON values from an input stream
+	Decoder = gojson.Decoder
+	// Marshaler is implemented by types that marshal themselves into JSON
+	Marshaler = gojson.Marshaler
+	// Unmarshaler is implemented by types that unmarshal a JSON description of themselves
+	Unmarshaler = gojson.Unmarshaler
+	// RawMessage is a raw encoded JSON value
+	RawMessage = stdjson.RawMessage
+	// Number represents a JSON number literal
+	Number = stdjson.Number
+)
+
+// Compile-time guarantees that this package keeps encoding/json's signatures, so
+// switching the implementation (or back to the standard library) is a one-line change.
+var (
+	_ func(v any) ([]byte, error)                        = stdjson.Marshal
+	_ func(v any) ([]byte, error)                        = Marshal
+	_ func(v any, prefix, indent string) ([]byte, error) = stdjson.MarshalIndent
+	_ func(v any, prefix, indent string) ([]byte, error) = MarshalIndent
+	_ func(data []byte, v any) error                     = stdjson.Unmarshal
+	_ func(data []byte, v any) error                     = Unmarshal
+	_ func(data []byte) bool                             = stdjson.Valid
+	_ func(data []byte) bool                             = Valid
+
+	_ encoder = (*stdjson.Encoder)(nil)
+	_ encoder = (*Encoder)(nil)
+	_ decoder = (*stdjson.Decoder)(nil)
+	_ decoder = (*Decoder)(nil)
+)
+
+// encoder is the method set shared with encoding/json's Encoder
+type encoder interface {
+	Encode(v any) error
+	SetEscapeHTML(on bool)
+	SetIndent(prefix, indent string)
+}
+
+// decoder is the method set shared with encoding/json's Decoder
+type decoder interface {
+	Buffered() io.Reader
+	Decode(v any) error
+	DisallowUnknownFields()
+	InputOffset() int64
+	More() bool
+	Token() (stdjson.Token, error)
+	UseNumber()
+}
+
+// Marshal returns the JSON encoding of v.
+//
+// Example:
+//
+//	data, err := json.Marshal(map[string]int{"count": 1}) // {"count":1}
+func Marshal(v any) ([]byte, error) {
+	return gojson.Marshal(v)
+}
+
+// MarshalIndent is like Marshal but applies indentation to format the output.
+//
+// Example:
+//
+//	data, err := json.MarshalIndent(config, "", "  ")
+func MarshalIndent(v any, prefix, indent string) ([]byte, error) {
+	return gojson.MarshalIndent(v, prefix, indent)
+}
+
+// MustMarshal is like Marshal but panics on error. Use it only for values that are
+// known to be serializable, such as static fixtures.
+//
+// Example:
+//
+//	body := json.MustMarshal(map[string]string{"status": "ok"})
+func MustMarshal(v any) []byte {
+	data, err := Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("json: marshal %T: %v", v, err))
+	}
+	return data
+}
+
+// Unmarshal parses the JSON-encoded data and stores the result in the value pointed to by v.
+//
+// Example:
+//
+//	var user User
+//	err := json.Unmarshal(data, &user)
+func Unmarshal(data []byte, v any) error {
+	return gojson.Unmarshal(data, v)
+}
+
+// UnmarshalUseNumber is like Unmarshal, but numbers decoded into interface values
+// become Number instead of float64. A float64 only holds integers up to 2^53 exactly,
+// so use it when payloads may carry 64-bit integers such as Snowflake IDs.
+//
+// Example:
+//
+//	var payload map[string]any
+//	err := json.UnmarshalUseNumber([]byte(`{"id":1234567890123456789}`), &payload)
+//	id, _ := payload["id"].(json.Number).Int64() // 1234567890123456789
+func UnmarshalUseNumber(data []byte, v any) error {
+	decoder := NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		// Unmarshal reports the syntax error of the trailing data after the value
+		return Unmarshal(data, v)
+	}
+	return nil
+}
+
+// Valid reports whether data is a valid JSON encoding
+func Valid(data []byte) bool {
+	return gojson.Valid(data)
+}
+
+// NewEncoder returns a new encoder that writes to w.
+//
+// Example:
+//
+//	err := json.NewEncoder(w).Encode(response)
+func NewEncoder(w io.Writer) *Encoder {
+	return gojson.NewEncoder(w)
+}
+
+// NewDecoder returns a new decoder that reads from r.
+//
+// Example:
+//
+//	err := json.NewDecoder(r.Body).Decode(&request)
+func NewDecoder(r io.Reader) *Decoder {
+	return gojson.NewDecoder(r)
+}
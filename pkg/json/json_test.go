@@ -0,0 +1,59 @@
+package json
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPayload struct {
+	Name  string `json:"name"`
+	Count int    `json:"count,omitempty"`
+}
+
+func Test_Marshal_And_Unmarshal_Round_Trip(t *testing.T) {
+	data, err := Marshal(testPayload{Name: "a<b"})
+	require.NoError(t, err)
+	// HTML escaping matches encoding/json
+	assert.Equal(t, `{"name":"a\u003cb"}`, string(data))
+
+	var decoded testPayload
+	require.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, "a<b", decoded.Name)
+}
+
+func Test_Encoder_And_Decoder(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewEncoder(&buf).Encode(testPayload{Name: "x", Count: 2}))
+	assert.Equal(t, "{\"name\":\"x\",\"count\":2}\n", buf.String())
+
+	dec := NewDecoder(strings.NewReader(`{"name":"x","extra":true}`))
+	dec.DisallowUnknownFields()
+	assert.Error(t, dec.Decode(&testPayload{}))
+}
+
+func Test_MustMarshal_Panics_On_Unsupported_Value(t *testing.T) {
+	assert.Equal(t, `{"name":"ok"}`, string(MustMarshal(testPayload{Name: "ok"})))
+	assert.Panics(t, func() { MustMarshal(make(chan int)) })
+}
+
+func Test_UnmarshalUseNumber_Preserves_Large_Integers(t *testing.T) {
+	var payload map[string]any
+	require.NoError(t, UnmarshalUseNumber([]byte(`{"id":1234567890123456789}`), &payload))
+
+	id, err := payload["id"].(Number).Int64()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234567890123456789), id)
+
+	// plain Unmarshal rounds through float64
+	require.NoError(t, Unmarshal([]byte(`{"id":1234567890123456789}`), &payload))
+	assert.IsType(t, float64(0), payload["id"])
+
+	assert.Error(t, UnmarshalUseNumber([]byte(`{"id":1} trailing`), &payload))
+	assert.Error(t, UnmarshalUseNumber([]byte(`{"id":1} {"id":2}`), &payload))
+	assert.Error(t, UnmarshalUseNumber([]byte(`{"id":`), &payload))
+	assert.NoError(t, UnmarshalUseNumber([]byte(" {\"id\":1} \n"), &payload))
+}
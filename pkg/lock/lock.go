@@ -0,0 +1,255 @@
+// Package lock provides distributed locks backed by Redis, for work that must run on
+// only one instance at a time, such as cron-style jobs. It complements the Postgres
+// advisory lock taken by the migrator for general application use.
+//
+// A lock is a key set with SET NX PX to a random token. Releasing and refreshing
+// compare the token first (atomically, in a Lua script), so an instance whose lock
+// expired can never delete a lock another instance acquired since.
+package lock
+
+import (
+	"context"
+	stdErrors "errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/utils"
+)
+
+var (
+	// ErrNotAcquired is the cause of the CodeConflict error returned when the lock is
+	// held by someone else
+	ErrNotAcquired = stdErrors.New("lock is held by another owner")
+
+	// ErrNotHeld is the cause of the CodeConflict error returned when releasing or
+	// refreshing a lock that has expired or been taken over
+	ErrNotHeld = stdErrors.New("lock is no longer held")
+)
+
+// releaseScript deletes the key only if it still holds our token
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// refreshScript extends the key's TTL only if it still holds our token
+var refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// Locker acquires locks in Redis. It is safe for concurrent use.
+type Locker struct {
+	client redis.Cmdable
+	prefix string
+}
+
+// Option configures a Locker
+type Option func(*Locker)
+
+// WithPrefix namespaces lock keys, defaulting to "lock:"
+func WithPrefix(prefix string) Option {
+	return func(l *Locker) {
+		l.prefix = prefix
+	}
+}
+
+// New creates a Locker using client, e.g. a *redis.Client or *redis.ClusterClient
+//
+// Example:
+//
+//	locker := lock.New(redis.NewClient(&redis.Options{Addr: cfg.Redis.Address()}))
+func New(client redis.Cmdable, opts ...Option) *Locker {
+	l := &Locker{client: client, prefix: "lock:"}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// AcquireOption configures Acquire
+type AcquireOption func(*acquireOptions)
+
+type acquireOptions struct {
+	retryInterval time.Duration
+	autoRenew     bool
+}
+
+// WithRetry makes Acquire wait for a held lock, retrying every interval until the
+// lock is acquired or ctx is done
+func WithRetry(interval time.Duration) AcquireOption {
+	return func(o *acquireOptions) {
+		o.retryInterval = interval
+	}
+}
+
+// WithAutoRenew keeps the lock alive for long tasks by refreshing its TTL every third
+// of the TTL until it is released. If a refresh fails the lock is given up and
+// Lost is closed.
+func WithAutoRenew() AcquireOption {
+	return func(o *acquireOptions) {
+		o.autoRenew = true
+	}
+}
+
+// Acquire takes the lock for key for ttl. When the lock is held elsewhere it returns a
+// CodeConflict error caused by ErrNotAcquired, unless WithRetry is given.
+//
+// Example:
+//
+//	l, err := locker.Acquire(ctx, "jobs:daily-report", time.Minute, lock.WithAutoRenew())
+//	if stdErrors.Is(err, lock.ErrNotAcquired) {
+//	    return nil // another instance is running the job
+//	}
+//	if err != nil {
+//	    return err
+//	}
+//	defer l.Release(context.Background())
+func (l *Locker) Acquire(ctx context.Context, key string, ttl time.Duration, opts ...AcquireOption) (*Lock, error) {
+	if ttl <= 0 {
+		return nil, errors.BadRequest("lock ttl must be positive")
+	}
+
+	options := acquireOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	lock := &Lock{
+		locker: l,
+		key:    l.prefix + key,
+		token:  utils.NewUUID(),
+		ttl:    ttl,
+		lost:   make(chan struct{}),
+	}
+
+	for {
+		acquired, err := l.client.SetNX(ctx, lock.key, lock.token, ttl).Result()
+		if err != nil {
+			return nil, errors.Wrap(err, errors.CodeServiceUnavailable, "failed to acquire lock").
+				WithContext("key", key)
+		}
+		if acquired {
+			break
+		}
+
+		if options.retryInterval <= 0 {
+			return nil, notAcquired(key)
+		}
+
+		timer := time.NewTimer(options.retryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, notAcquired(key)
+		case <-timer.C:
+		}
+	}
+
+	if options.autoRenew {
+		lock.startRenewal()
+	}
+
+	return lock, nil
+}
+
+func notAcquired(key string) *errors.AppError {
+	return errors.Wrap(ErrNotAcquired, errors.CodeConflict, "lock is held by another owner").
+		WithContext("key", key)
+}
+
+// Lock is an acquired lock
+type Lock struct {
+	locker *Locker
+	key    string
+	token  string
+	ttl    time.Duration
+
+	lost     chan struct{}
+	lostOnce sync.Once
+	stop     context.CancelFunc
+	renewing sync.WaitGroup
+}
+
+// Key returns the Redis key of the lock, including the Locker's prefix
+func (l *Lock) Key() string {
+	return l.key
+}
+
+// Lost is closed when auto-renewal fails to refresh the lock, meaning another owner
+// may acquire it. Long tasks should stop when it is closed.
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Refresh extends the lock's TTL to ttl. It returns a CodeConflict error caused by
+// ErrNotHeld when the lock has expired or been taken over.
+func (l *Lock) Refresh(ctx context.Context, ttl time.Duration) error {
+	refreshed, err := refreshScript.Run(ctx, l.locker.client, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return errors.Wrap(err, errors.CodeServiceUnavailable, "failed to refresh lock").
+			WithContext("key", l.key)
+	}
+	if refreshed == 0 {
+		return errors.Wrap(ErrNotHeld, errors.CodeConflict, "lock is no longer held").
+			WithContext("key", l.key)
+	}
+	return nil
+}
+
+// Release stops auto-renewal and deletes the lock if it is still held by us. It
+// returns a CodeConflict error caused by ErrNotHeld when the lock had already expired
+// or been taken over.
+func (l *Lock) Release(ctx context.Context) error {
+	if l.stop != nil {
+		l.stop()
+		l.renewing.Wait()
+	}
+
+	released, err := releaseScript.Run(ctx, l.locker.client, []string{l.key}, l.token).Int()
+	if err != nil {
+		return errors.Wrap(err, errors.CodeServiceUnavailable, "failed to release lock").
+			WithContext("key", l.key)
+	}
+	if released == 0 {
+		return errors.Wrap(ErrNotHeld, errors.CodeConflict, "lock is no longer held").
+			WithContext("key", l.key)
+	}
+	return nil
+}
+
+// startRenewal refreshes the lock every third of its TTL until Release
+func (l *Lock) startRenewal() {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.stop = cancel
+
+	l.renewing.Add(1)
+	go func() {
+		defer l.renewing.Done()
+
+		ticker := time.NewTicker(max(l.ttl/3, time.Millisecond))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := l.Refresh(ctx, l.ttl); err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					l.lostOnce.Do(func() { close(l.lost) })
+					return
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,166 @@
+package lock
+
+import (
+	"context"
+	stdErrors "errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+func newTestLocker(t *testing.T) (*Locker, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return New(client), server
+}
+
+func Test_Acquire_Is_Mutually_Exclusive(t *testing.T) {
+	locker, _ := newTestLocker(t)
+	ctx := context.Background()
+
+	var acquired atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := locker.Acquire(ctx, "job", time.Minute)
+			if err == nil {
+				acquired.Add(1)
+				return
+			}
+			assert.True(t, stdErrors.Is(err, ErrNotAcquired))
+			assert.True(t, errors.Is(err, errors.CodeConflict))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), acquired.Load())
+}
+
+func Test_Release_Allows_Reacquire(t *testing.T) {
+	locker, server := newTestLocker(t)
+	ctx := context.Background()
+
+	l, err := locker.Acquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, server.Exists("lock:job"))
+
+	require.NoError(t, l.Release(ctx))
+	assert.False(t, server.Exists("lock:job"))
+
+	_, err = locker.Acquire(ctx, "job", time.Minute)
+	assert.NoError(t, err)
+}
+
+func Test_Release_Does_Not_Delete_Another_Owners_Lock(t *testing.T) {
+	locker, server := newTestLocker(t)
+	ctx := context.Background()
+
+	first, err := locker.Acquire(ctx, "job", time.Second)
+	require.NoError(t, err)
+
+	server.FastForward(2 * time.Second)
+	second, err := locker.Acquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+
+	err = first.Release(ctx)
+	assert.True(t, stdErrors.Is(err, ErrNotHeld))
+	assert.True(t, server.Exists("lock:job"), "the expired owner must not delete the new lock")
+
+	assert.NoError(t, second.Release(ctx))
+}
+
+func Test_Acquire_WithRetry_Waits_For_Release(t *testing.T) {
+	locker, _ := newTestLocker(t)
+	ctx := context.Background()
+
+	held, err := locker.Acquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = held.Release(ctx)
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	l, err := locker.Acquire(waitCtx, "job", time.Minute, WithRetry(5*time.Millisecond))
+	require.NoError(t, err)
+	assert.NoError(t, l.Release(ctx))
+}
+
+func Test_Acquire_WithRetry_Gives_Up_When_Context_Done(t *testing.T) {
+	locker, _ := newTestLocker(t)
+	ctx := context.Background()
+
+	_, err := locker.Acquire(ctx, "job", time.Minute)
+	require.NoError(t, err)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+	defer cancel()
+	_, err = locker.Acquire(waitCtx, "job", time.Minute, WithRetry(5*time.Millisecond))
+	assert.True(t, stdErrors.Is(err, ErrNotAcquired))
+}
+
+func Test_Refresh_Extends_TTL(t *testing.T) {
+	locker, server := newTestLocker(t)
+	ctx := context.Background()
+
+	l, err := locker.Acquire(ctx, "job", time.Second)
+	require.NoError(t, err)
+
+	require.NoError(t, l.Refresh(ctx, time.Minute))
+	assert.Equal(t, time.Minute, server.TTL("lock:job"))
+}
+
+func Test_AutoRenew_Keeps_Lock_Alive(t *testing.T) {
+	locker, server := newTestLocker(t)
+	ctx := context.Background()
+
+	l, err := locker.Acquire(ctx, "job", 60*time.Millisecond, WithAutoRenew())
+	require.NoError(t, err)
+
+	// miniredis only expires keys on FastForward, so shorten the TTL by hand and
+	// check the renewal restores it
+	server.SetTTL("lock:job", time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return server.TTL("lock:job") > 10*time.Millisecond
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, l.Release(ctx))
+	assert.False(t, server.Exists("lock:job"))
+}
+
+func Test_AutoRenew_Signals_Lost_Lock(t *testing.T) {
+	locker, server := newTestLocker(t)
+	ctx := context.Background()
+
+	l, err := locker.Acquire(ctx, "job", 30*time.Millisecond, WithAutoRenew())
+	require.NoError(t, err)
+
+	require.NoError(t, server.Set("lock:job", "someone-else"))
+
+	select {
+	case <-l.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("lost lock was not signaled")
+	}
+}
+
+func Test_Acquire_Rejects_Invalid_TTL(t *testing.T) {
+	locker, _ := newTestLocker(t)
+
+	_, err := locker.Acquire(context.Background(), "job", 0)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
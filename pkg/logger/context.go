@@ -0,0 +1,17 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/phatnt199/go-infra/pkg/correlation"
+)
+
+// ContextFields returns the log fields carried by ctx, such as the correlation ID
+// under correlation.FieldName. The returned map is never nil and can be extended.
+func ContextFields(ctx context.Context) Fields {
+	fields := Fields{}
+	if id := correlation.FromContext(ctx); id != "" {
+		fields[correlation.FieldName] = id
+	}
+	return fields
+}
@@ -23,6 +23,13 @@ func (e emptyLogger) Debugf(template string, args ...interface{}) {
 func (e emptyLogger) Debugw(msg string, fields logger.Fields) {
 }
 
+func (e emptyLogger) DebugFields(msg string, fields ...logger.Field) {
+}
+
+func (e emptyLogger) SetLevel(level string) error {
+	return nil
+}
+
 func (e emptyLogger) LogType() models.LogType {
 	return models.Zap
 }
@@ -36,6 +43,9 @@ func (e emptyLogger) Infof(template string, args ...interface{}) {
 func (e emptyLogger) Infow(msg string, fields logger.Fields) {
 }
 
+func (e emptyLogger) InfoFields(msg string, fields ...logger.Field) {
+}
+
 func (e emptyLogger) Warn(args ...interface{}) {
 }
 
@@ -45,12 +55,21 @@ func (e emptyLogger) Warnf(template string, args ...interface{}) {
 func (e emptyLogger) WarnMsg(msg string, err error) {
 }
 
+func (e emptyLogger) Warnw(msg string, fields logger.Fields) {
+}
+
+func (e emptyLogger) WarnFields(msg string, fields ...logger.Field) {
+}
+
 func (e emptyLogger) Error(args ...interface{}) {
 }
 
 func (e emptyLogger) Errorw(msg string, fields logger.Fields) {
 }
 
+func (e emptyLogger) ErrorFields(msg string, fields ...logger.Field) {
+}
+
 func (e emptyLogger) Errorf(template string, args ...interface{}) {
 }
 
@@ -0,0 +1,73 @@
+package logger
+
+import "time"
+
+// Field is a typed log field passed to the *Fields logging methods. Unlike Fields map
+// literals, a []Field keeps insertion order, lets the compiler check keys and values,
+// and is converted to zap fields without allocating a map.
+//
+// Example:
+//
+//	log.InfoFields("order created",
+//	    logger.String("order_id", order.ID),
+//	    logger.Int("items", len(order.Items)),
+//	    logger.Duration("elapsed", time.Since(start)),
+//	)
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// String creates a string field
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int creates an int field
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 creates an int64 field
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 creates a float64 field
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool creates a bool field
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration creates a time.Duration field
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time creates a time.Time field
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err creates an "error" field holding err
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// Any creates a field holding an arbitrary value, serialized by reflection
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// FieldsOf converts a Fields map, such as the one returned by ContextFields, to typed fields
+func FieldsOf(fields Fields) []Field {
+	result := make([]Field, 0, len(fields))
+	for key, value := range fields {
+		result = append(result, Field{Key: key, Value: value})
+	}
+	return result
+}
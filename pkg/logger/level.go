@@ -0,0 +1,20 @@
+package logger
+
+// DebugEnabler is implemented by loggers that can report whether debug messages are
+// logged, so callers can skip building expensive debug output
+type DebugEnabler interface {
+	DebugEnabled() bool
+}
+
+// DebugEnabled reports whether l logs debug messages. Loggers not implementing
+// DebugEnabler are assumed not to.
+//
+// Example:
+//
+//	if logger.DebugEnabled(log) {
+//	    log.DebugFields("cache state", logger.Any("entries", cache.Dump()))
+//	}
+func DebugEnabled(l Logger) bool {
+	enabler, ok := l.(DebugEnabler)
+	return ok && enabler.DebugEnabled()
+}
@@ -13,21 +13,27 @@ type Logger interface {
 	Debug(args ...interface{})
 	Debugf(template string, args ...interface{})
 	Debugw(msg string, fields Fields)
+	DebugFields(msg string, fields ...Field)
 	LogType() models.LogType
 	Info(args ...interface{})
 	Infof(template string, args ...interface{})
 	Infow(msg string, fields Fields)
+	InfoFields(msg string, fields ...Field)
 	Warn(args ...interface{})
 	Warnf(template string, args ...interface{})
 	WarnMsg(msg string, err error)
+	Warnw(msg string, fields Fields)
+	WarnFields(msg string, fields ...Field)
 	Error(args ...interface{})
 	Errorw(msg string, fields Fields)
+	ErrorFields(msg string, fields ...Field)
 	Errorf(template string, args ...interface{})
 	Err(msg string, err error)
 	Fatal(args ...interface{})
 	Fatalf(template string, args ...interface{})
 	Printf(template string, args ...interface{})
 	WithName(name string)
+	SetLevel(level string) error
 	GrpcMiddlewareAccessLogger(
 		method string,
 		time time.Duration,
@@ -4,6 +4,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/phatnt199/go-infra/pkg/application/config"
 	"github.com/phatnt199/go-infra/pkg/application/constants"
 	"github.com/phatnt199/go-infra/pkg/application/environment"
 	"github.com/phatnt199/go-infra/pkg/logger"
@@ -18,6 +19,7 @@ import (
 
 type zapLogger struct {
 	level       string
+	atomicLevel zap.AtomicLevel
 	sugarLogger *zap.SugaredLogger
 	logger      *zap.Logger
 	logOptions  *config2.LogOptions
@@ -105,7 +107,8 @@ func (l *zapLogger) initLogger(env environment.Environment) {
 		encoder = zapcore.NewConsoleEncoder(encoderCfg)
 	}
 
-	core := zapcore.NewCore(encoder, logWriter, zap.NewAtomicLevelAt(logLevel))
+	l.atomicLevel = zap.NewAtomicLevelAt(logLevel)
+	core := zapcore.NewCore(encoder, logWriter, l.atomicLevel)
 
 	var options []zap.Option
 
@@ -154,6 +157,29 @@ func (l *zapLogger) Debugw(msg string, fields logger.Fields) {
 	l.logger.Debug(msg, zapFields...)
 }
 
+// DebugFields logs a message with typed fields, without building a map.
+func (l *zapLogger) DebugFields(msg string, fields ...logger.Field) {
+	l.logger.Debug(msg, toZapFields(fields)...)
+}
+
+// SetLevel changes the minimum logged level at runtime. Level must be one of the
+// levels accepted by LoggerConfig.
+func (l *zapLogger) SetLevel(level string) error {
+	if err := config.ValidateLogLevel(level); err != nil {
+		return err
+	}
+
+	l.atomicLevel.SetLevel(loggerLevelMap[level])
+	l.level = level
+
+	return nil
+}
+
+// DebugEnabled reports whether debug messages are logged
+func (l *zapLogger) DebugEnabled() bool {
+	return l.logger.Core().Enabled(zapcore.DebugLevel)
+}
+
 // Info uses fmt.Sprint to construct and log a message
 func (l *zapLogger) Info(args ...interface{}) {
 	l.sugarLogger.Info(args...)
@@ -170,6 +196,11 @@ func (l *zapLogger) Infow(msg string, fields logger.Fields) {
 	l.logger.Info(msg, zapFields...)
 }
 
+// InfoFields logs a message with typed fields, without building a map.
+func (l *zapLogger) InfoFields(msg string, fields ...logger.Field) {
+	l.logger.Info(msg, toZapFields(fields)...)
+}
+
 // Printf uses fmt.Sprintf to log a templated message
 func (l *zapLogger) Printf(template string, args ...interface{}) {
 	l.sugarLogger.Infof(template, args...)
@@ -185,6 +216,17 @@ func (l *zapLogger) WarnMsg(msg string, err error) {
 	l.logger.Warn(msg, zap.String("error", err.Error()))
 }
 
+// Warnw logs a message with some additional context.
+func (l *zapLogger) Warnw(msg string, fields logger.Fields) {
+	zapFields := mapToZapFields(fields)
+	l.logger.Warn(msg, zapFields...)
+}
+
+// WarnFields logs a message with typed fields, without building a map.
+func (l *zapLogger) WarnFields(msg string, fields ...logger.Field) {
+	l.logger.Warn(msg, toZapFields(fields)...)
+}
+
 // Warnf uses fmt.Sprintf to log a templated message.
 func (l *zapLogger) Warnf(template string, args ...interface{}) {
 	l.sugarLogger.Warnf(template, args...)
@@ -201,6 +243,11 @@ func (l *zapLogger) Errorw(msg string, fields logger.Fields) {
 	l.logger.Error(msg, zapFields...)
 }
 
+// ErrorFields logs a message with typed fields, without building a map.
+func (l *zapLogger) ErrorFields(msg string, fields ...logger.Field) {
+	l.logger.Error(msg, toZapFields(fields)...)
+}
+
 // Errorf uses fmt.Sprintf to log a templated message.
 func (l *zapLogger) Errorf(template string, args ...interface{}) {
 	l.sugarLogger.Errorf(template, args...)
@@ -295,6 +342,16 @@ func mapToZapFields(data map[string]interface{}) []zap.Field {
 	return fields
 }
 
+func toZapFields(fields []logger.Field) []zap.Field {
+	zapFields := make([]zap.Field, len(fields))
+
+	for i, field := range fields {
+		zapFields[i] = convertToZapField(field.Key, field.Value)
+	}
+
+	return zapFields
+}
+
 func convertToZapField(key string, value interface{}) zap.Field {
 	switch v := value.(type) {
 	case string:
@@ -0,0 +1,104 @@
+package zap
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/phatnt199/go-infra/pkg/logger"
+)
+
+func newObservedLogger() (*zapLogger, *observer.ObservedLogs) {
+	level := zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	core, logs := observer.New(level)
+	z := zap.New(core)
+	return &zapLogger{level: "debug", atomicLevel: level, logger: z, sugarLogger: z.Sugar()}, logs
+}
+
+func Test_InfoFields_Writes_Typed_Fields_In_Order(t *testing.T) {
+	l, logs := newObservedLogger()
+
+	l.InfoFields("order created",
+		logger.String("order_id", "o-1"),
+		logger.Int("items", 3),
+		logger.Duration("elapsed", 150*time.Millisecond),
+		logger.Err(errors.New("boom")),
+	)
+
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, zapcore.InfoLevel, entry.Level)
+	assert.Equal(t, "order created", entry.Message)
+
+	keys := make([]string, len(entry.Context))
+	for i, field := range entry.Context {
+		keys[i] = field.Key
+	}
+	assert.Equal(t, []string{"order_id", "items", "elapsed", "error"}, keys)
+	assert.Equal(t, zapcore.Int64Type, entry.Context[1].Type)
+	assert.Equal(t, map[string]interface{}{
+		"order_id": "o-1",
+		"items":    int64(3),
+		"elapsed":  150 * time.Millisecond,
+		"error":    "boom",
+	}, entry.ContextMap())
+}
+
+func Test_Fields_Methods_Use_Their_Level(t *testing.T) {
+	l, logs := newObservedLogger()
+
+	l.DebugFields("debug")
+	l.WarnFields("warn", logger.Bool("retry", true))
+	l.ErrorFields("error", logger.FieldsOf(logger.Fields{"attempt": 2})...)
+
+	levels := make([]zapcore.Level, 0, logs.Len())
+	for _, entry := range logs.All() {
+		levels = append(levels, entry.Level)
+	}
+	assert.Equal(t, []zapcore.Level{zapcore.DebugLevel, zapcore.WarnLevel, zapcore.ErrorLevel}, levels)
+	assert.Equal(t, int64(2), logs.All()[2].ContextMap()["attempt"])
+}
+
+func Test_DebugEnabled_Follows_Core_Level(t *testing.T) {
+	l, _ := newObservedLogger()
+	assert.True(t, logger.DebugEnabled(l))
+
+	core, _ := observer.New(zapcore.InfoLevel)
+	z := zap.New(core)
+	assert.False(t, logger.DebugEnabled(&zapLogger{logger: z, sugarLogger: z.Sugar()}))
+}
+
+func Test_SetLevel_Toggles_Filtering(t *testing.T) {
+	l, logs := newObservedLogger()
+
+	require.NoError(t, l.SetLevel("warn"))
+	l.Debug("hidden")
+	l.Info("hidden")
+	l.Warn("shown")
+	assert.False(t, logger.DebugEnabled(l))
+
+	require.NoError(t, l.SetLevel("debug"))
+	l.Debug("shown again")
+	assert.True(t, logger.DebugEnabled(l))
+
+	messages := make([]string, 0, logs.Len())
+	for _, entry := range logs.All() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"shown", "shown again"}, messages)
+}
+
+func Test_SetLevel_Rejects_Unknown_Level(t *testing.T) {
+	l, _ := newObservedLogger()
+
+	err := l.SetLevel("trace")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "logger.level")
+	assert.True(t, logger.DebugEnabled(l))
+}
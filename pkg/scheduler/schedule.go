@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// Schedule returns the next activation time after a given time
+type Schedule interface {
+	Next(time.Time) time.Time
+}
+
+// cronParser accepts standard 5-field expressions, an optional leading seconds field
+// and descriptors such as "@hourly" or "@every 5m"
+var cronParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// ParseCron parses a cron expression, e.g. "0 3 * * *", "*/30 * * * * *" (with
+// seconds) or "@every 10m"
+func ParseCron(spec string) (Schedule, error) {
+	schedule, err := cronParser.Parse(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CodeInvalidInput, "invalid cron expression").
+			WithContext("spec", spec)
+	}
+	if every, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		// Align "@every" like Every, so instances agree on activation times
+		return Every(every.Delay), nil
+	}
+	return schedule, nil
+}
+
+// Every returns a schedule activating every interval, at the multiples of interval
+// since the Unix epoch (every 10m activates at :00, :10, ...). Intervals shorter than a
+// second are supported.
+func Every(interval time.Duration) Schedule {
+	return everySchedule{interval: max(interval, time.Millisecond)}
+}
+
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(s.interval).Add(s.interval)
+}
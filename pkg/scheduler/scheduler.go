@@ -0,0 +1,247 @@
+// Package scheduler runs recurring jobs on cron schedules. Jobs run with panic
+// recovery and structured logging, optionally guarded by a distributed lock so that
+// only one instance runs each activation. Module wires the scheduler into the fx
+// lifecycle.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/lock"
+	"github.com/phatnt199/go-infra/pkg/logger"
+)
+
+// Job is the work run on every activation of a schedule
+type Job func(ctx context.Context) error
+
+// JobOption configures a job
+type JobOption func(*job)
+
+// WithLock runs each activation of the job on one instance only. The instance that
+// acquires the lock "scheduler:<name>:<activation>" runs it and leaves the lock to
+// expire after ttl (a minute when ttl <= 0), so instances whose timers fire later
+// skip the activation; ttl must exceed the clock skew between instances. While the
+// job runs it also holds "scheduler:<name>", renewed and released when it returns, so
+// activations never overlap across instances.
+//
+// Activation times are shared by every instance: cron schedules and Every are aligned
+// to the clock, not to the start of the scheduler.
+func WithLock(locker *lock.Locker, ttl time.Duration) JobOption {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	return func(j *job) {
+		j.locker = locker
+		j.lockTTL = ttl
+	}
+}
+
+// WithTimeout cancels the context of a job's run after timeout
+func WithTimeout(timeout time.Duration) JobOption {
+	return func(j *job) {
+		j.timeout = timeout
+	}
+}
+
+type job struct {
+	name     string
+	schedule Schedule
+	run      Job
+	locker   *lock.Locker
+	lockTTL  time.Duration
+	timeout  time.Duration
+}
+
+// Scheduler runs registered jobs on their schedules. Activations of a job never
+// overlap: an activation due while the previous run is still going is skipped.
+// It is safe for concurrent use.
+type Scheduler struct {
+	logger logger.Logger
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+}
+
+// New creates a stopped scheduler logging to log
+//
+// Example:
+//
+//	s := scheduler.New(log)
+//	err := s.Add("cleanup-sessions", "0 3 * * *", func(ctx context.Context) error {
+//	    return sessions.DeleteExpired(ctx)
+//	}, scheduler.WithLock(locker, time.Minute))
+//	s.Start()
+//	defer s.Stop(context.Background())
+func New(log logger.Logger) *Scheduler {
+	return &Scheduler{logger: log, jobs: map[string]*job{}}
+}
+
+// Add registers a job running on a cron expression (see ParseCron)
+func (s *Scheduler) Add(name, spec string, run Job, opts ...JobOption) error {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	return s.AddSchedule(name, schedule, run, opts...)
+}
+
+// AddSchedule registers a job running on schedule. Jobs added after Start begin
+// running immediately.
+func (s *Scheduler) AddSchedule(name string, schedule Schedule, run Job, opts ...JobOption) error {
+	if name == "" || schedule == nil || run == nil {
+		return errors.BadRequest("job name, schedule and function are required")
+	}
+
+	j := &job{name: name, schedule: schedule, run: run}
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[name]; exists {
+		return errors.AlreadyExists("job").WithContext("job", name)
+	}
+	s.jobs[name] = j
+
+	if s.ctx != nil {
+		s.startJob(s.ctx, j)
+	}
+	return nil
+}
+
+// Start starts running the registered jobs. Calling Start on a started scheduler
+// does nothing.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx != nil {
+		return
+	}
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for _, j := range s.jobs {
+		s.startJob(s.ctx, j)
+	}
+}
+
+// Stop stops scheduling and cancels the context of running jobs, then waits for them
+// to return or ctx to be done
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.ctx, s.cancel = nil, nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), errors.CodeTimeout, "timed out waiting for scheduled jobs to stop")
+	}
+}
+
+// startJob runs j's activations until ctx is canceled. s.mu must be held.
+func (s *Scheduler) startJob(ctx context.Context, j *job) {
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+
+		next := j.schedule.Next(time.Now())
+		for {
+			if next.IsZero() {
+				return
+			}
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			s.execute(ctx, j, next)
+			next = j.schedule.Next(time.Now())
+		}
+	}()
+}
+
+// execute runs the activation of j due at activation, recovering panics
+func (s *Scheduler) execute(ctx context.Context, j *job, activation time.Time) {
+	fields := []logger.Field{logger.String("job", j.name)}
+
+	if j.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.timeout)
+		defer cancel()
+	}
+
+	if j.locker != nil {
+		// The activation lock is never released: it expires after the TTL, once every
+		// instance is past the activation
+		activationKey := fmt.Sprintf("scheduler:%s:%d", j.name, activation.UnixMilli())
+		if _, err := j.locker.Acquire(ctx, activationKey, j.lockTTL); err != nil {
+			s.logLockError(err, fields)
+			return
+		}
+
+		l, err := j.locker.Acquire(ctx, "scheduler:"+j.name, j.lockTTL, lock.WithAutoRenew())
+		if err != nil {
+			s.logLockError(err, fields)
+			return
+		}
+		defer func() { _ = l.Release(context.Background()) }()
+	}
+
+	start := time.Now()
+	err := s.runRecovered(ctx, j)
+	fields = append(fields, logger.Duration("duration", time.Since(start)))
+
+	if err != nil {
+		s.logger.ErrorFields("scheduled job failed", append(fields, logger.Err(err))...)
+		return
+	}
+	s.logger.InfoFields("scheduled job completed", fields...)
+}
+
+// logLockError logs why an activation was skipped
+func (s *Scheduler) logLockError(err error, fields []logger.Field) {
+	if errors.Is(err, errors.CodeConflict) {
+		s.logger.DebugFields("scheduled job skipped, locked by another instance", fields...)
+		return
+	}
+	s.logger.ErrorFields("scheduled job lock failed", append(fields, logger.Err(err))...)
+}
+
+func (s *Scheduler) runRecovered(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Internal(fmt.Sprintf("scheduled job panicked: %v", r)).
+				WithContext("stack", string(debug.Stack()))
+		}
+	}()
+
+	return j.run(ctx)
+}
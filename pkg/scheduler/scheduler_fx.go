@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/phatnt199/go-infra/pkg/logger"
+
+	"go.uber.org/fx"
+)
+
+var (
+	// Module provides a *Scheduler, started when the application starts and stopped
+	// (waiting for running jobs) when it stops. Register jobs from fx.Invoke functions.
+	Module = fx.Module(
+		"schedulerfx",
+		schedulerProviders,
+		schedulerInvokes,
+	)
+
+	schedulerProviders = fx.Options(fx.Provide(ProvideScheduler))
+
+	schedulerInvokes = fx.Options(fx.Invoke(registerHooks))
+)
+
+// ProvideScheduler creates the application's scheduler
+func ProvideScheduler(log logger.Logger) *Scheduler {
+	return New(log)
+}
+
+// registerHooks starts the scheduler on start and stops it on stop
+func registerHooks(lc fx.Lifecycle, s *Scheduler) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			s.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return s.Stop(ctx)
+		},
+	})
+}
@@ -0,0 +1,241 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/lock"
+	"github.com/phatnt199/go-infra/pkg/logger"
+	"github.com/phatnt199/go-infra/pkg/logger/empty"
+)
+
+// captureLogger records the messages logged at error level
+type captureLogger struct {
+	logger.Logger
+
+	mu     sync.Mutex
+	errors []string
+}
+
+func newCaptureLogger() *captureLogger {
+	return &captureLogger{Logger: empty.EmptyLogger}
+}
+
+func (l *captureLogger) ErrorFields(msg string, fields ...logger.Field) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errors = append(l.errors, msg)
+}
+
+func (l *captureLogger) errorCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.errors)
+}
+
+func Test_Scheduler_Runs_Job_On_Interval(t *testing.T) {
+	s := New(newCaptureLogger())
+	var runs atomic.Int32
+	require.NoError(t, s.AddSchedule("tick", Every(10*time.Millisecond), func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}))
+
+	s.Start()
+	defer s.Stop(context.Background())
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+}
+
+func Test_Scheduler_Survives_Panicking_Job(t *testing.T) {
+	log := newCaptureLogger()
+	s := New(log)
+
+	var panics, healthy atomic.Int32
+	require.NoError(t, s.AddSchedule("panics", Every(10*time.Millisecond), func(context.Context) error {
+		panics.Add(1)
+		panic("boom")
+	}))
+	require.NoError(t, s.AddSchedule("healthy", Every(10*time.Millisecond), func(context.Context) error {
+		healthy.Add(1)
+		return nil
+	}))
+
+	s.Start()
+	defer s.Stop(context.Background())
+
+	assert.Eventually(t, func() bool {
+		return panics.Load() >= 3 && healthy.Load() >= 3
+	}, time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, log.errorCount(), 3)
+}
+
+func Test_Scheduler_Logs_Job_Errors(t *testing.T) {
+	log := newCaptureLogger()
+	s := New(log)
+	require.NoError(t, s.AddSchedule("fails", Every(10*time.Millisecond), func(context.Context) error {
+		return errors.Internal("failed")
+	}))
+
+	s.Start()
+	defer s.Stop(context.Background())
+
+	assert.Eventually(t, func() bool { return log.errorCount() >= 1 }, time.Second, 5*time.Millisecond)
+}
+
+func Test_Scheduler_Job_Added_After_Start_Runs(t *testing.T) {
+	s := New(newCaptureLogger())
+	s.Start()
+	defer s.Stop(context.Background())
+
+	var runs atomic.Int32
+	require.NoError(t, s.AddSchedule("late", Every(10*time.Millisecond), func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}))
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 1 }, time.Second, 5*time.Millisecond)
+}
+
+func Test_Scheduler_Stop_Cancels_And_Waits_For_Running_Jobs(t *testing.T) {
+	s := New(newCaptureLogger())
+	started := make(chan struct{})
+	var finished atomic.Bool
+	require.NoError(t, s.AddSchedule("slow", Every(time.Millisecond), func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-ctx.Done()
+		finished.Store(true)
+		return ctx.Err()
+	}))
+
+	s.Start()
+	<-started
+
+	require.NoError(t, s.Stop(context.Background()))
+	assert.True(t, finished.Load())
+}
+
+func Test_Scheduler_Add_Validates(t *testing.T) {
+	s := New(newCaptureLogger())
+	noop := func(context.Context) error { return nil }
+
+	assert.True(t, errors.Is(s.Add("bad", "not a cron", noop), errors.CodeInvalidInput))
+	assert.True(t, errors.Is(s.AddSchedule("", Every(time.Second), noop), errors.CodeBadRequest))
+
+	require.NoError(t, s.Add("nightly", "0 3 * * *", noop))
+	assert.True(t, errors.Is(s.Add("nightly", "@hourly", noop), errors.CodeAlreadyExists))
+}
+
+func Test_ParseCron(t *testing.T) {
+	from := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
+
+	schedule, err := ParseCron("0 3 * * *")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC), schedule.Next(from))
+
+	schedule, err = ParseCron("*/15 * * * * *")
+	require.NoError(t, err)
+	assert.Equal(t, from.Add(15*time.Second), schedule.Next(from))
+
+	schedule, err = ParseCron("@every 10m")
+	require.NoError(t, err)
+	assert.Equal(t, from.Add(10*time.Minute), schedule.Next(from))
+}
+
+func Test_Scheduler_WithLock_Runs_On_One_Instance_At_A_Time(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	locker := lock.New(client)
+
+	var active, maxActive, runs atomic.Int32
+	job := func(context.Context) error {
+		current := active.Add(1)
+		defer active.Add(-1)
+		for {
+			seen := maxActive.Load()
+			if current <= seen || maxActive.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		runs.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}
+
+	for i := 0; i < 3; i++ {
+		s := New(newCaptureLogger())
+		require.NoError(t, s.AddSchedule("report", Every(5*time.Millisecond), job, WithLock(locker, time.Second)))
+		s.Start()
+		t.Cleanup(func() { _ = s.Stop(context.Background()) })
+	}
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(1), maxActive.Load())
+}
+
+func Test_Scheduler_WithLock_Runs_Each_Activation_Once(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	locker := lock.New(client)
+
+	var runs atomic.Int32
+	newJob := func() *job {
+		return &job{name: "report", locker: locker, lockTTL: time.Minute, run: func(context.Context) error {
+			runs.Add(1)
+			return nil
+		}}
+	}
+	first, second := New(newCaptureLogger()), New(newCaptureLogger())
+	activation := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
+
+	// The second instance's timer fires after the first run has finished
+	first.execute(context.Background(), newJob(), activation)
+	second.execute(context.Background(), newJob(), activation)
+	assert.Equal(t, int32(1), runs.Load())
+
+	second.execute(context.Background(), newJob(), activation.Add(time.Minute))
+	assert.Equal(t, int32(2), runs.Load())
+}
+
+func Test_Every_Is_Aligned_To_The_Clock(t *testing.T) {
+	schedule := Every(10 * time.Minute)
+	assert.Equal(t, time.Date(2025, 1, 1, 10, 40, 0, 0, time.UTC), schedule.Next(time.Date(2025, 1, 1, 10, 33, 12, 0, time.UTC)))
+}
+
+func Test_Module_Starts_And_Stops_Scheduler(t *testing.T) {
+	var runs atomic.Int32
+	app := fxtest.New(t,
+		fx.Provide(func() logger.Logger { return newCaptureLogger() }),
+		Module,
+		fx.Invoke(func(s *Scheduler) error {
+			return s.AddSchedule("tick", Every(10*time.Millisecond), func(context.Context) error {
+				runs.Add(1)
+				return nil
+			})
+		}),
+	)
+
+	app.RequireStart()
+	assert.Eventually(t, func() bool { return runs.Load() >= 1 }, time.Second, 5*time.Millisecond)
+	app.RequireStop()
+
+	stopped := runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load())
+}
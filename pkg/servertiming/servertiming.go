@@ -0,0 +1,155 @@
+// Package servertiming records how long the phases of a request take (database,
+// cache, external calls) and reports them in the Server-Timing response header
+// (https://www.w3.org/TR/server-timing/), which browser dev tools display next to the
+// request. It shows where latency goes without setting up tracing.
+//
+// The HTTP adapters store a Timing in the request context with NewContext and write
+// the header when the handler returns. Code handling the request records its phases:
+//
+//	defer servertiming.Start(ctx, "cache").Stop()
+//
+// The postgres and mysql clients record their queries as "db" and httpclient records
+// its calls as "external". Without a Timing in the context, recording does nothing.
+package servertiming
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HeaderName is the HTTP response header carrying the timings
+const HeaderName = "Server-Timing"
+
+// Metric is the total time spent in one phase of a request
+type Metric struct {
+	Name     string
+	Duration time.Duration
+	// Count is the number of spans recorded under Name, e.g. queries
+	Count int
+}
+
+// Timing collects the metrics of one request. The nil *Timing records nothing, so
+// callers don't need to check whether the request has one. It is safe for concurrent
+// use.
+type Timing struct {
+	mu      sync.Mutex
+	metrics []Metric
+}
+
+// Span is a phase being timed, stopped with Stop
+type Span struct {
+	timing *Timing
+	name   string
+	start  time.Time
+	once   sync.Once
+}
+
+type contextKey struct{}
+
+// New creates an empty Timing
+func New() *Timing {
+	return &Timing{}
+}
+
+// NewContext returns a copy of ctx carrying t
+func NewContext(ctx context.Context, t *Timing) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the Timing carried by ctx, or nil
+func FromContext(ctx context.Context) *Timing {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(contextKey{}).(*Timing)
+	return t
+}
+
+// Start starts timing the phase name of the request of ctx
+func Start(ctx context.Context, name string) *Span {
+	return FromContext(ctx).Start(name)
+}
+
+// Start starts timing the phase name. Spans with the same name add up, so every
+// query of a request counts towards "db".
+func (t *Timing) Start(name string) *Span {
+	if t == nil {
+		return nil
+	}
+	return &Span{timing: t, name: name, start: time.Now()}
+}
+
+// Stop records the time elapsed since Start. Only the first call records.
+func (s *Span) Stop() {
+	if s == nil {
+		return
+	}
+	s.once.Do(func() {
+		s.timing.Record(s.name, time.Since(s.start))
+	})
+}
+
+// Record adds a span of duration d to the phase name, e.g. when the start time is
+// already known
+func (t *Timing) Record(name string, d time.Duration) {
+	if t == nil {
+		return
+	}
+
+	name = metricName(name)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i := range t.metrics {
+		if t.metrics[i].Name == name {
+			t.metrics[i].Duration += d
+			t.metrics[i].Count++
+			return
+		}
+	}
+	t.metrics = append(t.metrics, Metric{Name: name, Duration: d, Count: 1})
+}
+
+// Metrics returns the recorded metrics, in the order their phases were first recorded
+func (t *Timing) Metrics() []Metric {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Metric(nil), t.metrics...)
+}
+
+// Header formats the metrics as a Server-Timing header value, durations in
+// milliseconds, e.g. `db;dur=12.5;desc="3 calls", external;dur=80.1`
+func (t *Timing) Header() string {
+	metrics := t.Metrics()
+	entries := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		entry := m.Name + ";dur=" + strconv.FormatFloat(float64(m.Duration.Microseconds())/1000, 'f', -1, 64)
+		if m.Count > 1 {
+			entry += `;desc="` + strconv.Itoa(m.Count) + ` calls"`
+		}
+		entries = append(entries, entry)
+	}
+	return strings.Join(entries, ", ")
+}
+
+// metricName makes name a valid header token: other characters become "_"
+func metricName(name string) string {
+	if name == "" {
+		return "unnamed"
+	}
+	return strings.Map(func(r rune) rune {
+		if r < 0x80 && (r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' ||
+			strings.ContainsRune("!#$%&'*+-.^_`|~", r)) {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,73 @@
+package servertiming
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Timing_Adds_Up_Spans_By_Name(t *testing.T) {
+	timing := New()
+	timing.Record("db", 10*time.Millisecond)
+	timing.Record("external", 80*time.Millisecond+100*time.Microsecond)
+	timing.Record("db", 2500*time.Microsecond)
+
+	assert.Equal(t, []Metric{
+		{Name: "db", Duration: 12500 * time.Microsecond, Count: 2},
+		{Name: "external", Duration: 80100 * time.Microsecond, Count: 1},
+	}, timing.Metrics())
+	assert.Equal(t, `db;dur=12.5;desc="2 calls", external;dur=80.1`, timing.Header())
+}
+
+func Test_Span_Stop_Records_Once(t *testing.T) {
+	timing := New()
+	ctx := NewContext(context.Background(), timing)
+
+	span := Start(ctx, "cache")
+	span.Stop()
+	span.Stop()
+
+	metrics := timing.Metrics()
+	assert.Len(t, metrics, 1)
+	assert.Equal(t, 1, metrics[0].Count)
+	assert.Same(t, timing, FromContext(ctx))
+}
+
+func Test_Timing_Without_Context_Records_Nothing(t *testing.T) {
+	assert.Nil(t, FromContext(context.Background()))
+
+	span := Start(context.Background(), "db")
+	span.Stop()
+	FromContext(context.Background()).Record("db", time.Second)
+
+	var timing *Timing
+	assert.Empty(t, timing.Metrics())
+	assert.Empty(t, timing.Header())
+}
+
+func Test_Timing_Sanitizes_Names(t *testing.T) {
+	timing := New()
+	timing.Record("db query, users", time.Millisecond)
+	timing.Record("", time.Millisecond)
+
+	assert.Equal(t, "db_query__users;dur=1, unnamed;dur=1", timing.Header())
+}
+
+func Test_Timing_Is_Safe_For_Concurrent_Use(t *testing.T) {
+	timing := New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			timing.Start("db").Stop()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, timing.Metrics()[0].Count)
+}
@@ -192,6 +192,24 @@ func DefaultIfZero[T comparable](value, defaultValue T) T {
 	return value
 }
 
+// FirstNonZero returns the first value that isn't the zero value for its type, or the
+// zero value if all are zero. It is the non-pointer counterpart of Coalesce and extends
+// DefaultIfZero to any number of inputs, e.g. when layering config sources.
+//
+// Example:
+//
+//	host := utils.FirstNonZero(fileCfg.Host, os.Getenv("HOST"), "localhost")
+//	port := utils.FirstNonZero(0, envPort, 8080)  // envPort if set, else 8080
+func FirstNonZero[T comparable](values ...T) T {
+	var zero T
+	for _, v := range values {
+		if v != zero {
+			return v
+		}
+	}
+	return zero
+}
+
 // Swap swaps two values.
 //
 // Example:
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FirstNonZero_Strings(t *testing.T) {
+	assert.Equal(t, "env", FirstNonZero("", "env", "default"))
+	assert.Equal(t, "file", FirstNonZero("file", "env", "default"))
+	assert.Equal(t, "", FirstNonZero("", ""))
+	assert.Equal(t, "", FirstNonZero[string]())
+}
+
+func Test_FirstNonZero_Ints(t *testing.T) {
+	assert.Equal(t, 8080, FirstNonZero(0, 0, 8080))
+	assert.Equal(t, -1, FirstNonZero(0, -1, 8080))
+	assert.Equal(t, 0, FirstNonZero(0, 0))
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// Canceler releases the resources of a context created by WithTimeout
+type Canceler struct {
+	cancel context.CancelFunc
+}
+
+// Close cancels the context. It is safe to call more than once and always returns nil,
+// so it can be deferred directly or used as an io.Closer.
+func (c *Canceler) Close() error {
+	c.cancel()
+	return nil
+}
+
+// WithTimeout is context.WithTimeout returning a Canceler, so that the cancel can't be
+// forgotten behind a discarded function value: defer its Close right away.
+//
+// Example:
+//
+//	ctx, timeout := utils.WithTimeout(ctx, 2*time.Second)
+//	defer timeout.Close()
+func WithTimeout(ctx context.Context, d time.Duration) (context.Context, *Canceler) {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	return ctx, &Canceler{cancel: cancel}
+}
+
+// DoWithTimeout runs fn with a context that expires after d. It returns fn's error, or
+// a CodeTimeout error when the deadline passes first, without waiting for fn: fn keeps
+// running in the background until it returns, so it should honor ctx. Panics in fn are
+// returned as errors.
+//
+// Example:
+//
+//	err := utils.DoWithTimeout(ctx, time.Second, func(ctx context.Context) error {
+//	    return client.Ping(ctx)
+//	})
+//	if errors.Is(err, errors.CodeTimeout) {
+//	    // ...
+//	}
+func DoWithTimeout(ctx context.Context, d time.Duration, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				done <- errors.FromPanic(rec)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil && stdErrors.Is(err, context.DeadlineExceeded) && stdErrors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.Wrap(err, errors.CodeTimeout).WithDetails(fmt.Sprintf("timed out after %s", d))
+	}
+	return err
+}
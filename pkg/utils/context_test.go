@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithTimeout_Close_Cancels_Context(t *testing.T) {
+	ctx, timeout := WithTimeout(context.Background(), time.Minute)
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	assert.NoError(t, ctx.Err())
+
+	require.NoError(t, timeout.Close())
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.NoError(t, timeout.Close())
+}
+
+func Test_WithTimeout_Expires(t *testing.T) {
+	ctx, timeout := WithTimeout(context.Background(), 10*time.Millisecond)
+	defer timeout.Close()
+
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+}
+
+func Test_DoWithTimeout_Completes(t *testing.T) {
+	err := DoWithTimeout(context.Background(), time.Second, func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	errFailed := stdErrors.New("failed")
+	err = DoWithTimeout(context.Background(), time.Second, func(context.Context) error {
+		return errFailed
+	})
+	assert.ErrorIs(t, err, errFailed)
+	assert.False(t, errors.Is(err, errors.CodeTimeout))
+}
+
+func Test_DoWithTimeout_Times_Out(t *testing.T) {
+	start := time.Now()
+	err := DoWithTimeout(context.Background(), 20*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.CodeTimeout))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func Test_DoWithTimeout_Does_Not_Wait_For_Stuck_Function(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	err := DoWithTimeout(context.Background(), 20*time.Millisecond, func(context.Context) error {
+		<-release
+		return nil
+	})
+
+	assert.True(t, errors.Is(err, errors.CodeTimeout))
+}
+
+func Test_DoWithTimeout_Parent_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := DoWithTimeout(ctx, time.Second, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, errors.Is(err, errors.CodeTimeout))
+}
+
+func Test_DoWithTimeout_Recovers_Panic(t *testing.T) {
+	err := DoWithTimeout(context.Background(), time.Second, func(context.Context) error {
+		panic("boom")
+	})
+
+	assert.True(t, errors.Is(err, errors.CodeInternal))
+}
@@ -2,8 +2,13 @@ package utils
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/json"
 )
 
 // ToString converts any value to a string representation.
@@ -12,6 +17,7 @@ import (
 // - int, int8, int16, int32, int64: converted using strconv
 // - uint, uint8, uint16, uint32, uint64: converted using strconv
 // - float32, float64: converted using strconv
+// - json.Number: the number literal
 // - bool: returns "true" or "false"
 // - []byte: converts to string
 // - other types: uses fmt.Sprintf("%v")
@@ -103,6 +109,8 @@ func ToInt(value interface{}) int {
 		return int(v)
 	case float64:
 		return int(v)
+	case json.Number:
+		return int(ToInt64(v))
 	case string:
 		i, _ := strconv.Atoi(v)
 		return i
@@ -117,7 +125,8 @@ func ToInt(value interface{}) int {
 }
 
 // ToInt64 converts a value to an int64.
-// Similar to ToInt but returns int64.
+// Similar to ToInt but returns int64. json.Number values are parsed from their literal,
+// so 64-bit IDs decoded with json.UnmarshalUseNumber keep every digit.
 //
 // Example:
 //
@@ -153,6 +162,13 @@ func ToInt64(value interface{}) int64 {
 		return int64(v)
 	case float64:
 		return int64(v)
+	case json.Number:
+		// Parse the literal, a float64 would round integers above 2^53
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return int64(f)
 	case string:
 		i, _ := strconv.ParseInt(v, 10, 64)
 		return i
@@ -166,7 +182,8 @@ func ToInt64(value interface{}) int64 {
 	}
 }
 
-// ToFloat64 converts a value to a float64.
+// ToFloat64 converts a value to a float64. Floats can't represent most decimal
+// fractions exactly, so use Decimal for money and other exact amounts.
 //
 // Example:
 //
@@ -202,6 +219,9 @@ func ToFloat64(value interface{}) float64 {
 		return float64(v)
 	case uint64:
 		return float64(v)
+	case json.Number:
+		f, _ := v.Float64()
+		return f
 	case string:
 		f, _ := strconv.ParseFloat(v, 64)
 		return f
@@ -257,6 +277,183 @@ func ToBool(value interface{}) bool {
 	}
 }
 
+// ToIntE converts a value to an int, returning a `CodeInvalidInput` error instead of 0
+// when the value cannot be represented exactly: unparsable strings, floats with a
+// fractional part, out of range numbers, nil and unsupported types.
+//
+// Use the E-variants wherever a silent 0 would be a bug (amounts, quantities, IDs from
+// user input); keep ToInt for best-effort conversions such as optional display values.
+//
+// Example:
+//
+//	num, err := utils.ToIntE("42")   // 42, nil
+//	num, err = utils.ToIntE("abc")   // 0, CodeInvalidInput
+//	num, err = utils.ToIntE(3.14)    // 0, CodeInvalidInput
+func ToIntE(value interface{}) (int, error) {
+	i, err := ToInt64E(value)
+	if err != nil {
+		return 0, err
+	}
+	if i < math.MinInt || i > math.MaxInt {
+		return 0, conversionError(value, "int")
+	}
+	return int(i), nil
+}
+
+// ToInt64E converts a value to an int64, returning a `CodeInvalidInput` error when the
+// value cannot be represented exactly. See ToIntE.
+//
+// Example:
+//
+//	num, err := utils.ToInt64E("9007199254740993")  // 9007199254740993, nil
+//	num, err = utils.ToInt64E("abc")                // 0, CodeInvalidInput
+func ToInt64E(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint:
+		return uint64ToInt64E(uint64(v), value)
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		return uint64ToInt64E(v, value)
+	case float32:
+		return float64ToInt64E(float64(v), value)
+	case float64:
+		return float64ToInt64E(v, value)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return 0, conversionError(value, "int64")
+		}
+		return float64ToInt64E(f, value)
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return 0, conversionError(value, "int64")
+		}
+		return i, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return 0, conversionError(value, "int64")
+	}
+}
+
+// ToFloat64E converts a value to a float64, returning a `CodeInvalidInput` error for
+// unparsable strings, NaN or infinite values, nil and unsupported types.
+//
+// Example:
+//
+//	num, err := utils.ToFloat64E("3.14")  // 3.14, nil
+//	num, err = utils.ToFloat64E("abc")    // 0, CodeInvalidInput
+func ToFloat64E(value interface{}) (float64, error) {
+	var f float64
+	switch v := value.(type) {
+	case float32:
+		f = float64(v)
+	case float64:
+		f = v
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return ToFloat64(v), nil
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return 0, conversionError(value, "float64")
+		}
+		f = parsed
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, conversionError(value, "float64")
+		}
+		f = parsed
+	case bool:
+		return ToFloat64(v), nil
+	default:
+		return 0, conversionError(value, "float64")
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, conversionError(value, "float64")
+	}
+	return f, nil
+}
+
+// ToBoolE converts a value to a boolean, returning a `CodeInvalidInput` error for
+// strings that are not a recognized boolean, nil and unsupported types. In addition to
+// the strings accepted by ToBool, "no", "n" and "off" are false and "on" is true.
+//
+// Example:
+//
+//	b, err := utils.ToBoolE("yes")  // true, nil
+//	b, err = utils.ToBoolE("no")    // false, nil
+//	b, err = utils.ToBoolE("abc")   // false, CodeInvalidInput
+func ToBoolE(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return ToBool(v), nil
+	case string:
+		s := strings.TrimSpace(v)
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+		switch strings.ToLower(s) {
+		case "yes", "y", "on":
+			return true, nil
+		case "no", "n", "off":
+			return false, nil
+		}
+		return false, conversionError(value, "bool")
+	default:
+		return false, conversionError(value, "bool")
+	}
+}
+
+// uint64ToInt64E converts v to an int64, failing when it overflows
+func uint64ToInt64E(v uint64, value interface{}) (int64, error) {
+	if v > math.MaxInt64 {
+		return 0, conversionError(value, "int64")
+	}
+	return int64(v), nil
+}
+
+// float64ToInt64E converts f to an int64, failing when it has a fractional part or overflows
+func float64ToInt64E(f float64, value interface{}) (int64, error) {
+	// 2^63 is exactly representable; it and anything above overflows int64
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, conversionError(value, "int64")
+	}
+	return int64(f), nil
+}
+
+// conversionError builds the `CodeInvalidInput` error returned by the E-variants
+func conversionError(value interface{}, target string) error {
+	return errors.New(errors.CodeInvalidInput, fmt.Sprintf("cannot convert %T %v to %s", value, value, target)).
+		WithContext("value", value).
+		WithContext("type", target)
+}
+
 // ParseDuration parses a duration string.
 // Supports Go's time.Duration format (e.g., "1h30m", "45s", "100ms").
 // Returns zero duration and error for invalid inputs.
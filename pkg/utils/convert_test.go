@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/json"
+)
+
+func Test_Strict_Conversions_Error_Where_Plain_Ones_Return_Zero(t *testing.T) {
+	assert.Equal(t, 0, ToInt("abc"))
+	assert.Equal(t, int64(0), ToInt64("abc"))
+	assert.Equal(t, 0.0, ToFloat64("abc"))
+	assert.False(t, ToBool("abc"))
+
+	_, err := ToIntE("abc")
+	assert.True(t, errors.Is(err, errors.CodeInvalidInput), err)
+	_, err = ToInt64E("abc")
+	assert.True(t, errors.Is(err, errors.CodeInvalidInput), err)
+	_, err = ToFloat64E("abc")
+	assert.True(t, errors.Is(err, errors.CodeInvalidInput), err)
+	_, err = ToBoolE("abc")
+	assert.True(t, errors.Is(err, errors.CodeInvalidInput), err)
+}
+
+func Test_ToIntE(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    int
+		wantErr bool
+	}{
+		{name: "string", value: "42", want: 42},
+		{name: "padded string", value: " -7 ", want: -7},
+		{name: "integral float", value: 3.0, want: 3},
+		{name: "uint8", value: uint8(200), want: 200},
+		{name: "bool", value: true, want: 1},
+		{name: "fractional float", value: 3.14, wantErr: true},
+		{name: "overflowing uint64", value: uint64(math.MaxUint64), wantErr: true},
+		{name: "nan", value: math.NaN(), wantErr: true},
+		{name: "nil", value: nil, wantErr: true},
+		{name: "unsupported type", value: []int{1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToIntE(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, 0, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_ToFloat64E_Rejects_Non_Finite_Values(t *testing.T) {
+	f, err := ToFloat64E("2.5")
+	require.NoError(t, err)
+	assert.Equal(t, 2.5, f)
+
+	_, err = ToFloat64E("NaN")
+	assert.Error(t, err)
+	_, err = ToFloat64E(math.Inf(1))
+	assert.Error(t, err)
+}
+
+func Test_ToBoolE(t *testing.T) {
+	for _, value := range []interface{}{true, "true", "YES", "on", 1, 2.5} {
+		b, err := ToBoolE(value)
+		require.NoError(t, err, value)
+		assert.True(t, b, value)
+	}
+
+	for _, value := range []interface{}{false, "0", "no", "Off", 0} {
+		b, err := ToBoolE(value)
+		require.NoError(t, err, value)
+		assert.False(t, b, value)
+	}
+
+	_, err := ToBoolE(nil)
+	assert.Error(t, err)
+}
+
+func Test_Conversions_Of_JSON_Numbers(t *testing.T) {
+	id := json.Number("1234567890123456789")
+	assert.Equal(t, int64(1234567890123456789), ToInt64(id))
+	assert.Equal(t, 1234567890123456789, ToInt(id))
+	assert.Equal(t, "1234567890123456789", ToString(id))
+
+	i, err := ToInt64E(id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234567890123456789), i)
+
+	i, err = ToInt64E(json.Number("42.0"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), i)
+
+	_, err = ToInt64E(json.Number("1.5"))
+	assert.True(t, errors.Is(err, errors.CodeInvalidInput))
+
+	assert.Equal(t, 1.5, ToFloat64(json.Number("1.5")))
+	f, err := ToFloat64E(json.Number("1.5"))
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, f)
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCursor struct {
+	ID    string `json:"id"`
+	Score int    `json:"score"`
+}
+
+var testCursorKey = []byte("cursor-secret")
+
+func Test_Cursor_Round_Trip_Unsigned(t *testing.T) {
+	cursor, err := EncodeCursor(testCursor{ID: "a1", Score: 7})
+	require.NoError(t, err)
+	assert.NotContains(t, cursor, cursorSignatureSeparator)
+
+	var got testCursor
+	require.NoError(t, DecodeCursor(cursor, &got))
+	assert.Equal(t, testCursor{ID: "a1", Score: 7}, got)
+}
+
+func Test_Cursor_Round_Trip_Signed(t *testing.T) {
+	cursor, err := EncodeCursor(testCursor{ID: "a1", Score: 7}, WithCursorSigningKey(testCursorKey))
+	require.NoError(t, err)
+
+	var got testCursor
+	require.NoError(t, DecodeCursor(cursor, &got, WithCursorSigningKey(testCursorKey)))
+	assert.Equal(t, testCursor{ID: "a1", Score: 7}, got)
+}
+
+func Test_DecodeCursor_Rejects_Tampered_Cursor(t *testing.T) {
+	cursor, err := EncodeCursor(testCursor{ID: "a1", Score: 7}, WithCursorSigningKey(testCursorKey))
+	require.NoError(t, err)
+
+	forged, err := EncodeCursor(testCursor{ID: "a1", Score: 9000})
+	require.NoError(t, err)
+	_, signature, _ := strings.Cut(cursor, cursorSignatureSeparator)
+
+	tests := map[string]string{
+		"payload swapped":   forged + cursorSignatureSeparator + signature,
+		"signature missing": forged,
+		"signature changed": flipLastHex(cursor),
+	}
+	for name, tampered := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got testCursor
+			err := DecodeCursor(tampered, &got, WithCursorSigningKey(testCursorKey))
+			assert.True(t, errors.Is(err, errors.CodeBadRequest))
+		})
+	}
+}
+
+func Test_DecodeCursor_Rejects_Malformed_Cursor(t *testing.T) {
+	var got testCursor
+	err := DecodeCursor("not*base64", &got)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
+
+func Test_DecodeCursor_Tolerates_Schema_Changes(t *testing.T) {
+	cursor, err := EncodeCursor(map[string]any{"id": "a1", "legacy": true})
+	require.NoError(t, err)
+
+	var got testCursor
+	require.NoError(t, DecodeCursor(cursor, &got))
+	assert.Equal(t, testCursor{ID: "a1"}, got)
+}
+
+func flipLastHex(s string) string {
+	if strings.HasSuffix(s, "0") {
+		return s[:len(s)-1] + "1"
+	}
+	return s[:len(s)-1] + "0"
+}
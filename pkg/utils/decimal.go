@@ -0,0 +1,377 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// RoundingMode selects how Decimal.Round and Decimal.Div discard digits
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest value, ties to the even neighbour
+	// (banker's rounding). It is the default for money as it doesn't bias sums.
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest value, ties away from zero
+	RoundHalfUp
+	// RoundDown truncates toward zero
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+)
+
+// centsScale is the number of decimal places of FromCents and ToCents
+const centsScale = 2
+
+// maxDecimalExponent bounds the exponent parseDecimal accepts, so that input such as
+// "1e999999999" cannot allocate a huge number
+const maxDecimalExponent = 1000
+
+// Decimal is an exact, arbitrary-precision decimal number for money and other values
+// that must not go through float64. Use it instead of ToFloat64 for amounts: 0.1+0.2
+// is exactly 0.3, and rounding happens only where you ask for it.
+//
+// The zero value is 0. Decimals are immutable; every operation returns a new value.
+// They marshal to JSON as strings ("12.30") and unmarshal from strings or numbers
+// without a float conversion.
+//
+// Example:
+//
+//	price := utils.MustParseDecimal("19.99")
+//	total := price.Mul(utils.NewDecimal(3, 0)).Add(utils.FromCents(500)) // 64.97
+//	cents, err := total.ToCents()                                         // 6497, nil
+type Decimal struct {
+	value *big.Int // unscaled value, nil means 0
+	scale int32    // number of digits after the decimal point, never negative
+}
+
+// NewDecimal returns value * 10^-scale, e.g. NewDecimal(12345, 2) is 123.45.
+// A negative scale multiplies value by 10^-scale instead.
+func NewDecimal(value int64, scale int32) Decimal {
+	v := big.NewInt(value)
+	if scale < 0 {
+		v.Mul(v, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{value: v, scale: scale}
+}
+
+// FromCents returns the amount of minor units cents as a two-place decimal
+//
+// Example:
+//
+//	utils.FromCents(1999).String() // "19.99"
+func FromCents(cents int64) Decimal {
+	return NewDecimal(cents, centsScale)
+}
+
+// ParseDecimal parses a plain decimal string such as "-12.345", or one in exponent
+// notation such as "1.5e-3", returning a `CodeInvalidInput` error for anything else. Use ParseCurrency for user-facing
+// amounts with symbols or thousands separators.
+func ParseDecimal(s string) (Decimal, error) {
+	d, ok := parseDecimal(strings.TrimSpace(s))
+	if !ok {
+		return Decimal{}, conversionError(s, "decimal")
+	}
+	return d, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics on invalid input. Use it for
+// constants only.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// ParseCurrency parses a formatted amount, ignoring a leading or trailing currency
+// symbol or code, whitespace and "," thousands separators. A parenthesized amount
+// is negative. The decimal separator must be ".".
+//
+// Example:
+//
+//	d, err := utils.ParseCurrency("$1,234.50")  // 1234.50
+//	d, err = utils.ParseCurrency("-12.00 EUR")  // -12.00
+//	d, err = utils.ParseCurrency("(5.25)")      // -5.25
+func ParseCurrency(s string) (Decimal, error) {
+	cleaned := strings.TrimSpace(s)
+
+	negative := false
+	if strings.HasPrefix(cleaned, "(") && strings.HasSuffix(cleaned, ")") {
+		negative = true
+		cleaned = cleaned[1 : len(cleaned)-1]
+	}
+
+	cleaned = strings.TrimFunc(cleaned, func(r rune) bool {
+		return r != '-' && r != '+' && r != '.' && !unicode.IsDigit(r)
+	})
+	cleaned = strings.Map(func(r rune) rune {
+		if r == ',' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, cleaned)
+
+	d, ok := parseDecimal(cleaned)
+	if !ok {
+		return Decimal{}, conversionError(s, "currency amount")
+	}
+	if negative {
+		d = d.Neg()
+	}
+	return d, nil
+}
+
+// parseDecimal parses [+-]digits[.digits][(e|E)[+-]digits]
+func parseDecimal(s string) (Decimal, bool) {
+	digits := s
+	if len(digits) > 0 && (digits[0] == '-' || digits[0] == '+') {
+		digits = digits[1:]
+	}
+
+	exponent := 0
+	if i := strings.IndexAny(digits, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(digits[i+1:])
+		if err != nil || exp < -maxDecimalExponent || exp > maxDecimalExponent {
+			return Decimal{}, false
+		}
+		exponent = exp
+		digits = digits[:i]
+	}
+
+	intPart, fracPart, _ := strings.Cut(digits, ".")
+	if intPart == "" && fracPart == "" {
+		return Decimal{}, false
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return Decimal{}, false
+			}
+		}
+	}
+
+	v, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return Decimal{}, false
+	}
+	if s[0] == '-' {
+		v.Neg(v)
+	}
+
+	scale := int32(len(fracPart) - exponent)
+	if scale < 0 {
+		v.Mul(v, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{value: v, scale: scale}, true
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.unscaled().Sign()
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp compares d and other, returning -1, 0 or +1. Trailing zeros don't matter:
+// 1.50 equals 1.5.
+func (d Decimal) Cmp(other Decimal) int {
+	a, b := align(d, other)
+	return a.Cmp(b)
+}
+
+// Equal reports whether d and other have the same value
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b := align(d, other)
+	return Decimal{value: a.Add(a, b), scale: max(d.scale, other.scale)}
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b := align(d, other)
+	return Decimal{value: a.Sub(a, b), scale: max(d.scale, other.scale)}
+}
+
+// Mul returns d * other, exactly
+func (d Decimal) Mul(other Decimal) Decimal {
+	v := new(big.Int).Mul(d.unscaled(), other.unscaled())
+	return Decimal{value: v, scale: d.scale + other.scale}
+}
+
+// Div returns d / other rounded to places digits with mode. It panics if other is 0.
+//
+// Example:
+//
+//	share := utils.MustParseDecimal("10").Div(utils.NewDecimal(3, 0), 2, utils.RoundHalfEven) // 3.33
+func (d Decimal) Div(other Decimal, places int32, mode RoundingMode) Decimal {
+	if other.IsZero() {
+		panic("utils: Decimal division by zero")
+	}
+	places = max(places, 0)
+
+	// d/other = (dv * 10^-ds) / (ov * 10^-os); scaled by 10^places
+	num := new(big.Int).Mul(d.unscaled(), pow10(other.scale+places))
+	den := new(big.Int).Mul(other.unscaled(), pow10(d.scale))
+	return Decimal{value: roundQuo(num, den, mode), scale: places}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{value: new(big.Int).Neg(d.unscaled()), scale: d.scale}
+}
+
+// Abs returns |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{value: new(big.Int).Abs(d.unscaled()), scale: d.scale}
+}
+
+// Round returns d with exactly places digits after the decimal point, discarding
+// digits with mode. Rounding to more places than d has pads with zeros.
+//
+// Example:
+//
+//	utils.MustParseDecimal("2.345").Round(2, utils.RoundHalfEven) // 2.34
+//	utils.MustParseDecimal("2.345").Round(2, utils.RoundHalfUp)   // 2.35
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	places = max(places, 0)
+	if places >= d.scale {
+		v := new(big.Int).Mul(d.unscaled(), pow10(places-d.scale))
+		return Decimal{value: v, scale: places}
+	}
+	return Decimal{value: roundQuo(d.unscaled(), pow10(d.scale-places), mode), scale: places}
+}
+
+// ToCents returns d in minor units, rounded half-even to two places. It returns a
+// `CodeInvalidInput` error when the amount doesn't fit in an int64.
+func (d Decimal) ToCents() (int64, error) {
+	v := d.Round(centsScale, RoundHalfEven).unscaled()
+	if !v.IsInt64() {
+		return 0, conversionError(d.String(), "cents")
+	}
+	return v.Int64(), nil
+}
+
+// String returns d in plain notation with all of its digits, e.g. "-0.50"
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.unscaled()).String()
+
+	var b strings.Builder
+	if d.Sign() < 0 {
+		b.WriteByte('-')
+	}
+	if d.scale == 0 {
+		b.WriteString(digits)
+		return b.String()
+	}
+
+	scale := int(d.scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	b.WriteString(digits[:len(digits)-scale])
+	b.WriteByte('.')
+	b.WriteString(digits[len(digits)-scale:])
+	return b.String()
+}
+
+// MarshalJSON encodes d as a JSON string, so clients don't parse it as a float
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON decodes a JSON string or number. null leaves d unchanged.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+
+	parsed, ok := parseDecimal(string(data))
+	if !ok {
+		return errors.New(errors.CodeInvalidInput, fmt.Sprintf("invalid decimal %s", data))
+	}
+	*d = parsed
+	return nil
+}
+
+// unscaled returns the unscaled value, treating the zero Decimal as 0
+func (d Decimal) unscaled() *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+	return d.value
+}
+
+// align returns copies of the unscaled values of a and b at their common scale
+func align(a, b Decimal) (*big.Int, *big.Int) {
+	av := new(big.Int).Set(a.unscaled())
+	bv := new(big.Int).Set(b.unscaled())
+	switch {
+	case a.scale < b.scale:
+		av.Mul(av, pow10(b.scale-a.scale))
+	case b.scale < a.scale:
+		bv.Mul(bv, pow10(a.scale-b.scale))
+	}
+	return av, bv
+}
+
+// roundQuo returns num/den rounded to an integer with mode
+func roundQuo(num, den *big.Int, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	var away bool
+	switch mode {
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	default:
+		// Compare the discarded fraction |rem/den| with one half
+		half := new(big.Int).Abs(rem)
+		half.Lsh(half, 1)
+		c := half.Cmp(new(big.Int).Abs(den))
+		away = c > 0 || (c == 0 && (mode == RoundHalfUp || quo.Bit(0) == 1))
+	}
+
+	if away {
+		if num.Sign()*den.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
+
+// pow10 returns 10^n
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+	"github.com/phatnt199/go-infra/pkg/json"
+)
+
+func Test_Decimal_Arithmetic_Is_Exact(t *testing.T) {
+	sum := MustParseDecimal("0.1").Add(MustParseDecimal("0.2"))
+	assert.Equal(t, "0.3", sum.String())
+	assert.True(t, sum.Equal(MustParseDecimal("0.30")))
+
+	total := MustParseDecimal("19.99").Mul(NewDecimal(3, 0)).Add(FromCents(500))
+	assert.Equal(t, "64.97", total.String())
+
+	assert.Equal(t, "-0.05", FromCents(10).Sub(MustParseDecimal("0.15")).String())
+	assert.Equal(t, "3.33", MustParseDecimal("10").Div(NewDecimal(3, 0), 2, RoundHalfEven).String())
+	assert.Equal(t, "1200", NewDecimal(12, -2).String())
+	assert.Equal(t, "0", Decimal{}.String())
+}
+
+func Test_Decimal_Round_Half_Even(t *testing.T) {
+	tests := map[string]string{
+		"2.345":  "2.34",
+		"2.355":  "2.36",
+		"2.3451": "2.35",
+		"-2.345": "-2.34",
+		"-2.355": "-2.36",
+		"0.005":  "0.00",
+		"0.015":  "0.02",
+		"1.2":    "1.20",
+	}
+	for input, want := range tests {
+		got := MustParseDecimal(input).Round(2, RoundHalfEven)
+		assert.Equal(t, want, got.String(), input)
+	}
+}
+
+func Test_Decimal_Rounding_Modes(t *testing.T) {
+	d := MustParseDecimal("-2.345")
+
+	assert.Equal(t, "-2.34", d.Round(2, RoundHalfEven).String())
+	assert.Equal(t, "-2.35", d.Round(2, RoundHalfUp).String())
+	assert.Equal(t, "-2.34", d.Round(2, RoundDown).String())
+	assert.Equal(t, "-2.35", d.Round(2, RoundUp).String())
+	assert.Equal(t, "-2", d.Round(0, RoundHalfEven).String())
+}
+
+func Test_Decimal_Cents(t *testing.T) {
+	assert.Equal(t, "19.99", FromCents(1999).String())
+	assert.Equal(t, "-0.07", FromCents(-7).String())
+
+	cents, err := MustParseDecimal("10.125").ToCents()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1012), cents)
+
+	_, err = NewDecimal(math.MaxInt64, 0).ToCents()
+	assert.True(t, errors.Is(err, errors.CodeInvalidInput), err)
+}
+
+func Test_ParseDecimal_Rejects_Invalid_Input(t *testing.T) {
+	for _, input := range []string{"", "-", ".", "1.2.3", "abc", "$1", "e5", "1e", "1e+", "1e5.0", "1e1001"} {
+		_, err := ParseDecimal(input)
+		assert.True(t, errors.Is(err, errors.CodeInvalidInput), "%q: %v", input, err)
+	}
+}
+
+func Test_ParseDecimal_Exponent_Notation(t *testing.T) {
+	tests := map[string]string{
+		"1e-3":    "0.001",
+		"2.5E2":   "250",
+		"-1.25e1": "-12.5",
+		"1.50e+0": "1.50",
+		"12e-1":   "1.2",
+	}
+	for input, want := range tests {
+		d, err := ParseDecimal(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, d.String(), input)
+	}
+}
+
+func Test_ParseCurrency(t *testing.T) {
+	tests := map[string]string{
+		"$1,234.50":  "1234.50",
+		"-12.00 EUR": "-12.00",
+		"€ 7":        "7",
+		"(5.25)":     "-5.25",
+		"1 000.01":   "1000.01",
+	}
+	for input, want := range tests {
+		d, err := ParseCurrency(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, d.String(), input)
+	}
+
+	_, err := ParseCurrency("USD")
+	assert.True(t, errors.Is(err, errors.CodeInvalidInput), err)
+}
+
+func Test_Decimal_JSON_Round_Trip(t *testing.T) {
+	type invoice struct {
+		Total Decimal  `json:"total"`
+		Tax   *Decimal `json:"tax,omitempty"`
+	}
+
+	in := invoice{Total: MustParseDecimal("12345678901234567.89")}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"total":"12345678901234567.89"}`, string(data))
+
+	var out invoice
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.True(t, in.Total.Equal(out.Total))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"total":0.10,"tax":"1.5"}`), &out))
+	assert.Equal(t, "0.10", out.Total.String())
+	assert.Equal(t, "1.5", out.Tax.String())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"total":1e-3,"tax":2.5E2}`), &out))
+	assert.Equal(t, "0.001", out.Total.String())
+	assert.Equal(t, "250", out.Tax.String())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"total":"ten"}`), &out))
+}
@@ -0,0 +1,107 @@
+package utils
+
+import "reflect"
+
+// DeepEqual reports whether a and b are structurally equal, recursing into nested
+// structs, pointers, slices and maps. It is a typed wrapper over reflect.DeepEqual,
+// so callers don't need to import reflect for config diffing or test assertions.
+//
+// Note that a nil slice and an empty slice are not considered equal.
+//
+// Example:
+//
+//	utils.DeepEqual(cfgA, cfgB) // true if every nested field matches
+func DeepEqual[T any](a, b T) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// DeepClone returns a deep copy of v: pointers, slices, maps and nested structs are
+// copied recursively so that mutating the clone never affects the original.
+//
+// Caveats:
+//   - It relies on reflection and allocates for every nested reference, so avoid it
+//     in hot paths.
+//   - Unexported struct fields are copied shallowly (reflection cannot set them), so
+//     unexported pointers, slices or maps are shared with the original.
+//   - Functions and channels are copied by reference.
+//
+// Example:
+//
+//	clone := utils.DeepClone(cfg)
+//	clone.Server.HTTP.CORS.AllowedOrigins[0] = "https://example.com" // cfg is untouched
+func DeepClone[T any](v T) T {
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	deepCopyValue(dst, src, make(map[uintptr]reflect.Value))
+
+	// the comma-ok form keeps a nil interface T from panicking
+	clone, _ := dst.Interface().(T)
+	return clone
+}
+
+// deepCopyValue recursively copies src into dst. visited tracks already copied
+// pointers so cyclic structures are cloned without infinite recursion.
+func deepCopyValue(dst, src reflect.Value, visited map[uintptr]reflect.Value) {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		if copied, ok := visited[src.Pointer()]; ok {
+			dst.Set(copied)
+			return
+		}
+		ptr := reflect.New(src.Elem().Type())
+		visited[src.Pointer()] = ptr
+		deepCopyValue(ptr.Elem(), src.Elem(), visited)
+		dst.Set(ptr)
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		elem := reflect.New(src.Elem().Type()).Elem()
+		deepCopyValue(elem, src.Elem(), visited)
+		dst.Set(elem)
+
+	case reflect.Struct:
+		// Copy the whole struct first so unexported fields are preserved (shallowly)
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				deepCopyValue(dst.Field(i), src.Field(i), visited)
+			}
+		}
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		slice := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			deepCopyValue(slice.Index(i), src.Index(i), visited)
+		}
+		dst.Set(slice)
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			deepCopyValue(dst.Index(i), src.Index(i), visited)
+		}
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			value := reflect.New(src.Type().Elem()).Elem()
+			deepCopyValue(value, iter.Value(), visited)
+			m.SetMapIndex(iter.Key(), value)
+		}
+		dst.Set(m)
+
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTLSConfig struct {
+	Enabled  bool
+	CertFile string
+}
+
+type testHTTPConfig struct {
+	Port    int
+	Timeout time.Duration
+	Origins []string
+	TLS     *testTLSConfig
+}
+
+type testConfig struct {
+	Name    string
+	HTTP    testHTTPConfig
+	Labels  map[string]string
+	Backups []*testTLSConfig
+	Extra   interface{}
+	secret  string
+}
+
+func newTestConfig() testConfig {
+	return testConfig{
+		Name: "app",
+		HTTP: testHTTPConfig{
+			Port:    8080,
+			Timeout: 10 * time.Second,
+			Origins: []string{"*"},
+			TLS:     &testTLSConfig{Enabled: true, CertFile: "cert.pem"},
+		},
+		Labels:  map[string]string{"env": "dev"},
+		Backups: []*testTLSConfig{{CertFile: "backup.pem"}},
+		Extra:   []int{1, 2},
+		secret:  "s3cr3t",
+	}
+}
+
+func Test_DeepEqual(t *testing.T) {
+	assert.True(t, DeepEqual(newTestConfig(), newTestConfig()))
+
+	other := newTestConfig()
+	other.HTTP.TLS.CertFile = "other.pem"
+	assert.False(t, DeepEqual(newTestConfig(), other))
+
+	other = newTestConfig()
+	other.Labels["env"] = "prod"
+	assert.False(t, DeepEqual(newTestConfig(), other))
+}
+
+func Test_DeepClone_Nested_Config(t *testing.T) {
+	original := newTestConfig()
+	clone := DeepClone(original)
+
+	assert.True(t, DeepEqual(original, clone))
+
+	clone.HTTP.Origins[0] = "https://example.com"
+	clone.HTTP.TLS.Enabled = false
+	clone.Labels["env"] = "prod"
+	clone.Backups[0].CertFile = "changed.pem"
+	clone.Extra.([]int)[0] = 100
+
+	assert.Equal(t, "*", original.HTTP.Origins[0])
+	assert.True(t, original.HTTP.TLS.Enabled)
+	assert.Equal(t, "dev", original.Labels["env"])
+	assert.Equal(t, "backup.pem", original.Backups[0].CertFile)
+	assert.Equal(t, 1, original.Extra.([]int)[0])
+
+	// unexported fields are copied shallowly
+	assert.Equal(t, "s3cr3t", clone.secret)
+}
+
+func Test_DeepClone_Pointer_And_Nil_Values(t *testing.T) {
+	original := &testHTTPConfig{Port: 1}
+	clone := DeepClone(original)
+	clone.Port = 2
+
+	assert.Equal(t, 1, original.Port)
+	assert.Nil(t, clone.Origins)
+	assert.Nil(t, DeepClone[*testHTTPConfig](nil))
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), DeepClone(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+}
@@ -0,0 +1,82 @@
+// Package enum provides a small generic helper for string enums.
+//
+// It lives in its own package (rather than in utils) so that low-level packages
+// such as application/config can use it without importing the logger stack.
+package enum
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Enum is a fixed set of allowed string values.
+//
+// Example:
+//
+//	type Driver string
+//
+//	var Drivers = enum.New[Driver]("postgres", "mysql", "sqlite")
+//
+//	Drivers.Valid("mysql")        // true
+//	Drivers.Options()             // "postgres, mysql, sqlite"
+//	Drivers.MustBeOneOf("driver") // "driver must be one of: postgres, mysql, sqlite"
+type Enum[T ~string] struct {
+	values []T
+}
+
+// New creates an Enum from the allowed values, preserving their order
+func New[T ~string](values ...T) Enum[T] {
+	copied := make([]T, len(values))
+	copy(copied, values)
+	return Enum[T]{values: copied}
+}
+
+// Values returns a copy of the allowed values
+func (e Enum[T]) Values() []T {
+	values := make([]T, len(e.values))
+	copy(values, e.values)
+	return values
+}
+
+// Valid returns true if value is one of the allowed values
+func (e Enum[T]) Valid(value T) bool {
+	for _, v := range e.values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse converts s into T, returning an error if it is not an allowed value
+func (e Enum[T]) Parse(s string) (T, error) {
+	value := T(s)
+	if !e.Valid(value) {
+		return value, fmt.Errorf("invalid value %q: %s", s, e.MustBeOneOf("value"))
+	}
+	return value, nil
+}
+
+// MustParse is like Parse but panics if s is not an allowed value.
+// Use it only for constants and initialization code.
+func (e Enum[T]) MustParse(s string) T {
+	value, err := e.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Options returns the allowed values as a comma-separated list for error messages
+func (e Enum[T]) Options() string {
+	options := make([]string, len(e.values))
+	for i, v := range e.values {
+		options[i] = string(v)
+	}
+	return strings.Join(options, ", ")
+}
+
+// MustBeOneOf returns the standard "<name> must be one of: a, b" validation message
+func (e Enum[T]) MustBeOneOf(name string) string {
+	return fmt.Sprintf("%s must be one of: %s", name, e.Options())
+}
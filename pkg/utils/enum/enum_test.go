@@ -0,0 +1,34 @@
+package enum
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDriver string
+
+var testDrivers = New[testDriver]("postgres", "mysql", "sqlite")
+
+func Test_Enum_Valid_And_Options(t *testing.T) {
+	assert.True(t, testDrivers.Valid("mysql"))
+	assert.False(t, testDrivers.Valid("oracle"))
+	assert.False(t, testDrivers.Valid(""))
+	assert.Equal(t, "postgres, mysql, sqlite", testDrivers.Options())
+	assert.Equal(t, "driver must be one of: postgres, mysql, sqlite", testDrivers.MustBeOneOf("driver"))
+	assert.Equal(t, []testDriver{"postgres", "mysql", "sqlite"}, testDrivers.Values())
+}
+
+func Test_Enum_Parse(t *testing.T) {
+	driver, err := testDrivers.Parse("sqlite")
+	require.NoError(t, err)
+	assert.Equal(t, testDriver("sqlite"), driver)
+
+	_, err = testDrivers.Parse("oracle")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be one of: postgres, mysql, sqlite")
+
+	assert.Equal(t, testDriver("mysql"), testDrivers.MustParse("mysql"))
+	assert.Panics(t, func() { testDrivers.MustParse("oracle") })
+}
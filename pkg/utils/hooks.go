@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"context"
+	stdErrors "errors"
+	"sync"
+)
+
+// Hooks is an ordered list of callbacks for a lifecycle event, e.g. "user created".
+// The zero value is ready to use and safe for concurrent use; firing doesn't allocate.
+//
+// Example:
+//
+//	var userCreated utils.Hooks[*User]
+//	userCreated.Add(func(u *User) { metrics.UsersCreated.Add(ctx, 1) })
+//	userCreated.Fire(ctx, user)
+type Hooks[T any] struct {
+	mu  sync.RWMutex
+	fns []func(T)
+}
+
+// Add registers fn. Hooks run in the order they were added.
+func (h *Hooks[T]) Add(fn func(T)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// Copy on write, so that a Fire in progress keeps iterating its own snapshot
+	h.fns = append(h.fns[:len(h.fns):len(h.fns)], fn)
+}
+
+// Len returns the number of registered hooks
+func (h *Hooks[T]) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.fns)
+}
+
+// Fire calls the hooks in order with value, stopping early when ctx is done
+func (h *Hooks[T]) Fire(ctx context.Context, value T) {
+	h.mu.RLock()
+	fns := h.fns
+	h.mu.RUnlock()
+
+	for _, fn := range fns {
+		if ctx.Err() != nil {
+			return
+		}
+		fn(value)
+	}
+}
+
+// ErrorHooks is like Hooks for callbacks that can fail, such as "before create"
+// validations. The zero value is ready to use.
+//
+// Example:
+//
+//	var beforeCreate utils.ErrorHooks[*User]
+//	beforeCreate.Add(func(ctx context.Context, u *User) error {
+//	    return validateEmailDomain(u.Email)
+//	})
+//	if err := beforeCreate.Fire(ctx, user); err != nil {
+//	    return err
+//	}
+type ErrorHooks[T any] struct {
+	mu  sync.RWMutex
+	fns []func(context.Context, T) error
+}
+
+// Add registers fn. Hooks run in the order they were added.
+func (h *ErrorHooks[T]) Add(fn func(ctx context.Context, value T) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fns = append(h.fns[:len(h.fns):len(h.fns)], fn)
+}
+
+// Len returns the number of registered hooks
+func (h *ErrorHooks[T]) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.fns)
+}
+
+// Fire calls every hook in order with value and returns their errors joined with
+// errors.Join (nil when all succeed). A failing hook doesn't stop the following ones;
+// ctx being done does, and its error is included.
+func (h *ErrorHooks[T]) Fire(ctx context.Context, value T) error {
+	h.mu.RLock()
+	fns := h.fns
+	h.mu.RUnlock()
+
+	var errs []error
+	for _, fn := range fns {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := fn(ctx, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return stdErrors.Join(errs...)
+}
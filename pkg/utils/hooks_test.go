@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Hooks_Fire_In_Order(t *testing.T) {
+	var hooks Hooks[string]
+	var calls []string
+	hooks.Add(func(s string) { calls = append(calls, "first:"+s) })
+	hooks.Add(func(s string) { calls = append(calls, "second:"+s) })
+
+	hooks.Fire(context.Background(), "created")
+
+	assert.Equal(t, []string{"first:created", "second:created"}, calls)
+	assert.Equal(t, 2, hooks.Len())
+}
+
+func Test_Hooks_Stop_When_Context_Done(t *testing.T) {
+	var hooks Hooks[int]
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	hooks.Add(func(int) { calls++; cancel() })
+	hooks.Add(func(int) { calls++ })
+
+	hooks.Fire(ctx, 1)
+
+	assert.Equal(t, 1, calls)
+}
+
+func Test_Hooks_Add_During_Fire(t *testing.T) {
+	var hooks Hooks[int]
+	calls := 0
+	hooks.Add(func(int) {
+		calls++
+		hooks.Add(func(int) { calls++ })
+	})
+
+	hooks.Fire(context.Background(), 1)
+	assert.Equal(t, 1, calls, "hooks added while firing run from the next Fire")
+	assert.Equal(t, 2, hooks.Len())
+}
+
+func Test_ErrorHooks_Aggregate_Errors(t *testing.T) {
+	var hooks ErrorHooks[string]
+	errFirst := stdErrors.New("first")
+	errThird := stdErrors.New("third")
+	var calls []int
+	hooks.Add(func(context.Context, string) error { calls = append(calls, 1); return errFirst })
+	hooks.Add(func(context.Context, string) error { calls = append(calls, 2); return nil })
+	hooks.Add(func(context.Context, string) error { calls = append(calls, 3); return errThird })
+
+	err := hooks.Fire(context.Background(), "value")
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errFirst)
+	assert.ErrorIs(t, err, errThird)
+	assert.Equal(t, "first\nthird", err.Error())
+	assert.Equal(t, []int{1, 2, 3}, calls)
+}
+
+func Test_ErrorHooks_Succeed_And_Stop_On_Context(t *testing.T) {
+	var hooks ErrorHooks[string]
+	assert.NoError(t, hooks.Fire(context.Background(), "empty"))
+
+	hooks.Add(func(context.Context, string) error { return nil })
+	assert.NoError(t, hooks.Fire(context.Background(), "ok"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, hooks.Fire(ctx, "canceled"), context.Canceled)
+}
+
+func BenchmarkHooks_Fire(b *testing.B) {
+	var hooks Hooks[int]
+	sum := 0
+	for i := 0; i < 4; i++ {
+		hooks.Add(func(v int) { sum += v })
+	}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		hooks.Fire(ctx, i)
+	}
+}
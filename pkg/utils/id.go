@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// crockfordAlphabet is the Crockford base32 alphabet used by ULIDs
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidLength is the length of an encoded ULID
+const ulidLength = 26
+
+var (
+	uuidV7Mu     sync.Mutex
+	uuidV7LastMs uint64
+	uuidV7Seq    uint16
+
+	ulidMu     sync.Mutex
+	ulidLastMs uint64
+	ulidLast   [16]byte
+)
+
+// NewUUID generates a random (version 4) UUID.
+//
+// Example:
+//
+//	id := utils.NewUUID() // "0b9a7f4e-3c55-4c1f-9a8e-2f6d3c1b5e7a"
+func NewUUID() string {
+	var u [16]byte
+	_, _ = rand.Read(u[:])
+
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // RFC 9562 variant
+	return formatUUID(u)
+}
+
+// NewUUIDv7 generates a time-ordered (version 7) UUID. IDs generated by this process
+// sort in creation order, even within the same millisecond, which keeps B-tree
+// indexes compact and matches the repository's default created_at ordering.
+//
+// Example:
+//
+//	id := utils.NewUUIDv7() // "01909a8e-5f3a-7c4d-8e2b-6a1f0c9d3b7e"
+func NewUUIDv7() string {
+	var u [16]byte
+	_, _ = rand.Read(u[:])
+
+	uuidV7Mu.Lock()
+	ms := uint64(time.Now().UnixMilli())
+	if ms > uuidV7LastMs {
+		// New millisecond: seed the 12 bit counter randomly, leaving headroom to increment
+		uuidV7LastMs = ms
+		uuidV7Seq = binary.BigEndian.Uint16(u[6:8]) & 0x07ff
+	} else {
+		uuidV7Seq++
+		if uuidV7Seq > 0x0fff {
+			// Counter exhausted, borrow the next millisecond
+			uuidV7LastMs++
+			uuidV7Seq = 0
+		}
+	}
+	ms, seq := uuidV7LastMs, uuidV7Seq
+	uuidV7Mu.Unlock()
+
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+	u[6] = 0x70 | byte(seq>>8) // version 7
+	u[7] = byte(seq)
+	u[8] = (u[8] & 0x3f) | 0x80 // RFC 9562 variant
+	return formatUUID(u)
+}
+
+// formatUUID formats a UUID in its canonical 8-4-4-4-12 form
+func formatUUID(u [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// ParseUUID parses a canonical UUID string (case-insensitive).
+//
+// Example:
+//
+//	u, err := utils.ParseUUID("0b9a7f4e-3c55-4c1f-9a8e-2f6d3c1b5e7a")
+func ParseUUID(s string) ([16]byte, error) {
+	var u [16]byte
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("invalid UUID format: %q", s)
+	}
+
+	compact := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	if _, err := hex.Decode(u[:], []byte(compact)); err != nil {
+		return u, fmt.Errorf("invalid UUID %q: %w", s, err)
+	}
+	return u, nil
+}
+
+// IsUUID reports whether s is a valid canonical UUID.
+//
+// Example:
+//
+//	utils.IsUUID(utils.NewUUID()) // true
+//	utils.IsUUID("not-a-uuid")    // false
+func IsUUID(s string) bool {
+	_, err := ParseUUID(s)
+	return err == nil
+}
+
+// UUIDVersion returns the version of a UUID string (4 for NewUUID, 7 for NewUUIDv7).
+//
+// Example:
+//
+//	version, err := utils.UUIDVersion(utils.NewUUIDv7()) // 7
+func UUIDVersion(s string) (int, error) {
+	u, err := ParseUUID(s)
+	if err != nil {
+		return 0, err
+	}
+	return int(u[6] >> 4), nil
+}
+
+// UUIDv7Time returns the creation time embedded in a version 7 UUID.
+//
+// Example:
+//
+//	created, err := utils.UUIDv7Time(id)
+func UUIDv7Time(s string) (time.Time, error) {
+	u, err := ParseUUID(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if u[6]>>4 != 7 {
+		return time.Time{}, fmt.Errorf("UUID %q is not version 7", s)
+	}
+
+	var ms [8]byte
+	copy(ms[2:], u[0:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(ms[:]))), nil
+}
+
+// NewULID generates a ULID: a 26 character, time-sortable identifier made of a 48 bit
+// millisecond timestamp and 80 random bits. ULIDs generated by this process are
+// strictly increasing, even within the same millisecond.
+//
+// Example:
+//
+//	id := utils.NewULID() // "01J2Z9XQ7K8M3N4P5R6S7T8V9W"
+func NewULID() string {
+	var u [16]byte
+
+	ulidMu.Lock()
+	ms := uint64(time.Now().UnixMilli())
+	if ms > ulidLastMs {
+		ulidLastMs = ms
+		_, _ = rand.Read(ulidLast[6:])
+	} else if !incrementULIDEntropy(&ulidLast) {
+		// Entropy overflowed, borrow the next millisecond
+		ulidLastMs++
+		_, _ = rand.Read(ulidLast[6:])
+	}
+
+	ms = ulidLastMs
+	ulidLast[0] = byte(ms >> 40)
+	ulidLast[1] = byte(ms >> 32)
+	ulidLast[2] = byte(ms >> 24)
+	ulidLast[3] = byte(ms >> 16)
+	ulidLast[4] = byte(ms >> 8)
+	ulidLast[5] = byte(ms)
+	u = ulidLast
+	ulidMu.Unlock()
+
+	return encodeULID(u)
+}
+
+// incrementULIDEntropy increments the 80 bit random part, reporting false on overflow
+func incrementULIDEntropy(u *[16]byte) bool {
+	for i := 15; i >= 6; i-- {
+		u[i]++
+		if u[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeULID encodes 128 bits as 26 Crockford base32 characters
+func encodeULID(u [16]byte) string {
+	hi := binary.BigEndian.Uint64(u[:8])
+	lo := binary.BigEndian.Uint64(u[8:])
+
+	var buf [ulidLength]byte
+	for i := ulidLength - 1; i >= 0; i-- {
+		buf[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(buf[:])
+}
+
+// ParseULID decodes a ULID string (case-insensitive) into its 16 bytes.
+//
+// Example:
+//
+//	u, err := utils.ParseULID("01J2Z9XQ7K8M3N4P5R6S7T8V9W")
+func ParseULID(s string) ([16]byte, error) {
+	var u [16]byte
+	if len(s) != ulidLength {
+		return u, fmt.Errorf("invalid ULID length: %q", s)
+	}
+
+	upper := strings.ToUpper(s)
+	// The first character only holds 3 bits, larger values overflow 128 bits
+	if upper[0] > '7' {
+		return u, fmt.Errorf("invalid ULID %q: overflows 128 bits", s)
+	}
+
+	var hi, lo uint64
+	for i := 0; i < ulidLength; i++ {
+		value := strings.IndexByte(crockfordAlphabet, upper[i])
+		if value < 0 {
+			return u, fmt.Errorf("invalid ULID character %q in %q", upper[i], s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(value)
+	}
+
+	binary.BigEndian.PutUint64(u[:8], hi)
+	binary.BigEndian.PutUint64(u[8:], lo)
+	return u, nil
+}
+
+// IsULID reports whether s is a valid ULID.
+//
+// Example:
+//
+//	utils.IsULID(utils.NewULID()) // true
+func IsULID(s string) bool {
+	_, err := ParseULID(s)
+	return err == nil
+}
+
+// ULIDTime returns the creation time embedded in a ULID.
+//
+// Example:
+//
+//	created, err := utils.ULIDTime(id)
+func ULIDTime(s string) (time.Time, error) {
+	u, err := ParseULID(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var ms [8]byte
+	copy(ms[2:], u[0:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(ms[:]))), nil
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const idSampleSize = 10000
+
+func Test_NewUUID_Is_Unique_Version_4(t *testing.T) {
+	seen := make(map[string]bool, idSampleSize)
+	for i := 0; i < idSampleSize; i++ {
+		id := NewUUID()
+		require.False(t, seen[id], "duplicate UUID %s", id)
+		seen[id] = true
+	}
+
+	id := NewUUID()
+	version, err := UUIDVersion(id)
+	require.NoError(t, err)
+	assert.Equal(t, 4, version)
+	assert.Contains(t, "89ab", string(id[19]))
+}
+
+func Test_NewUUIDv7_Is_Monotonic(t *testing.T) {
+	previous := NewUUIDv7()
+	for i := 0; i < idSampleSize; i++ {
+		id := NewUUIDv7()
+		require.Greater(t, id, previous)
+		previous = id
+	}
+
+	version, err := UUIDVersion(previous)
+	require.NoError(t, err)
+	assert.Equal(t, 7, version)
+
+	created, err := UUIDv7Time(previous)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), created, time.Second)
+}
+
+func Test_NewULID_Is_Monotonic(t *testing.T) {
+	previous := NewULID()
+	for i := 0; i < idSampleSize; i++ {
+		id := NewULID()
+		require.Len(t, id, 26)
+		require.Greater(t, id, previous)
+		previous = id
+	}
+
+	created, err := ULIDTime(previous)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), created, time.Second)
+}
+
+func Test_NewULID_Is_Unique_Across_Goroutines(t *testing.T) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[string]bool)
+
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]string, 0, 1000)
+			for i := 0; i < 1000; i++ {
+				ids = append(ids, NewULID(), NewUUIDv7())
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range ids {
+				assert.False(t, seen[id], "duplicate ID %s", id)
+				seen[id] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, seen, 20000)
+}
+
+func Test_ParseULID_Round_Trips(t *testing.T) {
+	id := NewULID()
+
+	u, err := ParseULID(strings.ToLower(id))
+	require.NoError(t, err)
+	assert.Equal(t, id, encodeULID(u))
+
+	assert.True(t, IsULID("01ARZ3NDEKTSV4RRFFQ69G5FAV"))
+	assert.False(t, IsULID("01ARZ3NDEKTSV4RRFFQ69G5FA"))  // too short
+	assert.False(t, IsULID("81ARZ3NDEKTSV4RRFFQ69G5FAV")) // overflows 128 bits
+	assert.False(t, IsULID("01ARZ3NDEKTSV4RRFFQ69G5FAU")) // U is not in the alphabet
+
+	created, err := ULIDTime("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1469922850259), created.UnixMilli())
+}
+
+func Test_ParseUUID_Validation(t *testing.T) {
+	assert.True(t, IsUUID("0B9A7F4E-3C55-4C1F-9A8E-2F6D3C1B5E7A"))
+	assert.False(t, IsUUID("0b9a7f4e3c554c1f9a8e2f6d3c1b5e7a"))
+	assert.False(t, IsUUID("0b9a7f4e-3c55-4c1f-9a8e-2f6d3c1b5e7z"))
+
+	_, err := UUIDv7Time(NewUUID())
+	assert.Error(t, err)
+}
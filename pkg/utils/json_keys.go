@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/phatnt199/go-infra/pkg/json"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// MatchJSONKeys rewrites the object keys of the JSON document data to the json names
+// of the fields of dst (a pointer to a struct) they match ignoring case and style, so
+// "userName", "user_name" and "UserName" all decode into a field tagged
+// `json:"user_name"` or a field UserName without tag. Keys are compared in
+// SnakeCase form; keys matching a field exactly are kept, as are keys matching no
+// field. Nested structs, slices, arrays and maps of structs are rewritten too, while
+// types implementing json.Unmarshaler keep their input. Numbers keep their literal.
+// data is returned as is when no key needs renaming.
+//
+// Example:
+//
+//	type CreateUser struct {
+//	    FirstName string `json:"first_name"`
+//	}
+//
+//	data, err := utils.MatchJSONKeys([]byte(`{"firstName":"Ann"}`), &req)
+//	// data == {"first_name":"Ann"}
+func MatchJSONKeys(data []byte, dst interface{}) ([]byte, error) {
+	t := reflect.TypeOf(dst)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || !needsKeyMatching(t, map[reflect.Type]bool{}) {
+		return data, nil
+	}
+
+	var document interface{}
+	if err := json.UnmarshalUseNumber(data, &document); err != nil {
+		return nil, err
+	}
+	var renamed bool
+	document = matchJSONValue(document, t, &renamed)
+	if !renamed {
+		// Every key already matches, so data can be decoded as is
+		return data, nil
+	}
+	return json.Marshal(document)
+}
+
+// needsKeyMatching reports whether t contains structs whose keys can be rewritten
+func needsKeyMatching(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if seen[t] || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return needsKeyMatching(t.Elem(), seen)
+	}
+	return false
+}
+
+// matchJSONValue rewrites the keys of the decoded JSON value for the type t, setting
+// renamed when it changes any
+func matchJSONValue(value interface{}, t reflect.Type, renamed *bool) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			return matchJSONObject(v, jsonFieldTypes(t), renamed)
+		case reflect.Map:
+			for key, item := range v {
+				v[key] = matchJSONValue(item, t.Elem(), renamed)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range v {
+				v[i] = matchJSONValue(item, t.Elem(), renamed)
+			}
+		}
+	}
+	return value
+}
+
+// matchJSONObject renames the keys of object to the json names of fields
+func matchJSONObject(object map[string]interface{}, fields map[string]reflect.Type, renamed *bool) map[string]interface{} {
+	bySnakeCase := make(map[string]string, len(fields))
+	for name := range fields {
+		bySnakeCase[SnakeCase(name)] = name
+	}
+
+	matched := make(map[string]interface{}, len(object))
+	// Exact matches first, so they win over renamed keys
+	for key, value := range object {
+		if fieldType, ok := fields[key]; ok {
+			matched[key] = matchJSONValue(value, fieldType, renamed)
+		}
+	}
+	for key, value := range object {
+		if _, ok := fields[key]; ok {
+			continue
+		}
+		name, ok := bySnakeCase[SnakeCase(key)]
+		if !ok {
+			matched[key] = value
+			continue
+		}
+		*renamed = true
+		if _, taken := matched[name]; !taken {
+			matched[name] = matchJSONValue(value, fields[name], renamed)
+		}
+	}
+	return matched
+}
+
+// jsonFieldTypes returns the types of the fields of t by json name, with the fields of
+// embedded structs promoted like encoding/json does
+func jsonFieldTypes(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		embedded := field.Type
+		if embedded.Kind() == reflect.Ptr {
+			embedded = embedded.Elem()
+		}
+		if field.Anonymous && name == "" && embedded.Kind() == reflect.Struct {
+			for embeddedName, embeddedType := range jsonFieldTypes(embedded) {
+				if _, ok := fields[embeddedName]; !ok {
+					fields[embeddedName] = embeddedType
+				}
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/json"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonKeysAudit struct {
+	CreatedBy string `json:"created_by"`
+}
+
+type jsonKeysItem struct {
+	SKU      string `json:"sku"`
+	UnitCost int64  `json:"unit_cost"`
+}
+
+type jsonKeysOrder struct {
+	jsonKeysAudit
+	OrderID     int64                   `json:"order_id"`
+	Items       []jsonKeysItem          `json:"items"`
+	ByWarehouse map[string]jsonKeysItem `json:"by_warehouse"`
+	PlacedAt    time.Time               `json:"placed_at"`
+	Note        string
+	Internal    string `json:"-"`
+}
+
+func Test_MatchJSONKeys_Binds_CamelCase_Into_Snake_Case_Tags(t *testing.T) {
+	data := []byte(`{
+		"orderId": 1234567890123456789,
+		"createdBy": "ann",
+		"items": [{"SKU": "A-1", "unitCost": 250}],
+		"byWarehouse": {"HCM": {"unitCost": 1}},
+		"placedAt": "2026-01-02T03:04:05Z",
+		"note": "leave at door",
+		"internal": "ignored"
+	}`)
+
+	var order jsonKeysOrder
+	matched, err := MatchJSONKeys(data, &order)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(matched, &order))
+
+	assert.Equal(t, int64(1234567890123456789), order.OrderID)
+	assert.Equal(t, "ann", order.CreatedBy)
+	assert.Equal(t, []jsonKeysItem{{SKU: "A-1", UnitCost: 250}}, order.Items)
+	assert.Equal(t, map[string]jsonKeysItem{"HCM": {UnitCost: 1}}, order.ByWarehouse)
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), order.PlacedAt)
+	assert.Equal(t, "leave at door", order.Note)
+	assert.Empty(t, order.Internal)
+}
+
+func Test_MatchJSONKeys_Prefers_Exact_Keys(t *testing.T) {
+	matched, err := MatchJSONKeys([]byte(`{"orderId": 1, "order_id": 2, "extra": true}`), &jsonKeysOrder{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"order_id": 2, "extra": true}`, string(matched))
+}
+
+func Test_MatchJSONKeys_Leaves_Other_Targets_Unchanged(t *testing.T) {
+	data := []byte(`{"orderId": 1}`)
+
+	var generic map[string]interface{}
+	matched, err := MatchJSONKeys(data, &generic)
+	require.NoError(t, err)
+	assert.Equal(t, data, matched)
+
+	_, err = MatchJSONKeys([]byte(`{"orderId":`), &jsonKeysOrder{})
+	assert.Error(t, err)
+}
+
+func Test_MatchJSONKeys_Returns_Matching_Documents_As_Is(t *testing.T) {
+	data := []byte(`{"order_id": 1, "extra": true}`)
+
+	matched, err := MatchJSONKeys(data, &jsonKeysOrder{})
+	require.NoError(t, err)
+	assert.Equal(t, data, matched)
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maskChar is the character used by the masking helpers
+const maskChar = '*'
+
+// hiddenLength is the number of mask characters standing in for hidden text of unknown
+// length, so the masked value doesn't reveal how long the original was
+const hiddenLength = 3
+
+// MaskEmail masks the local part of an email address, keeping its first character and
+// the domain. Local parts of one character are masked entirely, and values without an
+// "@" are masked like a local part.
+//
+// Example:
+//
+//	utils.MaskEmail("john.doe@example.com") // "j***@example.com"
+//	utils.MaskEmail("j@example.com")        // "***@example.com"
+func MaskEmail(email string) string {
+	local, domain := email, ""
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		local, domain = email[:at], email[at:]
+	}
+
+	runes := []rune(local)
+	hidden := strings.Repeat(string(maskChar), hiddenLength)
+	if len(runes) <= 1 {
+		return hidden + domain
+	}
+	return string(runes[0]) + hidden + domain
+}
+
+// MaskCreditCard masks a card number, keeping its last 4 digits and formatting it in
+// groups of 4. Spaces and dashes are ignored; input containing other characters, or
+// too few digits, is masked entirely.
+//
+// Example:
+//
+//	utils.MaskCreditCard("4111-1111-1111-1234") // "**** **** **** 1234"
+func MaskCreditCard(number string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, number)
+
+	if len(digits) <= 4 || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return strings.Repeat(string(maskChar), 4)
+	}
+
+	masked := MaskString(digits, 0, 4, maskChar)
+
+	var sb strings.Builder
+	for i := 0; i < len(masked); i += 4 {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(masked[i:min(i+4, len(masked))])
+	}
+	return sb.String()
+}
+
+// MaskPhone masks the digits of a phone number except the last 4, keeping "+" and
+// formatting characters. Numbers with 4 digits or fewer have every digit masked.
+//
+// Example:
+//
+//	utils.MaskPhone("+84 901 234 567") // "+** *** **4 567"
+//	utils.MaskPhone("+14155552671")    // "+*******2671"
+func MaskPhone(phone string) string {
+	digits := 0
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+
+	visible := 4
+	if digits <= visible {
+		visible = 0
+	}
+
+	seen := 0
+	return strings.Map(func(r rune) rune {
+		if !unicode.IsDigit(r) {
+			return r
+		}
+		seen++
+		if seen > digits-visible {
+			return r
+		}
+		return maskChar
+	}, phone)
+}
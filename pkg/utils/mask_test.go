@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MaskEmail(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"regular":          {input: "john.doe@example.com", want: "j***@example.com"},
+		"single character": {input: "j@example.com", want: "***@example.com"},
+		"empty local part": {input: "@example.com", want: "***@example.com"},
+		"multibyte":        {input: "élodie@example.fr", want: "é***@example.fr"},
+		"missing at":       {input: "johndoe", want: "j***"},
+		"at in local part": {input: `"a@b"@example.com`, want: `"***@example.com`},
+		"empty":            {input: "", want: "***"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MaskEmail(tt.input))
+		})
+	}
+}
+
+func Test_MaskCreditCard(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"plain digits": {input: "4111111111111234", want: "**** **** **** 1234"},
+		"dashes":       {input: "4111-1111-1111-1234", want: "**** **** **** 1234"},
+		"spaces":       {input: "4111 1111 1111 1234", want: "**** **** **** 1234"},
+		"amex length":  {input: "378282246310005", want: "**** **** ***0 005"},
+		"non-digit":    {input: "4111-abcd-1111-1234", want: "****"},
+		"too short":    {input: "1234", want: "****"},
+		"empty":        {input: "", want: "****"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MaskCreditCard(tt.input))
+		})
+	}
+}
+
+func Test_MaskPhone(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"e164":      {input: "+14155552671", want: "+*******2671"},
+		"formatted": {input: "+84 901 234 567", want: "+** *** **4 567"},
+		"national":  {input: "(415) 555-2671", want: "(***) ***-2671"},
+		"short":     {input: "1234", want: "****"},
+		"no digits": {input: "n/a", want: "n/a"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MaskPhone(tt.input))
+		})
+	}
+}
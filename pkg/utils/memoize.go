@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// Memoized caches the results of a function by key. It is returned by Memoize and is
+// safe for concurrent use.
+type Memoized[K comparable, V any] struct {
+	fn       func(K) (V, error)
+	ttl      time.Duration
+	errorTTL time.Duration
+	now      func() time.Time
+
+	// mu guards entries, inflight and generations together, as a key moves from one
+	// to the other atomically; a SyncMap per field couldn't do that.
+	mu       sync.Mutex
+	entries  map[K]memoEntry[V]
+	inflight map[K]*memoCall[V]
+	// generations counts the invalidations of the keys in flight, so that a call
+	// doesn't cache a result computed before its key was invalidated
+	generations map[K]uint64
+}
+
+type memoEntry[V any] struct {
+	value     V
+	err       error
+	expiresAt time.Time // zero never expires
+}
+
+type memoCall[V any] struct {
+	done       chan struct{}
+	value      V
+	err        error
+	generation uint64
+}
+
+// MemoizeOption configures Memoize
+type MemoizeOption func(*memoizeOptions)
+
+type memoizeOptions struct {
+	errorTTL time.Duration
+}
+
+// WithErrorTTL caches errors for ttl, so a failing key is not recomputed on every
+// call. By default errors are not cached.
+func WithErrorTTL(ttl time.Duration) MemoizeOption {
+	return func(o *memoizeOptions) {
+		o.errorTTL = ttl
+	}
+}
+
+// Memoize caches the results of fn, an expensive pure function, for ttl (forever when
+// ttl <= 0). Concurrent calls for the same key share a single call to fn. Errors are
+// not cached unless WithErrorTTL is given. Expired entries are dropped when their key
+// is next requested.
+//
+// Example:
+//
+//	rates := utils.Memoize(fetchExchangeRate, time.Minute)
+//	rate, err := rates.Get("EUR")
+//	rates.Invalidate("EUR")
+//
+//	lookup := rates.Func() // plain func(string) (float64, error)
+func Memoize[K comparable, V any](fn func(K) (V, error), ttl time.Duration, opts ...MemoizeOption) *Memoized[K, V] {
+	options := memoizeOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &Memoized[K, V]{
+		fn:          fn,
+		ttl:         ttl,
+		errorTTL:    options.errorTTL,
+		now:         time.Now,
+		entries:     make(map[K]memoEntry[V]),
+		inflight:    make(map[K]*memoCall[V]),
+		generations: make(map[K]uint64),
+	}
+}
+
+// Get returns the cached result for key, calling the function when there is none
+func (m *Memoized[K, V]) Get(key K) (V, error) {
+	m.mu.Lock()
+	if entry, ok := m.entries[key]; ok {
+		if entry.expiresAt.IsZero() || m.now().Before(entry.expiresAt) {
+			m.mu.Unlock()
+			return entry.value, entry.err
+		}
+		delete(m.entries, key)
+	}
+
+	if call, ok := m.inflight[key]; ok {
+		m.mu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+
+	call := &memoCall[V]{done: make(chan struct{}), generation: m.generations[key]}
+	m.inflight[key] = call
+	m.mu.Unlock()
+
+	m.compute(key, call)
+	return call.value, call.err
+}
+
+// Func returns Get as a plain function, a drop-in replacement for the memoized one
+func (m *Memoized[K, V]) Func() func(K) (V, error) {
+	return m.Get
+}
+
+// Invalidate drops the cached result for key. A call already in flight still
+// returns its result to its callers but doesn't cache it.
+func (m *Memoized[K, V]) Invalidate(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	if _, ok := m.inflight[key]; ok {
+		m.generations[key]++
+	}
+}
+
+// Clear drops every cached result. Calls already in flight aren't cached either.
+func (m *Memoized[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.entries)
+	for key := range m.inflight {
+		m.generations[key]++
+	}
+}
+
+// compute calls the function for key and publishes the result to waiting callers.
+// A panic is reported to the waiters as an error and re-raised in the caller.
+func (m *Memoized[K, V]) compute(key K, call *memoCall[V]) {
+	completed := false
+	defer func() {
+		if !completed {
+			r := recover()
+			call.err = errors.Internal(fmt.Sprintf("memoized function panicked: %v", r))
+			m.finish(key, call, false)
+			panic(r)
+		}
+	}()
+
+	call.value, call.err = m.fn(key)
+	completed = true
+	m.finish(key, call, true)
+}
+
+func (m *Memoized[K, V]) finish(key K, call *memoCall[V], store bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.inflight, key)
+	close(call.done)
+
+	invalidated := m.generations[key] != call.generation
+	delete(m.generations, key)
+	if !store || invalidated {
+		return
+	}
+
+	ttl := m.ttl
+	if call.err != nil {
+		if m.errorTTL <= 0 {
+			return
+		}
+		ttl = m.errorTTL
+	}
+
+	entry := memoEntry[V]{value: call.value, err: call.err}
+	if ttl > 0 {
+		entry.expiresAt = m.now().Add(ttl)
+	}
+	m.entries[key] = entry
+}
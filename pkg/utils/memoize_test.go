@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Memoize_Concurrent_Calls_Compute_Once(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	square := Memoize(func(n int) (int, error) {
+		calls.Add(1)
+		<-release
+		return n * n, nil
+	}, time.Minute)
+
+	var wg sync.WaitGroup
+	results := make([]int, 50)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := square.Get(7)
+			assert.NoError(t, err)
+			results[i] = value
+		}()
+	}
+
+	// let every goroutine reach Get before the computation finishes
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, value := range results {
+		assert.Equal(t, 49, value)
+	}
+
+	value, err := square.Func()(7)
+	require.NoError(t, err)
+	assert.Equal(t, 49, value)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func Test_Memoize_Expires_After_TTL(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var calls atomic.Int32
+	m := Memoize(func(key string) (int32, error) {
+		return calls.Add(1), nil
+	}, time.Minute)
+	m.now = func() time.Time { return now }
+
+	first, _ := m.Get("a")
+	again, _ := m.Get("a")
+	assert.Equal(t, first, again)
+
+	now = now.Add(time.Minute)
+	expired, _ := m.Get("a")
+	assert.NotEqual(t, first, expired)
+}
+
+func Test_Memoize_Does_Not_Cache_Errors_By_Default(t *testing.T) {
+	var calls atomic.Int32
+	m := Memoize(func(key string) (string, error) {
+		if calls.Add(1) == 1 {
+			return "", errors.New(errors.CodeServiceUnavailable)
+		}
+		return "ok", nil
+	}, time.Minute)
+
+	_, err := m.Get("a")
+	assert.Error(t, err)
+
+	value, err := m.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", value)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func Test_Memoize_WithErrorTTL_Caches_Errors(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var calls atomic.Int32
+	m := Memoize(func(key string) (string, error) {
+		calls.Add(1)
+		return "", errors.New(errors.CodeServiceUnavailable)
+	}, time.Hour, WithErrorTTL(time.Second))
+	m.now = func() time.Time { return now }
+
+	_, err := m.Get("a")
+	assert.Error(t, err)
+	_, err = m.Get("a")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+
+	now = now.Add(time.Second)
+	_, _ = m.Get("a")
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func Test_Memoize_Invalidate_And_Clear(t *testing.T) {
+	var calls atomic.Int32
+	m := Memoize(func(key string) (int32, error) {
+		calls.Add(1)
+		return int32(len(key)), nil
+	}, 0)
+
+	_, _ = m.Get("a")
+	_, _ = m.Get("bb")
+	_, _ = m.Get("a")
+	assert.Equal(t, int32(2), calls.Load())
+
+	m.Invalidate("a")
+	_, _ = m.Get("a")
+	_, _ = m.Get("bb")
+	assert.Equal(t, int32(3), calls.Load())
+
+	m.Clear()
+	_, _ = m.Get("a")
+	_, _ = m.Get("bb")
+	assert.Equal(t, int32(5), calls.Load())
+}
+
+func Test_Memoize_Does_Not_Cache_Calls_Invalidated_In_Flight(t *testing.T) {
+	for name, invalidate := range map[string]func(m *Memoized[string, int32]){
+		"invalidate": func(m *Memoized[string, int32]) { m.Invalidate("a") },
+		"clear":      func(m *Memoized[string, int32]) { m.Clear() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int32
+			started := make(chan struct{})
+			release := make(chan struct{})
+			m := Memoize(func(key string) (int32, error) {
+				if calls.Add(1) == 1 {
+					close(started)
+					<-release
+				}
+				return calls.Load(), nil
+			}, 0)
+
+			done := make(chan int32)
+			go func() {
+				value, _ := m.Get("a")
+				done <- value
+			}()
+
+			<-started
+			invalidate(m)
+			close(release)
+			assert.Equal(t, int32(1), <-done, "the in-flight caller still gets its result")
+
+			value, err := m.Get("a")
+			require.NoError(t, err)
+			assert.Equal(t, int32(2), value, "the stale result was not cached")
+			assert.Empty(t, m.generations)
+		})
+	}
+}
+
+func Test_Memoize_Panic_Releases_Waiters(t *testing.T) {
+	m := Memoize(func(key string) (string, error) {
+		panic("boom")
+	}, time.Minute)
+
+	assert.PanicsWithValue(t, "boom", func() { _, _ = m.Get("a") })
+	assert.Panics(t, func() { _, _ = m.Get("a") }, "panics are not cached")
+}
@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// maxEmailLength is the maximum length of an email address (RFC 5321)
+const maxEmailLength = 254
+
+// Digit count bounds of E.164 numbers, country code included
+const (
+	e164MinDigits = 8
+	e164MaxDigits = 15
+)
+
+// gmailDomains are the domains whose local parts ignore dots and plus-tags
+var gmailDomains = map[string]struct{}{
+	"gmail.com":      {},
+	"googlemail.com": {},
+}
+
+// countryCallingCodes maps ISO 3166-1 alpha-2 regions to their calling code
+var countryCallingCodes = map[string]string{
+	"US": "1", "CA": "1", "GB": "44", "IE": "353", "FR": "33", "DE": "49", "ES": "34",
+	"IT": "39", "NL": "31", "BE": "32", "CH": "41", "AT": "43", "SE": "46", "NO": "47",
+	"DK": "45", "FI": "358", "PL": "48", "PT": "351", "RU": "7", "TR": "90", "IN": "91",
+	"CN": "86", "JP": "81", "KR": "82", "VN": "84", "TH": "66", "SG": "65", "MY": "60",
+	"ID": "62", "PH": "63", "AU": "61", "NZ": "64", "BR": "55", "MX": "52", "AR": "54",
+	"ZA": "27", "NG": "234", "EG": "20", "AE": "971", "SA": "966", "IL": "972",
+}
+
+// EmailOption configures NormalizeEmail
+type EmailOption func(*emailOptions)
+
+type emailOptions struct {
+	canonicalizeProvider bool
+	stripPlusTags        bool
+}
+
+// WithProviderCanonicalization applies provider-specific rules: for Gmail addresses
+// dots and plus-tags are removed from the local part and googlemail.com becomes
+// gmail.com, since they all reach the same mailbox.
+func WithProviderCanonicalization() EmailOption {
+	return func(o *emailOptions) {
+		o.canonicalizeProvider = true
+	}
+}
+
+// WithPlusTagStripping removes "+tag" suffixes from the local part for every domain.
+func WithPlusTagStripping() EmailOption {
+	return func(o *emailOptions) {
+		o.stripPlusTags = true
+	}
+}
+
+// NormalizeEmail trims and lowercases an email address after validating its basic
+// shape, returning a CodeInvalidInput error for malformed addresses. Provider-specific
+// canonicalization is opt-in.
+//
+// Example:
+//
+//	email, err := utils.NormalizeEmail("  John.Doe+news@GMail.com ", utils.WithProviderCanonicalization())
+//	// email = "johndoe@gmail.com"
+func NormalizeEmail(s string, opts ...EmailOption) (string, error) {
+	var o emailOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	email := strings.ToLower(strings.TrimSpace(s))
+	if len(email) > maxEmailLength {
+		return "", invalidInput(s, "email is too long")
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || addr.Name != "" {
+		return "", invalidInput(s, "invalid email address")
+	}
+
+	local, domain, _ := strings.Cut(email, "@")
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return "", invalidInput(s, "invalid email domain")
+	}
+
+	_, isGmail := gmailDomains[domain]
+	if o.stripPlusTags || (o.canonicalizeProvider && isGmail) {
+		local, _, _ = strings.Cut(local, "+")
+	}
+	if o.canonicalizeProvider && isGmail {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+	if local == "" {
+		return "", invalidInput(s, "invalid email address")
+	}
+
+	return local + "@" + domain, nil
+}
+
+// NormalizePhone converts a phone number to E.164 ("+84901234567"). Numbers starting
+// with "+" or the "00" international prefix keep their country code; national numbers
+// are prefixed with the calling code of defaultRegion (an ISO 3166-1 alpha-2 code such
+// as "US" or "VN") after dropping their trunk prefix. Spaces, dots, dashes and
+// parentheses are ignored. Malformed numbers return a CodeInvalidInput error.
+//
+// Example:
+//
+//	phone, err := utils.NormalizePhone("090 123 4567", "VN")    // "+84901234567"
+//	phone, err = utils.NormalizePhone("(415) 555-2671", "US")  // "+14155552671"
+func NormalizePhone(s, defaultRegion string) (string, error) {
+	number := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')', '\t':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+
+	international := false
+	switch {
+	case strings.HasPrefix(number, "+"):
+		number, international = number[1:], true
+	case strings.HasPrefix(number, "00"):
+		number, international = number[2:], true
+	}
+
+	if number == "" || strings.IndexFunc(number, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return "", invalidInput(s, "invalid phone number")
+	}
+
+	if !international {
+		region := strings.ToUpper(strings.TrimSpace(defaultRegion))
+		code, ok := countryCallingCodes[region]
+		if !ok {
+			return "", invalidInput(s, fmt.Sprintf("unsupported region %q", defaultRegion))
+		}
+
+		if code == "1" {
+			// North American numbers have no trunk zero but may be dialed with a leading 1
+			if len(number) == 11 && strings.HasPrefix(number, "1") {
+				number = number[1:]
+			}
+		} else {
+			number = strings.TrimPrefix(number, "0")
+		}
+		number = code + number
+	}
+
+	if len(number) < e164MinDigits || len(number) > e164MaxDigits || number[0] == '0' {
+		return "", invalidInput(s, "invalid phone number length")
+	}
+
+	return "+" + number, nil
+}
+
+// invalidInput builds the CodeInvalidInput error returned by the normalizers
+func invalidInput(value, message string) error {
+	return errors.New(errors.CodeInvalidInput, message).WithContext("value", value)
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NormalizeEmail(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		opts  []EmailOption
+		want  string
+	}{
+		"mixed case and spaces":       {input: "  John.Doe@Example.COM ", want: "john.doe@example.com"},
+		"plus tag kept by default":    {input: "john+news@example.com", want: "john+news@example.com"},
+		"plus tag stripped":           {input: "john+news@example.com", opts: []EmailOption{WithPlusTagStripping()}, want: "john@example.com"},
+		"gmail kept by default":       {input: "John.Doe+x@gmail.com", want: "john.doe+x@gmail.com"},
+		"gmail canonicalized":         {input: "John.Doe+x@GMail.com", opts: []EmailOption{WithProviderCanonicalization()}, want: "johndoe@gmail.com"},
+		"googlemail canonicalized":    {input: "j.doe@googlemail.com", opts: []EmailOption{WithProviderCanonicalization()}, want: "jdoe@gmail.com"},
+		"other providers keep dots":   {input: "j.doe+x@example.com", opts: []EmailOption{WithProviderCanonicalization()}, want: "j.doe+x@example.com"},
+		"subdomain is a valid domain": {input: "ops@mail.example.co.uk", want: "ops@mail.example.co.uk"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NormalizeEmail(tt.input, tt.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_NormalizeEmail_Rejects_Malformed_Addresses(t *testing.T) {
+	for _, input := range []string{"", "john", "john@", "@example.com", "john@localhost", "John <john@example.com>", "a b@example.com", "+tag@gmail.com"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := NormalizeEmail(input, WithProviderCanonicalization())
+			assert.True(t, errors.Is(err, errors.CodeInvalidInput), "%q: %v", input, err)
+		})
+	}
+}
+
+func Test_NormalizePhone(t *testing.T) {
+	tests := map[string]struct {
+		input  string
+		region string
+		want   string
+	}{
+		"national with trunk zero":  {input: "090 123 4567", region: "VN", want: "+84901234567"},
+		"north american format":     {input: "(415) 555-2671", region: "US", want: "+14155552671"},
+		"north american with one":   {input: "1-415-555-2671", region: "us", want: "+14155552671"},
+		"already e164":              {input: "+44 20 7946 0958", region: "US", want: "+442079460958"},
+		"international prefix":      {input: "0044 20 7946 0958", region: "VN", want: "+442079460958"},
+		"region ignored when +code": {input: "+84901234567", region: "", want: "+84901234567"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NormalizePhone(tt.input, tt.region)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_NormalizePhone_Rejects_Invalid_Numbers(t *testing.T) {
+	tests := map[string]struct {
+		input  string
+		region string
+	}{
+		"letters":        {input: "+1 415 CALL NOW", region: "US"},
+		"too short":      {input: "123", region: "US"},
+		"too long":       {input: "+1234567890123456", region: "US"},
+		"unknown region": {input: "0901234567", region: "XX"},
+		"empty":          {input: " ", region: "US"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NormalizePhone(tt.input, tt.region)
+			assert.True(t, errors.Is(err, errors.CodeInvalidInput), "%v", err)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package utils
+
+// Option and Result are optional ergonomics for code that wants to make optionality
+// explicit, e.g. when storing "maybe" values in structs or channels. They are not a
+// replacement for idiomatic Go returns: functions should keep returning (T, bool)
+// and (T, error), and callers can wrap those with OptionOf / ResultOf when useful.
+
+// Option represents a value that may or may not be present.
+// The zero value is an empty option (see NoneOption).
+type Option[T any] struct {
+	value T
+	ok    bool
+}
+
+// SomeOption returns an Option holding the given value.
+//
+// Example:
+//
+//	name := utils.SomeOption("John")
+//	v, ok := name.Get() // v = "John", ok = true
+func SomeOption[T any](value T) Option[T] {
+	return Option[T]{value: value, ok: true}
+}
+
+// NoneOption returns an empty Option.
+//
+// Example:
+//
+//	name := utils.NoneOption[string]()
+//	name.OrElse("Guest") // "Guest"
+func NoneOption[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// OptionOf converts an idiomatic (T, bool) pair into an Option.
+//
+// Example:
+//
+//	opt := utils.OptionOf(utils.First(users))
+func OptionOf[T any](value T, ok bool) Option[T] {
+	if !ok {
+		return NoneOption[T]()
+	}
+	return SomeOption(value)
+}
+
+// Get returns the value and whether it is present
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+// IsSome returns true if the option holds a value
+func (o Option[T]) IsSome() bool {
+	return o.ok
+}
+
+// IsNone returns true if the option is empty
+func (o Option[T]) IsNone() bool {
+	return !o.ok
+}
+
+// OrElse returns the value if present, otherwise the given default
+func (o Option[T]) OrElse(defaultValue T) T {
+	if o.ok {
+		return o.value
+	}
+	return defaultValue
+}
+
+// Result represents either a successful value or an error
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok returns a successful Result holding the given value.
+//
+// Example:
+//
+//	res := utils.Ok(42)
+//	res.Unwrap() // 42
+func Ok[T any](value T) Result[T] {
+	return Result[T]{value: value}
+}
+
+// Err returns a failed Result holding the given error.
+//
+// Example:
+//
+//	res := utils.Err[int](errors.New("boom"))
+//	res.UnwrapOr(0) // 0
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// ResultOf converts an idiomatic (T, error) pair into a Result.
+//
+// Example:
+//
+//	res := utils.ResultOf(strconv.Atoi("42"))
+func ResultOf[T any](value T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+	return Ok(value)
+}
+
+// IsOk returns true if the result holds no error
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// IsErr returns true if the result holds an error
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+// Error returns the held error, or nil on success
+func (r Result[T]) Error() error {
+	return r.err
+}
+
+// Get returns the value and error as an idiomatic Go pair
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// Unwrap returns the value, panicking if the result holds an error.
+// Like Must, use it only in initialization code or tests.
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(r.err)
+	}
+	return r.value
+}
+
+// UnwrapOr returns the value on success, otherwise the given default
+func (r Result[T]) UnwrapOr(defaultValue T) T {
+	if r.err != nil {
+		return defaultValue
+	}
+	return r.value
+}
+
+// FindOption is like Find but returns an Option.
+//
+// Example:
+//
+//	user := utils.FindOption(users, func(u User) bool { return u.ID == id })
+//	current := user.OrElse(guest)
+func FindOption[T any](slice []T, predicate func(T) bool) Option[T] {
+	return OptionOf(Find(slice, predicate))
+}
+
+// FirstOption is like First but returns an Option
+func FirstOption[T any](slice []T) Option[T] {
+	return OptionOf(First(slice))
+}
+
+// LastOption is like Last but returns an Option
+func LastOption[T any](slice []T) Option[T] {
+	return OptionOf(Last(slice))
+}
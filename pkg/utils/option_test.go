@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Option(t *testing.T) {
+	some := SomeOption("john")
+	v, ok := some.Get()
+	assert.True(t, ok)
+	assert.Equal(t, "john", v)
+	assert.True(t, some.IsSome())
+	assert.Equal(t, "john", some.OrElse("guest"))
+
+	none := NoneOption[string]()
+	_, ok = none.Get()
+	assert.False(t, ok)
+	assert.True(t, none.IsNone())
+	assert.Equal(t, "guest", none.OrElse("guest"))
+
+	var zero Option[int]
+	assert.True(t, zero.IsNone())
+}
+
+func Test_Result(t *testing.T) {
+	ok := ResultOf(strconv.Atoi("42"))
+	assert.True(t, ok.IsOk())
+	assert.Equal(t, 42, ok.Unwrap())
+	assert.Equal(t, 42, ok.UnwrapOr(0))
+
+	failed := ResultOf(strconv.Atoi("abc"))
+	assert.True(t, failed.IsErr())
+	assert.Error(t, failed.Error())
+	assert.Equal(t, -1, failed.UnwrapOr(-1))
+	assert.Panics(t, func() { failed.Unwrap() })
+
+	boom := errors.New("boom")
+	_, err := Err[int](boom).Get()
+	assert.ErrorIs(t, err, boom)
+}
+
+func Test_Find_Option_Variants(t *testing.T) {
+	numbers := []int{1, 2, 3, 4}
+
+	assert.Equal(t, 2, FindOption(numbers, func(n int) bool { return n%2 == 0 }).OrElse(0))
+	assert.True(t, FindOption(numbers, func(n int) bool { return n > 10 }).IsNone())
+	assert.Equal(t, 1, FirstOption(numbers).OrElse(0))
+	assert.Equal(t, 4, LastOption(numbers).OrElse(0))
+	assert.True(t, FirstOption([]int{}).IsNone())
+}
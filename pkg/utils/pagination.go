@@ -3,13 +3,16 @@ package utils
 import (
 	"fmt"
 	"math"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+	"github.com/phatnt199/go-infra/pkg/json"
 	"github.com/phatnt199/go-infra/pkg/mapper"
 
 	"emperror.dev/errors"
-	"github.com/goccy/go-json"
 )
 
 const (
@@ -33,6 +36,29 @@ func NewListResult[T any](items []T, size int, page int, totalItems int64) *List
 	return listResult
 }
 
+// Paginated converts the result to the standard list response envelope
+//
+// Example:
+//
+//	result := utils.NewListResult(users, query.Size, query.Page, total)
+//	return c.Paginated(http.StatusOK, result)
+func (p *ListResult[T]) Paginated() contracts.Paginated[T] {
+	return contracts.NewPaginated(p.Items, p.Page, p.Size, p.TotalItems)
+}
+
+// PageMeta implements contracts.Pageable
+func (p *ListResult[T]) PageMeta() contracts.PageMeta {
+	return contracts.NewPageMeta(p.Page, p.Size, p.TotalItems)
+}
+
+// PageData implements contracts.Pageable, returning the items or an empty slice
+func (p *ListResult[T]) PageData() interface{} {
+	if p.Items == nil {
+		return []T{}
+	}
+	return p.Items
+}
+
 func (p *ListResult[T]) String() string {
 	j, _ := json.Marshal(p)
 	return string(j)
@@ -77,12 +103,17 @@ func NewListQueryFromQueryParams(size string, page string) *ListQuery {
 
 func GetListQueryFromContext(c contracts.Context) (*ListQuery, error) {
 	q := &ListQuery{}
-	var page, size, orderBy string
 
-	// Get query parameters using the generic interface
-	size = c.QueryParam("size")
-	page = c.QueryParam("page")
-	orderBy = c.QueryParam("orderBy")
+	// size, page and orderBy are bound through their `query` tags
+	if err := c.BindQuery(q); err != nil {
+		return nil, err
+	}
+	if q.Size == 0 {
+		q.Size = defaultSize
+	}
+	if q.Page == 0 {
+		q.Page = defaultPage
+	}
 
 	// Handle filters from query params
 	queryParams := c.QueryParams()
@@ -108,14 +139,6 @@ func GetListQueryFromContext(c contracts.Context) (*ListQuery, error) {
 		}
 	}
 
-	if err := q.SetPage(page); err != nil {
-		return nil, err
-	}
-	if err := q.SetSize(size); err != nil {
-		return nil, err
-	}
-	q.SetOrderBy(orderBy)
-
 	return q, nil
 }
 
@@ -207,3 +230,72 @@ func ListResultToListResultDto[TDto any, TModel any](
 		TotalPage:  listResult.TotalPage,
 	}, nil
 }
+
+// HeaderTotalCount is the response header carrying the total number of items
+const HeaderTotalCount = "X-Total-Count"
+
+// WritePaginatedJSON writes result as JSON with an RFC 5988 Link header (first, prev,
+// next, last) and an X-Total-Count header. Links point at baseURL, or the request path
+// when baseURL is empty, and keep the other query parameters of the current request.
+// The prev/next links are omitted on the first/last page.
+//
+// Example:
+//
+//	result := utils.NewListResult(users, query.Size, query.Page, total)
+//	return utils.WritePaginatedJSON(c, result, "https://api.example.com/users")
+func WritePaginatedJSON[T any](c contracts.Context, result *ListResult[T], baseURL string) error {
+	if result == nil {
+		return errors.New("listResult is nil")
+	}
+
+	if baseURL == "" {
+		baseURL = c.Request().URL.Path
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return errors.WrapIf(err, "invalid pagination base url")
+	}
+
+	if link := paginationLinks(base, c.QueryParams(), result.Page, result.Size, result.TotalPage); link != "" {
+		c.SetHeader("Link", link)
+	}
+	c.SetHeader(HeaderTotalCount, strconv.FormatInt(result.TotalItems, 10))
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// paginationLinks builds the Link header value for the given page
+func paginationLinks(base *url.URL, query url.Values, page, size, totalPages int) string {
+	if size <= 0 {
+		return ""
+	}
+
+	link := func(targetPage int, rel string) string {
+		params := url.Values{}
+		for key, values := range query {
+			params[key] = values
+		}
+		params.Set("page", strconv.Itoa(targetPage))
+		params.Set("size", strconv.Itoa(size))
+
+		target := *base
+		target.RawQuery = params.Encode()
+		return fmt.Sprintf("<%s>; rel=\"%s\"", target.String(), rel)
+	}
+
+	lastPage := totalPages
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	links := []string{link(1, "first")}
+	if page > 1 {
+		links = append(links, link(min(page-1, lastPage), "prev"))
+	}
+	if page < lastPage {
+		links = append(links, link(page+1, "next"))
+	}
+	links = append(links, link(lastPage, "last"))
+
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/phatnt199/go-infra/pkg/adapter/http/contracts"
+)
+
+func Test_Pagination_Links_Last_And_First_Page(t *testing.T) {
+	base, _ := url.Parse("/users")
+
+	assert.Equal(t,
+		`</users?page=1&size=10>; rel="first", </users?page=2&size=10>; rel="prev", </users?page=3&size=10>; rel="last"`,
+		paginationLinks(base, url.Values{}, 3, 10, 3),
+	)
+	assert.Equal(t,
+		`</users?page=1&size=10>; rel="first", </users?page=2&size=10>; rel="next", </users?page=3&size=10>; rel="last"`,
+		paginationLinks(base, url.Values{"page": {"1"}}, 1, 10, 3),
+	)
+
+	// an empty result only links to the first page
+	assert.Equal(t,
+		`</users?page=1&size=10>; rel="first", </users?page=1&size=10>; rel="last"`,
+		paginationLinks(base, nil, 1, 10, 0),
+	)
+}
+
+func Test_ListResult_Paginated_Meta(t *testing.T) {
+	result := NewListResult([]string{"c", "d"}, 2, 2, 5)
+
+	paginated := result.Paginated()
+	assert.Equal(t, []string{"c", "d"}, paginated.Data)
+	assert.Equal(t, contracts.PageMeta{Page: 2, Size: 2, Total: 5, TotalPages: 3, HasNext: true, HasPrev: true}, paginated.Meta)
+	assert.Equal(t, paginated.Meta, result.PageMeta())
+
+	last := NewListResult[string](nil, 2, 3, 5)
+	assert.False(t, last.PageMeta().HasNext)
+	assert.Equal(t, []string{}, last.PageData())
+}
@@ -0,0 +1,21 @@
+package utils
+
+import "github.com/phatnt199/go-infra/pkg/utils/pool"
+
+// Pool is a type-safe sync.Pool with an optional reset hook, see pkg/utils/pool for
+// the pooling caveats
+type Pool[T any] = pool.Pool[T]
+
+// NewPool creates a pool allocating values with newFn and resetting them with resetFn
+// (when not nil) on Put. Use it for scratch objects on hot paths, such as encoding
+// buffers, and reset them so no data leaks between callers.
+//
+// Example:
+//
+//	var buffers = utils.NewPool(func() *bytes.Buffer { return new(bytes.Buffer) }, (*bytes.Buffer).Reset)
+//
+//	buf := buffers.Get()
+//	defer buffers.Put(buf)
+func NewPool[T any](newFn func() T, resetFn func(T)) *Pool[T] {
+	return pool.New(newFn, resetFn)
+}
@@ -0,0 +1,49 @@
+// Package pool provides a type-safe wrapper around sync.Pool.
+//
+// It lives in its own package (rather than in utils) so that low-level packages
+// such as errors can use it without an import cycle; utils re-exports it as
+// utils.Pool and utils.NewPool.
+package pool
+
+import "sync"
+
+// Pool is a typed sync.Pool with an optional reset hook run on Put.
+//
+// The usual pooling caveats apply:
+//   - Pooled values may be dropped at any garbage collection, so a pool is a cache of
+//     scratch objects, never a store of state.
+//   - A value must not be used after it was Put back; another goroutine may own it.
+//   - Reset values before reuse (see the reset hook), or data leaks between callers.
+//   - Don't pool values that grew very large, or the pool pins their memory; discard
+//     them instead of putting them back.
+//   - Pool pointer types (e.g. *bytes.Buffer), otherwise Put allocates.
+type Pool[T any] struct {
+	pool  sync.Pool
+	reset func(T)
+}
+
+// New creates a pool allocating values with newFn. When resetFn is not nil it is
+// called on every value passed to Put.
+//
+// Example:
+//
+//	buffers := pool.New(func() *bytes.Buffer { return new(bytes.Buffer) }, (*bytes.Buffer).Reset)
+func New[T any](newFn func() T, resetFn func(T)) *Pool[T] {
+	return &Pool[T]{
+		pool:  sync.Pool{New: func() any { return newFn() }},
+		reset: resetFn,
+	}
+}
+
+// Get returns a pooled value, or a new one when the pool is empty
+func (p *Pool[T]) Get() T {
+	return p.pool.Get().(T)
+}
+
+// Put resets value and returns it to the pool
+func (p *Pool[T]) Put(value T) {
+	if p.reset != nil {
+		p.reset(value)
+	}
+	p.pool.Put(value)
+}
@@ -0,0 +1,59 @@
+package pool
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Pool_Get_Allocates_With_New(t *testing.T) {
+	p := New(func() *bytes.Buffer { return bytes.NewBufferString("fresh") }, nil)
+
+	assert.Equal(t, "fresh", p.Get().String())
+}
+
+func Test_Pool_Put_Runs_Reset(t *testing.T) {
+	var reset []*bytes.Buffer
+	p := New(func() *bytes.Buffer { return new(bytes.Buffer) }, func(buf *bytes.Buffer) {
+		reset = append(reset, buf)
+		buf.Reset()
+	})
+
+	buf := p.Get()
+	buf.WriteString("scratch")
+	p.Put(buf)
+
+	assert.Equal(t, []*bytes.Buffer{buf}, reset)
+	assert.Zero(t, buf.Len())
+}
+
+// benchmarkPayload is a validation error response listing many fields, so the
+// buffer grows several times when it isn't reused
+var benchmarkPayload = func() map[string]any {
+	fields := make([]map[string]string, 50)
+	for i := range fields {
+		fields[i] = map[string]string{"field": "items.value", "message": "must be a positive number"}
+	}
+	return map[string]any{"code": "VALIDATION_ERROR", "fields": fields}
+}()
+
+func BenchmarkEncode_NewBuffer(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf := new(bytes.Buffer)
+		_ = json.NewEncoder(buf).Encode(benchmarkPayload)
+	}
+}
+
+func BenchmarkEncode_PooledBuffer(b *testing.B) {
+	p := New(func() *bytes.Buffer { return new(bytes.Buffer) }, (*bytes.Buffer).Reset)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf := p.Get()
+		_ = json.NewEncoder(buf).Encode(benchmarkPayload)
+		p.Put(buf)
+	}
+}
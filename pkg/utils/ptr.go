@@ -137,3 +137,67 @@ func Coalesce[T any](defaultValue T, ptrs ...*T) T {
 	}
 	return defaultValue
 }
+
+// PtrSlice converts a slice of values into a slice of pointers.
+// Each pointer refers to a copy of the element, so mutating through the result
+// does not modify the input slice. A nil input returns nil.
+//
+// Example:
+//
+//	names := []string{"John", "Jane"}
+//	ptrs := utils.PtrSlice(names) // []*string{&"John", &"Jane"}
+func PtrSlice[T any](s []T) []*T {
+	if s == nil {
+		return nil
+	}
+
+	result := make([]*T, len(s))
+	for i, v := range s {
+		result[i] = Ptr(v)
+	}
+	return result
+}
+
+// DerefSlice converts a slice of pointers into a slice of values.
+// When skipNil is true nil pointers are dropped from the result, otherwise they
+// are substituted with the zero value of T (preserving length and positions).
+// A nil input returns nil.
+//
+// Example:
+//
+//	ptrs := []*int{utils.Ptr(1), nil, utils.Ptr(3)}
+//	utils.DerefSlice(ptrs, false) // [1, 0, 3]
+//	utils.DerefSlice(ptrs, true)  // [1, 3]
+func DerefSlice[T any](s []*T, skipNil bool) []T {
+	if s == nil {
+		return nil
+	}
+
+	result := make([]T, 0, len(s))
+	for _, ptr := range s {
+		if ptr == nil && skipNil {
+			continue
+		}
+		result = append(result, ValueOrZero(ptr))
+	}
+	return result
+}
+
+// PtrMap converts a map of values into a map of pointers.
+// Each pointer refers to a copy of the value. A nil input returns nil.
+//
+// Example:
+//
+//	ages := map[string]int{"john": 30}
+//	ptrs := utils.PtrMap(ages) // map[string]*int{"john": &30}
+func PtrMap[K comparable, V any](m map[K]V) map[K]*V {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[K]*V, len(m))
+	for k, v := range m {
+		result[k] = Ptr(v)
+	}
+	return result
+}
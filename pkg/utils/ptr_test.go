@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PtrSlice(t *testing.T) {
+	values := []int{1, 2, 3}
+	ptrs := PtrSlice(values)
+
+	assert.Len(t, ptrs, 3)
+	for i, p := range ptrs {
+		assert.Equal(t, values[i], *p)
+	}
+
+	// pointers refer to copies, not the input slice
+	*ptrs[0] = 100
+	assert.Equal(t, 1, values[0])
+
+	assert.Nil(t, PtrSlice[int](nil))
+}
+
+func Test_DerefSlice_With_Nil_Pointers(t *testing.T) {
+	ptrs := []*string{Ptr("a"), nil, Ptr("c")}
+
+	assert.Equal(t, []string{"a", "", "c"}, DerefSlice(ptrs, false))
+	assert.Equal(t, []string{"a", "c"}, DerefSlice(ptrs, true))
+	assert.Nil(t, DerefSlice[string](nil, true))
+	assert.Empty(t, DerefSlice([]*string{nil}, true))
+}
+
+func Test_PtrMap(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+	ptrs := PtrMap(m)
+
+	assert.Len(t, ptrs, 2)
+	assert.Equal(t, 1, *ptrs["a"])
+	assert.Equal(t, 2, *ptrs["b"])
+
+	assert.Nil(t, PtrMap[string, int](nil))
+}
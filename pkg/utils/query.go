@@ -0,0 +1,203 @@
+package utils
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryTag is the struct tag used to map query parameters to fields
+const QueryTag = "query"
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// BindQueryValues decodes query parameters into the struct pointed to by dst, using
+// the `query:"..."` tags (the field name is used when the tag is missing, `query:"-"`
+// skips the field). It is the framework-agnostic core of contracts.Context.BindQuery.
+//
+// Supported field types are strings, bools, ints, uints, floats, time.Duration,
+// time.Time (RFC 3339 or date), encoding.TextUnmarshaler and pointers or slices of
+// those. Slices accept repeated keys (?tag=a&tag=b) or comma-separated values.
+// Fields of other types (e.g. nested structs) are left untouched. Embedded structs
+// are flattened.
+//
+// Example:
+//
+//	type SearchQuery struct {
+//	    Term  string   `query:"q"`
+//	    Page  int      `query:"page"`
+//	    Tags  []string `query:"tags"`
+//	}
+//
+//	var q SearchQuery
+//	err := utils.BindQueryValues(url.Values{"q": {"go"}, "page": {"2"}}, &q)
+func BindQueryValues(values url.Values, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("query binding requires a non-nil pointer to a struct, got %T", dst)
+	}
+	return bindQueryStruct(values, v.Elem())
+}
+
+func bindQueryStruct(values url.Values, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := v.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := bindQueryStruct(values, fieldValue); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Tag.Get(QueryTag)
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		raw, ok := values[name]
+		if !ok || len(raw) == 0 {
+			continue
+		}
+
+		if err := setQueryField(fieldValue, raw); err != nil {
+			return fmt.Errorf("invalid value for query parameter %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setQueryField assigns raw query values to a field, skipping unsupported types
+func setQueryField(field reflect.Value, raw []string) error {
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		elemType := field.Type().Elem()
+		if !isQueryScalar(elemType) {
+			return nil
+		}
+
+		var parts []string
+		for _, value := range raw {
+			for _, part := range strings.Split(value, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					parts = append(parts, part)
+				}
+			}
+		}
+
+		slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setQueryScalar(slice.Index(i), part); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	if !isQueryScalar(field.Type()) {
+		return nil
+	}
+	return setQueryScalar(field, raw[0])
+}
+
+// isQueryScalar reports whether a single query value can be decoded into t
+func isQueryScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// setQueryScalar parses a single query value into field
+func setQueryScalar(field reflect.Value, raw string) error {
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setQueryScalar(ptr.Elem(), raw); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+
+	if field.Type() == timeType {
+		parsed, err := ParseTimeRFC3339(raw)
+		if err != nil {
+			if parsed, err = ParseTimeDate(raw); err != nil {
+				return err
+			}
+		}
+		field.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+
+	if field.Type() == durationType {
+		parsed, err := ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(parsed))
+		return nil
+	}
+
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPaging struct {
+	Page int `query:"page"`
+	Size int `query:"size"`
+}
+
+type testSearchQuery struct {
+	testPaging
+	Term    string         `query:"q"`
+	Active  *bool          `query:"active"`
+	Tags    []string       `query:"tags"`
+	IDs     []int64        `query:"ids"`
+	MaxAge  time.Duration  `query:"maxAge"`
+	Since   time.Time      `query:"since"`
+	Score   float64        `query:"score"`
+	Ignored string         `query:"-"`
+	Filters []*FilterModel `query:"filters"`
+}
+
+func Test_Bind_Query_Values(t *testing.T) {
+	values := url.Values{
+		"page":    {"2"},
+		"size":    {"50"},
+		"q":       {"golang"},
+		"active":  {"true"},
+		"tags":    {"a,b", "c"},
+		"ids":     {"1", "2"},
+		"maxAge":  {"1h"},
+		"since":   {"2024-01-02"},
+		"score":   {"4.5"},
+		"Ignored": {"x"},
+		"filters": {"anything"},
+	}
+
+	var q testSearchQuery
+	require.NoError(t, BindQueryValues(values, &q))
+
+	assert.Equal(t, 2, q.Page)
+	assert.Equal(t, 50, q.Size)
+	assert.Equal(t, "golang", q.Term)
+	require.NotNil(t, q.Active)
+	assert.True(t, *q.Active)
+	assert.Equal(t, []string{"a", "b", "c"}, q.Tags)
+	assert.Equal(t, []int64{1, 2}, q.IDs)
+	assert.Equal(t, time.Hour, q.MaxAge)
+	assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), q.Since)
+	assert.Equal(t, 4.5, q.Score)
+	assert.Empty(t, q.Ignored)
+	assert.Nil(t, q.Filters)
+}
+
+func Test_Bind_Query_Values_Errors(t *testing.T) {
+	var q testSearchQuery
+	err := BindQueryValues(url.Values{"page": {"abc"}}, &q)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"page"`)
+
+	assert.Error(t, BindQueryValues(url.Values{}, q))
+	assert.Error(t, BindQueryValues(url.Values{}, (*testSearchQuery)(nil)))
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// BackoffConfig configures RetryWithBackoff. Zero fields take the defaults of
+// DefaultBackoffConfig, except Jitter, RetryAfter and ShouldRetry's default.
+type BackoffConfig struct {
+	// MaxAttempts is the total number of attempts, including the first
+	MaxAttempts int
+
+	// InitialDelay is the delay before the first retry
+	InitialDelay time.Duration
+
+	// MaxDelay caps every delay, including those requested through RetryAfter
+	MaxDelay time.Duration
+
+	// Multiplier grows the delay after every retry
+	Multiplier float64
+
+	// Jitter randomizes each delay by up to this fraction (0 to 1) so that many
+	// clients failing together do not retry in lockstep
+	Jitter float64
+
+	// ShouldRetry reports whether a failed attempt may be retried. Defaults to
+	// errors.IsRetryable.
+	ShouldRetry func(err error) bool
+
+	// RetryAfter optionally returns the delay a failed attempt asked for (e.g. an
+	// HTTP Retry-After header), used instead of the computed backoff
+	RetryAfter func(err error) (time.Duration, bool)
+}
+
+// DefaultBackoffConfig returns 3 attempts starting at 100ms, doubling up to 10s, with
+// 20% jitter
+func DefaultBackoffConfig() BackoffConfig {
+	return BackoffConfig{
+		MaxAttempts:  3,
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2,
+		Jitter:       0.2,
+	}
+}
+
+// RetryWithBackoff calls fn until it succeeds, returns an error ShouldRetry rejects,
+// or MaxAttempts is reached, sleeping with exponential backoff between attempts.
+// It stops early when ctx is done. The error of the last attempt is returned.
+//
+// Example:
+//
+//	err := utils.RetryWithBackoff(ctx, utils.DefaultBackoffConfig(), func(ctx context.Context) error {
+//	    return client.Publish(ctx, event)
+//	})
+func RetryWithBackoff(ctx context.Context, cfg BackoffConfig, fn func(ctx context.Context) error) error {
+	cfg = cfg.withDefaults()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+
+		if attempt >= cfg.MaxAttempts || !cfg.ShouldRetry(err) {
+			return err
+		}
+
+		timer := time.NewTimer(cfg.delay(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func (c BackoffConfig) withDefaults() BackoffConfig {
+	defaults := DefaultBackoffConfig()
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaults.MaxAttempts
+	}
+	if c.InitialDelay <= 0 {
+		c.InitialDelay = defaults.InitialDelay
+	}
+	if c.MaxDelay <= 0 {
+		c.MaxDelay = defaults.MaxDelay
+	}
+	if c.Multiplier < 1 {
+		c.Multiplier = defaults.Multiplier
+	}
+	c.Jitter = Clamp(c.Jitter, 0, 1)
+	if c.ShouldRetry == nil {
+		c.ShouldRetry = errors.IsRetryable
+	}
+	return c
+}
+
+// delay returns how long to wait after the given failed attempt
+func (c BackoffConfig) delay(attempt int, err error) time.Duration {
+	if c.RetryAfter != nil {
+		if d, ok := c.RetryAfter(err); ok && d >= 0 {
+			return min(d, c.MaxDelay)
+		}
+	}
+
+	d := float64(c.InitialDelay)
+	for i := 1; i < attempt && d < float64(c.MaxDelay); i++ {
+		d *= c.Multiplier
+	}
+	if c.Jitter > 0 {
+		d += d * c.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return min(time.Duration(d), c.MaxDelay)
+}
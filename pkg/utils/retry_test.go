@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fastBackoff() BackoffConfig {
+	return BackoffConfig{MaxAttempts: 4, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+func Test_RetryWithBackoff_Retries_Until_Success(t *testing.T) {
+	calls := 0
+	err := RetryWithBackoff(context.Background(), fastBackoff(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New(errors.CodeServiceUnavailable)
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func Test_RetryWithBackoff_Stops_After_Max_Attempts(t *testing.T) {
+	calls := 0
+	err := RetryWithBackoff(context.Background(), fastBackoff(), func(context.Context) error {
+		calls++
+		return errors.New(errors.CodeServiceUnavailable)
+	})
+
+	assert.True(t, errors.Is(err, errors.CodeServiceUnavailable))
+	assert.Equal(t, 4, calls)
+}
+
+func Test_RetryWithBackoff_Does_Not_Retry_Client_Errors(t *testing.T) {
+	calls := 0
+	err := RetryWithBackoff(context.Background(), fastBackoff(), func(context.Context) error {
+		calls++
+		return errors.BadRequest("invalid")
+	})
+
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	assert.Equal(t, 1, calls)
+}
+
+func Test_RetryWithBackoff_Stops_When_Context_Done(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := BackoffConfig{MaxAttempts: 5, InitialDelay: time.Hour, MaxDelay: time.Hour}
+
+	calls := 0
+	start := time.Now()
+	err := RetryWithBackoff(ctx, cfg, func(context.Context) error {
+		calls++
+		cancel()
+		return errors.New(errors.CodeTimeout)
+	})
+
+	assert.True(t, errors.Is(err, errors.CodeTimeout))
+	assert.Equal(t, 1, calls)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func Test_BackoffConfig_Delay(t *testing.T) {
+	cfg := BackoffConfig{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second}.withDefaults()
+
+	assert.Equal(t, 100*time.Millisecond, cfg.delay(1, nil))
+	assert.Equal(t, 200*time.Millisecond, cfg.delay(2, nil))
+	assert.Equal(t, 400*time.Millisecond, cfg.delay(3, nil))
+	assert.Equal(t, time.Second, cfg.delay(10, nil))
+
+	cfg.RetryAfter = func(error) (time.Duration, bool) { return 5 * time.Second, true }
+	assert.Equal(t, time.Second, cfg.delay(1, nil))
+}
+
+func Test_BackoffConfig_Delay_Jitter_Stays_In_Range(t *testing.T) {
+	cfg := BackoffConfig{InitialDelay: 100 * time.Millisecond, Jitter: 0.5}.withDefaults()
+
+	for i := 0; i < 100; i++ {
+		d := cfg.delay(1, nil)
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+		assert.LessOrEqual(t, d, 150*time.Millisecond)
+	}
+}
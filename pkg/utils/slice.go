@@ -292,6 +292,55 @@ func GroupBy[T any, K comparable](slice []T, keyFn func(T) K) map[K][]T {
 	return result
 }
 
+// SliceToMap builds a lookup map from a slice, keyed by keyFn. Use it instead of GroupBy
+// when keys are unique; on duplicate keys the last element wins.
+//
+// Example:
+//
+//	users := []User{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob"}}
+//	byID := utils.SliceToMap(users, func(u User) int { return u.ID })
+//	// byID = map[1:{1 Alice} 2:{2 Bob}]
+func SliceToMap[T any, K comparable](slice []T, keyFn func(T) K) map[K]T {
+	result := make(map[K]T, len(slice))
+	for _, v := range slice {
+		result[keyFn(v)] = v
+	}
+	return result
+}
+
+// SliceToMapV builds a map from a slice, with both key and value derived by kv.
+// On duplicate keys the last element wins.
+//
+// Example:
+//
+//	names := utils.SliceToMapV(users, func(u User) (int, string) { return u.ID, u.Name })
+//	// names = map[1:Alice 2:Bob]
+func SliceToMapV[T any, K comparable, V any](slice []T, kv func(T) (K, V)) map[K]V {
+	result := make(map[K]V, len(slice))
+	for _, v := range slice {
+		key, value := kv(v)
+		result[key] = value
+	}
+	return result
+}
+
+// MapToSlice converts a map to a slice using fn. Map iteration order is random, so
+// sort the result if the order matters.
+//
+// Example:
+//
+//	prices := map[string]float64{"apple": 1.5, "pear": 2}
+//	labels := utils.MapToSlice(prices, func(k string, v float64) string {
+//	    return fmt.Sprintf("%s=%.2f", k, v)
+//	})
+func MapToSlice[K comparable, V any, R any](m map[K]V, fn func(K, V) R) []R {
+	result := make([]R, 0, len(m))
+	for k, v := range m {
+		result = append(result, fn(k, v))
+	}
+	return result
+}
+
 // Partition splits a slice into two slices based on the predicate.
 // The first slice contains elements that satisfy the predicate,
 // the second contains elements that don't.
@@ -480,3 +529,38 @@ func Difference[T comparable](slice []T, others ...[]T) []T {
 
 	return result
 }
+
+// Get returns the element at index i, or the zero value and false when i is out of
+// range (including negative i). Use it instead of indexing dynamic data directly.
+//
+// Example:
+//
+//	first, ok := utils.Get(parts, 0)
+//	utils.Get([]int{1, 2, 3}, 5)  // 0, false
+//	utils.Get([]int{1, 2, 3}, -1) // 0, false
+func Get[T any](slice []T, i int) (T, bool) {
+	if i < 0 || i >= len(slice) {
+		var zero T
+		return zero, false
+	}
+	return slice[i], true
+}
+
+// SafeSlice returns slice[start:end] with start and end clamped into [0, len(slice)],
+// so it never panics. An empty slice is returned when start is at or past end.
+//
+// Example:
+//
+//	numbers := []int{1, 2, 3, 4, 5}
+//	utils.SafeSlice(numbers, 1, 3)   // [2, 3]
+//	utils.SafeSlice(numbers, -2, 2)  // [1, 2]
+//	utils.SafeSlice(numbers, 3, 100) // [4, 5]
+//	utils.SafeSlice(numbers, 4, 1)   // []
+func SafeSlice[T any](slice []T, start, end int) []T {
+	start = Clamp(start, 0, len(slice))
+	end = Clamp(end, 0, len(slice))
+	if start >= end {
+		return []T{}
+	}
+	return slice[start:end]
+}
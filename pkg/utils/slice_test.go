@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUser struct {
+	ID   int
+	Name string
+}
+
+func Test_SliceToMap_Last_Duplicate_Wins(t *testing.T) {
+	users := []testUser{{1, "Alice"}, {2, "Bob"}, {1, "Alicia"}}
+
+	byID := SliceToMap(users, func(u testUser) int { return u.ID })
+
+	assert.Equal(t, map[int]testUser{1: {1, "Alicia"}, 2: {2, "Bob"}}, byID)
+	assert.Empty(t, SliceToMap[testUser](nil, func(u testUser) int { return u.ID }))
+}
+
+func Test_SliceToMapV(t *testing.T) {
+	users := []testUser{{1, "Alice"}, {2, "Bob"}, {2, "Robert"}}
+
+	names := SliceToMapV(users, func(u testUser) (int, string) { return u.ID, u.Name })
+
+	assert.Equal(t, map[int]string{1: "Alice", 2: "Robert"}, names)
+}
+
+func Test_MapToSlice(t *testing.T) {
+	names := map[int]string{1: "Alice", 2: "Bob"}
+
+	users := MapToSlice(names, func(id int, name string) testUser { return testUser{id, name} })
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	assert.Equal(t, []testUser{{1, "Alice"}, {2, "Bob"}}, users)
+}
+
+func Test_Get(t *testing.T) {
+	numbers := []int{10, 20, 30}
+
+	value, ok := Get(numbers, 1)
+	assert.True(t, ok)
+	assert.Equal(t, 20, value)
+
+	for _, i := range []int{-1, 3, 100, math.MinInt, math.MaxInt} {
+		value, ok = Get(numbers, i)
+		assert.False(t, ok, i)
+		assert.Zero(t, value, i)
+	}
+
+	_, ok = Get([]string(nil), 0)
+	assert.False(t, ok)
+}
+
+func Test_SafeSlice(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5}
+
+	assert.Equal(t, []int{2, 3}, SafeSlice(numbers, 1, 3))
+	assert.Equal(t, []int{1, 2}, SafeSlice(numbers, -2, 2))
+	assert.Equal(t, []int{4, 5}, SafeSlice(numbers, 3, 100))
+	assert.Equal(t, numbers, SafeSlice(numbers, math.MinInt, math.MaxInt))
+	assert.Equal(t, []int{}, SafeSlice(numbers, 4, 1))
+	assert.Equal(t, []int{}, SafeSlice(numbers, 10, 20))
+	assert.Equal(t, []int{}, SafeSlice(numbers, -5, -1))
+	assert.Equal(t, []int{}, SafeSlice([]int(nil), 0, 1))
+}
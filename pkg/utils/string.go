@@ -487,3 +487,65 @@ func Join(elements []interface{}, separator string) string {
 	}
 	return strings.Join(strs, separator)
 }
+
+// ansiEscapePattern matches terminal escape sequences: CSI sequences such as colors
+// ("\x1b[31m"), OSC sequences such as window titles, and two-character escapes
+var ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// defaultAllowedControls are the control characters kept by SanitizeString
+const defaultAllowedControls = "\t\n"
+
+// SanitizeOption configures SanitizeString
+type SanitizeOption func(*sanitizeOptions)
+
+type sanitizeOptions struct {
+	allowed string
+}
+
+// WithAllowedControls sets the control characters SanitizeString keeps, instead of
+// tab and newline. Pass an empty string to remove every control character.
+//
+// Example:
+//
+//	line := utils.SanitizeString(input, utils.WithAllowedControls(""))
+func WithAllowedControls(chars string) SanitizeOption {
+	return func(o *sanitizeOptions) {
+		o.allowed = chars
+	}
+}
+
+// SanitizeString removes non-printable control characters (null bytes, carriage
+// returns, C1 controls, ...) except tab and newline, so user input can't corrupt
+// logs or forge log lines. Invalid UTF-8 is dropped. Combine it with StripANSI to
+// also remove terminal escape sequences.
+//
+// Example:
+//
+//	str := utils.SanitizeString("admin\r\x00\tok")
+//	// str = "admin\tok"
+func SanitizeString(s string, opts ...SanitizeOption) string {
+	o := sanitizeOptions{allowed: defaultAllowedControls}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && !strings.ContainsRune(o.allowed, r) {
+			return -1
+		}
+		return r
+	}, strings.ToValidUTF8(s, ""))
+}
+
+// StripANSI removes terminal escape sequences such as colors and cursor movements.
+//
+// Example:
+//
+//	str := utils.StripANSI("\x1b[31mred\x1b[0m")
+//	// str = "red"
+func StripANSI(s string) string {
+	if !strings.ContainsRune(s, '\x1b') {
+		return s
+	}
+	return ansiEscapePattern.ReplaceAllString(s, "")
+}
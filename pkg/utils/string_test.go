@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SanitizeString(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		opts  []SanitizeOption
+		want  string
+	}{
+		"null bytes":                  {input: "ad\x00min", want: "admin"},
+		"carriage return":             {input: "ok\r\nINFO forged entry", want: "ok\nINFO forged entry"},
+		"keeps tab and newline":       {input: "a\tb\nc", want: "a\tb\nc"},
+		"c1 controls and delete":      {input: "a\u0085b\x7fc", want: "abc"},
+		"invalid utf-8":               {input: "a\xffb", want: "ab"},
+		"unicode text untouched":      {input: "Xin chào 👋", want: "Xin chào 👋"},
+		"no allowed controls":         {input: "a\tb\nc", opts: []SanitizeOption{WithAllowedControls("")}, want: "abc"},
+		"custom allowed controls":     {input: "a\tb\r\nc", opts: []SanitizeOption{WithAllowedControls("\r\n")}, want: "ab\r\nc"},
+		"escape character is removed": {input: "\x1b[31mred", want: "[31mred"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SanitizeString(tt.input, tt.opts...))
+		})
+	}
+}
+
+func Test_StripANSI(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"colors":          {input: "\x1b[31mred\x1b[0m and \x1b[1;32mgreen\x1b[m", want: "red and green"},
+		"cursor movement": {input: "a\x1b[2Kb\x1b[10;20Hc", want: "abc"},
+		"window title":    {input: "\x1b]0;pwned\x07text", want: "text"},
+		"two-char escape": {input: "a\x1bMb", want: "ab"},
+		"plain text":      {input: "plain [31m text", want: "plain [31m text"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StripANSI(tt.input))
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package utils
+
+import (
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentMap is a map safe for concurrent use, implemented by SyncMap and ShardedMap
+type ConcurrentMap[K comparable, V any] interface {
+	// Load returns the value stored for key
+	Load(key K) (value V, ok bool)
+	// Store sets the value for key
+	Store(key K, value V)
+	// LoadOrStore returns the value stored for key, or stores and returns value when
+	// there is none. loaded reports whether the value was already stored.
+	LoadOrStore(key K, value V) (actual V, loaded bool)
+	// Delete removes key
+	Delete(key K)
+	// Range calls fn for the entries until it returns false. Like sync.Map.Range, it
+	// doesn't see a consistent snapshot: entries changed concurrently may be missed.
+	Range(fn func(key K, value V) bool)
+	// Len returns the number of entries
+	Len() int
+}
+
+var (
+	_ ConcurrentMap[string, int] = (*SyncMap[string, int])(nil)
+	_ ConcurrentMap[string, int] = (*ShardedMap[string, int])(nil)
+)
+
+// SyncMap is a typed sync.Map. Like sync.Map it suits keys written once and read many
+// times, or goroutines working on disjoint keys; use ShardedMap for keys updated often
+// from many goroutines. The zero value is ready to use.
+//
+// Example:
+//
+//	var sessions utils.SyncMap[string, *Session]
+//	sessions.Store(session.ID, session)
+//	if s, ok := sessions.Load(id); ok {
+//	    ...
+//	}
+type SyncMap[K comparable, V any] struct {
+	m   sync.Map
+	len atomic.Int64
+}
+
+// Load returns the value stored for key
+func (m *SyncMap[K, V]) Load(key K) (V, bool) {
+	value, ok := m.m.Load(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return value.(V), true
+}
+
+// Store sets the value for key
+func (m *SyncMap[K, V]) Store(key K, value V) {
+	if _, loaded := m.m.Swap(key, value); !loaded {
+		m.len.Add(1)
+	}
+}
+
+// LoadOrStore returns the value stored for key, or stores and returns value when there
+// is none. loaded reports whether the value was already stored.
+func (m *SyncMap[K, V]) LoadOrStore(key K, value V) (V, bool) {
+	actual, loaded := m.m.LoadOrStore(key, value)
+	if !loaded {
+		m.len.Add(1)
+	}
+	return actual.(V), loaded
+}
+
+// Delete removes key
+func (m *SyncMap[K, V]) Delete(key K) {
+	if _, loaded := m.m.LoadAndDelete(key); loaded {
+		m.len.Add(-1)
+	}
+}
+
+// Range calls fn for the entries until it returns false
+func (m *SyncMap[K, V]) Range(fn func(key K, value V) bool) {
+	m.m.Range(func(key, value any) bool {
+		return fn(key.(K), value.(V))
+	})
+}
+
+// Len returns the number of entries. It is exact once concurrent writes complete.
+func (m *SyncMap[K, V]) Len() int {
+	return int(m.len.Load())
+}
+
+// DefaultMapShards is the number of shards of NewShardedMap when shards <= 0
+const DefaultMapShards = 32
+
+// ShardedMap is a map split into shards, each guarded by its own mutex, so that writes
+// to different keys rarely contend. It outperforms SyncMap under heavy writes, e.g.
+// per-client counters. It must be created with NewShardedMap.
+//
+// Example:
+//
+//	attempts := utils.NewShardedMap[string, int](0)
+//	attempts.Store(clientIP, 1)
+type ShardedMap[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []mapShard[K, V]
+}
+
+type mapShard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// NewShardedMap creates a ShardedMap with shards shards, DefaultMapShards when
+// shards <= 0. More shards reduce contention at the cost of slower Range and Len.
+func NewShardedMap[K comparable, V any](shards int) *ShardedMap[K, V] {
+	if shards <= 0 {
+		shards = DefaultMapShards
+	}
+
+	m := &ShardedMap[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]mapShard[K, V], shards),
+	}
+	for i := range m.shards {
+		m.shards[i].m = make(map[K]V)
+	}
+	return m
+}
+
+func (m *ShardedMap[K, V]) shard(key K) *mapShard[K, V] {
+	return &m.shards[maphash.Comparable(m.seed, key)%uint64(len(m.shards))]
+}
+
+// Load returns the value stored for key
+func (m *ShardedMap[K, V]) Load(key K) (V, bool) {
+	shard := m.shard(key)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	value, ok := shard.m[key]
+	return value, ok
+}
+
+// Store sets the value for key
+func (m *ShardedMap[K, V]) Store(key K, value V) {
+	shard := m.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	shard.m[key] = value
+}
+
+// LoadOrStore returns the value stored for key, or stores and returns value when there
+// is none. loaded reports whether the value was already stored.
+func (m *ShardedMap[K, V]) LoadOrStore(key K, value V) (V, bool) {
+	shard := m.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if actual, ok := shard.m[key]; ok {
+		return actual, true
+	}
+	shard.m[key] = value
+	return value, false
+}
+
+// Delete removes key
+func (m *ShardedMap[K, V]) Delete(key K) {
+	shard := m.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	delete(shard.m, key)
+}
+
+// Range calls fn for the entries until it returns false. Shards are copied one at a
+// time, so fn may modify the map.
+func (m *ShardedMap[K, V]) Range(fn func(key K, value V) bool) {
+	for i := range m.shards {
+		shard := &m.shards[i]
+		shard.mu.RLock()
+		entries := make(map[K]V, len(shard.m))
+		for key, value := range shard.m {
+			entries[key] = value
+		}
+		shard.mu.RUnlock()
+
+		for key, value := range entries {
+			if !fn(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of entries
+func (m *ShardedMap[K, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		shard := &m.shards[i]
+		shard.mu.RLock()
+		n += len(shard.m)
+		shard.mu.RUnlock()
+	}
+	return n
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrentMaps returns an empty instance of each ConcurrentMap implementation
+func concurrentMaps() map[string]func() ConcurrentMap[string, int] {
+	return map[string]func() ConcurrentMap[string, int]{
+		"SyncMap":    func() ConcurrentMap[string, int] { return &SyncMap[string, int]{} },
+		"ShardedMap": func() ConcurrentMap[string, int] { return NewShardedMap[string, int](4) },
+	}
+}
+
+func Test_ConcurrentMap_Operations(t *testing.T) {
+	for name, newMap := range concurrentMaps() {
+		t.Run(name, func(t *testing.T) {
+			m := newMap()
+
+			_, ok := m.Load("a")
+			assert.False(t, ok)
+			assert.Equal(t, 0, m.Len())
+
+			m.Store("a", 1)
+			m.Store("a", 2)
+			m.Store("b", 3)
+			value, ok := m.Load("a")
+			require.True(t, ok)
+			assert.Equal(t, 2, value)
+			assert.Equal(t, 2, m.Len())
+
+			actual, loaded := m.LoadOrStore("a", 10)
+			assert.True(t, loaded)
+			assert.Equal(t, 2, actual)
+			actual, loaded = m.LoadOrStore("c", 4)
+			assert.False(t, loaded)
+			assert.Equal(t, 4, actual)
+			assert.Equal(t, 3, m.Len())
+
+			m.Delete("b")
+			m.Delete("missing")
+			_, ok = m.Load("b")
+			assert.False(t, ok)
+			assert.Equal(t, 2, m.Len())
+
+			seen := map[string]int{}
+			m.Range(func(key string, value int) bool {
+				seen[key] = value
+				return true
+			})
+			assert.Equal(t, map[string]int{"a": 2, "c": 4}, seen)
+
+			visited := 0
+			m.Range(func(string, int) bool {
+				visited++
+				return false
+			})
+			assert.Equal(t, 1, visited)
+		})
+	}
+}
+
+func Test_ConcurrentMap_Concurrent_Writers(t *testing.T) {
+	const goroutines, keys = 16, 200
+
+	for name, newMap := range concurrentMaps() {
+		t.Run(name, func(t *testing.T) {
+			m := newMap()
+			var winners atomic.Int32
+
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for k := 0; k < keys; k++ {
+						key := fmt.Sprintf("key-%d", k)
+						if _, loaded := m.LoadOrStore(key, g); !loaded {
+							winners.Add(1)
+						}
+						m.Store(fmt.Sprintf("own-%d-%d", g, k), k)
+						m.Load(key)
+						m.Len()
+						if k%2 == 0 {
+							m.Delete(fmt.Sprintf("own-%d-%d", g, k))
+						}
+						if k%50 == 0 {
+							m.Range(func(string, int) bool { return true })
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			// Each shared key is stored by exactly one goroutine
+			assert.Equal(t, int32(keys), winners.Load())
+			assert.Equal(t, keys+goroutines*keys/2, m.Len())
+
+			counted := 0
+			m.Range(func(string, int) bool {
+				counted++
+				return true
+			})
+			assert.Equal(t, m.Len(), counted)
+		})
+	}
+}
+
+func Test_ShardedMap_Range_Allows_Modifications(t *testing.T) {
+	m := NewShardedMap[int, int](0)
+	for i := 0; i < 100; i++ {
+		m.Store(i, i)
+	}
+
+	m.Range(func(key, value int) bool {
+		if key%2 == 1 {
+			m.Delete(key)
+		}
+		return true
+	})
+
+	assert.Equal(t, 50, m.Len())
+	assert.Len(t, m.shards, DefaultMapShards)
+}
+
+func BenchmarkConcurrentMaps(b *testing.B) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("client-%d", i)
+	}
+
+	for name, newMap := range concurrentMaps() {
+		b.Run(name, func(b *testing.B) {
+			m := newMap()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					m.Store(keys[i%len(keys)], i)
+					i++
+				}
+			})
+		})
+	}
+}
@@ -12,13 +12,23 @@ Safe pointer operations with generics:
   - ValueOrZero: Dereference or return zero value
   - Equal: Compare pointers safely
   - Coalesce: Get first non-nil value
+  - PtrSlice, DerefSlice, PtrMap: Convert slices and maps to/from pointers
+
+# Optional Values (option.go)
+
+Lightweight, opt-in wrappers for explicit optionality:
+  - Option: SomeOption/NoneOption with Get and OrElse
+  - Result: Ok/Err with Unwrap and UnwrapOr
+  - FindOption, FirstOption, LastOption: Option-returning search helpers
 
 # Type Conversion (convert.go)
 
 Type conversion and parsing utilities:
-  - ToString, ToInt, ToInt64, ToFloat64, ToBool: Safe type conversions
+  - ToString, ToInt, ToInt64, ToFloat64, ToBool: Best-effort conversions (0/false on failure)
+  - ToIntE, ToInt64E, ToFloat64E, ToBoolE: Strict conversions returning CodeInvalidInput errors
   - ParseDuration, ParseTime: Parse time values
   - FormatTime: Format time values
+  - Decimal, ParseDecimal, ParseCurrency, FromCents: Exact decimal math for money, unlike ToFloat64 (decimal.go)
 
 # Slice Operations (slice.go)
 
@@ -27,10 +37,12 @@ Functional programming utilities for slices:
   - Filter: Filter slice by predicate
   - Reduce: Reduce slice to single value
   - Contains, Find: Search operations
+  - Get, SafeSlice: Bounds-checked indexing and slicing
   - Unique: Remove duplicates
   - Chunk: Split into chunks
   - Flatten: Flatten nested slices
   - GroupBy: Group by key function
+  - SliceToMap, SliceToMapV, MapToSlice: Keyed conversions between slices and maps
   - Partition: Split by predicate
 
 # Pagination (pagination.go)
@@ -38,8 +50,12 @@ Functional programming utilities for slices:
 Comprehensive pagination support:
   - Pagination: Offset-based pagination
   - CursorPagination: Cursor-based pagination
+  - EncodeCursor, DecodeCursor: Opaque cursors with optional HMAC signing (cursor.go)
   - PageToOffset: Convert page/size to offset/limit
   - PaginationResult: Wrap paginated data
+  - ListResult.Paginated: Convert to the standard contracts.Paginated list envelope
+  - WritePaginatedJSON: Write a ListResult with Link and X-Total-Count headers
+  - BindQueryValues: Decode query parameters into structs via `query` tags (query.go)
 
 # String Operations (string.go)
 
@@ -47,19 +63,49 @@ String manipulation utilities:
   - IsEmpty, IsNotEmpty: Check string emptiness
   - Truncate, TruncateWords: Truncate strings
   - CamelCase, PascalCase, SnakeCase, KebabCase: Case conversion
+  - MatchJSONKeys: Match snake_case/camelCase JSON keys to the json names of struct fields (json_keys.go)
   - Slugify: Create URL-friendly slugs
   - MaskString: Mask sensitive data
+  - MaskEmail, MaskCreditCard, MaskPhone: Display-safe masking of common PII (mask.go)
+  - SanitizeString, StripANSI: Remove control characters and terminal escapes from user input
+  - NormalizeEmail, NormalizePhone: Canonical emails and E.164 phone numbers (normalize.go)
   - RandomString: Generate random strings
 
+# Identifiers (id.go)
+
+UUID and ULID generation using crypto/rand:
+  - NewUUID: Random (version 4) UUID
+  - NewUUIDv7, NewULID: Time-sortable, monotonic identifiers
+  - ParseUUID, IsUUID, UUIDVersion, UUIDv7Time: UUID parsing and validation
+  - ParseULID, IsULID, ULIDTime: ULID parsing and validation
+
 # Common Utilities (common.go)
 
 General-purpose utilities:
   - Ternary: Conditional expression
   - Min, Max, Clamp: Numeric operations
   - IsZero, IsNotZero: Zero value checks
+  - DefaultIfZero, FirstNonZero: Fall back past zero values
   - Must, MustNoError: Panic on error
   - Try: Safe function execution
   - RetryFunc: Retry with attempts
+  - RetryWithBackoff: Retry retryable errors with exponential backoff (retry.go)
+  - WithTimeout, DoWithTimeout: Deadlines that can't leak their cancel func (context.go)
+  - Memoize: Cache results of expensive pure functions with TTL and single-flight (memoize.go)
+  - DeepEqual, DeepClone: Structural equality and deep copies (deep.go)
+  - Hooks, ErrorHooks: Ordered lifecycle callbacks, with joined errors for the failing variant (hooks.go)
+  - NewPool, Pool: Type-safe sync.Pool with a reset hook for hot-path scratch objects (pool.go)
+  - SyncMap, ShardedMap: Typed concurrent maps for standalone keyed state, sharded for heavy write contention (syncmap.go)
+
+# Enums (enum/)
+
+The enum subpackage provides a generic Enum[T ~string] with Valid, Parse, MustParse,
+Options and MustBeOneOf. It is kept import-free so that config validation can use it.
+
+# Pools (pool/)
+
+The pool subpackage implements Pool, re-exported here as utils.Pool. Like enum it is
+import-free, so that pkg/errors can pool its response buffers.
 
 # Usage Examples
 
@@ -0,0 +1,173 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+)
+
+// ValidationError is a failed rule on a single field
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors is a collection of validation errors
+type ValidationErrors []ValidationError
+
+// Error implements the error interface
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add adds a validation error
+func (e *ValidationErrors) Add(field, message string) {
+	*e = append(*e, ValidationError{Field: field, Message: message})
+}
+
+// HasErrors returns true if there are validation errors
+func (e ValidationErrors) HasErrors() bool {
+	return len(e) > 0
+}
+
+// Fields converts the errors to the field errors rendered by the error handler
+func (e ValidationErrors) Fields() []errors.ValidationField {
+	fields := make([]errors.ValidationField, 0, len(e))
+	for _, err := range e {
+		fields = append(fields, errors.ValidationField{Field: err.Field, Message: err.Message})
+	}
+	return fields
+}
+
+// Rule validates a single value, returning a message describing the failure or ""
+type Rule func(value any) string
+
+// Required fails when value is nil or the zero value of its type, such as "" or 0.
+// Empty slices and maps fail too.
+func Required() Rule {
+	return func(value any) string {
+		if isEmpty(value) {
+			return "is required"
+		}
+		return ""
+	}
+}
+
+// OneOf fails when value is not one of allowed. Values of a different type than T
+// always fail.
+func OneOf[T comparable](allowed ...T) Rule {
+	names := make([]string, len(allowed))
+	for i, v := range allowed {
+		names[i] = fmt.Sprint(v)
+	}
+	message := "must be one of: " + strings.Join(names, ", ")
+
+	return func(value any) string {
+		v, ok := value.(T)
+		if !ok {
+			return message
+		}
+		for _, a := range allowed {
+			if v == a {
+				return ""
+			}
+		}
+		return message
+	}
+}
+
+// When applies rule only if cond holds, for rules depending on other fields
+//
+// Example:
+//
+//	validator.When(req.Type == "company", validator.Required())
+func When(cond bool, rule Rule) Rule {
+	return func(value any) string {
+		if !cond {
+			return ""
+		}
+		return rule(value)
+	}
+}
+
+// Custom adapts fn to a Rule, using the message of the returned error
+func Custom(fn func(value any) error) Rule {
+	return func(value any) string {
+		if err := fn(value); err != nil {
+			return err.Error()
+		}
+		return ""
+	}
+}
+
+// Chain collects the failures of rules applied to several fields. It complements
+// struct tag validation for dynamic and cross-field rules, and reports every
+// failure rather than stopping at the first one.
+//
+// Example:
+//
+//	err := validator.NewChain().
+//		Field("type", req.Type, validator.Required(), validator.OneOf("person", "company")).
+//		Field("vat_number", req.VATNumber, validator.When(req.Type == "company", validator.Required())).
+//		Validate()
+type Chain struct {
+	errs ValidationErrors
+}
+
+// NewChain creates an empty chain
+func NewChain() *Chain {
+	return &Chain{}
+}
+
+// Field applies rules to value in order, recording the first failure under field
+func (c *Chain) Field(field string, value any, rules ...Rule) *Chain {
+	for _, rule := range rules {
+		if message := rule(value); message != "" {
+			c.errs.Add(field, message)
+			break
+		}
+	}
+	return c
+}
+
+// Errors returns the failures recorded so far
+func (c *Chain) Errors() ValidationErrors {
+	return c.errs
+}
+
+// Validate returns nil when every rule passed, and otherwise a CodeValidation
+// AppError wrapping the ValidationErrors, with one field error per failure
+func (c *Chain) Validate() error {
+	if !c.errs.HasErrors() {
+		return nil
+	}
+	return errors.Wrap(c.errs, errors.CodeValidation).WithFieldErrors(c.errs.Fields()...)
+}
+
+// isEmpty reports whether value is nil or the zero value of its type
+func isEmpty(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
@@ -0,0 +1,98 @@
+package validator
+
+import (
+	stdErrors "errors"
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type accountRequest struct {
+	Type      string
+	Name      string
+	VATNumber string
+	Tags      []string
+}
+
+func validateAccount(req accountRequest) *Chain {
+	return NewChain().
+		Field("type", req.Type, Required(), OneOf("person", "company")).
+		Field("name", req.Name, Required()).
+		Field("vat_number", req.VATNumber, When(req.Type == "company", Required()))
+}
+
+func Test_Chain_Passes_Valid_Input(t *testing.T) {
+	chain := validateAccount(accountRequest{Type: "person", Name: "Ann"})
+
+	assert.False(t, chain.Errors().HasErrors())
+	assert.NoError(t, chain.Validate())
+}
+
+func Test_Chain_When_Applies_Rule_Only_If_Condition_Holds(t *testing.T) {
+	assert.NoError(t, validateAccount(accountRequest{Type: "person", Name: "Ann"}).Validate())
+
+	errs := validateAccount(accountRequest{Type: "company", Name: "Acme"}).Errors()
+	assert.Equal(t, ValidationErrors{{Field: "vat_number", Message: "is required"}}, errs)
+
+	assert.NoError(t, validateAccount(accountRequest{Type: "company", Name: "Acme", VATNumber: "DE123"}).Validate())
+}
+
+func Test_Chain_Collects_All_Failures(t *testing.T) {
+	errs := validateAccount(accountRequest{Type: "partner"}).Errors()
+
+	assert.Equal(t, ValidationErrors{
+		{Field: "type", Message: "must be one of: person, company"},
+		{Field: "name", Message: "is required"},
+	}, errs)
+	assert.Equal(t, "type: must be one of: person, company; name: is required", errs.Error())
+}
+
+func Test_Chain_Records_First_Failing_Rule_Per_Field(t *testing.T) {
+	errs := NewChain().Field("type", "", Required(), OneOf("person")).Errors()
+
+	assert.Equal(t, ValidationErrors{{Field: "type", Message: "is required"}}, errs)
+}
+
+func Test_Chain_Custom_Rule(t *testing.T) {
+	even := Custom(func(value any) error {
+		if value.(int)%2 != 0 {
+			return stdErrors.New("must be even")
+		}
+		return nil
+	})
+
+	assert.NoError(t, NewChain().Field("count", 4, even).Validate())
+	assert.Equal(t,
+		ValidationErrors{{Field: "count", Message: "must be even"}},
+		NewChain().Field("count", 3, even).Errors(),
+	)
+}
+
+func Test_Required_Rejects_Zero_Values(t *testing.T) {
+	var nilPtr *int
+	for _, value := range []any{nil, "", 0, nilPtr, []string{}, map[string]int{}} {
+		assert.Equal(t, "is required", Required()(value), "%#v", value)
+	}
+	for _, value := range []any{"a", 1, new(int), []string{"a"}} {
+		assert.Empty(t, Required()(value), "%#v", value)
+	}
+}
+
+func Test_Chain_Validate_Returns_Validation_AppError(t *testing.T) {
+	err := validateAccount(accountRequest{Type: "company"}).Validate()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.CodeValidation))
+
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Len(t, errs, 2)
+
+	problem := errors.ToProblemDetail(err, errors.DefaultConfig())
+	assert.Equal(t, []errors.ValidationField{
+		{Field: "name", Message: "is required"},
+		{Field: "vat_number", Message: "is required"},
+	}, problem.Errors)
+}
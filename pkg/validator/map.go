@@ -0,0 +1,106 @@
+package validator
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Tag adapts a go-playground validator tag, e.g. "required,email" or "numeric,gt=0", to
+// a Rule validated with the default validator. A nil value, such as a missing field,
+// only fails when the tag includes required.
+func Tag(tag string) Rule {
+	required := slices.Contains(strings.Split(tag, ","), "required")
+
+	return func(value any) string {
+		if value == nil {
+			if required {
+				return "is required"
+			}
+			return ""
+		}
+
+		err := Var(value, tag)
+		var fieldErrs validator.ValidationErrors
+		if stdErrors.As(err, &fieldErrs) && len(fieldErrs) > 0 {
+			return tagMessage(fieldErrs[0])
+		}
+		if err != nil {
+			return err.Error()
+		}
+		return ""
+	}
+}
+
+// Map validates the values of data against rules, a field→tag map such as
+// {"email": "required,email"}, and returns the chain of failures, so that data without a
+// struct, such as a decoded JSON object or query parameters, can be validated. Fields
+// are validated in lexical order; fields missing from data are nil (see Tag).
+//
+// Example:
+//
+//	err := validator.Map(payload, map[string]string{
+//	    "email": "required,email",
+//	    "age":   "gte=18",
+//	}).Validate()
+func Map(data map[string]any, rules map[string]string) *Chain {
+	fields := make([]string, 0, len(rules))
+	for field := range rules {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	chain := NewChain()
+	for _, field := range fields {
+		chain.Field(field, data[field], Tag(rules[field]))
+	}
+	return chain
+}
+
+// tagMessage describes a failed validator tag like the Chain rules do
+func tagMessage(fe validator.FieldError) string {
+	param := fe.Param()
+	unit := ""
+	switch fe.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		unit = " items"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email"
+	case "url", "uri":
+		return "must be a valid URL"
+	case "uuid", "uuid4", "uuid7":
+		return "must be a valid UUID"
+	case "numeric", "number":
+		return "must be a number"
+	case "boolean":
+		return "must be a boolean"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "len":
+		return fmt.Sprintf("must be exactly %s%s", param, unit)
+	case "min", "gte":
+		return fmt.Sprintf("must be at least %s%s", param, unit)
+	case "max", "lte":
+		return fmt.Sprintf("must be at most %s%s", param, unit)
+	case "gt":
+		return fmt.Sprintf("must be greater than %s%s", param, unit)
+	case "lt":
+		return fmt.Sprintf("must be less than %s%s", param, unit)
+	default:
+		if param != "" {
+			return fmt.Sprintf("must satisfy %s=%s", fe.Tag(), param)
+		}
+		return "must satisfy " + fe.Tag()
+	}
+}
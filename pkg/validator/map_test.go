@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/phatnt199/go-infra/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Map_Validates_Fields_With_Tags(t *testing.T) {
+	rules := map[string]string{
+		"email": "required,email",
+		"name":  "required,min=2",
+		"age":   "gte=18",
+		"role":  "oneof=admin member",
+	}
+
+	errs := Map(map[string]any{"email": "nope", "name": "A", "age": 16.0}, rules).Errors()
+	assert.Equal(t, ValidationErrors{
+		{Field: "age", Message: "must be at least 18"},
+		{Field: "email", Message: "must be a valid email"},
+		{Field: "name", Message: "must be at least 2 characters"},
+	}, errs)
+
+	assert.NoError(t, Map(map[string]any{"email": "ann@example.com", "name": "Ann", "role": "admin"}, rules).Validate())
+}
+
+func Test_Map_Missing_Fields_Only_Fail_Required(t *testing.T) {
+	err := Map(map[string]any{}, map[string]string{"email": "required,email", "age": "gte=18"}).Validate()
+	require.Error(t, err)
+
+	appErr, ok := errors.As(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.CodeValidation, appErr.Code)
+	assert.Equal(t, ValidationErrors{{Field: "email", Message: "is required"}}, appErr.Cause)
+}
+
+func Test_Tag_Rule_In_Chain(t *testing.T) {
+	errs := NewChain().
+		Field("tags", []string{}, Tag("min=2")).
+		Field("website", "not a url", Tag("url")).
+		Errors()
+
+	assert.Equal(t, ValidationErrors{
+		{Field: "tags", Message: "must be at least 2 items"},
+		{Field: "website", Message: "must be a valid URL"},
+	}, errs)
+}